@@ -19,7 +19,7 @@ Bad flag with an implied run command should display run flags
   
     note: to pass '--bad-flag' as a value, use '-- --bad-flag'
   
-  Usage: turbo <--cache-dir <CACHE_DIR>|--cache-workers <CACHE_WORKERS>|--concurrency <CONCURRENCY>|--continue|--dry-run [<DRY_RUN>]|--single-package|--filter <FILTER>|--force|--global-deps <GLOBAL_DEPS>|--graph [<GRAPH>]|--ignore <IGNORE>|--include-dependencies|--no-cache|--no-daemon|--no-deps|--output-logs <OUTPUT_LOGS>|--only|--parallel|--pkg-inference-root <PKG_INFERENCE_ROOT>|--profile <PROFILE>|--remote-only|--scope <SCOPE>|--since <SINCE>|--log-prefix <LOG_PREFIX>|TASKS|PASS_THROUGH_ARGS>
+  Usage: turbo <--cache-dir <CACHE_DIR>|--cache-key-salt <SALT>|--cache-workers <CACHE_WORKERS>|--cache-upload-concurrency <COUNT>|--cache-backend <URL>|--cache-signing <CACHE_SIGNING>|--max-cache-size <SIZE>|--check-reproducible|--affected-output <FILE>|--audit-io|--completion-webhook <URL>|--compress-logs|--concurrency <CONCURRENCY>|--concurrency-group <GROUP=LIMIT>|--continue|--defer-cache-writes|--dry-run [<DRY_RUN>]|--dump-inputs <DIR>|--env-mode <ENV_MODE>|--error-on-empty|--errors <ERRORS>|--events-socket <PATH>|--single-package|--filter <FILTER>|--force|--github-annotations[=<BOOL>]|--global-deps <GLOBAL_DEPS>|--graph [<GRAPH>]|--ignore <IGNORE>|--include-dependencies|--isolate-outputs|--kill-timeout <DURATION>|--log-file-name <TEMPLATE>|--max-log-bytes-per-task <SIZE>|--no-cache|--no-daemon|--no-deps|--no-lockfile-cache|--no-op [<DURATION>]|--no-preamble|--no-workspace-cache|--offline|--output-logs <OUTPUT_LOGS>|--output <OUTPUT>|--only|--parallel|--pkg-inference-root <PKG_INFERENCE_ROOT>|--prefetch|--profile <PROFILE>|--remote-cache-read-only|--remote-only|--rerun-dependents-of <TASK>|--require-remote-cache|--run-timeout <DURATION>|--scope <SCOPE>|--sequential-prefix-colors|--serial-within-package|--since <SINCE>|--stream-logs-to <DIR>|--strict|--strict-env|--summarize-global-hash|--summary-path <PATH>|--task-order-file <FILE>|--validate-config|--verify-cache-outputs|--log-prefix <LOG_PREFIX>|--log-prefix-template <TEMPLATE>|--log-order <LOG_ORDER>|TASKS|PASS_THROUGH_ARGS>
   
   For more information, try '--help'.
   
@@ -9,7 +9,7 @@ Run a build to get a local cache.
   \xe2\x80\xa2 Remote caching disabled (esc)
   
    Tasks:    2 successful, 2 total
-  Cached:    0 cached, 2 total
+  Cached:    0 cached, 2 built, 0 failed, 2 total (0% cache hit)
     Time:\s+[.0-9]+m?s  (re)
   
 
@@ -80,6 +80,7 @@ Setup
       "package.json": "f2a5d2525f3996a57680180a7cd9ad7310e4dec0"
     },
     "framework": "<NO FRAMEWORK DETECTED>",
+    "dependencyHashes": {},
     "environmentVariables": {
       "configured": [],
       "inferred": [],
@@ -123,6 +124,7 @@ Setup
       "package.json": "8d3e121335e16dbd8d99c03522b892ec52416dda"
     },
     "framework": "<NO FRAMEWORK DETECTED>",
+    "dependencyHashes": {},
     "environmentVariables": {
       "configured": [
         "NODE_ENV=e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
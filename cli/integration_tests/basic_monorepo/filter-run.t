@@ -4,16 +4,7 @@ Setup
 
 # Running with --filter works and exits with success
   $ ${TURBO} run build --filter="[main]"
-  \xe2\x80\xa2 Packages in scope:  (esc)
-  \xe2\x80\xa2 Running build in 0 packages (esc)
-  \xe2\x80\xa2 Remote caching disabled (esc)
-  
-  No tasks were executed as part of this run.
-  
-   Tasks:    0 successful, 0 total
-  Cached:    0 cached, 0 total
-    Time:\s*[\.0-9]+m?s  (re)
-  
+  \xe2\x80\xa2 No tasks in scope after filtering (0 packages in scope), nothing to run (esc)
 
 # with unstaged changes
   $ echo "new file contents" >> bar.txt
@@ -25,6 +16,6 @@ Setup
   No tasks were executed as part of this run.
   
    Tasks:    0 successful, 0 total
-  Cached:    0 cached, 0 total
+  Cached:    0 cached, 0 built, 0 failed, 0 total
     Time:\s*[\.0-9]+m?s  (re)
   
\ No newline at end of file
@@ -12,7 +12,7 @@ Setup
   util:build: cache miss, executing 1a3651e1149bfaf7
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
 # run again and ensure there's a cache hit
@@ -23,7 +23,7 @@ Setup
   util:build: cache hit, suppressing output 1a3651e1149bfaf7
   
    Tasks:    1 successful, 1 total
-  Cached:    1 cached, 1 total
+  Cached:    1 cached, 0 built, 0 failed, 1 total (100% cache hit)
     Time:\s*[\.0-9]+m?s >>> FULL TURBO (re)
   
 # set global env var and ensure cache miss
@@ -34,7 +34,7 @@ Setup
   util:build: cache miss, executing 06c790ebbaad3942
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
 # set env var with "THASH" and ensure cache miss
@@ -45,7 +45,7 @@ Setup
   util:build: cache miss, executing 4fb146b1bab74cdf
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
 # set vercel analytics env var and ensure cache miss
@@ -56,6 +56,6 @@ Setup
   util:build: cache miss, executing 83aa7ca58a185ef4
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
\ No newline at end of file
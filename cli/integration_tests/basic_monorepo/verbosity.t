@@ -16,7 +16,7 @@ Verbosity level 1
   util:build: building
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
   $ ${TURBO} build --verbosity=1 --filter=util --force
@@ -32,7 +32,7 @@ Verbosity level 1
   util:build: building
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
 
@@ -64,7 +64,7 @@ Verbosity level 2
   [-0-9:.TWZ+]+ \[DEBUG] turbo.: done: status=complete duration=[\.0-9]+m?s (re)
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
   $ ${TURBO} build --verbosity=2 --filter=util --force
@@ -94,7 +94,7 @@ Verbosity level 2
   [-0-9:.TWZ+]+ \[DEBUG] turbo.: done: status=complete duration=[\.0-9]+m?s (re)
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
  
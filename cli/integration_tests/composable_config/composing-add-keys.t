@@ -28,7 +28,7 @@ Setup
   add-keys:add-keys-task: 
   
    Tasks:    2 successful, 2 total
-  Cached:    0 cached, 2 total
+  Cached:    0 cached, 2 built, 0 failed, 2 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
   $ HASH=$(cat tmp.log | grep -E "add-keys:add-keys-task.* executing .*" | awk '{print $5}')
@@ -52,7 +52,7 @@ Setup
   add-keys:add-keys-task: cache hit, suppressing output c3b35d8aaef32d5b
   
    Tasks:    2 successful, 2 total
-  Cached:    2 cached, 2 total
+  Cached:    2 cached, 0 built, 0 failed, 2 total (100% cache hit)
     Time:\s*[\.0-9]+m?s >>> FULL TURBO (re)
   
 # 3. Change input file and assert cache miss
@@ -74,7 +74,7 @@ Setup
   add-keys:add-keys-task: 
   
    Tasks:    2 successful, 2 total
-  Cached:    0 cached, 2 total
+  Cached:    0 cached, 2 built, 0 failed, 2 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
 # 4. Set env var and assert cache miss
@@ -95,6 +95,6 @@ Setup
   add-keys:add-keys-task: 
   
    Tasks:    2 successful, 2 total
-  Cached:    1 cached, 2 total
+  Cached:    1 cached, 1 built, 0 failed, 2 total (50% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
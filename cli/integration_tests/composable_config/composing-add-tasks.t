@@ -13,6 +13,6 @@ Setup
   add-tasks:added-task: 
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s+[.0-9]+m?s  (re)
   
\ No newline at end of file
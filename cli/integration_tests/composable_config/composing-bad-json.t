@@ -10,5 +10,7 @@ Setup
   $ ${TURBO} run trailing-comma --filter=bad-json > tmp.log 2>&1
   [1]
   $ cat tmp.log
-   ERROR  run failed: error preparing engine: turbo.json: invalid character '}' looking for beginning of object key string
-  Turbo error: error preparing engine: turbo.json: invalid character '}' looking for beginning of object key string
+   ERROR  run failed: error preparing engine: turbo.json: invalid configuration
+   - *apps/bad-json/turbo.json:1:35: invalid character ',' looking for beginning of value (glob)
+  Turbo error: error preparing engine: turbo.json: invalid configuration
+   - *apps/bad-json/turbo.json:1:35: invalid character ',' looking for beginning of value (glob)
@@ -21,7 +21,7 @@ This test covers:
   cached:cached-task-1: 
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s+[.0-9]+m?s  (re)
   
   $ HASH=$(cat tmp.log | grep -E "cached:cached-task-1.* executing .*" | awk '{print $5}')
@@ -46,7 +46,7 @@ This test covers:
   cached:cached-task-2: 
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s+[.0-9]+m?s  (re)
   
   $ HASH=$(cat tmp.log | grep -E "cached:cached-task-2.* executing .*" | awk '{print $6}')
@@ -68,7 +68,7 @@ no `cache` config in root, cache:false in workspace
   cached:cached-task-3: 
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s+[.0-9]+m?s  (re)
   
   $ HASH=$(cat tmp.log | grep -E "cached:cached-task-3.* executing .*" | awk '{print $6}')
@@ -92,7 +92,7 @@ we already have a workspace that doesn't have a config
   missing-workspace-config:cached-task-4: 
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s+[.0-9]+m?s  (re)
   
   $ HASH=$(cat tmp.log | grep -E "missing-workspace-config:cached-task-4.* executing .*" | awk '{print $6}')
@@ -19,7 +19,7 @@ Setup
   missing-workspace-config:missing-workspace-config-task: 
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
   $ HASH=$(cat tmp.log | grep -E "missing-workspace-config:missing-workspace-config-task.* executing .*" | awk '{print $5}')
@@ -37,7 +37,7 @@ Setup
   missing-workspace-config:missing-workspace-config-task: cache hit, suppressing output 05c61aea3d614094
   
    Tasks:    1 successful, 1 total
-  Cached:    1 cached, 1 total
+  Cached:    1 cached, 0 built, 0 failed, 1 total (100% cache hit)
     Time:\s*[\.0-9]+m?s >>> FULL TURBO (re)
   
 3. Change input file and assert cache miss, and not FULL TURBO
@@ -53,7 +53,7 @@ Setup
   missing-workspace-config:missing-workspace-config-task: 
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
 
@@ -66,7 +66,7 @@ Setup
   missing-workspace-config:missing-workspace-config-task: cache hit, suppressing output 95c3172b0e76df0c
   
    Tasks:    1 successful, 1 total
-  Cached:    1 cached, 1 total
+  Cached:    1 cached, 0 built, 0 failed, 1 total (100% cache hit)
     Time:\s*[\.0-9]+m?s >>> FULL TURBO (re)
   
 4. Set env var and assert cache miss, and that hash is different from above
@@ -81,7 +81,7 @@ Setup
   missing-workspace-config:missing-workspace-config-task: 
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
 5. Assert that task with cache:false doesn't get cached
@@ -97,7 +97,7 @@ Setup
   missing-workspace-config:cached-task-4: 
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
   $ HASH=$(cat tmp.log | grep -E "missing-workspace-config:cached-task-4.* executing .*" | awk '{print $6}')
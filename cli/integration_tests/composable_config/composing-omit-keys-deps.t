@@ -40,7 +40,7 @@ Setup
 
   $ cat tmp.log | grep "Tasks:" -A 2
    Tasks:    3 successful, 3 total
-  Cached:    0 cached, 3 total
+  Cached:    0 cached, 3 built, 0 failed, 3 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
 
   $ HASH=$(cat tmp.log | grep -E "omit-keys:omit-keys-task-with-deps.* executing .*" | awk '{print $5}')
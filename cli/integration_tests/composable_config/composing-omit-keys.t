@@ -22,7 +22,7 @@ Setup
   omit-keys:omit-keys-task: 
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
   $ HASH=$(cat tmp.log | grep -E "omit-keys:omit-keys-task.* executing .*" | awk '{print $5}')
@@ -40,7 +40,7 @@ Setup
   omit-keys:omit-keys-task: cache hit, suppressing output 2fe73e4bc4e0f517
   
    Tasks:    1 successful, 1 total
-  Cached:    1 cached, 1 total
+  Cached:    1 cached, 0 built, 0 failed, 1 total (100% cache hit)
     Time:\s*[\.0-9]+m?s >>> FULL TURBO (re)
   
 3. Change input file and assert cache miss, and not FULL TURBO
@@ -56,7 +56,7 @@ Setup
   omit-keys:omit-keys-task: 
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
 
@@ -69,7 +69,7 @@ Setup
   omit-keys:omit-keys-task: cache hit, suppressing output 5039bd112be78dca
   
    Tasks:    1 successful, 1 total
-  Cached:    1 cached, 1 total
+  Cached:    1 cached, 0 built, 0 failed, 1 total (100% cache hit)
     Time:\s*[\.0-9]+m?s >>> FULL TURBO (re)
   
 4. Set env var and assert cache miss, and that hash is different from above
@@ -84,6 +84,6 @@ Setup
   omit-keys:omit-keys-task: 
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
@@ -19,7 +19,7 @@ Setup
   override-values:override-values-task: 
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
   $ HASH=$(cat tmp.log | grep -E "override-values:override-values-task.* executing .*" | awk '{print $5}')
@@ -41,7 +41,7 @@ Setup
   override-values:override-values-task: 
   
    Tasks:    1 successful, 1 total
-  Cached:    1 cached, 1 total
+  Cached:    1 cached, 0 built, 0 failed, 1 total (100% cache hit)
     Time:\s*[\.0-9]+m?s >>> FULL TURBO (re)
   
 3. Change input file and assert cache miss
@@ -57,7 +57,7 @@ Setup
   override-values:override-values-task: 
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
 3a. Change a file that is declared as input in root config, and assert cache hit and FULL TURBO
@@ -73,7 +73,7 @@ Setup
   override-values:override-values-task: 
   
    Tasks:    1 successful, 1 total
-  Cached:    1 cached, 1 total
+  Cached:    1 cached, 0 built, 0 failed, 1 total (100% cache hit)
     Time:\s*[\.0-9]+m?s >>> FULL TURBO (re)
   
 4. Set env var and assert cache miss, and that hash is different from above
@@ -88,7 +88,7 @@ Setup
   override-values:override-values-task: 
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
 4a. Set env var that is declared in root config, and assert cache hit and FULL TURBO
@@ -103,6 +103,6 @@ Setup
   override-values:override-values-task: 
   
    Tasks:    1 successful, 1 total
-  Cached:    1 cached, 1 total
+  Cached:    1 cached, 0 built, 0 failed, 1 total (100% cache hit)
     Time:\s*[\.0-9]+m?s >>> FULL TURBO (re)
   
@@ -37,7 +37,7 @@ This test covers:
   persistent:persistent-task-2-parent: persistent-task-2-parent
   
    Tasks:    2 successful, 2 total
-  Cached:    0 cached, 2 total
+  Cached:    0 cached, 2 built, 0 failed, 2 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
 # persistent-task-3-parent dependsOn persistent-task-3
@@ -13,16 +13,7 @@ Setup
   [-0-9:.TWZ+]+ \[DEBUG] turbo: global hash env vars: vars=\["VERCEL_ANALYTICS_ID"] (re)
   [-0-9:.TWZ+]+ \[DEBUG] turbo: global hash: value=c1fb8f74a026cdb8 (re)
   [-0-9:.TWZ+]+ \[DEBUG] turbo: local cache folder: path="" (re)
-  \xe2\x80\xa2 Packages in scope:  (esc)
-  \xe2\x80\xa2 Running build in 0 packages (esc)
-  \xe2\x80\xa2 Remote caching disabled (esc)
-  
-  No tasks were executed as part of this run.
-  
-   Tasks:    0 successful, 0 total
-  Cached:    0 cached, 0 total
-    Time:\s*[\.0-9]+m?s  (re)
-  
+  \xe2\x80\xa2 No tasks in scope after filtering (0 packages in scope), nothing to run (esc)
   $ cd $TARGET_DIR/apps/web && ${TURBO} run build --filter=nothing -vv
   [-0-9:.TWZ+]+ \[DEBUG] turborepo_lib::shim: Global turbo version: .* (re)
   [-0-9:.TWZ+]+ \[DEBUG] turborepo_lib::shim: No local turbo binary found at: .+node_modules/\.bin/turbo (re)
@@ -35,16 +26,7 @@ Setup
   [-0-9:.TWZ+]+ \[DEBUG] turbo: global hash env vars: vars=\["VERCEL_ANALYTICS_ID"] (re)
   [-0-9:.TWZ+]+ \[DEBUG] turbo: global hash: value=c1fb8f74a026cdb8 (re)
   [-0-9:.TWZ+]+ \[DEBUG] turbo: local cache folder: path="" (re)
-  \xe2\x80\xa2 Packages in scope:  (esc)
-  \xe2\x80\xa2 Running build in 0 packages (esc)
-  \xe2\x80\xa2 Remote caching disabled (esc)
-  
-  No tasks were executed as part of this run.
-  
-   Tasks:    0 successful, 0 total
-  Cached:    0 cached, 0 total
-    Time:\s*[\.0-9]+m?s  (re)
-  
+  \xe2\x80\xa2 No tasks in scope after filtering (0 packages in scope), nothing to run (esc)
   $ cd $TARGET_DIR/crates && ${TURBO} run build --filter=nothing -vv
   [-0-9:.TWZ+]+ \[DEBUG] turborepo_lib::shim: Global turbo version: .* (re)
   [-0-9:.TWZ+]+ \[DEBUG] turborepo_lib::shim: No local turbo binary found at: .+node_modules/\.bin/turbo (re)
@@ -57,16 +39,7 @@ Setup
   [-0-9:.TWZ+]+ \[DEBUG] turbo: global hash env vars: vars=\["VERCEL_ANALYTICS_ID"] (re)
   [-0-9:.TWZ+]+ \[DEBUG] turbo: global hash: value=c1fb8f74a026cdb8 (re)
   [-0-9:.TWZ+]+ \[DEBUG] turbo: local cache folder: path="" (re)
-  \xe2\x80\xa2 Packages in scope:  (esc)
-  \xe2\x80\xa2 Running build in 0 packages (esc)
-  \xe2\x80\xa2 Remote caching disabled (esc)
-  
-  No tasks were executed as part of this run.
-  
-   Tasks:    0 successful, 0 total
-  Cached:    0 cached, 0 total
-    Time:\s*[\.0-9]+m?s  (re)
-  
+  \xe2\x80\xa2 No tasks in scope after filtering (0 packages in scope), nothing to run (esc)
   $ cd $TARGET_DIR/crates/super-crate/tests/test-package && ${TURBO} run build --filter=nothing -vv
   [-0-9:.TWZ+]+ \[DEBUG] turborepo_lib::shim: Global turbo version: .* (re)
   [-0-9:.TWZ+]+ \[DEBUG] turborepo_lib::shim: No local turbo binary found at: .+node_modules/\.bin/turbo (re)
@@ -79,16 +52,7 @@ Setup
   [-0-9:.TWZ+]+ \[DEBUG] turbo: global hash env vars: vars=\["VERCEL_ANALYTICS_ID"] (re)
   [-0-9:.TWZ+]+ \[DEBUG] turbo: global hash: value=c1fb8f74a026cdb8 (re)
   [-0-9:.TWZ+]+ \[DEBUG] turbo: local cache folder: path="" (re)
-  \xe2\x80\xa2 Packages in scope:  (esc)
-  \xe2\x80\xa2 Running build in 0 packages (esc)
-  \xe2\x80\xa2 Remote caching disabled (esc)
-  
-  No tasks were executed as part of this run.
-  
-   Tasks:    0 successful, 0 total
-  Cached:    0 cached, 0 total
-    Time:\s*[\.0-9]+m?s  (re)
-  
+  \xe2\x80\xa2 No tasks in scope after filtering (0 packages in scope), nothing to run (esc)
   $ cd $TARGET_DIR/packages/ui-library/src && ${TURBO} run build --filter=nothing -vv
   [-0-9:.TWZ+]+ \[DEBUG] turborepo_lib::shim: Global turbo version: .* (re)
   [-0-9:.TWZ+]+ \[DEBUG] turborepo_lib::shim: No local turbo binary found at: .+node_modules/\.bin/turbo (re)
@@ -101,13 +65,5 @@ Setup
   [-0-9:.TWZ+]+ \[DEBUG] turbo: global hash env vars: vars=\["VERCEL_ANALYTICS_ID"] (re)
   [-0-9:.TWZ+]+ \[DEBUG] turbo: global hash: value=c1fb8f74a026cdb8 (re)
   [-0-9:.TWZ+]+ \[DEBUG] turbo: local cache folder: path="" (re)
-  \xe2\x80\xa2 Packages in scope:  (esc)
-  \xe2\x80\xa2 Running build in 0 packages (esc)
-  \xe2\x80\xa2 Remote caching disabled (esc)
-  
-  No tasks were executed as part of this run.
-  
-   Tasks:    0 successful, 0 total
-  Cached:    0 cached, 0 total
-    Time:\s*[\.0-9]+m?s  (re)
+  \xe2\x80\xa2 No tasks in scope after filtering (0 packages in scope), nothing to run (esc)
   
\ No newline at end of file
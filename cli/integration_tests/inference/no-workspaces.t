@@ -19,7 +19,7 @@ Setup
   No tasks were executed as part of this run.
   
    Tasks:    0 successful, 0 total
-  Cached:    0 cached, 0 total
+  Cached:    0 cached, 0 built, 0 failed, 0 total
     Time:\s*[\.0-9]+m?s  (re)
   
   $ cd $TARGET_DIR/parent && ${TURBO} run build --filter=nothing -vv
@@ -39,7 +39,7 @@ Setup
   No tasks were executed as part of this run.
   
    Tasks:    0 successful, 0 total
-  Cached:    0 cached, 0 total
+  Cached:    0 cached, 0 built, 0 failed, 0 total
     Time:\s*[\.0-9]+m?s  (re)
   
   $ cd $TARGET_DIR/parent/child && ${TURBO} run build --filter=nothing -vv
@@ -59,6 +59,6 @@ Setup
   No tasks were executed as part of this run.
   
    Tasks:    0 successful, 0 total
-  Cached:    0 cached, 0 total
+  Cached:    0 cached, 0 built, 0 failed, 0 total
     Time:\s*[\.0-9]+m?s  (re)
   
\ No newline at end of file
@@ -15,7 +15,7 @@ Running build for my-app succeeds
   my-app:build: building
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
 Update exluded file and try again
@@ -32,6 +32,6 @@ Update exluded file and try again
   my-app:build: building
   
    Tasks:    1 successful, 1 total
-  Cached:    1 cached, 1 total
+  Cached:    1 cached, 0 built, 0 failed, 1 total (100% cache hit)
     Time:\s*[\.0-9]+m?s >>> FULL TURBO (re)
   
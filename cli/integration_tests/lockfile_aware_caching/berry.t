@@ -11,7 +11,7 @@ Populate cache
   a:build: building
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
   $ ${TURBO} build --filter=b
@@ -22,7 +22,7 @@ Populate cache
   b:build: building
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
 
@@ -38,7 +38,7 @@ Only b should have a cache miss
   a:build: building
   
    Tasks:    1 successful, 1 total
-  Cached:    1 cached, 1 total
+  Cached:    1 cached, 0 built, 0 failed, 1 total (100% cache hit)
     Time:\s*[\.0-9]+m?s >>> FULL TURBO (re)
   
 
@@ -50,7 +50,7 @@ Only b should have a cache miss
   b:build: building
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
 Add lockfile changes to a commit
@@ -73,7 +73,7 @@ Bump of root workspace invalidates all packages
   a:build: building
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
   $ ${TURBO} build  --filter=b
@@ -84,7 +84,7 @@ Bump of root workspace invalidates all packages
   b:build: building
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
 Add lockfile changes to a commit
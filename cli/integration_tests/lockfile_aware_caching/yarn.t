@@ -15,7 +15,7 @@ Populate cache
   a:build: Done in [\.0-9]+m?s\. (re)
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
   $ ${TURBO} build --filter=b
@@ -30,7 +30,7 @@ Populate cache
   b:build: Done in [\.0-9]+m?s\. (re)
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
 
@@ -50,7 +50,7 @@ Only b should have a cache miss
   a:build: Done in [\.0-9]+m?s\. (re)
   
    Tasks:    1 successful, 1 total
-  Cached:    1 cached, 1 total
+  Cached:    1 cached, 0 built, 0 failed, 1 total (100% cache hit)
     Time:\s*[\.0-9]+m?s >>> FULL TURBO (re)
   
 
@@ -66,7 +66,7 @@ Only b should have a cache miss
   b:build: Done in [\.0-9]+m?s\. (re)
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
 Add lockfile changes to a commit
@@ -93,7 +93,7 @@ Bump of root workspace invalidates all packages
   a:build: Done in [\.0-9]+m?s\. (re)
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
   $ ${TURBO} build  --filter=b
@@ -108,7 +108,7 @@ Bump of root workspace invalidates all packages
   b:build: Done in [\.0-9]+m?s\. (re)
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
 Add lockfile changes to a commit
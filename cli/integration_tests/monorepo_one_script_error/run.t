@@ -28,7 +28,7 @@ Note that npm reports any failed script as exit code 1, even though we "exit 2"
   command \(.*/run\.t(-\d+)?/apps/my-app\) npm run error exited \(1\) (re)
   
    Tasks:    1 successful, 2 total
-  Cached:    0 cached, 2 total
+  Cached:    0 cached, 1 built, 1 failed, 2 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
    ERROR  run failed: command  exited (1)
@@ -59,7 +59,7 @@ Make sure error isn't cached
   command \(.*/run\.t(-\d+)?/apps/my-app\) npm run error exited \(1\) (re)
   
    Tasks:    1 successful, 2 total
-  Cached:    1 cached, 2 total
+  Cached:    1 cached, 0 built, 1 failed, 2 total (50% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
    ERROR  run failed: command  exited (1)
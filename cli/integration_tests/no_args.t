@@ -9,14 +9,18 @@ Make sure exit code is 2 when no args are passed
   
   Commands:
     bin         Get the path to the Turbo binary
+    cache       Inspect and repair the local and remote caches
     completion  Generate the autocompletion script for the specified shell
     daemon      Runs the Turborepo background daemon
+    diff        Compare two run summaries, reporting the tasks whose hashes or cache states changed and why
     link        Link your local directory to a Vercel organization and enable remote caching
     login       Login to your Vercel account
     logout      Logout to your Vercel account
     prune       Prepare a subset of your monorepo
     run         Run tasks across projects in your monorepo
+    stats       Report the slowest tasks and their cache hit rates across recent runs
     unlink      Unlink the current directory from your Vercel organization and disable Remote Caching
+    watch       Run tasks, then re-run the tasks whose inputs change as files are edited
   
   Options:
         --version                         
@@ -38,28 +42,73 @@ Make sure exit code is 2 when no args are passed
     -h, --help                            Print help
   
   Run Arguments:
-        --cache-dir <CACHE_DIR>          Override the filesystem cache directory
-        --cache-workers <CACHE_WORKERS>  Set the number of concurrent cache operations (default 10) [default: 10]
-        --concurrency <CONCURRENCY>      Limit the concurrency of task execution. Use 1 for serial (i.e. one-at-a-time) execution
-        --continue                       Continue execution even if a task exits with an error or non-zero exit code. The default behavior is to bail
-        --dry-run [<DRY_RUN>]            [possible values: text, json]
-        --single-package                 Run turbo in single-package mode
-        --filter <FILTER>                Use the given selector to specify package(s) to act as entry points. The syntax mirrors pnpm's syntax, and additional documentation and examples can be found in turbo's documentation https://turbo.build/repo/docs/reference/command-line-reference#--filter
-        --force                          Ignore the existing cache (to force execution)
-        --global-deps <GLOBAL_DEPS>      Specify glob of global filesystem dependencies to be hashed. Useful for .env and files
-        --graph [<GRAPH>]                Generate a graph of the task execution and output to a file when a filename is specified (.svg, .png, .jpg, .pdf, .json, .html). Outputs dot graph to stdout when if no filename is provided
-        --ignore <IGNORE>                Files to ignore when calculating changed files (i.e. --since). Supports globs
-        --include-dependencies           Include the dependencies of tasks in execution
-        --no-cache                       Avoid saving task results to the cache. Useful for development/watch tasks
-        --no-daemon                      Run without using turbo's daemon process
-        --no-deps                        Exclude dependent task consumers from execution
-        --output-logs <OUTPUT_LOGS>      Set type of process output logging. Use "full" to show all output. Use "hash-only" to show only turbo-computed task hashes. Use "new-only" to show only new output with only hashes for cached tasks. Use "none" to hide process output. (default full) [possible values: full, none, hash-only, new-only, errors-only]
-        --parallel                       Execute all tasks in parallel
-        --profile <PROFILE>              File to write turbo's performance profile output into. You can load the file up in chrome://tracing to see which parts of your build were slow
-        --remote-only                    Ignore the local filesystem cache for all tasks. Only allow reading and caching artifacts using the remote cache
-        --scope <SCOPE>                  Specify package(s) to act as entry points for task execution. Supports globs
-        --since <SINCE>                  Limit/Set scope to changed packages since a mergebase. This uses the git diff ${target_branch}... mechanism to identify which packages have changed
-        --log-prefix <LOG_PREFIX>        Use "none" to remove prefixes from task logs. Note that tasks running in parallel interleave their logs and prefix is the only way to identify which task produced a log [possible values: none]
+        --cache-dir <CACHE_DIR>             Override the filesystem cache directory
+        --cache-key-salt <SALT>             Fold a salt into the global hash, so that a new salt invalidates every cache. Can also be set with TURBO_CACHE_KEY_SALT
+        --cache-workers <CACHE_WORKERS>     Set the number of concurrent cache operations (default 10) [default: 10]
+        --cache-upload-concurrency <COUNT>  Limit the number of artifacts uploaded to the remote cache at once. Uploads proceed in the background once outputs are cached locally
+        --cache-backend <URL>               Use the cache backend at the given URL in place of the Vercel Remote Cache. The URL scheme selects the backend
+        --cache-signing <CACHE_SIGNING>     Set whether artifacts must be signed with TURBO_CACHE_SIGNING_KEY to be restored. Use "optional" to restore unsigned artifacts, and "required" to treat them as cache misses. (default optional) [possible values: optional, required]
+        --max-cache-size <SIZE>             Keep the local cache under a size such as 2GB, evicting the least recently used artifacts
+        --check-reproducible                Run each task twice and compare the files produced by both executions to detect tasks whose outputs are not reproducible
+        --affected-output <FILE>            Hash the tasks without running them, and write the packages that have at least one task that would not be restored from the cache to the given file, one per line
+        --audit-io                          Run each task under a syscall tracer (strace on Linux, dtrace on macOS) and report files it reads or writes that are not declared as inputs or outputs. Tasks run significantly slower
+        --completion-webhook <URL>          POST the JSON summary of the run to the given URL when the run ends. The body is signed with TURBO_WEBHOOK_SECRET, if it is set
+        --compress-logs                     Store task logs gzipped, as .log.gz files. Logs are decompressed when they are replayed
+        --concurrency <CONCURRENCY>         Limit the concurrency of task execution. Use 1 for serial (i.e. one-at-a-time) execution
+        --concurrency-group <GROUP=LIMIT>   Limit how many tasks of a "concurrencyGroup" run at once, as <group>=<limit>. Can be passed multiple times
+        --continue                          Continue execution even if a task exits with an error or non-zero exit code. The default behavior is to bail
+        --defer-cache-writes                Only write task outputs to the cache once every task in the run has succeeded. If any task fails, nothing is written to the cache
+        --dry-run [<DRY_RUN>]               [possible values: text, json]
+        --dump-inputs <DIR>                 Write the files that went into each task's hash, along with their hashes, into <DIR>/<package>/<task>.json
+        --env-mode <ENV_MODE>               Use "strict" to run tasks with only the environment variables that are part of their hash or their passThroughEnv, along with PATH. Use "loose" to pass every environment variable. (default loose) [possible values: loose, strict]
+        --error-on-empty                    Exit with an error when no tasks are in scope after filtering, instead of successfully running nothing
+        --errors <ERRORS>                   Set the format of the errors printed at the end of the run. Use "json" to print each error as a JSON object with its task, package, exit code, log file and category. (default text) [possible values: text, json]
+        --events-socket <PATH>              Write a line of JSON to the Unix socket at this path when each task starts, hits the cache, completes or fails
+        --single-package                    Run turbo in single-package mode
+        --filter <FILTER>                   Use the given selector to specify package(s) to act as entry points. The syntax mirrors pnpm's syntax, and additional documentation and examples can be found in turbo's documentation https://turbo.build/repo/docs/reference/command-line-reference#--filter
+        --force                             Ignore the existing cache (to force execution)
+        --github-annotations[=<BOOL>]       Group the output of each task and annotate failed tasks using GitHub Actions workflow commands. Enabled when GITHUB_ACTIONS is true, unless --log-order is given. Use --github-annotations=false to disable
+        --global-deps <GLOBAL_DEPS>         Specify glob of global filesystem dependencies to be hashed. Useful for .env and files
+        --graph [<GRAPH>]                   Generate a graph of the task execution and output to a file when a filename is specified (.svg, .png, .jpg, .pdf, .json, .html). Outputs dot graph to stdout when if no filename is provided
+        --ignore <IGNORE>                   Files to ignore when calculating changed files (i.e. --since). Supports globs
+        --include-dependencies              Include the dependencies of tasks in execution
+        --isolate-outputs                   Capture task logs and stage cache artifacts in a workspace unique to this run, so that concurrent runs in the same repository don't interfere with each other
+        --kill-timeout <DURATION>           How long tasks are given to exit after being sent SIGTERM when the run is stopped, before they are killed (default 10s)
+        --log-file-name <TEMPLATE>          The name of the log files written into --stream-logs-to. Supports {package}, {task} and {hash} (default "{package}/{task}.log")
+        --max-log-bytes-per-task <SIZE>     Truncate the output of each task after a size such as 10MB, leaving the rest of its output out of the terminal and its log file
+        --no-cache                          Avoid saving task results to the cache. Useful for development/watch tasks
+        --no-daemon                         Run without using turbo's daemon process
+        --no-deps                           Exclude dependent task consumers from execution
+        --no-lockfile-cache                 Parse the lockfile instead of reusing the result of parsing it on a previous run
+        --no-op [<DURATION>]                Walk the task graph without running any commands, simulating each task for the given duration (e.g. 500ms) when one is provided
+        --no-preamble                       Don't print the packages in scope, the tasks being run and whether remote caching is enabled before running tasks
+        --no-workspace-cache                Discover workspaces instead of reusing the workspaces found on a previous run
+        --offline                           Disable remote caching and fail rather than make network requests, for builds without network access
+        --output-logs <OUTPUT_LOGS>         Set type of process output logging. Use "full" to show all output. Use "hash-only" to show only turbo-computed task hashes. Use "new-only" to show only new output with only hashes for cached tasks. Use "none" to hide process output. (default full) [possible values: full, none, hash-only, new-only, errors-only]
+        --output <OUTPUT>                   Stream machine-readable output to stdout while the run progresses. Use "ndjson" to write a JSON object for each task, one per line, as soon as it finishes [possible values: ndjson]
+        --parallel                          Execute all tasks in parallel
+        --prefetch                          Calculate the hash of every task before running any, and download their remote cache artifacts in the background while tasks run
+        --profile <PROFILE>                 File to write turbo's performance profile output into. You can load the file up in chrome://tracing to see which parts of your build were slow
+        --remote-cache-read-only            Restore artifacts from the remote cache without uploading the outputs of executed tasks to it. The local cache is still written
+        --remote-only                       Ignore the local filesystem cache for all tasks. Only allow reading and caching artifacts using the remote cache
+        --rerun-dependents-of <TASK>        Execute every task that depends on the given task, even if it is cached. Use a task name to match it in every package, or <package>#<task>
+        --require-remote-cache              Fail before running any task if the remote cache is disabled, read-only or doesn't accept artifacts
+        --run-timeout <DURATION>            Stop the whole run once it has run for a duration such as 30m, stopping the tasks that are still running as if it was interrupted
+        --scope <SCOPE>                     Specify package(s) to act as entry points for task execution. Supports globs
+        --sequential-prefix-colors          Color the prefixes of task logs in the order packages first log, rather than from a hash of the package name that is stable across runs
+        --serial-within-package             Run at most one task of each package at a time, for packages whose tasks contend for the same resources when they run concurrently
+        --since <SINCE>                     Limit/Set scope to changed packages since a mergebase. This uses the git diff ${target_branch}... mechanism to identify which packages have changed
+        --stream-logs-to <DIR>              Write the output of each task into <DIR>/<package>/<task>.log as the task runs, in addition to the cached log file
+        --strict                            Fail the run, instead of warning, when the configuration can lead to inconsistent caching, such as outputs that are tracked by git
+        --strict-env                        Fail the run, instead of warning, when the script of a task uses environment variables that the task doesn't declare
+        --summarize-global-hash             Print the inputs of the global hash as JSON, sorted so that the output of two runs can be diffed, instead of running tasks
+        --summary-path <PATH>               Write the JSON summary of the run, the same as --dry=json reports plus the outcome of each task, to the given file when the run ends
+        --task-order-file <FILE>            Start the tasks listed in the given file, one task ID per line, as early as their dependencies allow, ahead of other tasks ready to run
+        --validate-config                   Check the turbo.json of the root and of every workspace for unknown keys, values of the wrong type and invalid globs, then exit without running tasks
+        --verify-cache-outputs              Check the outputs restored from the local cache against the hashes recorded in the cache manifest, and execute the task on a mismatch
+        --log-prefix <LOG_PREFIX>           Use "none" to remove prefixes from task logs. Note that tasks running in parallel interleave their logs and prefix is the only way to identify which task produced a log [possible values: none]
+        --log-prefix-template <TEMPLATE>    Set the prefix of task logs. {package}, {task}, {taskId} and {hash} are replaced with the task's values, and {hash:8} with the first 8 characters of its hash. (default "{package}:{task}")
+        --log-order <LOG_ORDER>             Set the order of task logs. Use "stream" to show output as tasks print it, interleaving the logs of concurrent tasks. Use "grouped" to show the output of each task at once when it finishes. (default stream) [possible values: stream, grouped]
   [1]
   $ ${TURBO} run
   Turbo error: at least one task must be specified
@@ -21,6 +21,6 @@ Make sure that the internal util package is part of the prune output
   docs:new-task: running new task
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
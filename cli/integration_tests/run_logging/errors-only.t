@@ -11,7 +11,7 @@ Setup
   \xe2\x80\xa2 Remote caching disabled (esc)
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
 
@@ -24,7 +24,7 @@ Setup
   \xe2\x80\xa2 Remote caching disabled (esc)
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
 # [x] error exit
@@ -47,7 +47,7 @@ Setup
   command .* npm run builderror exited \(1\) (re)
   
    Tasks:    0 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 0 built, 1 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
    ERROR  run failed: command  exited (1)
@@ -73,7 +73,7 @@ Setup
   command .* npm run builderror2 exited \(1\) (re)
   
    Tasks:    0 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 0 built, 1 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
    ERROR  run failed: command  exited (1)
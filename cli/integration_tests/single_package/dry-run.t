@@ -65,6 +65,7 @@ Check
           "turbo.json": "2b9b71e8eca61cda6f4c14e07067feac9c1f9862"
         },
         "framework": "\u003cNO FRAMEWORK DETECTED\u003e",
+        "dependencyHashes": {},
         "environmentVariables": {
           "configured": [],
           "inferred": [],
@@ -83,6 +83,7 @@ Check
           "turbo.json": "e1fe3e5402fe019ef3845cc63a736878a68934c7"
         },
         "framework": "\u003cNO FRAMEWORK DETECTED\u003e",
+        "dependencyHashes": {},
         "environmentVariables": {
           "configured": [],
           "inferred": [],
@@ -124,6 +125,9 @@ Check
           "turbo.json": "e1fe3e5402fe019ef3845cc63a736878a68934c7"
         },
         "framework": "\u003cNO FRAMEWORK DETECTED\u003e",
+        "dependencyHashes": {
+          "build": "8fc80cfff3b64237"
+        },
         "environmentVariables": {
           "configured": [],
           "inferred": [],
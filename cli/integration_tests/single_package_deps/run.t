@@ -18,7 +18,7 @@ Check
   test: 
   
    Tasks:    2 successful, 2 total
-  Cached:    0 cached, 2 total
+  Cached:    0 cached, 2 built, 0 failed, 2 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
 Run a second time, verify caching works because there is a config
@@ -37,7 +37,7 @@ Run a second time, verify caching works because there is a config
   test: 
   
    Tasks:    2 successful, 2 total
-  Cached:    2 cached, 2 total
+  Cached:    2 cached, 0 built, 0 failed, 2 total (100% cache hit)
     Time:\s*[\.0-9]+m?s >>> FULL TURBO (re)
   
 Run with --output-logs=hash-only
@@ -48,7 +48,7 @@ Run with --output-logs=hash-only
   test: cache hit, suppressing output c71366ccd6a86465
   
    Tasks:    2 successful, 2 total
-  Cached:    2 cached, 2 total
+  Cached:    2 cached, 0 built, 0 failed, 2 total (100% cache hit)
     Time:\s*[\.0-9]+m?s >>> FULL TURBO (re)
   
 Run with --output-logs=errors-only
@@ -57,7 +57,7 @@ Run with --output-logs=errors-only
   \xe2\x80\xa2 Remote caching disabled (esc)
   
    Tasks:    2 successful, 2 total
-  Cached:    2 cached, 2 total
+  Cached:    2 cached, 0 built, 0 failed, 2 total (100% cache hit)
     Time:\s*[\.0-9]+m?s >>> FULL TURBO (re)
   
 Run with --output-logs=none
@@ -66,6 +66,6 @@ Run with --output-logs=none
   \xe2\x80\xa2 Remote caching disabled (esc)
   
    Tasks:    2 successful, 2 total
-  Cached:    2 cached, 2 total
+  Cached:    2 cached, 0 built, 0 failed, 2 total (100% cache hit)
     Time:\s*[\.0-9]+m?s >>> FULL TURBO (re)
   
@@ -19,7 +19,7 @@ Check
     Cached (Remote)                  = false                                                                                                   
     Command                          = echo 'building'                                                                                         
     Outputs                          =                                                                                                         
-    Log File                         = .turbo/turbo-build.log                                                                                  
+    Log File                         =                                                                                                         
     Dependencies                     =                                                                                                         
     Dependendents                    =                                                                                                         
     Inputs Files Considered          = 3                                                                                                       
@@ -42,7 +42,7 @@ Check
         "command": "echo 'building'",
         "outputs": null,
         "excludedOutputs": null,
-        "logFile": ".turbo/turbo-build.log",
+        "logFile": "",
         "dependencies": [],
         "dependents": [],
         "resolvedTaskDefinition": {
@@ -60,6 +60,7 @@ Check
           "package.json": "581fe2b8dcba5b03cbe51d78a973143eb6d33e3a"
         },
         "framework": "\u003cNO FRAMEWORK DETECTED\u003e",
+        "dependencyHashes": {},
         "environmentVariables": {
           "configured": [],
           "inferred": [],
@@ -14,7 +14,7 @@ Check
   build: building
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
 Run a second time, verify no caching because there is no config
@@ -29,6 +29,6 @@ Run a second time, verify no caching because there is no config
   build: building
   
    Tasks:    1 successful, 1 total
-  Cached:    0 cached, 1 total
+  Cached:    0 cached, 1 built, 0 failed, 1 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
\ No newline at end of file
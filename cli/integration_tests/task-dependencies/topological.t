@@ -21,7 +21,7 @@ Check my-app#build output
   my-app:build: building
   
    Tasks:    2 successful, 2 total
-  Cached:    0 cached, 2 total
+  Cached:    0 cached, 2 built, 0 failed, 2 total (0% cache hit)
     Time:\s*[\.0-9]+m?s  (re)
   
 
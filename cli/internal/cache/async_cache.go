@@ -4,9 +4,13 @@
 package cache
 
 import (
+	"errors"
+	"log"
 	"sync"
 
+	"github.com/vercel/turbo/cli/internal/cacheitem"
 	"github.com/vercel/turbo/cli/internal/turbopath"
+	"github.com/vercel/turbo/cli/internal/util"
 )
 
 // An asyncCache is a wrapper around a Cache interface that handles incoming
@@ -14,10 +18,15 @@ import (
 // The requests are handled on an internal queue, if that fills up then
 // incoming requests will start to block again until it empties.
 // Retrieval requests are still handled synchronously.
+// Once a request fails because the real cache has been disabled, that error is
+// returned to the callers of Put and Fetch, so that a cacheMultiplexer can remove it.
 type asyncCache struct {
 	requests  chan cacheRequest
 	realCache Cache
 	wg        sync.WaitGroup
+
+	mu       sync.Mutex
+	disabled *util.CacheDisabledError
 }
 
 // A cacheRequest models an incoming cache request on our queue.
@@ -28,19 +37,22 @@ type cacheRequest struct {
 	files    []turbopath.AnchoredSystemPath
 }
 
-func newAsyncCache(realCache Cache, opts Opts) Cache {
+func newAsyncCache(realCache Cache, workers int) Cache {
 	c := &asyncCache{
 		requests:  make(chan cacheRequest),
 		realCache: realCache,
 	}
-	c.wg.Add(opts.Workers)
-	for i := 0; i < opts.Workers; i++ {
+	c.wg.Add(workers)
+	for i := 0; i < workers; i++ {
 		go c.run()
 	}
 	return c
 }
 
 func (c *asyncCache) Put(anchor turbopath.AbsoluteSystemPath, key string, duration int, files []turbopath.AnchoredSystemPath) error {
+	if err := c.disabledErr(); err != nil {
+		return err
+	}
 	c.requests <- cacheRequest{
 		anchor:   anchor,
 		key:      key,
@@ -51,9 +63,27 @@ func (c *asyncCache) Put(anchor turbopath.AbsoluteSystemPath, key string, durati
 }
 
 func (c *asyncCache) Fetch(anchor turbopath.AbsoluteSystemPath, key string, files []string) (bool, []turbopath.AnchoredSystemPath, int, error) {
+	if err := c.disabledErr(); err != nil {
+		return false, nil, 0, err
+	}
 	return c.realCache.Fetch(anchor, key, files)
 }
 
+func (c *asyncCache) ReadManifest(key string) (*cacheitem.Manifest, error) {
+	if reader, ok := c.realCache.(ManifestReader); ok {
+		return reader.ReadManifest(key)
+	}
+	return nil, nil
+}
+
+func (c *asyncCache) CheckRemote() error {
+	return CheckRemote(c.realCache)
+}
+
+func (c *asyncCache) Prefetch(key string) {
+	Prefetch(c.realCache, key)
+}
+
 func (c *asyncCache) Exists(key string) ItemStatus {
 	return c.realCache.Exists(key)
 }
@@ -71,12 +101,32 @@ func (c *asyncCache) Shutdown() {
 	close(c.requests)
 	c.wg.Wait()
 	// fmt.Println("Shut down all cache workers")
+	c.realCache.Shutdown()
+}
+
+// disabledErr returns the error a request failed with because the real cache was disabled, if any
+func (c *asyncCache) disabledErr() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.disabled == nil {
+		return nil
+	}
+	return c.disabled
 }
 
 // run implements the actual async logic.
 func (c *asyncCache) run() {
 	for r := range c.requests {
-		_ = c.realCache.Put(r.anchor, r.key, r.duration, r.files)
+		if err := c.realCache.Put(r.anchor, r.key, r.duration, r.files); err != nil {
+			cd := &util.CacheDisabledError{}
+			if errors.As(err, &cd) {
+				c.mu.Lock()
+				c.disabled = cd
+				c.mu.Unlock()
+			} else {
+				log.Printf("[ERROR] Error storing artifact %v in cache due to: %v", r.key, err)
+			}
+		}
 	}
 	c.wg.Done()
 }
@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"gotest.tools/v3/assert"
+)
+
+func TestAsyncCacheDisabled(t *testing.T) {
+	disabledCache := newAsyncCache(newDisabledCache(), 1)
+	defer disabledCache.Shutdown()
+	var removeCalled uint64
+	mplex := &cacheMultiplexer{
+		caches: []Cache{newEnabledCache(), disabledCache},
+		onCacheRemoved: func(cache Cache, err error) {
+			atomic.AddUint64(&removeCalled, 1)
+		},
+	}
+
+	// The first upload fails in the background. With a single worker, its error has been
+	// recorded by the time the third Put is made.
+	for i := 0; i < 3; i++ {
+		err := mplex.Put("unused-target", "some-hash", 5, []turbopath.AnchoredSystemPath{"a-file"})
+		assert.NilError(t, err, "Put")
+	}
+
+	assert.Equal(t, atomic.LoadUint64(&removeCalled), uint64(1))
+	mplex.mu.RLock()
+	defer mplex.mu.RUnlock()
+	assert.Equal(t, len(mplex.caches), 1)
+	assert.Assert(t, mplex.caches[0] != disabledCache, "expected the disabled cache to be removed")
+}
@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/vercel/turbo/cli/internal/analytics"
+	"github.com/vercel/turbo/cli/internal/turbopath"
+)
+
+// FilesystemBackend is the scheme of the built-in local filesystem cache
+const FilesystemBackend = "file"
+
+// RemoteBackend is the scheme of the built-in Vercel Remote Cache
+const RemoteBackend = "vercel"
+
+// BackendOptions holds everything a BackendFactory may use to construct a cache
+type BackendOptions struct {
+	// URL is the backend URL that selected this factory. It is nil for the built-in
+	// backends, which are configured entirely through Opts.
+	URL      *url.URL
+	Opts     Opts
+	RepoRoot turbopath.AbsoluteSystemPath
+	Recorder analytics.Recorder
+	// Client is the API client for the Vercel Remote Cache
+	Client client
+}
+
+// BackendFactory constructs a cache backend. Backends implement the Cache interface:
+// Fetch restores the artifact for a hash into the anchor directory, Exists reports
+// whether an artifact is present without restoring it, Put stores the given files
+// under a hash (calling Opts.OnUpload once stored), and Shutdown flushes any pending
+// work before turbo exits. A backend may return a *util.CacheDisabledError from any
+// operation to be removed from the run.
+type BackendFactory = func(backendOpts BackendOptions) (Cache, error)
+
+var (
+	backendsMu sync.RWMutex
+	backends   = make(map[string]BackendFactory)
+)
+
+func init() {
+	RegisterBackend(FilesystemBackend, func(backendOpts BackendOptions) (Cache, error) {
+		opts := backendOpts.Opts
+		// file:///path/to/cache stores artifacts in the given directory
+		if backendOpts.URL != nil && backendOpts.URL.Path != "" {
+			opts.OverrideDir = backendOpts.URL.Path
+		}
+		return newFsCache(opts, backendOpts.Recorder, backendOpts.RepoRoot)
+	})
+	RegisterBackend(RemoteBackend, func(backendOpts BackendOptions) (Cache, error) {
+		return newHTTPCache(backendOpts.Opts, backendOpts.Client, backendOpts.Recorder), nil
+	})
+	newLocalServerCache := func(backendOpts BackendOptions) (Cache, error) {
+		client, err := newLocalServerClient(backendOpts.URL)
+		if err != nil {
+			return nil, err
+		}
+		return newHTTPCache(backendOpts.Opts, client, backendOpts.Recorder), nil
+	}
+	RegisterBackend(LocalServerBackend, newLocalServerCache)
+	RegisterBackend(LocalSocketBackend, newLocalServerCache)
+}
+
+// RegisterBackend makes a cache backend available under the given URL scheme, so
+// that it can be selected with --cache-backend=<scheme>://... Custom backends are
+// registered from an init function of a package compiled into turbo.
+// RegisterBackend panics if a backend is already registered for the scheme.
+func RegisterBackend(scheme string, factory BackendFactory) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+	scheme = strings.ToLower(scheme)
+	if factory == nil {
+		panic("cache: RegisterBackend factory is nil")
+	}
+	if _, ok := backends[scheme]; ok {
+		panic("cache: RegisterBackend called twice for scheme " + scheme)
+	}
+	backends[scheme] = factory
+}
+
+// Backends returns the sorted schemes of the registered cache backends
+func Backends() []string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	schemes := make([]string, 0, len(backends))
+	for scheme := range backends {
+		schemes = append(schemes, scheme)
+	}
+	sort.Strings(schemes)
+	return schemes
+}
+
+// newBackend constructs the backend registered for scheme
+func newBackend(scheme string, backendOpts BackendOptions) (Cache, error) {
+	backendsMu.RLock()
+	factory, ok := backends[strings.ToLower(scheme)]
+	backendsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no cache backend is registered for %q, available backends are: %v", scheme, strings.Join(Backends(), ", "))
+	}
+	return factory(backendOpts)
+}
+
+// newRemoteBackend constructs the backend that stores artifacts somewhere other than
+// the local filesystem: the one selected by opts.Backend, or the Vercel Remote Cache.
+func newRemoteBackend(backendOpts BackendOptions) (Cache, error) {
+	if backendOpts.Opts.Backend == "" {
+		return newBackend(RemoteBackend, backendOpts)
+	}
+	backendURL, err := url.Parse(backendOpts.Opts.Backend)
+	if err != nil || backendURL.Scheme == "" {
+		return nil, fmt.Errorf("invalid cache backend %q: expected a URL such as <scheme>://<location>", backendOpts.Opts.Backend)
+	}
+	backendOpts.URL = backendURL
+	return newBackend(backendURL.Scheme, backendOpts)
+}
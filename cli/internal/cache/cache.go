@@ -9,26 +9,69 @@ import (
 	"errors"
 	"sync"
 
+	"github.com/hashicorp/go-hclog"
 	"github.com/vercel/turbo/cli/internal/analytics"
+	"github.com/vercel/turbo/cli/internal/cacheitem"
 	"github.com/vercel/turbo/cli/internal/fs"
 	"github.com/vercel/turbo/cli/internal/turbopath"
 	"github.com/vercel/turbo/cli/internal/util"
 	"golang.org/x/sync/errgroup"
 )
 
-// Cache is abstracted way to cache/fetch previously run tasks
+// Cache is abstracted way to cache/fetch previously run tasks.
+// Custom implementations can be made available with RegisterBackend.
 type Cache interface {
 	// Fetch returns true if there is a cache it. It is expected to move files
-	// into their correct position as a side effect
+	// into their correct position as a side effect. It also returns the restored
+	// files and the duration, in milliseconds, of the task that produced them.
 	Fetch(anchor turbopath.AbsoluteSystemPath, hash string, files []string) (bool, []turbopath.AnchoredSystemPath, int, error)
+	// Exists reports whether artifacts for the given hash are available, without fetching them
 	Exists(hash string) ItemStatus
 	// Put caches files for a given hash
 	Put(anchor turbopath.AbsoluteSystemPath, hash string, duration int, files []turbopath.AnchoredSystemPath) error
 	Clean(anchor turbopath.AbsoluteSystemPath)
 	CleanAll()
+	// Shutdown is called once before turbo exits, and must wait for pending writes
 	Shutdown()
 }
 
+// ManifestReader is implemented by caches that keep the manifest of each artifact they store
+type ManifestReader interface {
+	// ReadManifest returns the manifest of the artifact for the given hash, or nil if
+	// there is none
+	ReadManifest(hash string) (*cacheitem.Manifest, error)
+}
+
+// RemoteChecker is implemented by caches that can check that their remote cache will
+// accept reads and writes, without storing anything
+type RemoteChecker interface {
+	// CheckRemote returns an error describing why the remote cache can't be used
+	CheckRemote() error
+}
+
+// CheckRemote checks the remote cache of the given cache, if it can be checked. Backends
+// that don't implement RemoteChecker are assumed to work.
+func CheckRemote(cache Cache) error {
+	if checker, ok := cache.(RemoteChecker); ok {
+		return checker.CheckRemote()
+	}
+	return nil
+}
+
+// Prefetcher is implemented by caches that can download artifacts ahead of the Fetch
+// that restores them
+type Prefetcher interface {
+	// Prefetch starts downloading the artifact for the given hash in the background
+	Prefetch(hash string)
+}
+
+// Prefetch starts downloading the artifact for the given hash, if the cache supports it
+func Prefetch(cache Cache, hash string) {
+	if prefetcher, ok := cache.(Prefetcher); ok {
+		prefetcher.Prefetch(hash)
+	}
+}
+
 // ItemStatus holds whether artifacts exists for a given hash on local
 // and/or remote caching server
 type ItemStatus struct {
@@ -62,16 +105,38 @@ var ErrNoCachesEnabled = errors.New("no caches are enabled")
 // Opts holds configuration options for the cache
 // TODO(gsoltis): further refactor this into fs cache opts and http cache opts
 type Opts struct {
-	OverrideDir     string
-	SkipRemote      bool
+	OverrideDir string
+	SkipRemote  bool
+	// RemoteReadOnly, when set, fetches artifacts from the remote cache without ever
+	// writing to it
+	RemoteReadOnly  bool
 	SkipFilesystem  bool
 	Workers         int
 	RemoteCacheOpts fs.RemoteCacheOptions
+	// UploadConcurrency, when set, limits how many artifacts are uploaded to
+	// the remote cache at once. Uploads proceed in the background, on that many workers.
+	UploadConcurrency int
+	// Backend, when set, is the URL of the cache backend used in place of the
+	// Vercel Remote Cache. Its scheme selects a backend registered with RegisterBackend.
+	Backend string
+	// OnUpload, when set, is called with the hash of each artifact that has been
+	// successfully written to the remote cache. It may be called concurrently.
+	OnUpload func(hash string)
+	// MaxSize, when positive, is the size in bytes that the filesystem cache is kept
+	// under. The least recently used entries are evicted when the cache shuts down.
+	MaxSize int64
+	// Logger, when set, receives the debug logs of the filesystem cache
+	Logger hclog.Logger
+	// SigningKey, when set, is the key that artifacts written to the cache are signed
+	// with, and that the signatures of the artifacts fetched from it are verified with
+	SigningKey string
+	// RequireSignatures treats artifacts without a signature as missing from the cache
+	RequireSignatures bool
 }
 
-// resolveCacheDir calculates the location turbo should use to cache artifacts,
+// ResolveCacheDir calculates the location turbo should use to cache artifacts,
 // based on the options supplied by the user.
-func (o *Opts) resolveCacheDir(repoRoot turbopath.AbsoluteSystemPath) turbopath.AbsoluteSystemPath {
+func (o *Opts) ResolveCacheDir(repoRoot turbopath.AbsoluteSystemPath) turbopath.AbsoluteSystemPath {
 	if o.OverrideDir != "" {
 		return fs.ResolveUnknownPath(repoRoot, o.OverrideDir)
 	}
@@ -88,7 +153,7 @@ func New(opts Opts, repoRoot turbopath.AbsoluteSystemPath, client client, record
 		return nil, err
 	}
 	if opts.Workers > 0 {
-		return newAsyncCache(c, opts), err
+		return newAsyncCache(c, opts.Workers), err
 	}
 	return c, err
 }
@@ -97,7 +162,7 @@ func New(opts Opts, repoRoot turbopath.AbsoluteSystemPath, client client, record
 func newSyncCache(opts Opts, repoRoot turbopath.AbsoluteSystemPath, client client, recorder analytics.Recorder, onCacheRemoved OnCacheRemoved) (Cache, error) {
 	// Check to see if the user has turned off particular cache implementations.
 	useFsCache := !opts.SkipFilesystem
-	useHTTPCache := !opts.SkipRemote
+	useHTTPCache := !opts.SkipRemote || opts.Backend != ""
 
 	// Since the above two flags are not mutually exclusive it is possible to configure
 	// yourself out of having a cache. We should tell you about it but we shouldn't fail
@@ -111,9 +176,15 @@ func newSyncCache(opts Opts, repoRoot turbopath.AbsoluteSystemPath, client clien
 
 	// Build up an array of cache implementations, we can only ever have 1 or 2.
 	cacheImplementations := make([]Cache, 0, 2)
+	backendOpts := BackendOptions{
+		Opts:     opts,
+		RepoRoot: repoRoot,
+		Recorder: recorder,
+		Client:   client,
+	}
 
 	if useFsCache {
-		implementation, err := newFsCache(opts, recorder, repoRoot)
+		implementation, err := newBackend(FilesystemBackend, backendOpts)
 		if err != nil {
 			return nil, err
 		}
@@ -121,7 +192,15 @@ func newSyncCache(opts Opts, repoRoot turbopath.AbsoluteSystemPath, client clien
 	}
 
 	if useHTTPCache {
-		implementation := newHTTPCache(opts, client, recorder)
+		implementation, err := newRemoteBackend(backendOpts)
+		if err != nil {
+			return nil, err
+		}
+		if opts.RemoteReadOnly {
+			implementation = newReadOnlyCache(implementation)
+		} else if opts.UploadConcurrency > 0 {
+			implementation = newAsyncCache(implementation, opts.UploadConcurrency)
+		}
 		cacheImplementations = append(cacheImplementations, implementation)
 	}
 
@@ -279,6 +358,51 @@ func (mplex *cacheMultiplexer) Exists(target string) ItemStatus {
 	return syncCacheState
 }
 
+// ReadManifest returns the manifest kept by the first cache that has one for the hash
+func (mplex *cacheMultiplexer) ReadManifest(hash string) (*cacheitem.Manifest, error) {
+	mplex.mu.RLock()
+	defer mplex.mu.RUnlock()
+	for _, cache := range mplex.caches {
+		reader, ok := cache.(ManifestReader)
+		if !ok {
+			continue
+		}
+		manifest, err := reader.ReadManifest(hash)
+		if err != nil || manifest != nil {
+			return manifest, err
+		}
+	}
+	return nil, nil
+}
+
+// CheckRemote checks every cache that can be checked
+func (mplex *cacheMultiplexer) CheckRemote() error {
+	mplex.mu.RLock()
+	defer mplex.mu.RUnlock()
+	for _, cache := range mplex.caches {
+		if err := CheckRemote(cache); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Prefetch prefetches the artifact from the first cache that supports it, unless a
+// cache before it already has the artifact locally
+func (mplex *cacheMultiplexer) Prefetch(hash string) {
+	mplex.mu.RLock()
+	defer mplex.mu.RUnlock()
+	for _, cache := range mplex.caches {
+		if prefetcher, ok := cache.(Prefetcher); ok {
+			prefetcher.Prefetch(hash)
+			return
+		}
+		if cache.Exists(hash).Local {
+			return
+		}
+	}
+}
+
 func (mplex *cacheMultiplexer) Clean(anchor turbopath.AbsoluteSystemPath) {
 	for _, cache := range mplex.caches {
 		cache.Clean(anchor)
@@ -0,0 +1,229 @@
+package cache
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vercel/turbo/cli/internal/cacheitem"
+	"github.com/vercel/turbo/cli/internal/turbopath"
+)
+
+// The suffixes of the files the filesystem cache stores for each hash
+const (
+	_artifactSuffix           = ".tar"
+	_compressedArtifactSuffix = ".tar.zst"
+	_metaSuffix               = "-meta.json"
+	_manifestSuffix           = "-manifest.json"
+)
+
+// LocalCacheIssue is a problem found with a single entry of the filesystem cache
+type LocalCacheIssue struct {
+	Hash string
+	// Problem describes what is wrong with the entry
+	Problem string
+	// Files are the files of the entry that are removed to fix the issue
+	Files []turbopath.AbsoluteSystemPath
+}
+
+// Fix removes the files of the broken entry, so that the next run misses the cache
+// and caches the task again.
+func (i *LocalCacheIssue) Fix() error {
+	return removeFiles(i.Files)
+}
+
+// removeFiles removes the given files, ignoring those that are already gone
+func removeFiles(files []turbopath.AbsoluteSystemPath) error {
+	for _, file := range files {
+		if err := file.Remove(); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// LocalCacheReport is the result of checking every entry of the filesystem cache
+type LocalCacheReport struct {
+	// Artifacts is the number of cached artifacts
+	Artifacts int
+	// Size is the total size of the files belonging to cache entries, in bytes
+	Size int64
+	// Verified is the number of artifacts that passed every check
+	Verified int
+	Issues   []*LocalCacheIssue
+}
+
+// localCacheEntry holds the files stored in the filesystem cache for one hash
+type localCacheEntry struct {
+	artifact turbopath.AbsoluteSystemPath
+	meta     turbopath.AbsoluteSystemPath
+	manifest turbopath.AbsoluteSystemPath
+	// size is the total size of the entry's files, in bytes
+	size int64
+	// lastUsed is the latest modification time of the entry's files. Artifacts are
+	// touched when they are fetched, so this is when the entry was last written or read.
+	lastUsed time.Time
+}
+
+func (e *localCacheEntry) files() []turbopath.AbsoluteSystemPath {
+	files := []turbopath.AbsoluteSystemPath{}
+	for _, file := range []turbopath.AbsoluteSystemPath{e.artifact, e.meta, e.manifest} {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// readLocalCacheEntries groups the files of the filesystem cache at cacheDir by hash.
+// Files in cacheDir that don't belong to cache entries are ignored.
+func readLocalCacheEntries(cacheDir turbopath.AbsoluteSystemPath) (map[string]*localCacheEntry, error) {
+	dirEntries, err := os.ReadDir(cacheDir.ToString())
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string]*localCacheEntry)
+	entryFor := func(hash string) *localCacheEntry {
+		if _, ok := entries[hash]; !ok {
+			entries[hash] = &localCacheEntry{}
+		}
+		return entries[hash]
+	}
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() {
+			continue
+		}
+		name := dirEntry.Name()
+		path := cacheDir.UntypedJoin(name)
+		var entry *localCacheEntry
+		switch {
+		case strings.HasSuffix(name, _compressedArtifactSuffix):
+			entry = entryFor(strings.TrimSuffix(name, _compressedArtifactSuffix))
+			entry.artifact = path
+		case strings.HasSuffix(name, _artifactSuffix):
+			entry = entryFor(strings.TrimSuffix(name, _artifactSuffix))
+			entry.artifact = path
+		case strings.HasSuffix(name, _metaSuffix):
+			entry = entryFor(strings.TrimSuffix(name, _metaSuffix))
+			entry.meta = path
+		case strings.HasSuffix(name, _manifestSuffix):
+			entry = entryFor(strings.TrimSuffix(name, _manifestSuffix))
+			entry.manifest = path
+		default:
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			return nil, err
+		}
+		entry.size += info.Size()
+		if info.ModTime().After(entry.lastUsed) {
+			entry.lastUsed = info.ModTime()
+		}
+	}
+	return entries, nil
+}
+
+// CheckLocalCache verifies every entry of the filesystem cache at cacheDir. It reports
+// metadata without an artifact, artifacts that can't be fetched because their metadata
+// is missing, and artifacts that can't be read or don't match their manifest.
+// Files in cacheDir that don't belong to cache entries are ignored.
+func CheckLocalCache(cacheDir turbopath.AbsoluteSystemPath) (*LocalCacheReport, error) {
+	entries, err := readLocalCacheEntries(cacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &LocalCacheReport{}
+	for _, entry := range entries {
+		report.Size += entry.size
+	}
+
+	hashes := make([]string, 0, len(entries))
+	for hash := range entries {
+		hashes = append(hashes, hash)
+	}
+	sort.Strings(hashes)
+	for _, hash := range hashes {
+		entry := entries[hash]
+		if entry.artifact == "" {
+			report.Issues = append(report.Issues, &LocalCacheIssue{
+				Hash:    hash,
+				Problem: "metadata without an artifact",
+				Files:   entry.files(),
+			})
+			continue
+		}
+		report.Artifacts++
+		if entry.meta == "" {
+			report.Issues = append(report.Issues, &LocalCacheIssue{
+				Hash:    hash,
+				Problem: "artifact without metadata, it can't be restored",
+				Files:   entry.files(),
+			})
+			continue
+		}
+		if _, err := ReadCacheMetaFile(entry.meta); err != nil {
+			report.Issues = append(report.Issues, &LocalCacheIssue{
+				Hash:    hash,
+				Problem: fmt.Sprintf("unreadable metadata: %v", err),
+				Files:   entry.files(),
+			})
+			continue
+		}
+		if problem := checkArtifact(entry); problem != "" {
+			report.Issues = append(report.Issues, &LocalCacheIssue{
+				Hash:    hash,
+				Problem: problem,
+				Files:   entry.files(),
+			})
+			continue
+		}
+		report.Verified++
+	}
+	return report, nil
+}
+
+// checkArtifact reads the whole artifact of a cache entry, and describes why it is corrupt,
+// if it is.
+func checkArtifact(entry *localCacheEntry) string {
+	cacheItem, err := cacheitem.Open(entry.artifact)
+	if err != nil {
+		return fmt.Sprintf("unreadable artifact: %v", err)
+	}
+	actual, err := cacheItem.ReadManifest()
+	_ = cacheItem.Close()
+	if err != nil {
+		return fmt.Sprintf("corrupt artifact: %v", err)
+	}
+	if entry.manifest == "" {
+		return ""
+	}
+
+	manifestFile, err := entry.manifest.Open()
+	if err != nil {
+		return fmt.Sprintf("unreadable manifest: %v", err)
+	}
+	recorded, err := cacheitem.ReadManifest(manifestFile)
+	_ = manifestFile.Close()
+	if err != nil {
+		return err.Error()
+	}
+	// The signature is only part of the recorded manifest, it isn't stored in the artifact
+	actual.Signature = recorded.Signature
+	var recordedContents, actualContents bytes.Buffer
+	if err := recorded.Write(&recordedContents); err != nil {
+		return err.Error()
+	}
+	if err := actual.Write(&actualContents); err != nil {
+		return err.Error()
+	}
+	if !bytes.Equal(recordedContents.Bytes(), actualContents.Bytes()) {
+		return "artifact contents don't match its manifest"
+	}
+	return ""
+}
@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"gotest.tools/v3/assert"
+)
+
+func TestCheckLocalCache(t *testing.T) {
+	src := turbopath.AbsoluteSystemPath(t.TempDir())
+	assert.NilError(t, src.UntypedJoin("out.txt").WriteFile([]byte("output"), 0644), "WriteFile")
+	files := []turbopath.AnchoredSystemPath{turbopath.AnchoredUnixPath("out.txt").ToSystemPath()}
+
+	cacheDir := turbopath.AbsoluteSystemPath(t.TempDir())
+	cache := &fsCache{cacheDirectory: cacheDir, recorder: &dummyRecorder{}}
+	for _, hash := range []string{"intact", "no-meta", "corrupt", "mismatched"} {
+		assert.NilError(t, cache.Put(src, hash, 0, files), "Put")
+	}
+	assert.NilError(t, cacheDir.UntypedJoin("no-meta-meta.json").Remove(), "Remove")
+	assert.NilError(t, cacheDir.UntypedJoin("corrupt.tar.zst").WriteFile([]byte("not a tarball"), 0644), "WriteFile")
+	assert.NilError(t, WriteCacheMetaFile(cacheDir.UntypedJoin("orphan-meta.json"), &CacheMetadata{Hash: "orphan"}), "WriteCacheMetaFile")
+	// Cache a different file under the same name, then restore the original manifest
+	manifest, err := cacheDir.UntypedJoin("mismatched-manifest.json").ReadFile()
+	assert.NilError(t, err, "ReadFile")
+	assert.NilError(t, src.UntypedJoin("out.txt").WriteFile([]byte("different output"), 0644), "WriteFile")
+	assert.NilError(t, cache.Put(src, "mismatched", 0, files), "Put")
+	assert.NilError(t, cacheDir.UntypedJoin("mismatched-manifest.json").WriteFile(manifest, 0644), "WriteFile")
+	// Files that aren't cache entries are left alone
+	assert.NilError(t, cacheDir.UntypedJoin("workspaces-v1.json").WriteFile([]byte("{}"), 0644), "WriteFile")
+
+	report, err := CheckLocalCache(cacheDir)
+	assert.NilError(t, err, "CheckLocalCache")
+	assert.Equal(t, report.Artifacts, 4)
+	assert.Equal(t, report.Verified, 1)
+	problems := map[string]string{}
+	for _, issue := range report.Issues {
+		problems[issue.Hash] = issue.Problem
+	}
+	assert.Equal(t, len(problems), 4)
+	assert.Equal(t, problems["no-meta"], "artifact without metadata, it can't be restored")
+	assert.Equal(t, problems["orphan"], "metadata without an artifact")
+	assert.Equal(t, problems["mismatched"], "artifact contents don't match its manifest")
+	assert.Assert(t, problems["corrupt"] != "", "corrupt artifact is reported")
+
+	for _, issue := range report.Issues {
+		assert.NilError(t, issue.Fix(), "Fix")
+	}
+	report, err = CheckLocalCache(cacheDir)
+	assert.NilError(t, err, "CheckLocalCache")
+	assert.Equal(t, report.Artifacts, 1)
+	assert.Equal(t, report.Verified, 1)
+	assert.Equal(t, len(report.Issues), 0)
+	assert.Assert(t, cacheDir.UntypedJoin("intact.tar.zst").FileExists(), "intact artifact is kept")
+	assert.Assert(t, cacheDir.UntypedJoin("workspaces-v1.json").FileExists(), "unrelated file is kept")
+}
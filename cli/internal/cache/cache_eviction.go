@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"sort"
+
+	"github.com/vercel/turbo/cli/internal/turbopath"
+)
+
+// evictionResult describes the entries removed from the filesystem cache to bring it
+// under its size limit
+type evictionResult struct {
+	// Evicted are the hashes of the removed entries, least recently used first
+	Evicted []string
+	// Freed is the total size of the removed entries, in bytes
+	Freed int64
+	// Size is the total size of the entries that remain, in bytes
+	Size int64
+}
+
+// evictLocalCache removes the least recently used entries of the filesystem cache at
+// cacheDir until the total size of its entries is at most maxSize bytes. The most
+// recently used entry is kept even if it is larger than maxSize on its own.
+func evictLocalCache(cacheDir turbopath.AbsoluteSystemPath, maxSize int64) (*evictionResult, error) {
+	entries, err := readLocalCacheEntries(cacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &evictionResult{}
+	hashes := make([]string, 0, len(entries))
+	for hash, entry := range entries {
+		hashes = append(hashes, hash)
+		result.Size += entry.size
+	}
+	sort.Slice(hashes, func(i, j int) bool {
+		a, b := entries[hashes[i]], entries[hashes[j]]
+		if !a.lastUsed.Equal(b.lastUsed) {
+			return a.lastUsed.Before(b.lastUsed)
+		}
+		return hashes[i] < hashes[j]
+	})
+
+	for i, hash := range hashes {
+		if result.Size <= maxSize || i == len(hashes)-1 {
+			break
+		}
+		entry := entries[hash]
+		if err := removeFiles(entry.files()); err != nil {
+			return result, err
+		}
+		result.Evicted = append(result.Evicted, hash)
+		result.Freed += entry.size
+		result.Size -= entry.size
+	}
+	return result, nil
+}
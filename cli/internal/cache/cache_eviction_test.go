@@ -0,0 +1,59 @@
+package cache
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"gotest.tools/v3/assert"
+)
+
+func TestEvictLocalCache(t *testing.T) {
+	src := turbopath.AbsoluteSystemPath(t.TempDir())
+	assert.NilError(t, src.UntypedJoin("out.txt").WriteFile([]byte("output"), 0644), "WriteFile")
+	files := []turbopath.AnchoredSystemPath{turbopath.AnchoredUnixPath("out.txt").ToSystemPath()}
+
+	cacheDir := turbopath.AbsoluteSystemPath(t.TempDir())
+	cache := &fsCache{cacheDirectory: cacheDir, recorder: &dummyRecorder{}}
+	// Cache the entries an hour apart, oldest first. The hashes are the same length, so that
+	// the entries are the same size.
+	start := time.Now().Add(-24 * time.Hour)
+	hashes := []string{"hash-1", "hash-2", "hash-3", "hash-4"}
+	for i, hash := range hashes {
+		assert.NilError(t, cache.Put(src, hash, 0, files), "Put")
+		for _, suffix := range []string{_compressedArtifactSuffix, _metaSuffix, _manifestSuffix} {
+			used := start.Add(time.Duration(i) * time.Hour)
+			assert.NilError(t, os.Chtimes(cacheDir.UntypedJoin(hash+suffix).ToString(), used, used), "Chtimes")
+		}
+	}
+	// Fetching an entry makes it the most recently used one
+	hit, _, _, err := cache.Fetch(turbopath.AbsoluteSystemPath(t.TempDir()), "hash-2", nil)
+	assert.NilError(t, err, "Fetch")
+	assert.Assert(t, hit, "Fetch hit")
+
+	entries, err := readLocalCacheEntries(cacheDir)
+	assert.NilError(t, err, "readLocalCacheEntries")
+	entrySize := entries["hash-1"].size
+
+	// Everything fits, nothing is evicted
+	result, err := evictLocalCache(cacheDir, 4*entrySize)
+	assert.NilError(t, err, "evictLocalCache")
+	assert.Equal(t, len(result.Evicted), 0)
+
+	result, err = evictLocalCache(cacheDir, 2*entrySize)
+	assert.NilError(t, err, "evictLocalCache")
+	assert.DeepEqual(t, result.Evicted, []string{"hash-1", "hash-3"})
+	assert.Equal(t, result.Freed, 2*entrySize)
+	assert.Equal(t, result.Size, 2*entrySize)
+	for _, hash := range hashes {
+		assert.Equal(t, cache.Exists(hash).Local, hash == "hash-2" || hash == "hash-4", hash)
+	}
+	assert.Assert(t, !cacheDir.UntypedJoin("hash-1-meta.json").FileExists(), "metadata is evicted with the artifact")
+
+	// The most recently used entry is kept, even if it doesn't fit on its own
+	result, err = evictLocalCache(cacheDir, 1)
+	assert.NilError(t, err, "evictLocalCache")
+	assert.DeepEqual(t, result.Evicted, []string{"hash-4"})
+	assert.Assert(t, cache.Exists("hash-2").Local, "most recently used entry is kept")
+}
@@ -6,9 +6,13 @@
 package cache
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"os"
+	"time"
 
+	"github.com/hashicorp/go-hclog"
 	"github.com/vercel/turbo/cli/internal/analytics"
 	"github.com/vercel/turbo/cli/internal/cacheitem"
 	"github.com/vercel/turbo/cli/internal/turbopath"
@@ -18,17 +22,29 @@ import (
 type fsCache struct {
 	cacheDirectory turbopath.AbsoluteSystemPath
 	recorder       analytics.Recorder
+	// maxSize, when positive, is the size in bytes the cache is trimmed to on shutdown
+	maxSize int64
+	logger  hclog.Logger
+	// signer, when set, signs artifacts and verifies them before they are restored
+	signer *artifactSigner
 }
 
 // newFsCache creates a new filesystem cache
 func newFsCache(opts Opts, recorder analytics.Recorder, repoRoot turbopath.AbsoluteSystemPath) (*fsCache, error) {
-	cacheDir := opts.resolveCacheDir(repoRoot)
+	cacheDir := opts.ResolveCacheDir(repoRoot)
 	if err := cacheDir.MkdirAll(0775); err != nil {
 		return nil, err
 	}
+	logger := opts.Logger
+	if logger == nil {
+		logger = hclog.NewNullLogger()
+	}
 	return &fsCache{
 		cacheDirectory: cacheDir,
 		recorder:       recorder,
+		maxSize:        opts.MaxSize,
+		logger:         logger,
+		signer:         newArtifactSigner(opts),
 	}, nil
 }
 
@@ -48,6 +64,13 @@ func (f *fsCache) Fetch(anchor turbopath.AbsoluteSystemPath, hash string, _unuse
 		return false, nil, 0, nil
 	}
 
+	if f.signer != nil {
+		if err := f.verifySignature(hash, actualCachePath); err != nil {
+			f.logFetch(false, hash, 0)
+			return false, nil, 0, fmt.Errorf("artifact verification failed: %w", err)
+		}
+	}
+
 	cacheItem, openErr := cacheitem.Open(actualCachePath)
 	if openErr != nil {
 		return false, nil, 0, openErr
@@ -65,6 +88,10 @@ func (f *fsCache) Fetch(anchor turbopath.AbsoluteSystemPath, hash string, _unuse
 		return false, nil, 0, fmt.Errorf("error reading cache metadata: %w", err)
 	}
 	f.logFetch(true, hash, meta.Duration)
+	// Mark the entry as recently used, so that it is evicted last. Failing to do so only
+	// affects which entries are evicted.
+	now := time.Now()
+	_ = os.Chtimes(actualCachePath.ToString(), now, now)
 
 	// Wait to see what happens with close.
 	closeErr := cacheItem.Close()
@@ -85,6 +112,32 @@ func (f *fsCache) Exists(hash string) ItemStatus {
 	return ItemStatus{Local: false}
 }
 
+// ReadManifest reads the manifest written next to the artifact for hash, if there is one
+func (f *fsCache) ReadManifest(hash string) (*cacheitem.Manifest, error) {
+	manifestFile, err := f.cacheDirectory.UntypedJoin(hash + _manifestSuffix).Open()
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer func() { _ = manifestFile.Close() }()
+	return cacheitem.ReadManifest(manifestFile)
+}
+
+// verifySignature checks the artifact for hash at path against the signature recorded in
+// its manifest
+func (f *fsCache) verifySignature(hash string, path turbopath.AbsoluteSystemPath) error {
+	manifest, err := f.ReadManifest(hash)
+	if err != nil {
+		return err
+	}
+	signature := ""
+	if manifest != nil {
+		signature = manifest.Signature
+	}
+	return f.signer.verifyFile(hash, path, signature)
+}
+
 func (f *fsCache) logFetch(hit bool, hash string, duration int) {
 	var event string
 	if hit {
@@ -126,7 +179,26 @@ func (f *fsCache) Put(anchor turbopath.AbsoluteSystemPath, hash string, duration
 		return writeErr
 	}
 
-	return cacheItem.Close()
+	manifest := cacheItem.Manifest()
+	if f.signer == nil {
+		manifestErr := WriteCacheManifestFile(f.cacheDirectory.UntypedJoin(hash+"-manifest.json"), manifest)
+		if manifestErr != nil {
+			_ = cacheItem.Close()
+			return manifestErr
+		}
+		return cacheItem.Close()
+	}
+
+	// The artifact is only complete, and can be signed, once it is closed
+	if err := cacheItem.Close(); err != nil {
+		return err
+	}
+	signature, err := f.signer.signFile(hash, cachePath)
+	if err != nil {
+		return fmt.Errorf("failed to sign artifact: %w", err)
+	}
+	manifest.Signature = signature
+	return WriteCacheManifestFile(f.cacheDirectory.UntypedJoin(hash+"-manifest.json"), manifest)
 }
 
 func (f *fsCache) Clean(anchor turbopath.AbsoluteSystemPath) {
@@ -137,7 +209,21 @@ func (f *fsCache) CleanAll() {
 	fmt.Println("Not implemented yet")
 }
 
-func (f *fsCache) Shutdown() {}
+// Shutdown evicts the least recently used entries if the cache has grown past its
+// maximum size
+func (f *fsCache) Shutdown() {
+	if f.maxSize <= 0 {
+		return
+	}
+	result, err := evictLocalCache(f.cacheDirectory, f.maxSize)
+	if err != nil {
+		f.logger.Warn("failed to evict local cache entries", "error", err)
+		return
+	}
+	if len(result.Evicted) > 0 {
+		f.logger.Debug("evicted least recently used local cache entries", "count", len(result.Evicted), "freed", result.Freed, "size", result.Size, "maxSize", f.maxSize)
+	}
+}
 
 // CacheMetadata stores duration and hash information for a cache entry so that aggregate Time Saved calculations
 // can be made from artifacts from various caches
@@ -159,6 +245,15 @@ func WriteCacheMetaFile(path turbopath.AbsoluteSystemPath, config *CacheMetadata
 	return nil
 }
 
+// WriteCacheManifestFile writes the manifest of a cache artifact at a path
+func WriteCacheManifestFile(path turbopath.AbsoluteSystemPath, manifest *cacheitem.Manifest) error {
+	var contents bytes.Buffer
+	if err := manifest.Write(&contents); err != nil {
+		return err
+	}
+	return path.WriteFile(contents.Bytes(), 0644)
+}
+
 // ReadCacheMetaFile reads cache metadata file at a path
 func ReadCacheMetaFile(path turbopath.AbsoluteSystemPath) (*CacheMetadata, error) {
 	jsonBytes, readFileErr := path.ReadFile()
@@ -120,6 +120,18 @@ func TestPut(t *testing.T) {
 	}
 
 	assert.NilError(t, cacheItem.Close(), "Close")
+
+	manifestFile, err := dst.UntypedJoin(hash + "-manifest.json").Open()
+	assert.NilError(t, err, "Open")
+	manifest, err := cacheitem.ReadManifest(manifestFile)
+	assert.NilError(t, err, "ReadManifest")
+	assert.NilError(t, manifestFile.Close(), "Close")
+	assert.Equal(t, manifest.Version, cacheitem.ManifestVersion)
+	paths := []string{}
+	for _, entry := range manifest.Files {
+		paths = append(paths, entry.Path)
+	}
+	assert.DeepEqual(t, paths, []string{"b", "child", "child/a", "child/broken", "child/circle", "child/link"})
 }
 
 func assertFileMatches(t *testing.T, orig turbopath.AbsoluteSystemPath, copy turbopath.AbsoluteSystemPath) {
@@ -250,3 +262,20 @@ func TestFetch(t *testing.T) {
 	assert.NilError(t, circleReadlinkErr, "Circle Readlink")
 	assert.Equal(t, circleTarget, srcCircleLinkTarget.ToString())
 }
+
+func TestReadManifest(t *testing.T) {
+	src := turbopath.AbsoluteSystemPath(t.TempDir())
+	assert.NilError(t, src.UntypedJoin("out.txt").WriteFile([]byte("output"), 0644), "WriteFile")
+	cache := &fsCache{cacheDirectory: turbopath.AbsoluteSystemPath(t.TempDir()), recorder: &dummyRecorder{}}
+	assert.NilError(t, cache.Put(src, "some-hash", 0, []turbopath.AnchoredSystemPath{"out.txt"}), "Put")
+
+	mplex := &cacheMultiplexer{caches: []Cache{newEnabledCache(), cache}}
+	manifest, err := mplex.ReadManifest("some-hash")
+	assert.NilError(t, err, "ReadManifest")
+	assert.Equal(t, len(manifest.Files), 1)
+	assert.Equal(t, manifest.Files[0].Path, "out.txt")
+
+	manifest, err = mplex.ReadManifest("missing-hash")
+	assert.NilError(t, err, "ReadManifest")
+	assert.Assert(t, manifest == nil)
+}
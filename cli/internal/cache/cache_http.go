@@ -15,6 +15,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/DataDog/zstd"
@@ -22,6 +23,7 @@ import (
 	"github.com/vercel/turbo/cli/internal/analytics"
 	"github.com/vercel/turbo/cli/internal/tarpatch"
 	"github.com/vercel/turbo/cli/internal/turbopath"
+	"github.com/vercel/turbo/cli/internal/util"
 )
 
 type client interface {
@@ -29,6 +31,7 @@ type client interface {
 	FetchArtifact(hash string) (*http.Response, error)
 	ArtifactExists(hash string) (*http.Response, error)
 	GetTeamID() string
+	GetCachingStatus() (util.CachingStatus, error)
 }
 
 type httpCache struct {
@@ -37,7 +40,24 @@ type httpCache struct {
 	requestLimiter limiter
 	recorder       analytics.Recorder
 	signerVerifier *ArtifactSignatureAuthentication
-	repoRoot       turbopath.AbsoluteSystemPath
+	// signer, when set and the remote cache's own signatures are disabled, signs the
+	// artifacts that are uploaded, and verifies those that are downloaded, via x-artifact-tag
+	signer   *artifactSigner
+	onUpload func(hash string)
+	// prefetched holds the artifacts that Prefetch downloaded, or is downloading, by hash
+	prefetchMu sync.Mutex
+	prefetched map[string]*prefetchedArtifact
+}
+
+// prefetchedArtifact is an artifact that is downloaded before the Fetch that restores it
+type prefetchedArtifact struct {
+	// done is closed once the download has finished, successfully or not
+	done     chan struct{}
+	found    bool
+	duration int
+	// path is the temporary file holding the downloaded artifact
+	path string
+	err  error
 }
 
 type limiter chan struct{}
@@ -62,7 +82,7 @@ func (cache *httpCache) Put(anchor turbopath.AbsoluteSystemPath, hash string, du
 	defer cache.requestLimiter.release()
 
 	r, w := io.Pipe()
-	go cache.write(w, hash, files)
+	go cache.write(w, anchor, hash, files)
 
 	// Read the entire artifact tar into memory so we can easily compute the signature.
 	// Note: retryablehttp.NewRequest reads the files into memory anyways so there's no
@@ -77,12 +97,23 @@ func (cache *httpCache) Put(anchor turbopath.AbsoluteSystemPath, hash string, du
 		if err != nil {
 			return fmt.Errorf("failed to store files in HTTP cache: %w", err)
 		}
+	} else if cache.signer != nil {
+		tag, err = cache.signer.sign(hash, bytes.NewReader(artifactBody))
+		if err != nil {
+			return fmt.Errorf("failed to store files in HTTP cache: %w", err)
+		}
 	}
-	return cache.client.PutArtifact(hash, artifactBody, duration, tag)
+	if err := cache.client.PutArtifact(hash, artifactBody, duration, tag); err != nil {
+		return err
+	}
+	if cache.onUpload != nil {
+		cache.onUpload(hash)
+	}
+	return nil
 }
 
-// write writes a series of files into the given Writer.
-func (cache *httpCache) write(w io.WriteCloser, hash string, files []turbopath.AnchoredSystemPath) {
+// write writes a series of files, relative to anchor, into the given Writer.
+func (cache *httpCache) write(w io.WriteCloser, anchor turbopath.AbsoluteSystemPath, hash string, files []turbopath.AnchoredSystemPath) {
 	defer w.Close()
 	defer func() { _ = w.Close() }()
 	zw := zstd.NewWriter(w)
@@ -91,15 +122,15 @@ func (cache *httpCache) write(w io.WriteCloser, hash string, files []turbopath.A
 	defer func() { _ = tw.Close() }()
 	for _, file := range files {
 		// log.Printf("caching file %v", file)
-		if err := cache.storeFile(tw, file); err != nil {
+		if err := cache.storeFile(tw, anchor, file); err != nil {
 			log.Printf("[ERROR] Error uploading artifact %s to HTTP cache due to: %s", file, err)
 			// TODO(jaredpalmer): How can we cancel the request at this point?
 		}
 	}
 }
 
-func (cache *httpCache) storeFile(tw *tar.Writer, repoRelativePath turbopath.AnchoredSystemPath) error {
-	absoluteFilePath := repoRelativePath.RestoreAnchor(cache.repoRoot)
+func (cache *httpCache) storeFile(tw *tar.Writer, anchor turbopath.AbsoluteSystemPath, repoRelativePath turbopath.AnchoredSystemPath) error {
+	absoluteFilePath := repoRelativePath.RestoreAnchor(anchor)
 	info, err := absoluteFilePath.Lstat()
 	if err != nil {
 		return err
@@ -144,9 +175,19 @@ func (cache *httpCache) storeFile(tw *tar.Writer, repoRelativePath turbopath.Anc
 }
 
 func (cache *httpCache) Fetch(anchor turbopath.AbsoluteSystemPath, key string, _unusedOutputGlobs []string) (bool, []turbopath.AnchoredSystemPath, int, error) {
-	cache.requestLimiter.acquire()
-	defer cache.requestLimiter.release()
-	hit, files, duration, err := cache.retrieve(key)
+	var hit bool
+	var files []turbopath.AnchoredSystemPath
+	var duration int
+	var err error
+	// Wait for a prefetch outside of the limiter, since the prefetch needs it to download.
+	// If the prefetch failed, the artifact is downloaded again.
+	if artifact := cache.takePrefetched(key); artifact != nil && artifact.wait() == nil {
+		hit, files, duration, err = cache.restorePrefetched(anchor, artifact)
+	} else {
+		cache.requestLimiter.acquire()
+		hit, files, duration, err = cache.retrieve(anchor, key)
+		cache.requestLimiter.release()
+	}
 	if err != nil {
 		// TODO: analytics event?
 		return false, files, duration, fmt.Errorf("failed to retrieve files from HTTP cache: %w", err)
@@ -181,6 +222,24 @@ func (cache *httpCache) logFetch(hit bool, hash string, duration int) {
 	cache.recorder.LogEvent(payload)
 }
 
+// CheckRemote asks the remote cache whether it accepts artifacts from this team
+func (cache *httpCache) CheckRemote() error {
+	status, err := cache.client.GetCachingStatus()
+	if err != nil {
+		return fmt.Errorf("could not reach the remote cache, check your network and run `turbo login` if your token has expired: %w", err)
+	}
+	switch status {
+	case util.CachingStatusEnabled:
+		return nil
+	case util.CachingStatusOverLimit:
+		return errors.New("remote caching is paused because your team has reached its usage limit")
+	case util.CachingStatusPaused:
+		return errors.New("remote caching is paused because spending has been paused for your team")
+	default:
+		return errors.New("remote caching is disabled for your team, enable it in your team's settings")
+	}
+}
+
 func (cache *httpCache) exists(hash string) (bool, error) {
 	resp, err := cache.client.ArtifactExists(hash)
 	if err != nil {
@@ -197,58 +256,158 @@ func (cache *httpCache) exists(hash string) (bool, error) {
 	return true, err
 }
 
-func (cache *httpCache) retrieve(hash string) (bool, []turbopath.AnchoredSystemPath, int, error) {
+func (cache *httpCache) retrieve(anchor turbopath.AbsoluteSystemPath, hash string) (bool, []turbopath.AnchoredSystemPath, int, error) {
 	resp, err := cache.client.FetchArtifact(hash)
 	if err != nil {
 		return false, nil, 0, err
 	}
 	defer resp.Body.Close()
+	found, duration, tarReader, err := cache.readArtifact(hash, resp)
+	if err != nil || !found {
+		return false, nil, 0, err
+	}
+	files, err := restoreTar(anchor, tarReader)
+	if err != nil {
+		return false, nil, 0, err
+	}
+	return true, files, duration, nil
+}
+
+// readArtifact reads the response to a request for the artifact of the given hash. It
+// returns whether the artifact exists, the duration of the task that produced it, and
+// a reader of its verified contents.
+func (cache *httpCache) readArtifact(hash string, resp *http.Response) (bool, int, io.Reader, error) {
 	if resp.StatusCode == http.StatusNotFound {
-		return false, nil, 0, nil // doesn't exist - not an error
+		return false, 0, nil, nil // doesn't exist - not an error
 	} else if resp.StatusCode != http.StatusOK {
 		b, _ := ioutil.ReadAll(resp.Body)
-		return false, nil, 0, fmt.Errorf("%s", string(b))
+		return false, 0, nil, fmt.Errorf("%s", string(b))
 	}
 	// If present, extract the duration from the response.
 	duration := 0
 	if resp.Header.Get("x-artifact-duration") != "" {
 		intVar, err := strconv.Atoi(resp.Header.Get("x-artifact-duration"))
 		if err != nil {
-			return false, nil, 0, fmt.Errorf("invalid x-artifact-duration header: %w", err)
+			return false, 0, nil, fmt.Errorf("invalid x-artifact-duration header: %w", err)
 		}
 		duration = intVar
 	}
-	var tarReader io.Reader
-
-	defer func() { _ = resp.Body.Close() }()
-	if cache.signerVerifier.isEnabled() {
-		expectedTag := resp.Header.Get("x-artifact-tag")
-		if expectedTag == "" {
-			// If the verifier is enabled all incoming artifact downloads must have a signature
-			return false, nil, 0, errors.New("artifact verification failed: Downloaded artifact is missing required x-artifact-tag header")
+	if !cache.signerVerifier.isEnabled() {
+		if cache.signer == nil {
+			return true, duration, resp.Body, nil
 		}
 		b, err := ioutil.ReadAll(resp.Body)
 		if err != nil {
-			return false, nil, 0, fmt.Errorf("artifact verification failed: %w", err)
-		}
-		isValid, err := cache.signerVerifier.validate(hash, b, expectedTag)
-		if err != nil {
-			return false, nil, 0, fmt.Errorf("artifact verification failed: %w", err)
+			return false, 0, nil, fmt.Errorf("artifact verification failed: %w", err)
 		}
-		if !isValid {
-			err = fmt.Errorf("artifact verification failed: artifact tag does not match expected tag %s", expectedTag)
-			return false, nil, 0, err
+		if err := cache.signer.verify(hash, bytes.NewReader(b), resp.Header.Get("x-artifact-tag")); err != nil {
+			return false, 0, nil, fmt.Errorf("artifact verification failed: %w", err)
 		}
-		// The artifact has been verified and the body can be read and untarred
-		tarReader = bytes.NewReader(b)
-	} else {
-		tarReader = resp.Body
+		return true, duration, bytes.NewReader(b), nil
+	}
+	expectedTag := resp.Header.Get("x-artifact-tag")
+	if expectedTag == "" {
+		// If the verifier is enabled all incoming artifact downloads must have a signature
+		return false, 0, nil, errors.New("artifact verification failed: Downloaded artifact is missing required x-artifact-tag header")
+	}
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return false, 0, nil, fmt.Errorf("artifact verification failed: %w", err)
 	}
-	files, err := restoreTar(cache.repoRoot, tarReader)
+	isValid, err := cache.signerVerifier.validate(hash, b, expectedTag)
+	if err != nil {
+		return false, 0, nil, fmt.Errorf("artifact verification failed: %w", err)
+	}
+	if !isValid {
+		return false, 0, nil, fmt.Errorf("artifact verification failed: artifact tag does not match expected tag %s", expectedTag)
+	}
+	// The artifact has been verified and the body can be read and untarred
+	return true, duration, bytes.NewReader(b), nil
+}
+
+// Prefetch starts downloading the artifact for the given hash in the background, so that
+// a later Fetch of the hash restores it from disk.
+func (cache *httpCache) Prefetch(hash string) {
+	cache.prefetchMu.Lock()
+	defer cache.prefetchMu.Unlock()
+	if _, ok := cache.prefetched[hash]; ok {
+		return
+	}
+	if cache.prefetched == nil {
+		cache.prefetched = make(map[string]*prefetchedArtifact)
+	}
+	artifact := &prefetchedArtifact{done: make(chan struct{})}
+	cache.prefetched[hash] = artifact
+	go func() {
+		defer close(artifact.done)
+		cache.requestLimiter.acquire()
+		defer cache.requestLimiter.release()
+		artifact.found, artifact.duration, artifact.path, artifact.err = cache.download(hash)
+	}()
+}
+
+// download writes the artifact for the given hash to a temporary file, and returns its path
+func (cache *httpCache) download(hash string) (bool, int, string, error) {
+	resp, err := cache.client.FetchArtifact(hash)
+	if err != nil {
+		return false, 0, "", err
+	}
+	defer resp.Body.Close()
+	found, duration, tarReader, err := cache.readArtifact(hash, resp)
+	if err != nil || !found {
+		return false, 0, "", err
+	}
+	f, err := os.CreateTemp("", "turbo-prefetch-*.tar.zst")
+	if err != nil {
+		return false, 0, "", err
+	}
+	_, err = io.Copy(f, tarReader)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return false, 0, "", err
+	}
+	return true, duration, f.Name(), nil
+}
+
+// takePrefetched returns the prefetch of the given hash, if there is one, so that
+// it is only restored once
+func (cache *httpCache) takePrefetched(hash string) *prefetchedArtifact {
+	cache.prefetchMu.Lock()
+	defer cache.prefetchMu.Unlock()
+	artifact, ok := cache.prefetched[hash]
+	if !ok {
+		return nil
+	}
+	delete(cache.prefetched, hash)
+	return artifact
+}
+
+// wait blocks until the download of the artifact has finished, and returns its error
+func (artifact *prefetchedArtifact) wait() error {
+	<-artifact.done
+	return artifact.err
+}
+
+// restorePrefetched restores the files of a downloaded artifact into anchor, and removes its
+// temporary file
+func (cache *httpCache) restorePrefetched(anchor turbopath.AbsoluteSystemPath, artifact *prefetchedArtifact) (bool, []turbopath.AnchoredSystemPath, int, error) {
+	if !artifact.found {
+		return false, nil, 0, nil
+	}
+	defer func() { _ = os.Remove(artifact.path) }()
+	f, err := os.Open(artifact.path)
 	if err != nil {
 		return false, nil, 0, err
 	}
-	return true, files, duration, nil
+	defer util.CloseAndIgnoreError(f)
+	files, err := restoreTar(anchor, f)
+	if err != nil {
+		return false, nil, 0, err
+	}
+	return true, files, artifact.duration, nil
 }
 
 // restoreTar returns posix-style repo-relative paths of the files it
@@ -357,7 +516,17 @@ func (cache *httpCache) CleanAll() {
 	// Also not possible.
 }
 
-func (cache *httpCache) Shutdown() {}
+func (cache *httpCache) Shutdown() {
+	// Remove the artifacts that were prefetched, but never fetched
+	cache.prefetchMu.Lock()
+	defer cache.prefetchMu.Unlock()
+	for hash, artifact := range cache.prefetched {
+		if artifact.wait() == nil && artifact.found {
+			_ = os.Remove(artifact.path)
+		}
+		delete(cache.prefetched, hash)
+	}
+}
 
 func newHTTPCache(opts Opts, client client, recorder analytics.Recorder) *httpCache {
 	return &httpCache{
@@ -365,6 +534,8 @@ func newHTTPCache(opts Opts, client client, recorder analytics.Recorder) *httpCa
 		client:         client,
 		requestLimiter: make(limiter, 20),
 		recorder:       recorder,
+		onUpload:       opts.OnUpload,
+		signer:         newArtifactSigner(opts),
 		signerVerifier: &ArtifactSignatureAuthentication{
 			// TODO(Gaspar): this should use RemoteCacheOptions.TeamId once we start
 			// enforcing team restrictions for repositories.
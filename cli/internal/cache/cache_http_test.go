@@ -4,7 +4,11 @@ import (
 	"archive/tar"
 	"bytes"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/DataDog/zstd"
@@ -12,6 +16,7 @@ import (
 	"github.com/vercel/turbo/cli/internal/fs"
 	"github.com/vercel/turbo/cli/internal/turbopath"
 	"github.com/vercel/turbo/cli/internal/util"
+	"golang.org/x/sync/errgroup"
 	"gotest.tools/v3/assert"
 )
 
@@ -35,6 +40,10 @@ func (sr *errorResp) GetTeamID() string {
 	return ""
 }
 
+func (sr *errorResp) GetCachingStatus() (util.CachingStatus, error) {
+	return util.CachingStatusDisabled, sr.err
+}
+
 func TestRemoteCachingDisabled(t *testing.T) {
 	clientErr := &util.CacheDisabledError{
 		Status:  util.CachingStatusDisabled,
@@ -243,3 +252,172 @@ func TestRestoreInvalidTar(t *testing.T) {
 // Note that testing Put will require mocking the filesystem and is not currently the most
 // interesting test. The current implementation directly returns the error from PutArtifact.
 // We should still add the test once feasible to avoid future breakage.
+
+type concurrencyRecorder struct {
+	mu      sync.Mutex
+	active  int
+	maxSeen int
+	puts    int
+	block   chan struct{}
+}
+
+func (cr *concurrencyRecorder) PutArtifact(hash string, body []byte, duration int, tag string) error {
+	cr.mu.Lock()
+	cr.active++
+	cr.puts++
+	if cr.active > cr.maxSeen {
+		cr.maxSeen = cr.active
+	}
+	cr.mu.Unlock()
+	<-cr.block
+	cr.mu.Lock()
+	cr.active--
+	cr.mu.Unlock()
+	return nil
+}
+
+func (cr *concurrencyRecorder) FetchArtifact(hash string) (*http.Response, error) {
+	return nil, nil
+}
+
+func (cr *concurrencyRecorder) ArtifactExists(hash string) (*http.Response, error) {
+	return nil, nil
+}
+
+func (cr *concurrencyRecorder) GetTeamID() string {
+	return ""
+}
+
+func (cr *concurrencyRecorder) GetCachingStatus() (util.CachingStatus, error) {
+	return util.CachingStatusEnabled, nil
+}
+
+func TestUploadConcurrency(t *testing.T) {
+	client := &concurrencyRecorder{block: make(chan struct{})}
+	var uploaded int32
+	onUpload := func(hash string) { atomic.AddInt32(&uploaded, 1) }
+	cache := newAsyncCache(newHTTPCache(Opts{OnUpload: onUpload}, client, nil), 2)
+
+	// Puts return once a worker has picked up the upload, without waiting for it to complete
+	g := &errgroup.Group{}
+	for i := 0; i < 5; i++ {
+		hash := fmt.Sprintf("hash-%v", i)
+		g.Go(func() error {
+			return cache.Put("unused-anchor", hash, 0, nil)
+		})
+	}
+	close(client.block)
+	assert.NilError(t, g.Wait(), "Put")
+	cache.Shutdown()
+
+	assert.Equal(t, client.puts, 5)
+	assert.Assert(t, client.maxSeen <= 2, "saw %v concurrent uploads, want at most 2", client.maxSeen)
+	assert.Equal(t, atomic.LoadInt32(&uploaded), int32(5))
+}
+
+type statusClient struct {
+	errorResp
+	status util.CachingStatus
+}
+
+func (sc *statusClient) GetCachingStatus() (util.CachingStatus, error) {
+	return sc.status, nil
+}
+
+func TestCheckRemote(t *testing.T) {
+	enabled := newHTTPCache(Opts{}, &statusClient{status: util.CachingStatusEnabled}, nil)
+	assert.NilError(t, CheckRemote(enabled), "CheckRemote")
+
+	overLimit := newHTTPCache(Opts{}, &statusClient{status: util.CachingStatusOverLimit}, nil)
+	assert.Error(t, CheckRemote(newReadOnlyCache(overLimit)), "remote caching is paused because your team has reached its usage limit")
+
+	unreachable := newHTTPCache(Opts{}, &errorResp{err: errors.New("connection refused")}, nil)
+	assert.Error(t, CheckRemote(unreachable), "could not reach the remote cache, check your network and run `turbo login` if your token has expired: connection refused")
+}
+
+type artifactClient struct {
+	errorResp
+	artifacts map[string][]byte
+	// tags are the x-artifact-tag headers of the artifacts, by hash
+	tags    map[string]string
+	fetches int32
+}
+
+func (ac *artifactClient) FetchArtifact(hash string) (*http.Response, error) {
+	atomic.AddInt32(&ac.fetches, 1)
+	artifact, ok := ac.artifacts[hash]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(&bytes.Buffer{})}, nil
+	}
+	header := http.Header{"X-Artifact-Duration": []string{"42"}}
+	if tag, ok := ac.tags[hash]; ok {
+		header.Set("X-Artifact-Tag", tag)
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewReader(artifact)),
+	}, nil
+}
+
+func TestPrefetch(t *testing.T) {
+	client := &artifactClient{artifacts: map[string][]byte{"hit": makeValidTar(t).Bytes()}}
+	cache := newHTTPCache(Opts{}, client, nullRecorder{})
+	anchor := turbopath.AbsoluteSystemPath(t.TempDir())
+
+	Prefetch(cache, "hit")
+	Prefetch(cache, "hit")
+	Prefetch(cache, "miss")
+	Prefetch(cache, "unused")
+
+	hit, files, duration, err := cache.Fetch(anchor, "hit", nil)
+	assert.NilError(t, err, "Fetch")
+	assert.Assert(t, hit, "prefetched artifact is restored")
+	assert.Equal(t, len(files), 5)
+	assert.Equal(t, duration, 42)
+	contents, err := anchor.UntypedJoin("my-pkg", "some-file").ReadFile()
+	assert.NilError(t, err, "ReadFile")
+	assert.Equal(t, string(contents), "some-file-contents")
+
+	hit, _, _, err = cache.Fetch(anchor, "miss", nil)
+	assert.NilError(t, err, "Fetch")
+	assert.Assert(t, !hit, "a prefetched miss is a miss")
+
+	cache.Shutdown()
+	assert.Equal(t, atomic.LoadInt32(&client.fetches), int32(3), "each hash is downloaded once")
+	assert.Equal(t, len(cache.prefetched), 0)
+
+	// Without a prefetch, the artifact is downloaded by Fetch
+	hit, _, _, err = cache.Fetch(anchor, "hit", nil)
+	assert.NilError(t, err, "Fetch")
+	assert.Assert(t, hit, "artifact is fetched")
+	assert.Equal(t, atomic.LoadInt32(&client.fetches), int32(4))
+}
+
+func TestFetchSignedRemoteArtifact(t *testing.T) {
+	artifact := makeValidTar(t).Bytes()
+	signer := &artifactSigner{key: []byte("secret")}
+	tag, err := signer.sign("signed", bytes.NewReader(artifact))
+	assert.NilError(t, err, "sign")
+	client := &artifactClient{
+		artifacts: map[string][]byte{"signed": artifact, "tampered": artifact, "unsigned": artifact},
+		tags:      map[string]string{"signed": tag, "tampered": tag},
+	}
+
+	cache := newHTTPCache(Opts{SigningKey: "secret"}, client, nullRecorder{})
+	anchor := turbopath.AbsoluteSystemPath(t.TempDir())
+	hit, _, _, err := cache.Fetch(anchor, "signed", nil)
+	assert.NilError(t, err, "Fetch")
+	assert.Assert(t, hit, "signed artifact is restored")
+	hit, _, _, err = cache.Fetch(anchor, "tampered", nil)
+	assert.ErrorIs(t, err, errInvalidSignature)
+	assert.Assert(t, !hit, "the signature of another hash doesn't verify")
+	hit, _, _, err = cache.Fetch(anchor, "unsigned", nil)
+	assert.NilError(t, err, "Fetch")
+	assert.Assert(t, hit, "unsigned artifact is restored")
+
+	required := newHTTPCache(Opts{SigningKey: "secret", RequireSignatures: true}, client, nullRecorder{})
+	hit, _, _, err = required.Fetch(anchor, "unsigned", nil)
+	assert.ErrorIs(t, err, errUnsignedArtifact)
+	assert.Assert(t, !hit, "unsigned artifact is refused")
+}
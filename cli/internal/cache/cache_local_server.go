@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/vercel/turbo/cli/internal/util"
+)
+
+// The schemes of the backend that talks to a cache server running on this machine,
+// over HTTP or over a Unix domain socket
+const (
+	LocalServerBackend = "http"
+	LocalSocketBackend = "unix"
+)
+
+// _localServerTimeout bounds each request to a local cache server, so that a hung
+// server doesn't hold up the run
+const _localServerTimeout = 60 * time.Second
+
+// localServerClient talks to a cache server with a minimal protocol keyed by hash:
+//
+//	HEAD <base>/<hash>  200 if the artifact exists, 404 otherwise
+//	GET  <base>/<hash>  200 with the artifact as the body, 404 if it doesn't exist
+//	PUT  <base>/<hash>  stores the body as the artifact, any 2xx status is a success
+//
+// Artifacts are the same zstd-compressed tarballs as those of the Vercel Remote Cache.
+// PUT requests carry the duration of the task in milliseconds in x-artifact-duration,
+// which GET responses may return, and x-artifact-tag when artifacts are signed.
+type localServerClient struct {
+	// base is the URL that requests are made relative to
+	base   string
+	client *http.Client
+}
+
+// newLocalServerClient returns a client for the server at backendURL: http://host:port/path,
+// or unix:///path/to/socket for a server listening on a Unix domain socket
+func newLocalServerClient(backendURL *url.URL) (*localServerClient, error) {
+	client := &http.Client{Timeout: _localServerTimeout}
+	base := strings.TrimSuffix(backendURL.String(), "/")
+	if strings.EqualFold(backendURL.Scheme, LocalSocketBackend) {
+		socket := backendURL.Path
+		if socket == "" {
+			return nil, fmt.Errorf("invalid cache backend %q: expected unix:///path/to/socket", backendURL.String())
+		}
+		dialer := &net.Dialer{}
+		client.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _network, _addr string) (net.Conn, error) {
+				return dialer.DialContext(ctx, "unix", socket)
+			},
+		}
+		// The host is ignored, every request goes to the socket
+		base = "http://unix"
+	}
+	return &localServerClient{base: base, client: client}, nil
+}
+
+func (c *localServerClient) PutArtifact(hash string, body []byte, duration int, tag string) error {
+	req, err := http.NewRequest(http.MethodPut, c.base+"/"+hash, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("x-artifact-duration", fmt.Sprintf("%v", duration))
+	if tag != "" {
+		req.Header.Set("x-artifact-tag", tag)
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to store %v in the cache backend: %v", hash, resp.Status)
+	}
+	return nil
+}
+
+func (c *localServerClient) FetchArtifact(hash string) (*http.Response, error) {
+	return c.request(http.MethodGet, hash)
+}
+
+func (c *localServerClient) ArtifactExists(hash string) (*http.Response, error) {
+	return c.request(http.MethodHead, hash)
+}
+
+func (c *localServerClient) request(method string, hash string) (*http.Response, error) {
+	req, err := http.NewRequest(method, c.base+"/"+hash, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(req)
+}
+
+// do sends the request. A server that can't be reached is reported as a disabled cache,
+// which removes the backend from the run so that the local cache is used on its own.
+func (c *localServerClient) do(req *http.Request) (*http.Response, error) {
+	resp, err := c.client.Do(req)
+	opErr := &net.OpError{}
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return nil, &util.CacheDisabledError{
+			Status:  util.CachingStatusDisabled,
+			Message: fmt.Sprintf("the cache backend at %v can't be reached: %v", c.base, opErr.Err),
+		}
+	}
+	return resp, err
+}
+
+// GetTeamID is empty, a local cache server isn't shared between teams
+func (c *localServerClient) GetTeamID() string {
+	return ""
+}
+
+// GetCachingStatus reports caching as enabled if the server responds at all
+func (c *localServerClient) GetCachingStatus() (util.CachingStatus, error) {
+	req, err := http.NewRequest(http.MethodHead, c.base+"/", nil)
+	if err != nil {
+		return util.CachingStatusDisabled, err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return util.CachingStatusDisabled, err
+	}
+	_ = resp.Body.Close()
+	return util.CachingStatusEnabled, nil
+}
@@ -0,0 +1,113 @@
+package cache
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"github.com/vercel/turbo/cli/internal/util"
+	"gotest.tools/v3/assert"
+)
+
+// localCacheServer is a minimal implementation of the protocol of localServerClient
+type localCacheServer struct {
+	mu        sync.Mutex
+	artifacts map[string][]byte
+	durations map[string]string
+}
+
+func (s *localCacheServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	hash := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		s.artifacts[hash] = body
+		s.durations[hash] = r.Header.Get("x-artifact-duration")
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodGet, http.MethodHead:
+		artifact, ok := s.artifacts[hash]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("x-artifact-duration", s.durations[hash])
+		_, _ = w.Write(artifact)
+	}
+}
+
+func newLocalCacheServer() *localCacheServer {
+	return &localCacheServer{artifacts: make(map[string][]byte), durations: make(map[string]string)}
+}
+
+func testLocalServerBackend(t *testing.T, backend string) {
+	repoRoot := turbopath.AbsoluteSystemPath(t.TempDir())
+	file := repoRoot.UntypedJoin("dist", "index.js")
+	assert.NilError(t, file.EnsureDir(), "EnsureDir")
+	assert.NilError(t, file.WriteFile([]byte("built"), 0644), "WriteFile")
+	backendURL, err := url.Parse(backend)
+	assert.NilError(t, err, "Parse")
+	cache, err := newBackend(backendURL.Scheme, BackendOptions{URL: backendURL, RepoRoot: repoRoot, Recorder: nullRecorder{}})
+	assert.NilError(t, err, "newBackend")
+
+	assert.Equal(t, cache.Exists("abc"), ItemStatus{Remote: false})
+	files := []turbopath.AnchoredSystemPath{turbopath.AnchoredUnixPath("dist/index.js").ToSystemPath()}
+	assert.NilError(t, cache.Put(repoRoot, "abc", 42, files), "Put")
+	assert.Equal(t, cache.Exists("abc"), ItemStatus{Remote: true})
+	assert.NilError(t, file.Remove(), "Remove")
+
+	hit, restored, duration, err := cache.Fetch(repoRoot, "abc", nil)
+	assert.NilError(t, err, "Fetch")
+	assert.Assert(t, hit, "the artifact is restored")
+	assert.DeepEqual(t, restored, files)
+	assert.Equal(t, duration, 42)
+	contents, err := file.ReadFile()
+	assert.NilError(t, err, "ReadFile")
+	assert.Equal(t, string(contents), "built")
+
+	hit, _, _, err = cache.Fetch(repoRoot, "missing", nil)
+	assert.NilError(t, err, "Fetch")
+	assert.Assert(t, !hit, "a missing artifact is a miss")
+}
+
+func TestLocalServerBackend(t *testing.T) {
+	server := httptest.NewServer(newLocalCacheServer())
+	defer server.Close()
+	testLocalServerBackend(t, server.URL+"/artifacts")
+}
+
+func TestLocalSocketBackend(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a Unix domain socket")
+	}
+	socket := turbopath.AbsoluteSystemPath(t.TempDir()).UntypedJoin("cache.sock").ToString()
+	listener, err := net.Listen("unix", socket)
+	assert.NilError(t, err, "Listen")
+	server := &http.Server{Handler: newLocalCacheServer()}
+	go func() { _ = server.Serve(listener) }()
+	defer func() { _ = server.Close() }()
+	testLocalServerBackend(t, "unix://"+socket)
+}
+
+func TestUnreachableLocalServer(t *testing.T) {
+	server := httptest.NewServer(newLocalCacheServer())
+	backendURL, err := url.Parse(server.URL)
+	assert.NilError(t, err, "Parse")
+	server.Close()
+	cache, err := newBackend(LocalServerBackend, BackendOptions{URL: backendURL, Recorder: nullRecorder{}})
+	assert.NilError(t, err, "newBackend")
+
+	_, _, _, err = cache.Fetch("unused-anchor", "abc", nil)
+	cd := &util.CacheDisabledError{}
+	assert.Assert(t, errors.As(err, &cd), "an unreachable server disables the backend, got %v", err)
+	assert.ErrorContains(t, CheckRemote(cache), "can't be reached")
+}
@@ -0,0 +1,25 @@
+package cache
+
+import "github.com/vercel/turbo/cli/internal/turbopath"
+
+// readOnlyCache wraps a cache so that artifacts are fetched from it, but never stored
+// in it. It is used for the remote cache with --remote-cache-read-only.
+type readOnlyCache struct {
+	Cache
+}
+
+func newReadOnlyCache(cache Cache) *readOnlyCache {
+	return &readOnlyCache{Cache: cache}
+}
+
+func (c *readOnlyCache) CheckRemote() error {
+	return CheckRemote(c.Cache)
+}
+
+func (c *readOnlyCache) Prefetch(key string) {
+	Prefetch(c.Cache, key)
+}
+
+func (c *readOnlyCache) Put(anchor turbopath.AbsoluteSystemPath, key string, duration int, files []turbopath.AnchoredSystemPath) error {
+	return nil
+}
@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"hash"
+	"io"
+
+	"github.com/vercel/turbo/cli/internal/turbopath"
+)
+
+// errUnsignedArtifact is returned when signatures are required, and an artifact has none
+var errUnsignedArtifact = errors.New("artifact is not signed, and signatures are required")
+
+// errInvalidSignature is returned when the signature of an artifact doesn't match its contents
+var errInvalidSignature = errors.New("artifact signature does not match its contents")
+
+// artifactSigner signs the artifacts written to the cache with an HMAC-SHA256 of their hash
+// and contents, and verifies the signatures of the artifacts fetched from it. Artifacts that
+// weren't written by a holder of the key don't verify, so they are not restored.
+type artifactSigner struct {
+	key []byte
+	// required rejects artifacts without a signature, rather than accepting them
+	required bool
+}
+
+// newArtifactSigner returns the signer configured by opts, or nil when artifacts aren't signed
+func newArtifactSigner(opts Opts) *artifactSigner {
+	if opts.SigningKey == "" {
+		return nil
+	}
+	return &artifactSigner{
+		key:      []byte(opts.SigningKey),
+		required: opts.RequireSignatures,
+	}
+}
+
+func (s *artifactSigner) newHMAC(hash string) hash.Hash {
+	h := hmac.New(sha256.New, s.key)
+	// The hash is part of the signature, so that a signed artifact can't be served for
+	// another hash. Hashes never contain a newline.
+	_, _ = io.WriteString(h, hash+"\n")
+	return h
+}
+
+// sign returns the signature of the artifact for hash, whose contents are read from artifact
+func (s *artifactSigner) sign(hash string, artifact io.Reader) (string, error) {
+	h := s.newHMAC(hash)
+	if _, err := io.Copy(h, artifact); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(h.Sum(nil)), nil
+}
+
+// signFile returns the signature of the artifact for hash stored at path
+func (s *artifactSigner) signFile(hash string, path turbopath.AbsoluteSystemPath) (string, error) {
+	file, err := path.Open()
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = file.Close() }()
+	return s.sign(hash, file)
+}
+
+// verify checks the signature of the artifact for hash, whose contents are read from
+// artifact. An empty signature is only accepted when signatures aren't required.
+func (s *artifactSigner) verify(hash string, artifact io.Reader, signature string) error {
+	if signature == "" {
+		if s.required {
+			return errUnsignedArtifact
+		}
+		return nil
+	}
+	expected, err := s.sign(hash, artifact)
+	if err != nil {
+		return err
+	}
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return errInvalidSignature
+	}
+	return nil
+}
+
+// verifyFile checks the signature of the artifact for hash stored at path
+func (s *artifactSigner) verifyFile(hash string, path turbopath.AbsoluteSystemPath, signature string) error {
+	file, err := path.Open()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+	return s.verify(hash, file, signature)
+}
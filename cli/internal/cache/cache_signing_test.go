@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"gotest.tools/v3/assert"
+)
+
+func TestArtifactSigner(t *testing.T) {
+	signer := &artifactSigner{key: []byte("secret")}
+	signature, err := signer.sign("some-hash", strings.NewReader("artifact"))
+	assert.NilError(t, err, "sign")
+
+	assert.NilError(t, signer.verify("some-hash", strings.NewReader("artifact"), signature))
+	assert.ErrorIs(t, signer.verify("some-hash", strings.NewReader("tampered"), signature), errInvalidSignature)
+	assert.ErrorIs(t, signer.verify("other-hash", strings.NewReader("artifact"), signature), errInvalidSignature)
+	otherKey := &artifactSigner{key: []byte("other-secret")}
+	assert.ErrorIs(t, otherKey.verify("some-hash", strings.NewReader("artifact"), signature), errInvalidSignature)
+
+	assert.NilError(t, signer.verify("some-hash", strings.NewReader("artifact"), ""), "unsigned artifacts are accepted")
+	required := &artifactSigner{key: []byte("secret"), required: true}
+	assert.ErrorIs(t, required.verify("some-hash", strings.NewReader("artifact"), ""), errUnsignedArtifact)
+}
+
+func TestFetchSignedArtifact(t *testing.T) {
+	src := turbopath.AbsoluteSystemPath(t.TempDir())
+	assert.NilError(t, src.UntypedJoin("out.txt").WriteFile([]byte("output"), 0644), "WriteFile")
+	files := []turbopath.AnchoredSystemPath{"out.txt"}
+	cacheDir := turbopath.AbsoluteSystemPath(t.TempDir())
+	cache := &fsCache{cacheDirectory: cacheDir, recorder: &dummyRecorder{}, signer: &artifactSigner{key: []byte("secret")}}
+	assert.NilError(t, cache.Put(src, "signed-hash", 0, files), "Put")
+
+	manifest, err := cache.ReadManifest("signed-hash")
+	assert.NilError(t, err, "ReadManifest")
+	assert.Assert(t, manifest.Signature != "")
+	hit, _, _, err := cache.Fetch(turbopath.AbsoluteSystemPath(t.TempDir()), "signed-hash", nil)
+	assert.NilError(t, err, "Fetch")
+	assert.Assert(t, hit)
+
+	// An artifact signed with another key is a miss
+	otherKey := &fsCache{cacheDirectory: cacheDir, recorder: &dummyRecorder{}, signer: &artifactSigner{key: []byte("other-secret")}}
+	hit, _, _, err = otherKey.Fetch(turbopath.AbsoluteSystemPath(t.TempDir()), "signed-hash", nil)
+	assert.ErrorIs(t, err, errInvalidSignature)
+	assert.Assert(t, !hit)
+
+	// Unsigned artifacts are only restored when signatures aren't required
+	unsigned := &fsCache{cacheDirectory: cacheDir, recorder: &dummyRecorder{}}
+	assert.NilError(t, unsigned.Put(src, "unsigned-hash", 0, files), "Put")
+	hit, _, _, err = cache.Fetch(turbopath.AbsoluteSystemPath(t.TempDir()), "unsigned-hash", nil)
+	assert.NilError(t, err, "Fetch")
+	assert.Assert(t, hit)
+	required := &fsCache{cacheDirectory: cacheDir, recorder: &dummyRecorder{}, signer: &artifactSigner{key: []byte("secret"), required: true}}
+	hit, _, _, err = required.Fetch(turbopath.AbsoluteSystemPath(t.TempDir()), "unsigned-hash", nil)
+	assert.ErrorIs(t, err, errUnsignedArtifact)
+	assert.Assert(t, !hit)
+}
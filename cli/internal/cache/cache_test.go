@@ -146,6 +146,36 @@ func TestExists(t *testing.T) {
 	}
 }
 
+func TestReadOnlyCache(t *testing.T) {
+	local := newEnabledCache()
+	remote := newEnabledCache()
+	remote.entries["remote-hash"] = []turbopath.AnchoredSystemPath{"a-file"}
+	mplex := &cacheMultiplexer{
+		caches: []Cache{local, newReadOnlyCache(remote)},
+	}
+
+	hit, _, _, err := mplex.Fetch("unused-target", "remote-hash", []string{"unused", "files"})
+	if err != nil {
+		t.Errorf("Fetch got error %v, want <nil>", err)
+	}
+	if !hit {
+		t.Error("expected a hit from the read-only cache")
+	}
+	if _, ok := local.entries["remote-hash"]; !ok {
+		t.Error("expected artifacts fetched from the read-only cache to be stored locally")
+	}
+
+	if err := mplex.Put("unused-target", "local-hash", 5, []turbopath.AnchoredSystemPath{"a-file"}); err != nil {
+		t.Errorf("Put got error %v, want <nil>", err)
+	}
+	if _, ok := local.entries["local-hash"]; !ok {
+		t.Error("expected the local cache to be written")
+	}
+	if _, ok := remote.entries["local-hash"]; ok {
+		t.Error("expected the read-only cache not to be written")
+	}
+}
+
 type fakeClient struct{}
 
 // FetchArtifact implements client
@@ -162,6 +192,11 @@ func (*fakeClient) GetTeamID() string {
 	return "fake-team-id"
 }
 
+// GetCachingStatus implements client
+func (*fakeClient) GetCachingStatus() (util.CachingStatus, error) {
+	panic("unimplemented")
+}
+
 // PutArtifact implements client
 func (*fakeClient) PutArtifact(hash string, body []byte, duration int, tag string) error {
 	panic("unimplemented")
@@ -260,6 +295,22 @@ func TestNew(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "With a read-only remote cache, new returns an fsCache and a read-only httpCache",
+			args: args{
+				opts: Opts{
+					RemoteReadOnly: true,
+					RemoteCacheOpts: fs.RemoteCacheOptions{
+						Signature: true,
+					},
+				},
+				recorder:       &nullRecorder{},
+				onCacheRemoved: func(Cache, error) {},
+			},
+			want: &cacheMultiplexer{
+				caches: []Cache{&fsCache{}, &readOnlyCache{}},
+			},
+		},
 		{
 			name: "With just fsCache configured, new returns only an fsCache",
 			args: args{
@@ -314,3 +365,43 @@ func TestNew(t *testing.T) {
 		})
 	}
 }
+
+func TestRegisteredBackend(t *testing.T) {
+	repoRoot := fs.AbsoluteSystemPathFromUpstream(t.TempDir())
+	custom := newEnabledCache()
+	var gotURL string
+	RegisterBackend("test-backend", func(backendOpts BackendOptions) (Cache, error) {
+		gotURL = backendOpts.URL.String()
+		return custom, nil
+	})
+	// Backends are registered globally, so remove it for the next run of the test
+	t.Cleanup(func() {
+		backendsMu.Lock()
+		defer backendsMu.Unlock()
+		delete(backends, "test-backend")
+	})
+
+	// A custom backend replaces the remote cache, even when not linked to Vercel
+	opts := Opts{SkipRemote: true, Backend: "test-backend://localhost:9000/artifacts"}
+	got, err := New(opts, repoRoot, &fakeClient{}, &nullRecorder{}, func(Cache, error) {})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	multiplexer, ok := got.(*cacheMultiplexer)
+	if !ok {
+		t.Fatalf("New() = %v, want a cacheMultiplexer", reflect.TypeOf(got))
+	}
+	if len(multiplexer.caches) != 2 || reflect.TypeOf(multiplexer.caches[0]) != reflect.TypeOf(&fsCache{}) || multiplexer.caches[1] != custom {
+		t.Errorf("New() caches = %v, want the fsCache and the custom backend", multiplexer.caches)
+	}
+	if gotURL != opts.Backend {
+		t.Errorf("backend URL = %v, want %v", gotURL, opts.Backend)
+	}
+
+	for _, backend := range []string{"missing-backend://localhost", "localhost:9000"} {
+		opts := Opts{SkipFilesystem: true, Backend: backend}
+		if _, err := New(opts, repoRoot, &fakeClient{}, &nullRecorder{}, func(Cache, error) {}); err == nil {
+			t.Errorf("New() with backend %v expected an error", backend)
+		}
+	}
+}
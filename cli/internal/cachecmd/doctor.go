@@ -0,0 +1,152 @@
+// Package cachecmd implements the `turbo cache` subcommands
+package cachecmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/mitchellh/cli"
+	"github.com/vercel/turbo/cli/internal/cache"
+	"github.com/vercel/turbo/cli/internal/cmdutil"
+	"github.com/vercel/turbo/cli/internal/fs"
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"github.com/vercel/turbo/cli/internal/turbostate"
+	"github.com/vercel/turbo/cli/internal/ui"
+	"github.com/vercel/turbo/cli/internal/util"
+)
+
+// remoteCacheClient is the part of the API client used to check the remote cache
+type remoteCacheClient interface {
+	IsLinked() bool
+	GetCachingStatus() (util.CachingStatus, error)
+}
+
+// ExecuteCache executes the `cache` command.
+func ExecuteCache(helper *cmdutil.Helper, args *turbostate.ParsedArgsFromRust) error {
+	base, err := helper.GetCmdBase(args)
+	if err != nil {
+		return err
+	}
+	if args.TestRun {
+		base.UI.Info("Cache test run successful")
+		return nil
+	}
+	switch args.Command.Cache.Command {
+	case "Doctor":
+		return runDoctor(base, args.Command.Cache)
+	case "Verify":
+		return runVerify(base.UI, base.RepoRoot, localCacheDir(base.RepoRoot, args.Command.Cache), args.Command.Cache.Prune)
+	default:
+		return fmt.Errorf("unknown cache command: %v", args.Command.Cache.Command)
+	}
+}
+
+// runDoctor checks the local and remote caches, and reports anything that would prevent
+// them from being used. With --fix, broken local cache entries are removed.
+func runDoctor(base *cmdutil.CmdBase, opts *turbostate.CachePayload) error {
+	cacheDir := localCacheDir(base.RepoRoot, opts)
+	problems := 0
+	terminal := base.UI
+	terminal.Output(util.Sprintf("${BOLD}Local cache${RESET} %v", relativeToRepo(base.RepoRoot, cacheDir)))
+	if !cacheDir.DirExists() {
+		terminal.Output(ui.Dim("  Empty, nothing has been cached yet"))
+	} else {
+		report, err := cache.CheckLocalCache(cacheDir)
+		if err != nil {
+			return fmt.Errorf("failed to check local cache: %w", err)
+		}
+		terminal.Output(fmt.Sprintf("  %v artifacts, %v", report.Artifacts, formatSize(report.Size)))
+		for _, issue := range report.Issues {
+			if !opts.Fix {
+				problems++
+				printProblem(terminal, fmt.Sprintf("%v: %v", issue.Hash, issue.Problem))
+				continue
+			}
+			if err := issue.Fix(); err != nil {
+				problems++
+				printProblem(terminal, fmt.Sprintf("%v: %v, and failed to remove it: %v", issue.Hash, issue.Problem, err))
+				continue
+			}
+			terminal.Output(fmt.Sprintf("  %v %v: %v, removed", color.GreenString("✓"), issue.Hash, issue.Problem))
+		}
+		if len(report.Issues) == 0 {
+			terminal.Output(fmt.Sprintf("  %v Every entry is intact", color.GreenString("✓")))
+		}
+	}
+
+	terminal.Output("")
+	terminal.Output(util.Sprintf("${BOLD}Remote cache${RESET}"))
+	if status, problem := checkRemoteCache(base.APIClient); problem != "" {
+		problems++
+		printProblem(terminal, problem)
+	} else {
+		terminal.Output(fmt.Sprintf("  %v %v", color.GreenString("✓"), status))
+	}
+
+	terminal.Output("")
+	if problems > 0 {
+		if !opts.Fix {
+			terminal.Output(ui.Dim("Run `turbo cache doctor --fix` to remove broken local cache entries"))
+		}
+		return fmt.Errorf("found %v cache problem(s)", problems)
+	}
+	terminal.Output(color.GreenString("No problems found"))
+	return nil
+}
+
+// checkRemoteCache returns a description of the state of the remote cache if it can be
+// used, or of the problem preventing it from being used. Not being linked is not a problem.
+func checkRemoteCache(client remoteCacheClient) (string, string) {
+	if !client.IsLinked() {
+		return "Not linked, run `turbo link` to enable Remote Caching", ""
+	}
+	status, err := client.GetCachingStatus()
+	if err != nil {
+		return "", fmt.Sprintf("could not reach the remote cache, check your network and run `turbo login` if your token has expired: %v", err)
+	}
+	switch status {
+	case util.CachingStatusEnabled:
+		return "Reachable, and remote caching is enabled", ""
+	case util.CachingStatusOverLimit:
+		return "", "remote caching is paused because your team has reached its usage limit"
+	case util.CachingStatusPaused:
+		return "", "remote caching is paused because spending has been paused for your team"
+	default:
+		return "", "remote caching is disabled for your team, enable it in your team's settings"
+	}
+}
+
+// localCacheDir returns the filesystem cache directory a cache subcommand works on
+func localCacheDir(repoRoot turbopath.AbsoluteSystemPath, opts *turbostate.CachePayload) turbopath.AbsoluteSystemPath {
+	if opts.CacheDir != "" {
+		return fs.ResolveUnknownPath(repoRoot, opts.CacheDir)
+	}
+	return cache.DefaultLocation(repoRoot)
+}
+
+func printProblem(terminal cli.Ui, problem string) {
+	terminal.Output(fmt.Sprintf("  %v %v", color.RedString("✗"), problem))
+}
+
+func relativeToRepo(repoRoot turbopath.AbsoluteSystemPath, path turbopath.AbsoluteSystemPath) string {
+	relative, err := repoRoot.RelativePathString(path.ToString())
+	if err != nil {
+		return path.ToString()
+	}
+	return filepath.ToSlash(relative)
+}
+
+// formatSize formats a number of bytes with a binary unit
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,55 @@
+package cachecmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vercel/turbo/cli/internal/util"
+	"gotest.tools/v3/assert"
+)
+
+type mockRemoteCacheClient struct {
+	linked bool
+	status util.CachingStatus
+	err    error
+}
+
+func (m *mockRemoteCacheClient) IsLinked() bool {
+	return m.linked
+}
+
+func (m *mockRemoteCacheClient) GetCachingStatus() (util.CachingStatus, error) {
+	return m.status, m.err
+}
+
+func Test_checkRemoteCache(t *testing.T) {
+	tests := []struct {
+		name        string
+		client      *mockRemoteCacheClient
+		wantProblem bool
+	}{
+		{name: "not linked", client: &mockRemoteCacheClient{}},
+		{name: "enabled", client: &mockRemoteCacheClient{linked: true, status: util.CachingStatusEnabled}},
+		{name: "over limit", client: &mockRemoteCacheClient{linked: true, status: util.CachingStatusOverLimit}, wantProblem: true},
+		{name: "disabled", client: &mockRemoteCacheClient{linked: true, status: util.CachingStatusDisabled}, wantProblem: true},
+		{name: "unreachable", client: &mockRemoteCacheClient{linked: true, err: errors.New("failed to get caching status (403): forbidden")}, wantProblem: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, problem := checkRemoteCache(tt.client)
+			if tt.wantProblem {
+				assert.Equal(t, status, "")
+				assert.Assert(t, problem != "")
+			} else {
+				assert.Assert(t, status != "")
+				assert.Equal(t, problem, "")
+			}
+		})
+	}
+}
+
+func Test_formatSize(t *testing.T) {
+	assert.Equal(t, formatSize(512), "512 B")
+	assert.Equal(t, formatSize(1536), "1.5 KiB")
+	assert.Equal(t, formatSize(3*1024*1024*1024), "3.0 GiB")
+}
@@ -0,0 +1,51 @@
+package cachecmd
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/mitchellh/cli"
+	"github.com/vercel/turbo/cli/internal/cache"
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"github.com/vercel/turbo/cli/internal/ui"
+	"github.com/vercel/turbo/cli/internal/util"
+)
+
+// runVerify checks every entry of the local cache against its manifest, and fails if any
+// is corrupt. With --prune, corrupt entries are removed instead.
+func runVerify(terminal cli.Ui, repoRoot turbopath.AbsoluteSystemPath, cacheDir turbopath.AbsoluteSystemPath, prune bool) error {
+	terminal.Output(util.Sprintf("${BOLD}Local cache${RESET} %v", relativeToRepo(repoRoot, cacheDir)))
+	if !cacheDir.DirExists() {
+		terminal.Output(ui.Dim("  Empty, nothing has been cached yet"))
+		return nil
+	}
+	report, err := cache.CheckLocalCache(cacheDir)
+	if err != nil {
+		return fmt.Errorf("failed to verify local cache: %w", err)
+	}
+
+	pruned := 0
+	for _, issue := range report.Issues {
+		if !prune {
+			printProblem(terminal, fmt.Sprintf("%v: %v", issue.Hash, issue.Problem))
+			continue
+		}
+		if err := issue.Fix(); err != nil {
+			printProblem(terminal, fmt.Sprintf("%v: %v, and failed to remove it: %v", issue.Hash, issue.Problem, err))
+			continue
+		}
+		pruned++
+		terminal.Output(fmt.Sprintf("  %v %v: %v, pruned", color.GreenString("✓"), issue.Hash, issue.Problem))
+	}
+
+	corrupt := len(report.Issues)
+	terminal.Output("")
+	terminal.Output(fmt.Sprintf("%v verified, %v corrupt, %v pruned", report.Verified, corrupt, pruned))
+	if remaining := corrupt - pruned; remaining > 0 {
+		if !prune {
+			terminal.Output(ui.Dim("Run `turbo cache verify --prune` to remove corrupt entries"))
+		}
+		return fmt.Errorf("found %v corrupt cache entries", remaining)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package cachecmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/vercel/turbo/cli/internal/cache"
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"gotest.tools/v3/assert"
+)
+
+func Test_runVerify(t *testing.T) {
+	repoRoot := turbopath.AbsoluteSystemPath(t.TempDir())
+	cacheDir := repoRoot.UntypedJoin("cache")
+	assert.NilError(t, cacheDir.MkdirAll(0755), "MkdirAll")
+	assert.NilError(t, cacheDir.UntypedJoin("corrupt.tar.zst").WriteFile([]byte("not a tarball"), 0644), "WriteFile")
+	assert.NilError(t, cache.WriteCacheMetaFile(cacheDir.UntypedJoin("corrupt-meta.json"), &cache.CacheMetadata{Hash: "corrupt"}), "WriteCacheMetaFile")
+
+	terminal := cli.NewMockUi()
+	err := runVerify(terminal, repoRoot, cacheDir, false)
+	assert.ErrorContains(t, err, "found 1 corrupt cache entries")
+	assert.Assert(t, cacheDir.UntypedJoin("corrupt.tar.zst").FileExists(), "corrupt entry is kept without --prune")
+	assert.Assert(t, strings.Contains(terminal.OutputWriter.String(), "0 verified, 1 corrupt, 0 pruned"))
+
+	terminal = cli.NewMockUi()
+	assert.NilError(t, runVerify(terminal, repoRoot, cacheDir, true), "runVerify")
+	assert.Assert(t, !cacheDir.UntypedJoin("corrupt.tar.zst").FileExists(), "corrupt entry is pruned")
+	assert.Assert(t, strings.Contains(terminal.OutputWriter.String(), "0 verified, 1 corrupt, 1 pruned"))
+
+	// A missing cache has nothing to verify
+	assert.NilError(t, runVerify(cli.NewMockUi(), repoRoot, repoRoot.UntypedJoin("missing"), false), "runVerify")
+}
@@ -34,6 +34,9 @@ type CacheItem struct {
 	fileBuffer *bufio.Writer
 	handle     *os.File
 	compressed bool
+
+	// manifestEntries describe the files added during creation
+	manifestEntries []ManifestEntry
 }
 
 // Close any open pipes
@@ -3,6 +3,7 @@ package cacheitem
 import (
 	"archive/tar"
 	"bufio"
+	"hash"
 	"io"
 	"os"
 	"strings"
@@ -108,12 +109,26 @@ func (ci *CacheItem) AddFile(fsAnchor turbopath.AbsoluteSystemPath, filePath tur
 			return sourceErr
 		}
 
-		if _, err := io.Copy(ci.tw, sourceFile); err != nil {
+		hasher := newBlobHasher(header)
+		if _, err := io.Copy(io.MultiWriter(ci.tw, hasher), sourceFile); err != nil {
 			return err
 		}
 
-		return sourceFile.Close()
+		if err := sourceFile.Close(); err != nil {
+			return err
+		}
+		return ci.addManifestEntry(header, hasher)
 	}
 
+	return ci.addManifestEntry(header, newBlobHasher(header))
+}
+
+// addManifestEntry records a header written to the tar, and the hash of its contents.
+func (ci *CacheItem) addManifestEntry(header *tar.Header, hasher hash.Hash) error {
+	entry, err := newManifestEntry(header, hasher)
+	if err != nil {
+		return err
+	}
+	ci.manifestEntries = append(ci.manifestEntries, entry)
 	return nil
 }
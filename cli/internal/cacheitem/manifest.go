@@ -0,0 +1,225 @@
+package cacheitem
+
+import (
+	"archive/tar"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/zstd"
+	"github.com/vercel/turbo/cli/internal/hashing"
+	"github.com/vercel/turbo/cli/internal/turbopath"
+)
+
+// ManifestVersion is the version of the manifest format written by this version of turbo.
+// It must be incremented whenever a field is removed or its meaning changes.
+// Adding a field does not require a new version, readers ignore fields they don't know.
+const ManifestVersion = 1
+
+// Types of the entries in a Manifest
+const (
+	ManifestTypeFile    = "file"
+	ManifestTypeDir     = "dir"
+	ManifestTypeSymlink = "symlink"
+)
+
+// Manifest describes the contents of a cache artifact. It is written next to each artifact in
+// the local cache as <hash>-manifest.json, so that other tools can inspect artifacts without
+// extracting them.
+type Manifest struct {
+	Version int `json:"version"`
+	// Files are sorted by Path
+	Files []ManifestEntry `json:"files"`
+	// Signature, when the cache signs artifacts, is the signature of the artifact's contents
+	Signature string `json:"signature,omitempty"`
+}
+
+// ManifestEntry describes a single entry of a cache artifact
+type ManifestEntry struct {
+	// Path is the repo-relative, forward-slashed path of the entry, without a trailing slash
+	Path string `json:"path"`
+	// Type is one of "file", "dir" or "symlink"
+	Type string `json:"type"`
+	// Mode holds the permission bits of the entry, in octal
+	Mode string `json:"mode"`
+	// Size is the size of a file's contents in bytes
+	Size int64 `json:"size,omitempty"`
+	// Hash is the git blob hash of a file's contents, the same hash turbo uses for task inputs
+	Hash string `json:"hash,omitempty"`
+	// Linkname is the target of a symlink
+	Linkname string `json:"linkname,omitempty"`
+}
+
+// ReadManifest parses a manifest. Manifests written by a newer, incompatible version of turbo are rejected.
+func ReadManifest(r io.Reader) (*Manifest, error) {
+	var manifest Manifest
+	if err := json.NewDecoder(r).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("malformed cache manifest: %w", err)
+	}
+	if manifest.Version < 1 || manifest.Version > ManifestVersion {
+		return nil, fmt.Errorf("unsupported cache manifest version %v, this version of turbo supports up to version %v", manifest.Version, ManifestVersion)
+	}
+	return &manifest, nil
+}
+
+// Write serializes the manifest. The output is deterministic: files are sorted by path.
+func (m *Manifest) Write(w io.Writer) error {
+	sorted := &Manifest{
+		Version:   m.Version,
+		Files:     append([]ManifestEntry{}, m.Files...),
+		Signature: m.Signature,
+	}
+	if sorted.Version == 0 {
+		sorted.Version = ManifestVersion
+	}
+	sort.Slice(sorted.Files, func(i, j int) bool {
+		return sorted.Files[i].Path < sorted.Files[j].Path
+	})
+	contents, err := json.MarshalIndent(sorted, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(contents, '\n'))
+	return err
+}
+
+// Manifest returns the manifest of the files added so far to a CacheItem that is being created.
+func (ci *CacheItem) Manifest() *Manifest {
+	return &Manifest{
+		Version: ManifestVersion,
+		Files:   append([]ManifestEntry{}, ci.manifestEntries...),
+	}
+}
+
+// ReadManifest computes the manifest of an existing CacheItem by reading the whole archive.
+// It is used for artifacts that were stored without a manifest, e.g. by older versions of turbo.
+func (ci *CacheItem) ReadManifest() (*Manifest, error) {
+	var tr *tar.Reader
+	if ci.compressed {
+		zr := zstd.NewReader(ci.handle)
+		defer func() { _ = zr.Close() }()
+		tr = tar.NewReader(zr)
+	} else {
+		tr = tar.NewReader(ci.handle)
+	}
+
+	manifest := &Manifest{Version: ManifestVersion}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		hasher := newBlobHasher(header)
+		if hasher != nil {
+			if _, err := io.Copy(hasher, tr); err != nil {
+				return nil, err
+			}
+		}
+		entry, err := newManifestEntry(header, hasher)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Files = append(manifest.Files, entry)
+	}
+	sort.Slice(manifest.Files, func(i, j int) bool {
+		return manifest.Files[i].Path < manifest.Files[j].Path
+	})
+	return manifest, nil
+}
+
+// Missing returns the paths, sorted, of the entries of the manifest that don't exist under
+// anchor, e.g. because the artifact doesn't contain every file it was recorded with. Unlike
+// Verify, it doesn't read the contents of the files.
+func (m *Manifest) Missing(anchor turbopath.AbsoluteSystemPath) ([]string, error) {
+	missing := []string{}
+	for _, entry := range m.Files {
+		_, err := anchor.UntypedJoin(filepath.FromSlash(entry.Path)).Lstat()
+		if os.IsNotExist(err) {
+			missing = append(missing, entry.Path)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(missing)
+	return missing, nil
+}
+
+// Verify compares the files under anchor with the files of the manifest. It returns a
+// description of the first file that is missing or whose contents differ, or "" if they
+// all match. Files are hashed the same way task inputs are.
+func (m *Manifest) Verify(anchor turbopath.AbsoluteSystemPath) (string, error) {
+	var files []turbopath.AbsoluteSystemPath
+	for _, entry := range m.Files {
+		if entry.Type != ManifestTypeFile {
+			continue
+		}
+		file := anchor.UntypedJoin(filepath.FromSlash(entry.Path))
+		info, err := file.Lstat()
+		if os.IsNotExist(err) {
+			return fmt.Sprintf("%v is missing", entry.Path), nil
+		} else if err != nil {
+			return "", err
+		}
+		if !info.Mode().IsRegular() {
+			return fmt.Sprintf("%v is not a file", entry.Path), nil
+		}
+		files = append(files, file)
+	}
+	if len(files) == 0 {
+		return "", nil
+	}
+	hashes, err := hashing.GetHashableDeps(anchor, files)
+	if err != nil {
+		return "", err
+	}
+	for _, entry := range m.Files {
+		if entry.Type == ManifestTypeFile && hashes[turbopath.AnchoredUnixPath(entry.Path)] != entry.Hash {
+			return fmt.Sprintf("%v has changed", entry.Path), nil
+		}
+	}
+	return "", nil
+}
+
+// newBlobHasher returns a hash primed with the git blob header for a regular file's contents,
+// or nil for other types of entries.
+func newBlobHasher(header *tar.Header) hash.Hash {
+	if header.Typeflag != tar.TypeReg {
+		return nil
+	}
+	hasher := sha1.New()
+	hasher.Write([]byte("blob "))
+	hasher.Write([]byte(strconv.FormatInt(header.Size, 10)))
+	hasher.Write([]byte{0})
+	return hasher
+}
+
+func newManifestEntry(header *tar.Header, hasher hash.Hash) (ManifestEntry, error) {
+	entry := ManifestEntry{
+		Path: strings.TrimSuffix(header.Name, "/"),
+		Mode: fmt.Sprintf("%04o", header.Mode&0777),
+	}
+	switch header.Typeflag {
+	case tar.TypeReg:
+		entry.Type = ManifestTypeFile
+		entry.Size = header.Size
+		entry.Hash = hex.EncodeToString(hasher.Sum(nil))
+	case tar.TypeDir:
+		entry.Type = ManifestTypeDir
+	case tar.TypeSymlink:
+		entry.Type = ManifestTypeSymlink
+		entry.Linkname = header.Linkname
+	default:
+		return ManifestEntry{}, errUnsupportedFileType
+	}
+	return entry, nil
+}
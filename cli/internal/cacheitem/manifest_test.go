@@ -0,0 +1,149 @@
+package cacheitem
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/vercel/turbo/cli/internal/fs"
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"gotest.tools/v3/assert"
+)
+
+func TestManifest(t *testing.T) {
+	src := turbopath.AbsoluteSystemPath(t.TempDir())
+	assert.NilError(t, src.UntypedJoin("dist").MkdirAll(0755), "MkdirAll")
+	assert.NilError(t, src.UntypedJoin("dist", "index.js").WriteFile([]byte("module.exports = {}\n"), 0644), "WriteFile")
+	assert.NilError(t, src.UntypedJoin("dist", "link.js").Symlink("index.js"), "Symlink")
+
+	archivePath := turbopath.AbsoluteSystemPath(t.TempDir()).UntypedJoin("out.tar.zst")
+	cacheItem, err := Create(archivePath)
+	assert.NilError(t, err, "Create")
+	files := []turbopath.AnchoredSystemPath{
+		turbopath.AnchoredUnixPath("dist/").ToSystemPath(),
+		turbopath.AnchoredUnixPath("dist/link.js").ToSystemPath(),
+		turbopath.AnchoredUnixPath("dist/index.js").ToSystemPath(),
+	}
+	for _, file := range files {
+		assert.NilError(t, cacheItem.AddFile(src, file), "AddFile")
+	}
+	created := cacheItem.Manifest()
+	assert.NilError(t, cacheItem.Close(), "Close")
+
+	expectedHash, err := fs.GitLikeHashFile(src.UntypedJoin("dist", "index.js").ToString())
+	assert.NilError(t, err, "GitLikeHashFile")
+	assert.Equal(t, created.Version, ManifestVersion)
+	assert.Equal(t, len(created.Files), 3)
+	assert.Equal(t, created.Files[0].Path, "dist")
+	assert.Equal(t, created.Files[0].Type, ManifestTypeDir)
+	assert.Equal(t, created.Files[1].Type, ManifestTypeSymlink)
+	assert.Equal(t, created.Files[1].Linkname, "index.js")
+	assert.Equal(t, created.Files[2].Type, ManifestTypeFile)
+	assert.Equal(t, created.Files[2].Size, int64(20))
+	assert.Equal(t, created.Files[2].Hash, expectedHash)
+
+	// Writing sorts the files by path, and reading it back gives the same manifest
+	var written bytes.Buffer
+	assert.NilError(t, created.Write(&written), "Write")
+	roundTripped, err := ReadManifest(bytes.NewReader(written.Bytes()))
+	assert.NilError(t, err, "ReadManifest")
+	assert.Equal(t, roundTripped.Version, ManifestVersion)
+	assert.DeepEqual(t, roundTripped.Files, []ManifestEntry{created.Files[0], created.Files[2], created.Files[1]})
+
+	var rewritten bytes.Buffer
+	assert.NilError(t, roundTripped.Write(&rewritten), "Write")
+	assert.Equal(t, rewritten.String(), written.String())
+
+	// The manifest computed from the archive matches the one recorded during creation
+	opened, err := Open(archivePath)
+	assert.NilError(t, err, "Open")
+	read, err := opened.ReadManifest()
+	assert.NilError(t, err, "ReadManifest")
+	assert.NilError(t, opened.Close(), "Close")
+	assert.DeepEqual(t, read, roundTripped)
+}
+
+func TestReadManifestVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		wantErr  string
+	}{
+		{
+			name:     "current version",
+			contents: `{"version": 1, "files": [{"path": "dist/index.js", "type": "file", "mode": "0644", "unknownField": true}]}`,
+		},
+		{
+			name:     "newer version",
+			contents: `{"version": 2, "files": []}`,
+			wantErr:  "unsupported cache manifest version 2",
+		},
+		{
+			name:     "missing version",
+			contents: `{"files": []}`,
+			wantErr:  "unsupported cache manifest version 0",
+		},
+		{
+			name:     "malformed",
+			contents: `{"version": `,
+			wantErr:  "malformed cache manifest",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest, err := ReadManifest(strings.NewReader(tt.contents))
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NilError(t, err, "ReadManifest")
+			assert.Equal(t, manifest.Files[0].Path, "dist/index.js")
+		})
+	}
+}
+
+func TestManifestVerify(t *testing.T) {
+	anchor := turbopath.AbsoluteSystemPath(t.TempDir())
+	assert.NilError(t, anchor.UntypedJoin("dist").MkdirAll(0755), "MkdirAll")
+	assert.NilError(t, anchor.UntypedJoin("dist", "index.js").WriteFile([]byte("module.exports = {}\n"), 0644), "WriteFile")
+	assert.NilError(t, anchor.UntypedJoin("dist", "index.d.ts").WriteFile([]byte("export {}\n"), 0644), "WriteFile")
+
+	archivePath := turbopath.AbsoluteSystemPath(t.TempDir()).UntypedJoin("out.tar.zst")
+	cacheItem, err := Create(archivePath)
+	assert.NilError(t, err, "Create")
+	for _, file := range []string{"dist/", "dist/index.js", "dist/index.d.ts"} {
+		assert.NilError(t, cacheItem.AddFile(anchor, turbopath.AnchoredUnixPath(file).ToSystemPath()), "AddFile")
+	}
+	manifest := cacheItem.Manifest()
+	assert.NilError(t, cacheItem.Close(), "Close")
+
+	problem, err := manifest.Verify(anchor)
+	assert.NilError(t, err, "Verify")
+	assert.Equal(t, problem, "")
+
+	assert.NilError(t, anchor.UntypedJoin("dist", "index.js").WriteFile([]byte("corrupt"), 0644), "WriteFile")
+	problem, err = manifest.Verify(anchor)
+	assert.NilError(t, err, "Verify")
+	assert.Equal(t, problem, "dist/index.js has changed")
+
+	assert.NilError(t, anchor.UntypedJoin("dist", "index.d.ts").Remove(), "Remove")
+	problem, err = manifest.Verify(anchor)
+	assert.NilError(t, err, "Verify")
+	assert.Equal(t, problem, "dist/index.d.ts is missing")
+}
+
+func TestManifestMissing(t *testing.T) {
+	anchor := turbopath.AbsoluteSystemPath(t.TempDir())
+	assert.NilError(t, anchor.UntypedJoin("dist").MkdirAll(0755), "MkdirAll")
+	assert.NilError(t, anchor.UntypedJoin("dist", "index.js").WriteFile([]byte("module.exports = {}\n"), 0644), "WriteFile")
+	manifest := &Manifest{Files: []ManifestEntry{
+		{Path: "dist", Type: ManifestTypeDir},
+		{Path: "dist/index.js", Type: ManifestTypeFile},
+		{Path: "dist/types/index.d.ts", Type: ManifestTypeFile},
+		{Path: "dist/index.d.ts", Type: ManifestTypeFile},
+	}}
+
+	missing, err := manifest.Missing(anchor)
+	assert.NilError(t, err, "Missing")
+	assert.DeepEqual(t, missing, []string{"dist/index.d.ts", "dist/types/index.d.ts"})
+}
@@ -120,8 +120,9 @@ func writeEvent(ev *traceinternal.ViewerEvent) {
 }
 
 const (
-	begin = "B"
-	end   = "E"
+	begin   = "B"
+	end     = "E"
+	counter = "C"
 )
 
 // A PendingEvent represents an ongoing unit of work. The begin trace event has
@@ -178,6 +179,21 @@ func Event(name string) *PendingEvent {
 	}
 }
 
+// Counter logs the current value of a counter, e.g. the number of units of work
+// running concurrently. chrome://tracing graphs each counter over time.
+func Counter(name string, value int) {
+	if trace.file == nil {
+		return
+	}
+	writeEvent(&traceinternal.ViewerEvent{
+		Name:  name,
+		Phase: counter,
+		Pid:   trace.pid,
+		Time:  float64(time.Since(trace.start).Microseconds()),
+		Arg:   map[string]int{name: value},
+	})
+}
+
 // tids is a chrome://tracing thread id pool. Go does not permit accessing the
 // goroutine id, so we need to maintain our own identifier. The chrome://tracing
 // file format requires a numeric thread id, so we just increment whenever we
@@ -0,0 +1,52 @@
+package chrometracing
+
+import (
+	"time"
+
+	"github.com/google/chrometracing/traceinternal"
+)
+
+const complete = "X"
+
+// A CompleteEvent represents an ongoing unit of work that is written as a single
+// complete trace event, with its duration and arguments, once it is done. Like
+// PendingEvent, it holds a thread id for as long as it runs, so concurrent units
+// of work are shown on separate rows of chrome://tracing.
+type CompleteEvent struct {
+	name  string
+	tid   uint64
+	start time.Duration
+}
+
+// Complete starts a unit of work that is recorded once Done is called.
+func Complete(name string) *CompleteEvent {
+	if trace.file == nil {
+		return &CompleteEvent{}
+	}
+	return &CompleteEvent{
+		name:  name,
+		tid:   tid(),
+		start: time.Since(trace.start),
+	}
+}
+
+// Done writes the trace event for this unit of work, with the given arguments.
+func (ce *CompleteEvent) Done(args map[string]string) {
+	if ce == nil || ce.name == "" || trace.file == nil {
+		return
+	}
+	var arg interface{}
+	if len(args) > 0 {
+		arg = args
+	}
+	writeEvent(&traceinternal.ViewerEvent{
+		Name:  ce.name,
+		Phase: complete,
+		Pid:   trace.pid,
+		Tid:   ce.tid,
+		Time:  float64(ce.start.Microseconds()),
+		Dur:   float64((time.Since(trace.start) - ce.start).Microseconds()),
+		Arg:   arg,
+	})
+	releaseTid(ce.tid)
+}
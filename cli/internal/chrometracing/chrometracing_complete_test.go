@@ -0,0 +1,45 @@
+package chrometracing
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/google/chrometracing/traceinternal"
+	"gotest.tools/v3/assert"
+)
+
+func TestComplete(t *testing.T) {
+	t.Setenv("CHROMETRACING_DIR", t.TempDir())
+	EnableTracing()
+	t.Cleanup(func() { trace.file = nil })
+
+	build := Complete("web#build")
+	lint := Complete("web#lint")
+	lint.Done(nil)
+	build.Done(map[string]string{"hash": "abc123", "cache": "MISS"})
+	assert.NilError(t, Close(), "Close")
+
+	contents, err := os.ReadFile(Path())
+	assert.NilError(t, err, "ReadFile")
+	var events []traceinternal.ViewerEvent
+	assert.NilError(t, json.Unmarshal(contents, &events), "Unmarshal")
+
+	// The process name is written first, then each task once it is done
+	assert.Equal(t, len(events), 3)
+	assert.Equal(t, events[1].Name, "web#lint")
+	assert.Equal(t, events[1].Phase, complete)
+	assert.Equal(t, events[1].Arg, nil)
+	assert.Equal(t, events[2].Name, "web#build")
+	assert.Equal(t, events[2].Phase, complete)
+	assert.DeepEqual(t, events[2].Arg, map[string]interface{}{"hash": "abc123", "cache": "MISS"})
+	assert.Assert(t, events[2].Time <= events[1].Time)
+	// Tasks that run at the same time are on separate rows
+	assert.Assert(t, events[1].Tid != events[2].Tid)
+}
+
+func TestComplete_disabled(t *testing.T) {
+	event := &CompleteEvent{}
+	// Without a trace file, nothing is written
+	event.Done(map[string]string{"hash": "abc123"})
+}
@@ -35,11 +35,16 @@ type ApiClient struct {
 	teamSlug   string
 	// Whether or not to send preflight requests before uploads
 	usePreflight bool
+	// Whether requests fail with ErrOffline rather than reaching the network
+	offline bool
 }
 
 // ErrTooManyFailures is returned from remote cache API methods after `maxRemoteFailCount` errors have occurred
 var ErrTooManyFailures = errors.New("skipping HTTP Request, too many failures have occurred")
 
+// ErrOffline is returned from every request made after SetOffline, in place of reaching the network
+var ErrOffline = errors.New("network requests are disabled with --offline")
+
 // _maxRemoteFailCount is the number of failed requests before we stop trying to upload/download
 // artifacts to the remote cache
 const _maxRemoteFailCount = uint64(3)
@@ -90,6 +95,20 @@ func NewClient(remoteConfig RemoteConfig, logger hclog.Logger, turboVersion stri
 	return client
 }
 
+// SetOffline makes every request fail with ErrOffline, without reaching the network
+func (c *ApiClient) SetOffline() {
+	c.offline = true
+	c.HttpClient.HTTPClient.Transport = offlineTransport{}
+}
+
+// offlineTransport fails every request, rather than letting them wait for a network
+// that can't be reached to time out
+type offlineTransport struct{}
+
+func (offlineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, ErrOffline
+}
+
 // HasUser returns true if we have credentials for a user
 func (c *ApiClient) HasUser() bool {
 	return c.token != ""
@@ -112,6 +131,10 @@ func (c *ApiClient) GetTeamID() string {
 
 func (c *ApiClient) retryCachePolicy(resp *http.Response, err error) (bool, error) {
 	if err != nil {
+		if errors.Is(err, ErrOffline) {
+			// Retrying can't reach the network either
+			return false, err
+		}
 		if errors.As(err, &x509.UnknownAuthorityError{}) {
 			// Don't retry if the error was due to TLS cert verification failure.
 			atomic.AddUint64(&c.currentFailCount, 1)
@@ -164,6 +187,9 @@ func (c *ApiClient) checkRetry(ctx context.Context, resp *http.Response, err err
 // okToRequest returns nil if it's ok to make a request, and returns the error to
 // return to the caller if a request is not allowed
 func (c *ApiClient) okToRequest() error {
+	if c.offline {
+		return ErrOffline
+	}
 	if atomic.LoadUint64(&c.currentFailCount) < _maxRemoteFailCount {
 		return nil
 	}
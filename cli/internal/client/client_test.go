@@ -157,3 +157,30 @@ func Test_FetchWhenCachingDisabled(t *testing.T) {
 		t.Errorf("response got %v, want <nil>", resp)
 	}
 }
+
+func Test_FetchWhenOffline(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		w.WriteHeader(200)
+	}))
+	defer ts.Close()
+
+	remoteConfig := RemoteConfig{
+		TeamSlug: "my-team-slug",
+		APIURL:   ts.URL,
+		Token:    "my-token",
+	}
+	apiClient := NewClient(remoteConfig, hclog.Default(), "v1", Opts{})
+	apiClient.SetOffline()
+	resp, err := apiClient.FetchArtifact("hash")
+	if !errors.Is(err, ErrOffline) {
+		t.Errorf("expected offline error, got %v", err)
+	}
+	if resp != nil {
+		t.Errorf("response got %v, want <nil>", resp)
+	}
+	if requests != 0 {
+		t.Errorf("requests got %v, want 0", requests)
+	}
+}
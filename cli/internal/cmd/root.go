@@ -9,12 +9,15 @@ import (
 	"runtime/trace"
 
 	"github.com/pkg/errors"
+	"github.com/vercel/turbo/cli/internal/cachecmd"
 	"github.com/vercel/turbo/cli/internal/cmdutil"
 	"github.com/vercel/turbo/cli/internal/daemon"
+	"github.com/vercel/turbo/cli/internal/diffcmd"
 	"github.com/vercel/turbo/cli/internal/process"
 	"github.com/vercel/turbo/cli/internal/prune"
 	"github.com/vercel/turbo/cli/internal/run"
 	"github.com/vercel/turbo/cli/internal/signals"
+	"github.com/vercel/turbo/cli/internal/statscmd"
 	"github.com/vercel/turbo/cli/internal/turbostate"
 	"github.com/vercel/turbo/cli/internal/util"
 )
@@ -64,12 +67,20 @@ func RunWithArgs(args *turbostate.ParsedArgsFromRust, turboVersion string) int {
 	var execErr error
 	go func() {
 		command := args.Command
-		if command.Daemon != nil {
+		if command.Cache != nil {
+			execErr = cachecmd.ExecuteCache(helper, args)
+		} else if command.Daemon != nil {
 			execErr = daemon.ExecuteDaemon(ctx, helper, signalWatcher, args)
+		} else if command.Diff != nil {
+			execErr = diffcmd.ExecuteDiff(helper, args)
 		} else if command.Prune != nil {
 			execErr = prune.ExecutePrune(helper, args)
 		} else if command.Run != nil {
 			execErr = run.ExecuteRun(ctx, helper, signalWatcher, args)
+		} else if command.Stats != nil {
+			execErr = statscmd.ExecuteStats(helper, args)
+		} else if command.Watch != nil {
+			execErr = run.ExecuteWatch(ctx, helper, signalWatcher, args)
 		} else {
 			execErr = fmt.Errorf("unknown command: %v", command)
 		}
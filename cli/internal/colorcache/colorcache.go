@@ -1,6 +1,7 @@
 package colorcache
 
 import (
+	"hash/fnv"
 	"sync"
 
 	"github.com/vercel/turbo/cli/internal/util"
@@ -19,9 +20,12 @@ type ColorCache struct {
 	index      int
 	TermColors []colorFn
 	Cache      map[interface{}]colorFn
+	// sequential assigns colors in the order keys are first used, instead of from their hash
+	sequential bool
 }
 
-// New creates an instance of ColorCache with helpers for adding colors to task outputs
+// New creates an instance of ColorCache with helpers for adding colors to task outputs.
+// The color of each key comes from a hash of it, so a package keeps its color across runs.
 func New() *ColorCache {
 	return &ColorCache{
 		TermColors: getTerminalPackageColors(),
@@ -30,6 +34,14 @@ func New() *ColorCache {
 	}
 }
 
+// NewSequential creates a ColorCache that assigns each color in turn, in the order keys are
+// first used, so that the packages that start logging one after the other get different colors.
+func NewSequential() *ColorCache {
+	colorCache := New()
+	colorCache.sequential = true
+	return colorCache
+}
+
 // colorForKey returns a color function for a given package name
 func (c *ColorCache) colorForKey(key string) colorFn {
 	c.mu.Lock()
@@ -38,8 +50,16 @@ func (c *ColorCache) colorForKey(key string) colorFn {
 	if ok {
 		return colorFn
 	}
-	c.index++
-	colorFn = c.TermColors[util.PositiveMod(c.index, len(c.TermColors))] // 5 possible colors
+	var index int
+	if c.sequential {
+		c.index++
+		index = c.index
+	} else {
+		hash := fnv.New32a()
+		_, _ = hash.Write([]byte(key))
+		index = int(hash.Sum32() % uint32(len(c.TermColors)))
+	}
+	colorFn = c.TermColors[util.PositiveMod(index, len(c.TermColors))] // 5 possible colors
 	c.Cache[key] = colorFn
 	return colorFn
 }
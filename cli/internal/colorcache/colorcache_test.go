@@ -0,0 +1,46 @@
+package colorcache
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+	"gotest.tools/v3/assert"
+)
+
+func forceColor(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = false
+	t.Cleanup(func() { color.NoColor = noColor })
+}
+
+func TestPrefixWithColor_hashed(t *testing.T) {
+	forceColor(t)
+	keys := []string{"web", "docs", "ui", "api", "eslint-config-custom", "tsconfig"}
+
+	first := New()
+	colored := make(map[string]string, len(keys))
+	for _, key := range keys {
+		colored[key] = first.PrefixWithColor(key, key)
+	}
+
+	// A key keeps its color whatever order the keys are used in
+	second := New()
+	for i := len(keys) - 1; i >= 0; i-- {
+		assert.Equal(t, second.PrefixWithColor(keys[i], keys[i]), colored[keys[i]])
+	}
+	assert.Equal(t, first.PrefixWithColor("web", ""), "")
+}
+
+func TestPrefixWithColor_sequential(t *testing.T) {
+	forceColor(t)
+	colorCache := NewSequential()
+	termColors := getTerminalPackageColors()
+
+	// Colors are given out in turn, starting from the second one, and a key keeps its color
+	keys := []string{"web", "docs", "ui", "api", "tsconfig", "eslint"}
+	for i, key := range keys {
+		want := termColors[(i+1)%len(termColors)]("%s: ", key)
+		assert.Equal(t, colorCache.PrefixWithColor(key, key), want)
+	}
+	assert.Equal(t, colorCache.PrefixWithColor("web", "web"), termColors[1]("%s: ", "web"))
+}
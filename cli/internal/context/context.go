@@ -161,8 +161,10 @@ func SinglePackageGraph(repoRoot turbopath.AbsoluteSystemPath, rootPackageJSON *
 	return c, nil
 }
 
-// BuildPackageGraph constructs a Context instance with information about the package dependency graph
-func BuildPackageGraph(repoRoot turbopath.AbsoluteSystemPath, rootPackageJSON *fs.PackageJSON) (*Context, error) {
+// BuildPackageGraph constructs a Context instance with information about the package dependency graph.
+// If lockfileCacheDir is set, the parsed lockfile is cached in it.
+// If workspaceCacheDir is set, the discovered workspaces are cached in it.
+func BuildPackageGraph(repoRoot turbopath.AbsoluteSystemPath, rootPackageJSON *fs.PackageJSON, lockfileCacheDir turbopath.AbsoluteSystemPath, workspaceCacheDir turbopath.AbsoluteSystemPath) (*Context, error) {
 	c := &Context{}
 	rootpath := repoRoot.ToStringDuringMigration()
 	c.WorkspaceInfos = workspace.Catalog{
@@ -179,7 +181,7 @@ func BuildPackageGraph(repoRoot turbopath.AbsoluteSystemPath, rootPackageJSON *f
 	}
 	c.PackageManager = packageManager
 
-	if lockfile, err := c.PackageManager.ReadLockfile(repoRoot); err != nil {
+	if lockfile, err := c.PackageManager.ReadLockfile(repoRoot, lockfileCacheDir); err != nil {
 		warnings.append(err)
 	} else {
 		c.Lockfile = lockfile
@@ -192,7 +194,7 @@ func BuildPackageGraph(repoRoot turbopath.AbsoluteSystemPath, rootPackageJSON *f
 
 	// Get the workspaces from the package manager.
 	// workspaces are absolute paths
-	workspaces, err := c.PackageManager.GetWorkspaces(repoRoot)
+	workspaces, err := c.PackageManager.GetWorkspacesCached(repoRoot, workspaceCacheDir)
 
 	if err != nil {
 		return nil, fmt.Errorf("workspace configuration error: %w", err)
@@ -135,7 +135,7 @@ func TestBuildPackageGraph_DuplicateNames(t *testing.T) {
 		PackageManager: "pnpm@7.15.0",
 	}
 
-	_, actualErr := BuildPackageGraph(path, pkgJSON)
+	_, actualErr := BuildPackageGraph(path, pkgJSON, "", "")
 
 	// Not asserting the full error message, because it includes a path with slashes and backslashes
 	// getting the regex incantation to check that is not worth it.
@@ -6,7 +6,9 @@ import (
 	"os"
 	"sort"
 	"strings"
+	"sync"
 
+	"github.com/vercel/turbo/cli/internal/chrometracing"
 	"github.com/vercel/turbo/cli/internal/fs"
 	"github.com/vercel/turbo/cli/internal/graph"
 	"github.com/vercel/turbo/cli/internal/util"
@@ -69,11 +71,67 @@ type EngineExecutionOptions struct {
 	Parallel bool
 	// Concurrency is the number of concurrent tasks that can be executed
 	Concurrency int
+	// SerialWithinPackage is whether to run at most one task of each package at a time.
+	// By default, tasks of the same package that don't depend on each other run concurrently.
+	// PersistentTasks are exempt, since they would keep the package's other tasks from ever
+	// starting.
+	SerialWithinPackage bool
+	// PersistentTasks are the IDs of the tasks that never exit, such as dev servers
+	PersistentTasks util.Set
+	// ConcurrencyGroups limits how many tasks of each concurrency group run at a time, on top
+	// of Concurrency. The limits also apply when running in parallel.
+	ConcurrencyGroups map[string]int
+	// TaskConcurrencyGroups maps the IDs of the tasks that are in a concurrency group to the group
+	TaskConcurrencyGroups map[string]string
+	// ParallelUnsafeTasks are the IDs of the tasks that must not run at the same time as each
+	// other. They run one at a time, also when running in parallel, while other tasks keep
+	// running alongside them.
+	ParallelUnsafeTasks util.Set
+	// TaskPriorities maps the IDs of tasks that start ahead of other tasks ready to run to
+	// their rank. Tasks with a lower rank start first, and tasks without a rank start last.
+	// Dependencies always finish before the tasks that depend on them start.
+	TaskPriorities map[string]int
 }
 
 // Execute executes the pipeline, constructing an internal task graph and walking it accordingly.
+// Tasks start as soon as their dependencies have finished, up to the concurrency limit.
 func (e *Engine) Execute(visitor Visitor, opts EngineExecutionOptions) []error {
 	var sema = util.NewSemaphore(opts.Concurrency)
+	// When some tasks are prioritized, slots go to the waiting task with the lowest rank
+	var prioritySema *prioritySemaphore
+	if len(opts.TaskPriorities) > 0 && !opts.Parallel {
+		prioritySema = newPrioritySemaphore(opts.Concurrency)
+	}
+
+	// running counts the tasks being visited, to show the actual concurrency in the trace
+	var running struct {
+		sync.Mutex
+		count int
+	}
+
+	// The locks are created up front so that the walk only reads the map
+	packageLocks := make(map[string]*sync.Mutex)
+	if opts.SerialWithinPackage {
+		for _, v := range e.TaskGraph.Vertices() {
+			taskID := dag.VertexName(v)
+			if strings.Contains(taskID, ROOT_NODE_NAME) {
+				continue
+			}
+			pkg, _ := util.GetPackageTaskFromId(taskID)
+			if _, ok := packageLocks[pkg]; !ok {
+				packageLocks[pkg] = &sync.Mutex{}
+			}
+		}
+	}
+
+	// parallelUnsafeLock is held by the ParallelUnsafeTasks while they run
+	var parallelUnsafeLock sync.Mutex
+
+	groupSemas := make(map[string]util.Semaphore, len(opts.ConcurrencyGroups))
+	for group, limit := range opts.ConcurrencyGroups {
+		groupSemas[group] = util.NewSemaphore(limit)
+	}
+
 	return e.TaskGraph.Walk(func(v dag.Vertex) error {
 		// Each vertex in the graph is a taskID (package#task format)
 		taskID := dag.VertexName(v)
@@ -83,12 +141,50 @@ func (e *Engine) Execute(visitor Visitor, opts EngineExecutionOptions) []error {
 			return nil
 		}
 
+		// Wait for the package's other tasks before taking a slot, so that waiting
+		// doesn't keep tasks of other packages from running
+		if opts.SerialWithinPackage && !opts.PersistentTasks.Includes(taskID) {
+			pkg, _ := util.GetPackageTaskFromId(taskID)
+			packageLock := packageLocks[pkg]
+			packageLock.Lock()
+			defer packageLock.Unlock()
+		}
+
+		// Likewise, wait for the other tasks that aren't parallel safe, and for a slot in the
+		// task's concurrency group, first
+		if opts.ParallelUnsafeTasks.Includes(taskID) {
+			parallelUnsafeLock.Lock()
+			defer parallelUnsafeLock.Unlock()
+		}
+		if groupSema, ok := groupSemas[opts.TaskConcurrencyGroups[taskID]]; ok {
+			groupSema.Acquire()
+			defer groupSema.Release()
+		}
+
 		// Acquire the semaphore unless parallel
-		if !opts.Parallel {
+		if prioritySema != nil {
+			rank, ok := opts.TaskPriorities[taskID]
+			if !ok {
+				rank = len(opts.TaskPriorities)
+			}
+			prioritySema.acquire(rank)
+			defer prioritySema.release()
+		} else if !opts.Parallel {
 			sema.Acquire()
 			defer sema.Release()
 		}
 
+		running.Lock()
+		running.count++
+		chrometracing.Counter("running tasks", running.count)
+		running.Unlock()
+		defer func() {
+			running.Lock()
+			running.count--
+			chrometracing.Counter("running tasks", running.count)
+			running.Unlock()
+		}()
+
 		return visitor(taskID)
 	})
 }
@@ -247,7 +343,18 @@ func (e *Engine) Prepare(options *EngineBuildingOptions) error {
 
 		taskDefinition, err := fs.MergeTaskDefinitions(taskDefinitions)
 		if err != nil {
-			return err
+			return fmt.Errorf("%v: %w", taskID, err)
+		}
+
+		// Env groups can only be defined in the root turbo.json, but can be used by any definition
+		if len(taskDefinition.EnvGroups) > 0 {
+			rootTurboJSON, err := e.completeGraph.GetTurboConfigFromWorkspace(util.RootPkgName, e.isSinglePackage)
+			if err != nil {
+				return err
+			}
+			if err := rootTurboJSON.ResolveEnvGroups(taskDefinition); err != nil {
+				return fmt.Errorf("%v: %w", taskID, err)
+			}
 		}
 
 		// Skip this iteration of the loop if we've already seen this taskID
@@ -502,18 +609,8 @@ func (e *Engine) getTaskDefinitionChain(taskID string, taskName string) ([]fs.Bo
 		} else {
 			// Run some validations on a workspace turbo.json. Note that these validations are on
 			// the whole struct, and not relevant to the taskID we're looking at right now.
-			validationErrors := workspaceTurboJSON.Validate([]fs.TurboJSONValidation{
-				validateNoPackageTaskSyntax,
-				validateExtends,
-			})
-
-			if len(validationErrors) > 0 {
-				fullError := errors.New("Invalid turbo.json")
-				for _, validationErr := range validationErrors {
-					fullError = fmt.Errorf("%w\n - %s", fullError, validationErr)
-				}
-
-				return nil, fullError
+			if err := ValidateWorkspaceTurboJSON(workspaceTurboJSON); err != nil {
+				return nil, err
 			}
 
 			// If there are no errors, we can (try to) add the TaskDefinition to our list.
@@ -530,6 +627,26 @@ func (e *Engine) getTaskDefinitionChain(taskID string, taskName string) ([]fs.Bo
 	return taskDefinitions, nil
 }
 
+// ValidateWorkspaceTurboJSON returns an error listing the problems with the turbo.json of a
+// workspace other than the root, which has to extend the root turbo.json
+func ValidateWorkspaceTurboJSON(turboJSON *fs.TurboJSON) error {
+	validationErrors := turboJSON.Validate([]fs.TurboJSONValidation{
+		validateNoPackageTaskSyntax,
+		validateExtends,
+		validateNoEnvGroups,
+	})
+
+	if len(validationErrors) > 0 {
+		fullError := errors.New("Invalid turbo.json")
+		for _, validationErr := range validationErrors {
+			fullError = fmt.Errorf("%w\n - %s", fullError, validationErr)
+		}
+
+		return fullError
+	}
+	return nil
+}
+
 func validateNoPackageTaskSyntax(turboJSON *fs.TurboJSON) []error {
 	errors := []error{}
 
@@ -543,6 +660,13 @@ func validateNoPackageTaskSyntax(turboJSON *fs.TurboJSON) []error {
 	return errors
 }
 
+func validateNoEnvGroups(turboJSON *fs.TurboJSON) []error {
+	if len(turboJSON.EnvGroups) > 0 {
+		return []error{fmt.Errorf("\"envGroups\" can only be defined in the root turbo.json")}
+	}
+	return nil
+}
+
 func validateExtends(turboJSON *fs.TurboJSON) []error {
 	extendErrors := []error{}
 	extends := turboJSON.Extends
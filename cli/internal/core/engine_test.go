@@ -0,0 +1,221 @@
+package core
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pyr-sh/dag"
+	"github.com/vercel/turbo/cli/internal/util"
+	"gotest.tools/v3/assert"
+)
+
+// newTestEngine returns an Engine whose task graph holds the given independent tasks
+func newTestEngine(taskIDs ...string) *Engine {
+	engine := &Engine{TaskGraph: &dag.AcyclicGraph{}}
+	engine.TaskGraph.Add(ROOT_NODE_NAME)
+	for _, taskID := range taskIDs {
+		engine.TaskGraph.Add(taskID)
+		engine.TaskGraph.Connect(dag.BasicEdge(taskID, ROOT_NODE_NAME))
+	}
+	return engine
+}
+
+// concurrencyTracker records how many tasks, overall and per package, run at the same time
+type concurrencyTracker struct {
+	mu            sync.Mutex
+	running       int
+	maxRunning    int
+	pkgRunning    map[string]int
+	maxPkgRunning map[string]int
+}
+
+func newConcurrencyTracker() *concurrencyTracker {
+	return &concurrencyTracker{
+		pkgRunning:    make(map[string]int),
+		maxPkgRunning: make(map[string]int),
+	}
+}
+
+func (ct *concurrencyTracker) start(taskID string) {
+	pkg, _ := util.GetPackageTaskFromId(taskID)
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	ct.running++
+	ct.pkgRunning[pkg]++
+	if ct.running > ct.maxRunning {
+		ct.maxRunning = ct.running
+	}
+	if ct.pkgRunning[pkg] > ct.maxPkgRunning[pkg] {
+		ct.maxPkgRunning[pkg] = ct.pkgRunning[pkg]
+	}
+}
+
+func (ct *concurrencyTracker) done(taskID string) {
+	pkg, _ := util.GetPackageTaskFromId(taskID)
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	ct.running--
+	ct.pkgRunning[pkg]--
+}
+
+func TestExecute_IndependentTasksInPackageRunConcurrently(t *testing.T) {
+	taskIDs := []string{"web#build", "web#lint", "web#test"}
+	engine := newTestEngine(taskIDs...)
+
+	// Every task waits for all of them to have started, which only finishes if they overlap
+	var started sync.WaitGroup
+	started.Add(len(taskIDs))
+	allStarted := make(chan struct{})
+	go func() {
+		started.Wait()
+		close(allStarted)
+	}()
+	errs := engine.Execute(func(taskID string) error {
+		started.Done()
+		select {
+		case <-allStarted:
+			return nil
+		case <-time.After(10 * time.Second):
+			t.Errorf("%v: the package's other tasks did not run concurrently", taskID)
+			return nil
+		}
+	}, EngineExecutionOptions{Concurrency: len(taskIDs)})
+	assert.Equal(t, len(errs), 0)
+}
+
+func TestExecute_ConcurrencyLimit(t *testing.T) {
+	engine := newTestEngine("web#build", "web#lint", "web#test", "docs#build", "docs#lint")
+	tracker := newConcurrencyTracker()
+	errs := engine.Execute(func(taskID string) error {
+		tracker.start(taskID)
+		time.Sleep(10 * time.Millisecond)
+		tracker.done(taskID)
+		return nil
+	}, EngineExecutionOptions{Concurrency: 2})
+	assert.Equal(t, len(errs), 0)
+	assert.Assert(t, tracker.maxRunning <= 2, "ran %v tasks at once", tracker.maxRunning)
+}
+
+func TestExecute_SerialWithinPackage(t *testing.T) {
+	engine := newTestEngine("web#build", "web#lint", "web#test", "docs#build", "docs#lint")
+	tracker := newConcurrencyTracker()
+	ran := make(chan string, 5)
+	errs := engine.Execute(func(taskID string) error {
+		tracker.start(taskID)
+		time.Sleep(10 * time.Millisecond)
+		tracker.done(taskID)
+		ran <- taskID
+		return nil
+	}, EngineExecutionOptions{Concurrency: 10, SerialWithinPackage: true})
+	assert.Equal(t, len(errs), 0)
+	assert.Equal(t, len(ran), 5)
+	assert.Equal(t, tracker.maxPkgRunning["web"], 1)
+	assert.Equal(t, tracker.maxPkgRunning["docs"], 1)
+}
+
+func TestExecute_SerialWithinPackagePersistentTasks(t *testing.T) {
+	engine := newTestEngine("web#dev", "web#build")
+	// The persistent task only exits once the package's other task has run, which would
+	// never happen if it held the package's lock
+	built := make(chan struct{})
+	errs := engine.Execute(func(taskID string) error {
+		if taskID == "web#build" {
+			close(built)
+			return nil
+		}
+		select {
+		case <-built:
+		case <-time.After(10 * time.Second):
+			t.Errorf("%v kept the other tasks of its package from running", taskID)
+		}
+		return nil
+	}, EngineExecutionOptions{
+		Concurrency:         10,
+		SerialWithinPackage: true,
+		PersistentTasks:     util.SetFromStrings([]string{"web#dev"}),
+	})
+	assert.Equal(t, len(errs), 0)
+}
+
+func TestExecute_ConcurrencyGroups(t *testing.T) {
+	engine := newTestEngine("web#e2e", "docs#e2e", "admin#e2e", "web#lint", "docs#lint")
+	heavy := newConcurrencyTracker()
+	errs := engine.Execute(func(taskID string) error {
+		_, task := util.GetPackageTaskFromId(taskID)
+		if task == "e2e" {
+			heavy.start(taskID)
+			defer heavy.done(taskID)
+		}
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}, EngineExecutionOptions{
+		Concurrency:       10,
+		ConcurrencyGroups: map[string]int{"heavy": 1},
+		TaskConcurrencyGroups: map[string]string{
+			"web#e2e":   "heavy",
+			"docs#e2e":  "heavy",
+			"admin#e2e": "heavy",
+		},
+	})
+	assert.Equal(t, len(errs), 0)
+	assert.Equal(t, heavy.maxRunning, 1)
+}
+
+func TestExecute_TaskPriorities(t *testing.T) {
+	engine := newTestEngine("web#build", "web#lint", "web#test", "docs#build", "docs#lint")
+	var mu sync.Mutex
+	var order []string
+	errs := engine.Execute(func(taskID string) error {
+		mu.Lock()
+		order = append(order, taskID)
+		first := len(order) == 1
+		mu.Unlock()
+		// Hold the only slot until the other tasks are waiting for it
+		if first {
+			time.Sleep(50 * time.Millisecond)
+		}
+		return nil
+	}, EngineExecutionOptions{
+		Concurrency: 1,
+		TaskPriorities: map[string]int{
+			"docs#lint": 0,
+			"web#test":  1,
+		},
+	})
+	assert.Equal(t, len(errs), 0)
+	assert.Equal(t, len(order), 5)
+
+	// Whichever task got the slot first, the prioritized tasks run next, in the order of their ranks
+	want := []string{"docs#lint", "web#test"}
+	if order[0] == "docs#lint" {
+		want = []string{"web#test"}
+	} else if order[0] == "web#test" {
+		want = []string{"docs#lint"}
+	}
+	assert.DeepEqual(t, order[1:1+len(want)], want)
+}
+
+func TestExecute_ParallelUnsafeTasks(t *testing.T) {
+	engine := newTestEngine("web#dev", "docs#dev", "admin#dev", "web#lint", "docs#lint")
+	unsafe := newConcurrencyTracker()
+	all := newConcurrencyTracker()
+	errs := engine.Execute(func(taskID string) error {
+		all.start(taskID)
+		defer all.done(taskID)
+		_, task := util.GetPackageTaskFromId(taskID)
+		if task == "dev" {
+			unsafe.start(taskID)
+			defer unsafe.done(taskID)
+		}
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	}, EngineExecutionOptions{
+		Parallel:            true,
+		Concurrency:         1,
+		ParallelUnsafeTasks: util.SetFromStrings([]string{"web#dev", "docs#dev", "admin#dev"}),
+	})
+	assert.Equal(t, len(errs), 0)
+	assert.Equal(t, unsafe.maxRunning, 1)
+	assert.Assert(t, all.maxRunning > 1, "the other tasks still run in parallel")
+}
@@ -0,0 +1,83 @@
+package core
+
+import (
+	"container/heap"
+	"sync"
+)
+
+// prioritySemaphore limits how many tasks run at a time, like util.Semaphore, but hands
+// each slot that frees up to the waiting task with the lowest rank. Tasks of the same
+// rank get a slot in the order they started waiting.
+type prioritySemaphore struct {
+	mu        sync.Mutex
+	available int
+	waiting   waitQueue
+	// arrivals orders waiters of the same rank
+	arrivals int
+}
+
+func newPrioritySemaphore(n int) *prioritySemaphore {
+	if n <= 0 {
+		panic("semaphore with limit <=0")
+	}
+	return &prioritySemaphore{available: n}
+}
+
+// acquire blocks until a slot is available for a task of the given rank
+func (s *prioritySemaphore) acquire(rank int) {
+	s.mu.Lock()
+	if s.available > 0 && s.waiting.Len() == 0 {
+		s.available--
+		s.mu.Unlock()
+		return
+	}
+	w := &waiter{rank: rank, arrival: s.arrivals, ready: make(chan struct{})}
+	s.arrivals++
+	heap.Push(&s.waiting, w)
+	s.mu.Unlock()
+	<-w.ready
+}
+
+// release returns a slot, handing it directly to the next waiter if there is one
+func (s *prioritySemaphore) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.waiting.Len() > 0 {
+		w := heap.Pop(&s.waiting).(*waiter)
+		close(w.ready)
+		return
+	}
+	s.available++
+}
+
+type waiter struct {
+	rank    int
+	arrival int
+	ready   chan struct{}
+}
+
+// waitQueue implements heap.Interface, ordering waiters by rank and then by arrival
+type waitQueue []*waiter
+
+func (q waitQueue) Len() int { return len(q) }
+
+func (q waitQueue) Less(i, j int) bool {
+	if q[i].rank != q[j].rank {
+		return q[i].rank < q[j].rank
+	}
+	return q[i].arrival < q[j].arrival
+}
+
+func (q waitQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *waitQueue) Push(x interface{}) {
+	*q = append(*q, x.(*waiter))
+}
+
+func (q *waitQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	w := old[n-1]
+	*q = old[:n-1]
+	return w
+}
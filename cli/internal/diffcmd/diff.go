@@ -0,0 +1,340 @@
+// Package diffcmd implements the `turbo diff` command
+package diffcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/vercel/turbo/cli/internal/cache"
+	"github.com/vercel/turbo/cli/internal/cmdutil"
+	"github.com/vercel/turbo/cli/internal/fs"
+	"github.com/vercel/turbo/cli/internal/runsummary"
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"github.com/vercel/turbo/cli/internal/turbostate"
+)
+
+// _maxListed is how many of the changed files, env vars or dependencies are listed for a
+// task, so that a change to every file doesn't flood the report
+const _maxListed = 5
+
+// summary is the part of a run summary, written by `turbo run --dry=json` or --summary-path,
+// that is compared
+type summary struct {
+	GlobalHashSummary *globalHashSummary `json:"globalHashSummary"`
+	Tasks             []*taskSummary     `json:"tasks"`
+}
+
+type globalHashSummary struct {
+	GlobalFileHashMap    map[string]string `json:"globalFileHashMap"`
+	RootExternalDepsHash string            `json:"rootExternalDepsHash"`
+	GlobalCacheKey       string            `json:"globalCacheKey"`
+	Pipeline             json.RawMessage   `json:"pipeline"`
+}
+
+type taskSummary struct {
+	TaskID string `json:"taskId"`
+	// Task identifies the tasks of single package summaries, which have no taskId
+	Task                   string                       `json:"task"`
+	Hash                   string                       `json:"hash"`
+	CacheState             cache.ItemStatus             `json:"cacheState"`
+	Command                string                       `json:"command"`
+	ResolvedTaskDefinition json.RawMessage              `json:"resolvedTaskDefinition"`
+	ExpandedInputs         map[string]string            `json:"expandedInputs"`
+	DependencyHashes       map[string]string            `json:"dependencyHashes"`
+	EnvVars                runsummary.TaskEnvVarSummary `json:"environmentVariables"`
+}
+
+func (t *taskSummary) id() string {
+	if t.TaskID != "" {
+		return t.TaskID
+	}
+	return t.Task
+}
+
+// envVars maps the names of the env vars in the task's hash to their hashed values
+func (t *taskSummary) envVars() map[string]string {
+	envVars := make(map[string]string)
+	for _, list := range [][]string{t.EnvVars.Configured, t.EnvVars.Inferred, t.EnvVars.Global} {
+		for _, pair := range list {
+			name, value, _ := strings.Cut(pair, "=")
+			envVars[name] = value
+		}
+	}
+	return envVars
+}
+
+// changes are the keys of a map that were added, removed or given a new value
+type changes struct {
+	added   []string
+	removed []string
+	changed []string
+}
+
+func (c changes) empty() bool {
+	return len(c.added) == 0 && len(c.removed) == 0 && len(c.changed) == 0
+}
+
+// describe lists the changes, such as "changed a, b; added c"
+func (c changes) describe() string {
+	parts := []string{}
+	for _, group := range []struct {
+		verb  string
+		names []string
+	}{{"changed", c.changed}, {"added", c.added}, {"removed", c.removed}} {
+		if len(group.names) > 0 {
+			parts = append(parts, fmt.Sprintf("%v %v", group.verb, listNames(group.names)))
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+func listNames(names []string) string {
+	if len(names) <= _maxListed {
+		return strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%v and %v more", strings.Join(names[:_maxListed], ", "), len(names)-_maxListed)
+}
+
+func diffMaps(before map[string]string, after map[string]string) changes {
+	c := changes{added: []string{}, removed: []string{}, changed: []string{}}
+	for key, value := range after {
+		if beforeValue, ok := before[key]; !ok {
+			c.added = append(c.added, key)
+		} else if beforeValue != value {
+			c.changed = append(c.changed, key)
+		}
+	}
+	for key := range before {
+		if _, ok := after[key]; !ok {
+			c.removed = append(c.removed, key)
+		}
+	}
+	sort.Strings(c.added)
+	sort.Strings(c.removed)
+	sort.Strings(c.changed)
+	return c
+}
+
+// taskDiff is how a task changed between two summaries
+type taskDiff struct {
+	taskID string
+	before *taskSummary
+	after  *taskSummary
+
+	commandChanged    bool
+	definitionChanged bool
+	inputs            changes
+	envVars           changes
+	dependencies      changes
+}
+
+// summaryDiff is how two summaries differ. Tasks whose hashes and cache states didn't
+// change are only counted.
+type summaryDiff struct {
+	// global are the inputs of the global hash that changed, which change every task's hash
+	global    []string
+	added     []string
+	removed   []string
+	changed   []*taskDiff
+	unchanged int
+}
+
+// ExecuteDiff executes the `diff` command.
+func ExecuteDiff(helper *cmdutil.Helper, args *turbostate.ParsedArgsFromRust) error {
+	base, err := helper.GetCmdBase(args)
+	if err != nil {
+		return err
+	}
+	if args.TestRun {
+		base.UI.Info("Diff test run successful")
+		return nil
+	}
+	before, err := readSummary(fs.ResolveUnknownPath(base.RepoRoot, args.Command.Diff.Before))
+	if err != nil {
+		return err
+	}
+	after, err := readSummary(fs.ResolveUnknownPath(base.RepoRoot, args.Command.Diff.After))
+	if err != nil {
+		return err
+	}
+	return printDiff(os.Stdout, diffSummaries(before, after))
+}
+
+func readSummary(path turbopath.AbsoluteSystemPath) (*summary, error) {
+	contents, err := path.ReadFile()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run summary: %w", err)
+	}
+	s := &summary{}
+	if err := json.Unmarshal(contents, s); err != nil {
+		return nil, fmt.Errorf("failed to read run summary %v: %w", path, err)
+	}
+	if s.Tasks == nil {
+		return nil, fmt.Errorf("%v is not a run summary, write one with --dry=json or --summary-path", path)
+	}
+	return s, nil
+}
+
+func diffSummaries(before *summary, after *summary) *summaryDiff {
+	diff := &summaryDiff{global: diffGlobalHash(before.GlobalHashSummary, after.GlobalHashSummary)}
+	beforeTasks := make(map[string]*taskSummary, len(before.Tasks))
+	for _, task := range before.Tasks {
+		beforeTasks[task.id()] = task
+	}
+	afterTasks := make(map[string]*taskSummary, len(after.Tasks))
+	for _, task := range after.Tasks {
+		afterTasks[task.id()] = task
+	}
+
+	for taskID, task := range afterTasks {
+		beforeTask, ok := beforeTasks[taskID]
+		if !ok {
+			diff.added = append(diff.added, taskID)
+			continue
+		}
+		if beforeTask.Hash == task.Hash && beforeTask.CacheState == task.CacheState {
+			diff.unchanged++
+			continue
+		}
+		diff.changed = append(diff.changed, &taskDiff{
+			taskID:            taskID,
+			before:            beforeTask,
+			after:             task,
+			commandChanged:    beforeTask.Command != task.Command,
+			definitionChanged: !jsonEqual(beforeTask.ResolvedTaskDefinition, task.ResolvedTaskDefinition),
+			inputs:            diffMaps(beforeTask.ExpandedInputs, task.ExpandedInputs),
+			envVars:           diffMaps(beforeTask.envVars(), task.envVars()),
+			dependencies:      diffMaps(beforeTask.DependencyHashes, task.DependencyHashes),
+		})
+	}
+	for taskID := range beforeTasks {
+		if _, ok := afterTasks[taskID]; !ok {
+			diff.removed = append(diff.removed, taskID)
+		}
+	}
+	sort.Strings(diff.added)
+	sort.Strings(diff.removed)
+	sort.Slice(diff.changed, func(i, j int) bool { return diff.changed[i].taskID < diff.changed[j].taskID })
+	return diff
+}
+
+// diffGlobalHash describes the inputs of the global hash that changed. Single package
+// summaries have no global hash summary, and nothing is reported for them.
+func diffGlobalHash(before *globalHashSummary, after *globalHashSummary) []string {
+	if before == nil || after == nil {
+		return nil
+	}
+	global := []string{}
+	if files := diffMaps(before.GlobalFileHashMap, after.GlobalFileHashMap); !files.empty() {
+		global = append(global, fmt.Sprintf("global dependencies: %v", files.describe()))
+	}
+	if before.RootExternalDepsHash != after.RootExternalDepsHash {
+		global = append(global, "the root package's external dependencies")
+	}
+	if before.GlobalCacheKey != after.GlobalCacheKey {
+		global = append(global, "the global cache key")
+	}
+	if !jsonEqual(before.Pipeline, after.Pipeline) {
+		global = append(global, "the pipeline in turbo.json")
+	}
+	return global
+}
+
+// jsonEqual is whether two JSON values are the same, regardless of their whitespace
+func jsonEqual(a json.RawMessage, b json.RawMessage) bool {
+	var compactA, compactB bytes.Buffer
+	if json.Compact(&compactA, a) != nil || json.Compact(&compactB, b) != nil {
+		return bytes.Equal(a, b)
+	}
+	return bytes.Equal(compactA.Bytes(), compactB.Bytes())
+}
+
+func printDiff(w io.Writer, diff *summaryDiff) error {
+	var b strings.Builder
+	if len(diff.global) > 0 {
+		fmt.Fprintln(&b, "Global hash inputs changed, which changes the hash of every task:")
+		for _, change := range diff.global {
+			fmt.Fprintf(&b, "  %v\n", change)
+		}
+		fmt.Fprintln(&b)
+	}
+	if len(diff.added) > 0 {
+		fmt.Fprintln(&b, "Added tasks:")
+		for _, taskID := range diff.added {
+			fmt.Fprintf(&b, "  %v\n", taskID)
+		}
+		fmt.Fprintln(&b)
+	}
+	if len(diff.removed) > 0 {
+		fmt.Fprintln(&b, "Removed tasks:")
+		for _, taskID := range diff.removed {
+			fmt.Fprintf(&b, "  %v\n", taskID)
+		}
+		fmt.Fprintln(&b)
+	}
+	if len(diff.changed) > 0 {
+		fmt.Fprintln(&b, "Changed tasks:")
+		for _, task := range diff.changed {
+			printTaskDiff(&b, task, len(diff.global) > 0)
+		}
+		fmt.Fprintln(&b)
+	}
+	fmt.Fprintf(&b, "%v task(s) changed, %v added, %v removed, %v unchanged\n", len(diff.changed), len(diff.added), len(diff.removed), diff.unchanged)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func printTaskDiff(b *strings.Builder, task *taskDiff, globalChanged bool) {
+	fmt.Fprintf(b, "  %v\n", task.taskID)
+	if task.before.Hash == task.after.Hash {
+		fmt.Fprintf(b, "    hash: unchanged (%v)\n", task.after.Hash)
+	} else {
+		fmt.Fprintf(b, "    hash: %v -> %v\n", task.before.Hash, task.after.Hash)
+	}
+	if task.before.CacheState != task.after.CacheState {
+		fmt.Fprintf(b, "    cache: %v -> %v\n", cacheStatus(task.before.CacheState), cacheStatus(task.after.CacheState))
+	}
+	if task.before.Hash == task.after.Hash {
+		return
+	}
+
+	explained := false
+	if task.commandChanged {
+		fmt.Fprintf(b, "    command: %q -> %q\n", task.before.Command, task.after.Command)
+		explained = true
+	}
+	if task.definitionChanged {
+		fmt.Fprintln(b, "    task definition changed")
+		explained = true
+	}
+	for _, group := range []struct {
+		name    string
+		changes changes
+	}{{"inputs", task.inputs}, {"environment variables", task.envVars}, {"dependencies", task.dependencies}} {
+		if !group.changes.empty() {
+			fmt.Fprintf(b, "    %v: %v\n", group.name, group.changes.describe())
+			explained = true
+		}
+	}
+	if !explained && !globalChanged {
+		fmt.Fprintln(b, "    inputs that aren't in the summaries changed, such as the args passed through to the task")
+	}
+}
+
+// cacheStatus describes where a task's hash was found in the cache
+func cacheStatus(status cache.ItemStatus) string {
+	switch {
+	case status.Local && status.Remote:
+		return "HIT (local, remote)"
+	case status.Local:
+		return "HIT (local)"
+	case status.Remote:
+		return "HIT (remote)"
+	}
+	return "MISS"
+}
@@ -0,0 +1,114 @@
+package diffcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"gotest.tools/v3/assert"
+)
+
+const _beforeSummary = `{
+  "globalHashSummary": {
+    "globalFileHashMap": {"tsconfig.json": "1"},
+    "rootExternalDepsHash": "deps",
+    "globalCacheKey": "key",
+    "pipeline": {"build": {"outputs": ["dist/**"]}}
+  },
+  "tasks": [
+    {
+      "taskId": "web#build",
+      "hash": "aaa",
+      "cacheState": {"local": true, "remote": false},
+      "command": "next build",
+      "resolvedTaskDefinition": {"outputs": ["dist/**"]},
+      "expandedInputs": {"src/index.ts": "1", "src/old.ts": "2", "package.json": "3"},
+      "dependencyHashes": {"ui#build": "ccc"},
+      "environmentVariables": {"configured": ["API_URL=1"], "inferred": [], "global": []}
+    },
+    {"taskId": "ui#build", "hash": "ccc", "cacheState": {"local": true, "remote": false}, "command": "tsc"},
+    {"taskId": "docs#build", "hash": "ddd", "cacheState": {"local": false, "remote": false}, "command": "next build"}
+  ]
+}`
+
+const _afterSummary = `{
+  "globalHashSummary": {
+    "globalFileHashMap": {"tsconfig.json": "1", ".env": "2"},
+    "rootExternalDepsHash": "deps",
+    "globalCacheKey": "key",
+    "pipeline": {"build": {"outputs": ["dist/**"]}}
+  },
+  "tasks": [
+    {
+      "taskId": "web#build",
+      "hash": "bbb",
+      "cacheState": {"local": false, "remote": false},
+      "command": "next build",
+      "resolvedTaskDefinition": {"outputs": ["dist/**"]},
+      "expandedInputs": {"src/index.ts": "4", "src/new.ts": "5", "package.json": "3"},
+      "dependencyHashes": {"ui#build": "ccc"},
+      "environmentVariables": {"configured": ["API_URL=1", "NODE_ENV=2"], "inferred": [], "global": []}
+    },
+    {"taskId": "ui#build", "hash": "ccc", "cacheState": {"local": true, "remote": false}, "command": "tsc"},
+    {"taskId": "web#lint", "hash": "eee", "cacheState": {"local": false, "remote": false}, "command": "eslint ."}
+  ]
+}`
+
+func parseSummary(t *testing.T, contents string) *summary {
+	t.Helper()
+	s := &summary{}
+	assert.NilError(t, json.Unmarshal([]byte(contents), s))
+	return s
+}
+
+func Test_printDiff(t *testing.T) {
+	diff := diffSummaries(parseSummary(t, _beforeSummary), parseSummary(t, _afterSummary))
+	output := &bytes.Buffer{}
+	assert.NilError(t, printDiff(output, diff))
+	assert.DeepEqual(t, strings.Split(output.String(), "\n"), []string{
+		"Global hash inputs changed, which changes the hash of every task:",
+		"  global dependencies: added .env",
+		"",
+		"Added tasks:",
+		"  web#lint",
+		"",
+		"Removed tasks:",
+		"  docs#build",
+		"",
+		"Changed tasks:",
+		"  web#build",
+		"    hash: aaa -> bbb",
+		"    cache: HIT (local) -> MISS",
+		"    inputs: changed src/index.ts; added src/new.ts; removed src/old.ts",
+		"    environment variables: added NODE_ENV",
+		"",
+		"1 task(s) changed, 1 added, 1 removed, 1 unchanged",
+		"",
+	})
+}
+
+func Test_diffSummaries_singlePackage(t *testing.T) {
+	before := parseSummary(t, `{"tasks": [{"task": "build", "hash": "aaa", "command": "tsc"}]}`)
+	after := parseSummary(t, `{"tasks": [{"task": "build", "hash": "bbb", "command": "tsc --build"}]}`)
+	diff := diffSummaries(before, after)
+	assert.Equal(t, len(diff.global), 0)
+	assert.Equal(t, len(diff.changed), 1)
+	assert.Equal(t, diff.changed[0].taskID, "build")
+	assert.Assert(t, diff.changed[0].commandChanged)
+}
+
+func Test_readSummary(t *testing.T) {
+	dir := turbopath.AbsoluteSystemPathFromUpstream(t.TempDir())
+	path := dir.UntypedJoin("summary.json")
+	assert.NilError(t, path.WriteFile([]byte(_beforeSummary), 0644))
+	s, err := readSummary(path)
+	assert.NilError(t, err)
+	assert.Equal(t, len(s.Tasks), 3)
+
+	notSummary := dir.UntypedJoin("package.json")
+	assert.NilError(t, notSummary.WriteFile([]byte(`{"name": "web"}`), 0644))
+	_, err = readSummary(notSummary)
+	assert.ErrorContains(t, err, "is not a run summary")
+}
@@ -0,0 +1,83 @@
+package env
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// ParseDotEnv parses the contents of a .env file into the variables it sets. Each line
+// is a KEY=value assignment, optionally prefixed with "export". Blank lines and lines
+// starting with "#" are ignored. Values may be wrapped in single quotes, which are taken
+// literally, or in double quotes, which support \n, \" and \\ escapes. A " #" ends an
+// unquoted value.
+func ParseDotEnv(contents []byte) (EnvironmentVariableMap, error) {
+	vars := EnvironmentVariableMap{}
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+		key, rawValue, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return nil, fmt.Errorf("line %v: expected KEY=value", lineNumber)
+		}
+		value, err := parseDotEnvValue(strings.TrimSpace(rawValue))
+		if err != nil {
+			return nil, fmt.Errorf("line %v: %w", lineNumber, err)
+		}
+		vars[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return vars, nil
+}
+
+func parseDotEnvValue(raw string) (string, error) {
+	if raw == "" {
+		return "", nil
+	}
+	switch quote := raw[0]; quote {
+	case '\'':
+		end := strings.IndexByte(raw[1:], '\'')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated quoted value")
+		}
+		return raw[1 : end+1], nil
+	case '"':
+		var value strings.Builder
+		for i := 1; i < len(raw); i++ {
+			switch c := raw[i]; {
+			case c == '"':
+				return value.String(), nil
+			case c == '\\' && i+1 < len(raw):
+				i++
+				switch raw[i] {
+				case 'n':
+					value.WriteByte('\n')
+				case 'r':
+					value.WriteByte('\r')
+				case 't':
+					value.WriteByte('\t')
+				default:
+					value.WriteByte(raw[i])
+				}
+			default:
+				value.WriteByte(c)
+			}
+		}
+		return "", fmt.Errorf("unterminated quoted value")
+	default:
+		if comment := strings.Index(raw, " #"); comment >= 0 {
+			raw = raw[:comment]
+		}
+		return strings.TrimSpace(raw), nil
+	}
+}
@@ -0,0 +1,48 @@
+package env
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDotEnv(t *testing.T) {
+	contents := []byte(`# Comments and blank lines are ignored
+
+API_URL=https://example.com
+export REGION = us-east-1
+EMPTY=
+UNQUOTED=value # a comment
+SINGLE='literal \n # not a comment'
+DOUBLE="line one\nline \"two\""
+URL_WITH_HASH=https://example.com/#anchor
+`)
+	got, err := ParseDotEnv(contents)
+	if err != nil {
+		t.Fatalf("ParseDotEnv: %v", err)
+	}
+	want := EnvironmentVariableMap{
+		"API_URL":       "https://example.com",
+		"REGION":        "us-east-1",
+		"EMPTY":         "",
+		"UNQUOTED":      "value",
+		"SINGLE":        `literal \n # not a comment`,
+		"DOUBLE":        "line one\nline \"two\"",
+		"URL_WITH_HASH": "https://example.com/#anchor",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+
+	invalid := map[string]string{
+		"NOT AN ASSIGNMENT":    "line 1: expected KEY=value",
+		"=value":               "line 1: expected KEY=value",
+		"OK=1\nQUOTED=\"open":  "line 2: unterminated quoted value",
+		"QUOTED='open":         "line 1: unterminated quoted value",
+		"TWO WORDS=not a name": "line 1: expected KEY=value",
+	}
+	for contents, wantErr := range invalid {
+		if _, err := ParseDotEnv([]byte(contents)); err == nil || err.Error() != wantErr {
+			t.Errorf("ParseDotEnv(%q) error = %v, want %v", contents, err, wantErr)
+		}
+	}
+}
@@ -45,6 +45,25 @@ func (evm EnvironmentVariableMap) Names() []string {
 	return names
 }
 
+// Without returns a copy of the DetailedMap that doesn't contain the given env vars
+func (dm DetailedMap) Without(keys []string) DetailedMap {
+	without := func(evm EnvironmentVariableMap) EnvironmentVariableMap {
+		output := EnvironmentVariableMap{}
+		output.Merge(evm)
+		for _, key := range keys {
+			delete(output, key)
+		}
+		return output
+	}
+	return DetailedMap{
+		All: without(dm.All),
+		BySource: BySource{
+			Explicit: without(dm.BySource.Explicit),
+			Matching: without(dm.BySource.Matching),
+		},
+	}
+}
+
 // EnvironmentVariablePairs is a list of "k=v" strings for env variables and their values
 type EnvironmentVariablePairs []string
 
@@ -92,16 +111,61 @@ func getEnvMap() EnvironmentVariableMap {
 	return envMap
 }
 
-// fromKeys returns a map of env vars and their values from a given set of env var names
+// _wildcard matches any sequence of characters in an env var name
+const _wildcard = "*"
+
+// _exclusionPrefix marks an env var name that is left out, even if another name matches it
+const _exclusionPrefix = "!"
+
+// wildcardToRegex converts an env var name that may contain wildcards into an anchored regex
+func wildcardToRegex(name string) *regexp.Regexp {
+	parts := strings.Split(name, _wildcard)
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, ".*") + "$")
+}
+
+// fromKeys returns a map of env vars and their values from a given set of env var names.
+// Names containing "*" match every env var they fit, e.g. MYAPP_* matches MYAPP_URL.
+// Exclusions, prefixed with "!", are skipped, they are applied by removeExcluded.
 func fromKeys(all EnvironmentVariableMap, keys []string) EnvironmentVariableMap {
 	output := EnvironmentVariableMap{}
 	for _, key := range keys {
-		output[key] = all[key]
+		if strings.HasPrefix(key, _exclusionPrefix) {
+			continue
+		}
+		if !strings.Contains(key, _wildcard) {
+			output[key] = all[key]
+			continue
+		}
+		rex := wildcardToRegex(key)
+		for k, v := range all {
+			if rex.MatchString(k) {
+				output[k] = v
+			}
+		}
 	}
 
 	return output
 }
 
+// removeExcluded removes the env vars matched by the names in keys that are prefixed
+// with "!". Exclusions may contain wildcards too.
+func (evm EnvironmentVariableMap) removeExcluded(keys []string) {
+	for _, key := range keys {
+		if !strings.HasPrefix(key, _exclusionPrefix) {
+			continue
+		}
+		rex := wildcardToRegex(strings.TrimPrefix(key, _exclusionPrefix))
+		for k := range evm {
+			if rex.MatchString(k) {
+				delete(evm, k)
+			}
+		}
+	}
+}
+
 func fromMatching(all EnvironmentVariableMap, keyMatchers []string, shouldExclude func(k, v string) bool) (EnvironmentVariableMap, error) {
 	output := EnvironmentVariableMap{}
 	compileFailures := []string{}
@@ -132,7 +196,9 @@ func fromMatching(all EnvironmentVariableMap, keyMatchers []string, shouldExclud
 	return output, nil
 }
 
-// GetHashableEnvVars returns all sorted key=value env var pairs for both frameworks and from envKeys
+// GetHashableEnvVars returns all sorted key=value env var pairs for both frameworks and from envKeys.
+// Keys may be wildcard patterns, and keys prefixed with "!" drop the env vars they match
+// from both sources.
 func GetHashableEnvVars(keys []string, matchers []string, envVarContainingExcludePrefix string) (DetailedMap, error) {
 	all := getEnvMap()
 
@@ -142,6 +208,7 @@ func GetHashableEnvVars(keys []string, matchers []string, envVarContainingExclud
 	}
 
 	detailedMap.BySource.Explicit = fromKeys(all, keys)
+	detailedMap.BySource.Explicit.removeExcluded(keys)
 	detailedMap.All.Merge(detailedMap.BySource.Explicit)
 
 	// Create an excluder function to pass to matcher.
@@ -163,7 +230,41 @@ func GetHashableEnvVars(keys []string, matchers []string, envVarContainingExclud
 		return DetailedMap{}, err
 	}
 
+	matchedEnvVars.removeExcluded(keys)
 	detailedMap.BySource.Matching = matchedEnvVars
 	detailedMap.All.Merge(detailedMap.BySource.Matching)
 	return detailedMap, nil
 }
+
+// GetEnvVars returns the env vars that are set and match the given names. Like the keys of
+// GetHashableEnvVars, names may be wildcard patterns, and names prefixed with "!" drop the
+// env vars they match.
+func GetEnvVars(keys []string) EnvironmentVariableMap {
+	all := getEnvMap()
+	output := fromKeys(all, keys)
+	output.removeExcluded(keys)
+	for k := range output {
+		if _, ok := all[k]; !ok {
+			delete(output, k)
+		}
+	}
+	return output
+}
+
+// IsMatched reports whether the env var name is matched by the given keys, which may be
+// wildcard patterns and exclusions like the keys of GetHashableEnvVars
+func IsMatched(name string, keys []string) bool {
+	matched := false
+	for _, key := range keys {
+		if strings.HasPrefix(key, _exclusionPrefix) {
+			if wildcardToRegex(strings.TrimPrefix(key, _exclusionPrefix)).MatchString(name) {
+				return false
+			}
+			continue
+		}
+		if key == name || (strings.Contains(key, _wildcard) && wildcardToRegex(key).MatchString(name)) {
+			matched = true
+		}
+	}
+	return matched
+}
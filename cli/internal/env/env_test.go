@@ -275,6 +275,33 @@ func TestGetHashableEnvVars(t *testing.T) {
 			},
 			want: EnvironmentVariablePairs{"MANUAL=true", "NEXT_PUBLIC_VERCEL_ENV=true"},
 		},
+		{
+			env:  []string{"MYAPP_URL=a.com", "MYAPP_PORT=80", "MYAPP=bare", "OTHER_MYAPP_URL=b.com"},
+			name: "wildcards match every env var they fit",
+			args: args{
+				envKeys:     []string{"MYAPP_*"},
+				envPrefixes: []string{},
+			},
+			want: EnvironmentVariablePairs{"MYAPP_PORT=80", "MYAPP_URL=a.com"},
+		},
+		{
+			env:  []string{"MYAPP_URL=a.com", "MYAPP_SECRET=shh", "MYAPP_SECRET_KEY=shh", "MYAPP_TOKEN=shh"},
+			name: "exclusions drop env vars matched by a wildcard",
+			args: args{
+				envKeys:     []string{"MYAPP_*", "!MYAPP_SECRET*", "!MYAPP_TOKEN"},
+				envPrefixes: []string{},
+			},
+			want: EnvironmentVariablePairs{"MYAPP_URL=a.com"},
+		},
+		{
+			env:  []string{"NEXT_PUBLIC_URL=a.com", "NEXT_PUBLIC_BUILD_ID=123"},
+			name: "exclusions drop automatically added env vars",
+			args: args{
+				envKeys:     []string{"!NEXT_PUBLIC_BUILD_ID"},
+				envPrefixes: []string{"NEXT_PUBLIC_"},
+			},
+			want: EnvironmentVariablePairs{"NEXT_PUBLIC_URL=a.com"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -295,3 +322,55 @@ func TestGetHashableEnvVars(t *testing.T) {
 		})
 	}
 }
+
+func TestDetailedMapWithout(t *testing.T) {
+	detailedMap := DetailedMap{
+		All: EnvironmentVariableMap{"API_URL": "a", "NEXT_PUBLIC_LOG_LEVEL": "debug", "TELEMETRY": "1"},
+		BySource: BySource{
+			Explicit: EnvironmentVariableMap{"API_URL": "a", "TELEMETRY": "1"},
+			Matching: EnvironmentVariableMap{"NEXT_PUBLIC_LOG_LEVEL": "debug"},
+		},
+	}
+	without := detailedMap.Without([]string{"NEXT_PUBLIC_LOG_LEVEL", "TELEMETRY", "UNSET"})
+
+	if got, want := without.All.ToHashable(), (EnvironmentVariablePairs{"API_URL=a"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("All = %v, want %v", got, want)
+	}
+	if got, want := without.BySource.Explicit.Names(), []string{"API_URL"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Explicit = %v, want %v", got, want)
+	}
+	if got := without.BySource.Matching.Names(); len(got) != 0 {
+		t.Errorf("Matching = %v, want none", got)
+	}
+	// The original map is left untouched
+	if got := len(detailedMap.All); got != 3 {
+		t.Errorf("len(All) of the original = %v, want 3", got)
+	}
+}
+
+func TestGetEnvVars(t *testing.T) {
+	t.Setenv("MYAPP_URL", "https://example.com")
+	t.Setenv("MYAPP_TOKEN", "secret")
+	t.Setenv("EMPTY", "")
+
+	got := GetEnvVars([]string{"MYAPP_*", "!MYAPP_TOKEN", "EMPTY", "UNSET_FOR_TEST"})
+	want := EnvironmentVariablePairs{"EMPTY=", "MYAPP_URL=https://example.com"}
+	if !reflect.DeepEqual(got.ToHashable(), want) {
+		t.Errorf("GetEnvVars = %v, want %v", got.ToHashable(), want)
+	}
+}
+
+func TestIsMatched(t *testing.T) {
+	keys := []string{"API_URL", "MYAPP_*", "!MYAPP_TOKEN"}
+	for name, want := range map[string]bool{
+		"API_URL":     true,
+		"MYAPP_URL":   true,
+		"MYAPP_TOKEN": false,
+		"API":         false,
+		"OTHER":       false,
+	} {
+		if got := IsMatched(name, keys); got != want {
+			t.Errorf("IsMatched(%v) = %v, want %v", name, got, want)
+		}
+	}
+}
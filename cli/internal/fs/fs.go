@@ -1,6 +1,7 @@
 package fs
 
 import (
+	"bytes"
 	"io"
 	"io/ioutil"
 	"log"
@@ -132,6 +133,12 @@ func writeFileFromStream(fromFile io.Reader, to string, mode os.FileMode) error
 	return renameFile(tempFile.Name(), to)
 }
 
+// WriteFileAtomic writes contents to the file named 'to' through a temporary file in the same
+// directory, so that a concurrent reader sees either the previous file or the complete new one.
+func WriteFileAtomic(to string, contents []byte, mode os.FileMode) error {
+	return writeFileFromStream(bytes.NewReader(contents), to, mode)
+}
+
 // IsDirectory checks if a given path is a directory
 func IsDirectory(path string) bool {
 	info, err := os.Stat(path)
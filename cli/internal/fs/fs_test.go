@@ -1,6 +1,7 @@
 package fs
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 )
@@ -58,3 +59,27 @@ func Test_DirContainsPath(t *testing.T) {
 		}
 	}
 }
+
+func Test_WriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "reports", "summary.json")
+	for _, contents := range []string{"first", "second"} {
+		if err := WriteFileAtomic(file, []byte(contents), 0644); err != nil {
+			t.Fatalf("failed to write %v: %v", file, err)
+		}
+		got, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("failed to read %v: %v", file, err)
+		}
+		if string(got) != contents {
+			t.Errorf("WriteFileAtomic got %q, want %q", got, contents)
+		}
+	}
+	entries, err := os.ReadDir(filepath.Dir(file))
+	if err != nil {
+		t.Fatalf("failed to read %v: %v", dir, err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("WriteFileAtomic left %v files in the directory, want 1", len(entries))
+	}
+}
@@ -7,9 +7,13 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/Masterminds/semver"
 	"github.com/muhammadmuzzammil1998/jsonc"
 	"github.com/pkg/errors"
 	"github.com/vercel/turbo/cli/internal/turbopath"
@@ -27,25 +31,47 @@ type rawTurboJSON struct {
 	GlobalDependencies []string `json:"globalDependencies,omitempty"`
 	// Global env
 	GlobalEnv []string `json:"globalEnv,omitempty"`
+	// Env vars that every task is given but that aren't part of any hash
+	GlobalPassThroughEnv []string `json:"globalPassThroughEnv,omitempty"`
+	// Commands whose output, e.g. the version of a tool, is part of the global hash
+	GlobalToolVersions []string `json:"globalToolVersions,omitempty"`
 	// Pipeline is a map of Turbo pipeline entries which define the task graph
 	// and cache behavior on a per task or per package-task basis.
 	Pipeline Pipeline `json:"pipeline"`
 	// Configuration options when interfacing with the remote cache
 	RemoteCacheOptions RemoteCacheOptions `json:"remoteCache,omitempty"`
 
-	// Extends can be the name of another workspace
+	// Extends can be the name of another workspace, or a path or package of a shared config
 	Extends []string `json:"extends,omitempty"`
+
+	// TurboVersion is a semver range that the running version of turbo must satisfy
+	TurboVersion string `json:"turboVersion,omitempty"`
+
+	// ExitCodeCategories maps task exit codes to a label describing the kind of failure
+	ExitCodeCategories map[string]string `json:"exitCodeCategories,omitempty"`
+
+	// EnvGroups are named lists of env vars that tasks can include with "envGroups"
+	EnvGroups map[string][]string `json:"envGroups,omitempty"`
+
+	// TaskHooks are commands run before and after the command of every task
+	TaskHooks *TaskHooks `json:"taskHooks,omitempty"`
 }
 
 // pristineTurboJSON is used when marshaling a TurboJSON object into a turbo.json string
 // Notably, it includes a PristinePipeline instead of the regular Pipeline. (i.e. TaskDefinition
 // instead of BookkeepingTaskDefinition.)
 type pristineTurboJSON struct {
-	GlobalDependencies []string           `json:"globalDependencies,omitempty"`
-	GlobalEnv          []string           `json:"globalEnv,omitempty"`
-	Pipeline           PristinePipeline   `json:"pipeline"`
-	RemoteCacheOptions RemoteCacheOptions `json:"remoteCache,omitempty"`
-	Extends            []string           `json:"extends,omitempty"`
+	GlobalDependencies   []string            `json:"globalDependencies,omitempty"`
+	GlobalEnv            []string            `json:"globalEnv,omitempty"`
+	GlobalPassThroughEnv []string            `json:"globalPassThroughEnv,omitempty"`
+	GlobalToolVersions   []string            `json:"globalToolVersions,omitempty"`
+	Pipeline             PristinePipeline    `json:"pipeline"`
+	RemoteCacheOptions   RemoteCacheOptions  `json:"remoteCache,omitempty"`
+	Extends              []string            `json:"extends,omitempty"`
+	TurboVersion         string              `json:"turboVersion,omitempty"`
+	ExitCodeCategories   map[string]string   `json:"exitCodeCategories,omitempty"`
+	EnvGroups            map[string][]string `json:"envGroups,omitempty"`
+	TaskHooks            *TaskHooks          `json:"taskHooks,omitempty"`
 }
 
 // TurboJSON represents a turbo.json configuration file
@@ -55,8 +81,26 @@ type TurboJSON struct {
 	Pipeline           Pipeline
 	RemoteCacheOptions RemoteCacheOptions
 
+	// Env vars, sorted, that every task is given but that aren't part of any hash
+	GlobalPassThroughEnv []string
+
+	// Commands, sorted, whose output is part of the global hash
+	GlobalToolVersions []string
+
 	// A list of Workspace names
 	Extends []string
+
+	// A semver range of turbo versions this configuration supports
+	TurboVersion string
+
+	// Labels for the kinds of failures that task exit codes represent
+	ExitCodeCategories map[int]string
+
+	// Named lists of env vars, sorted, that tasks can include in their hash
+	EnvGroups map[string][]string
+
+	// Commands run around the command of every task
+	TaskHooks *TaskHooks
 }
 
 // RemoteCacheOptions is a struct for deserializing .remoteCache of configFile
@@ -69,25 +113,57 @@ type RemoteCacheOptions struct {
 // We use this for printing ResolvedTaskConfiguration, because we _want_ to show
 // the user the default values for key they have not configured.
 type rawTaskWithDefaults struct {
-	Outputs    []string            `json:"outputs"`
-	Cache      *bool               `json:"cache"`
-	DependsOn  []string            `json:"dependsOn"`
-	Inputs     []string            `json:"inputs"`
-	OutputMode util.TaskOutputMode `json:"outputMode"`
-	Env        []string            `json:"env"`
-	Persistent bool                `json:"persistent"`
+	Outputs            []string              `json:"outputs"`
+	RestoreOnlyOutputs []string              `json:"restoreOnlyOutputs,omitempty"`
+	Cache              *bool                 `json:"cache"`
+	DependsOn          []string              `json:"dependsOn"`
+	Inputs             []string              `json:"inputs"`
+	RootInputs         []string              `json:"rootInputs,omitempty"`
+	OutputMode         util.TaskOutputMode   `json:"outputMode"`
+	Env                []string              `json:"env"`
+	EnvGroups          []string              `json:"envGroups,omitempty"`
+	PassThroughEnv     []string              `json:"passThroughEnv,omitempty"`
+	Persistent         bool                  `json:"persistent"`
+	Timeout            string                `json:"timeout,omitempty"`
+	Retries            int                   `json:"retries,omitempty"`
+	RetryBackoff       string                `json:"retryBackoff,omitempty"`
+	Framework          string                `json:"framework,omitempty"`
+	HashInputsFrom     string                `json:"hashInputsFrom,omitempty"`
+	DotEnv             []string              `json:"dotEnv,omitempty"`
+	OutputTransform    string                `json:"outputTransform,omitempty"`
+	PostCacheRestore   *PostCacheRestoreHook `json:"postCacheRestore,omitempty"`
+	OutputsFromLog     string                `json:"outputsFromLog,omitempty"`
+	Interactive        bool                  `json:"interactive,omitempty"`
+	ConcurrencyGroup   string                `json:"concurrencyGroup,omitempty"`
+	ParallelSafe       *bool                 `json:"parallelSafe,omitempty"`
 }
 
 // rawTask exists to Unmarshal from json. When fields are omitted, we _want_
 // them to be missing, so that we can distinguish missing from empty value.
 type rawTask struct {
-	Outputs    []string             `json:"outputs,omitempty"`
-	Cache      *bool                `json:"cache,omitempty"`
-	DependsOn  []string             `json:"dependsOn,omitempty"`
-	Inputs     []string             `json:"inputs,omitempty"`
-	OutputMode *util.TaskOutputMode `json:"outputMode,omitempty"`
-	Env        []string             `json:"env,omitempty"`
-	Persistent *bool                `json:"persistent,omitempty"`
+	Outputs            []string              `json:"outputs,omitempty"`
+	RestoreOnlyOutputs []string              `json:"restoreOnlyOutputs,omitempty"`
+	Cache              *bool                 `json:"cache,omitempty"`
+	DependsOn          []string              `json:"dependsOn,omitempty"`
+	Inputs             []string              `json:"inputs,omitempty"`
+	RootInputs         []string              `json:"rootInputs,omitempty"`
+	OutputMode         *util.TaskOutputMode  `json:"outputMode,omitempty"`
+	Env                []string              `json:"env,omitempty"`
+	EnvGroups          []string              `json:"envGroups,omitempty"`
+	PassThroughEnv     []string              `json:"passThroughEnv,omitempty"`
+	Persistent         *bool                 `json:"persistent,omitempty"`
+	Timeout            *string               `json:"timeout,omitempty"`
+	Retries            *int                  `json:"retries,omitempty"`
+	RetryBackoff       *string               `json:"retryBackoff,omitempty"`
+	Framework          *string               `json:"framework,omitempty"`
+	HashInputsFrom     *string               `json:"hashInputsFrom,omitempty"`
+	DotEnv             []string              `json:"dotEnv,omitempty"`
+	OutputTransform    *string               `json:"outputTransform,omitempty"`
+	PostCacheRestore   *PostCacheRestoreHook `json:"postCacheRestore,omitempty"`
+	OutputsFromLog     *string               `json:"outputsFromLog,omitempty"`
+	Interactive        *bool                 `json:"interactive,omitempty"`
+	ConcurrencyGroup   *string               `json:"concurrencyGroup,omitempty"`
+	ParallelSafe       *bool                 `json:"parallelSafe,omitempty"`
 }
 
 // PristinePipeline contains original TaskDefinitions without the bookkeeping
@@ -108,9 +184,23 @@ type TaskDefinition struct {
 	Outputs     TaskOutputs
 	ShouldCache bool
 
+	// RestoreOnlyOutputs are globs of files that are restored from the cache along with
+	// Outputs, but are never saved to it. They are hashed like Outputs, so that moving a
+	// glob from Outputs to RestoreOnlyOutputs doesn't change the hash.
+	RestoreOnlyOutputs []string
+
 	// This field is custom-marshalled from rawTask.Env and rawTask.DependsOn
 	EnvVarDependencies []string
 
+	// EnvGroups are the names of the root turbo.json's "envGroups" whose env vars are
+	// added to EnvVarDependencies once the Task's definitions are merged.
+	EnvGroups []string
+
+	// PassThroughEnv are env vars that the Task uses but that don't affect its outputs.
+	// They are never included in the hash, even if they are also inferred from the
+	// Task's framework or come from EnvVarDependencies.
+	PassThroughEnv []string
+
 	// TopologicalDependencies are tasks from package dependencies.
 	// E.g. "build" is a topological dependency in:
 	// dependsOn: ['^build'].
@@ -127,12 +217,98 @@ type TaskDefinition struct {
 	// we can conclude that any cached outputs or logs for this Task should be invalidated.
 	Inputs []string
 
+	// RootInputs are globs, anchored at the repository root, of files outside of the
+	// package that this Task also depends on. They are hashed in addition to Inputs.
+	RootInputs []string
+
 	// OutputMode determins how we should log the output.
 	OutputMode util.TaskOutputMode
 
 	// Persistent indicates whether the Task is expected to exit or not
 	// Tasks marked Persistent do not exit (e.g. --watch mode or dev servers)
 	Persistent bool
+
+	// Timeout is how long the Task's command may run before it is stopped and the Task
+	// fails. Zero means the Task may run forever.
+	Timeout time.Duration
+
+	// Retries is how many more times the Task's command is run when it exits with an
+	// error, before the Task fails
+	Retries int
+
+	// RetryBackoff is how long to wait before each retry
+	RetryBackoff time.Duration
+
+	// Framework overrides the framework inferred for the Task's package, which determines
+	// the environment variables that are automatically included in the hash. "none" disables
+	// inference, and an empty value means the framework is inferred.
+	Framework string
+
+	// HashInputsFrom is a command, run in the Task's package directory, whose output
+	// is included in the hash. It captures state that isn't a file or an env var.
+	HashInputsFrom string
+
+	// DotEnv are .env files, relative to the Task's package directory, whose variables
+	// are included in the hash. Later files take precedence, and missing files are skipped.
+	DotEnv []string
+
+	// OutputTransform normalizes the Task's outputs, on disk, before they are cached.
+	// It is either a built-in normalizer, prefixed with OutputTransformBuiltinPrefix,
+	// or a command run in the Task's package directory.
+	OutputTransform string
+
+	// PostCacheRestore is run after the Task's outputs are restored from the cache.
+	// It is nil if the Task has no hook.
+	PostCacheRestore *PostCacheRestoreHook
+
+	// OutputsFromLog is a regular expression matched against each line the Task prints
+	// to stdout. The first capture group of each match is the path, relative to the
+	// Task's package directory, of a file that is cached along with Outputs.
+	OutputsFromLog string
+
+	// Interactive connects the Task's command to the terminal, so that it can read from stdin.
+	// Its output is shown as is, without prefixes, and isn't written to the log file.
+	Interactive bool
+
+	// ConcurrencyGroup names the group of Tasks that --concurrency-group limits the Task with.
+	// It is empty if the Task is only limited by --concurrency.
+	ConcurrencyGroup string
+
+	// ParallelUnsafe keeps the Task from running at the same time as other ParallelUnsafe
+	// Tasks, even with --parallel. It is set with "parallelSafe": false.
+	ParallelUnsafe bool
+}
+
+// TurboDefaultInput, in a Task's inputs, stands for the files hashed when a Task has no
+// inputs: every file of its package that isn't ignored by git
+const TurboDefaultInput = "$TURBO_DEFAULT"
+
+// OutputTransformBuiltinPrefix marks an OutputTransform that names a built-in normalizer,
+// rather than a command
+const OutputTransformBuiltinPrefix = "builtin:"
+
+// OutputTransformRepoRoot is the built-in normalizer that replaces the absolute path of the
+// repository root in the outputs with a relative one
+const OutputTransformRepoRoot = "repo-root"
+
+// _outputTransformBuiltins are the names of the built-in normalizers, in the order they are listed in errors
+var _outputTransformBuiltins = []string{OutputTransformRepoRoot}
+
+// TaskHooks are commands run in each Task's package directory, before and after the
+// Task's own command. They are told which task is running through TURBO_TASK_ID and TURBO_HASH.
+type TaskHooks struct {
+	// Before runs before the Task's command. The Task fails without running if it fails.
+	Before string `json:"before,omitempty"`
+	// After runs once the Task's command finished, whether or not it succeeded
+	After string `json:"after,omitempty"`
+}
+
+// PostCacheRestoreHook is a command run in the Task's package directory after a cache hit,
+// for side effects that the Task's outputs alone don't reproduce.
+type PostCacheRestoreHook struct {
+	Command string `json:"command"`
+	// Fatal makes the Task fail when the hook fails. Otherwise the failure is only reported.
+	Fatal bool `json:"fatal,omitempty"`
 }
 
 // GetTask returns a TaskDefinition based on the ID (package#task format) or name (e.g. "build")
@@ -234,6 +410,53 @@ func (tj *TurboJSON) Validate(validations []TurboJSONValidation) []error {
 	return allErrors
 }
 
+// CheckTurboVersion returns an error if the given version of turbo does not satisfy
+// the "turboVersion" range configured in turbo.json
+func (tj *TurboJSON) CheckTurboVersion(turboVersion string) error {
+	if tj.TurboVersion == "" {
+		return nil
+	}
+	constraint, err := semver.NewConstraint(tj.TurboVersion)
+	if err != nil {
+		return fmt.Errorf("invalid \"turboVersion\" in %s: %w", configFile, err)
+	}
+	version, err := semver.NewVersion(turboVersion)
+	if err != nil {
+		// Development builds don't have a meaningful version to check against
+		return nil
+	}
+	// Canary releases should satisfy the same ranges as the release they precede
+	release, err := version.SetPrerelease("")
+	if err != nil {
+		return err
+	}
+	if !constraint.Check(&release) {
+		return fmt.Errorf("This repository requires turbo %s, but turbo %s is running. Upgrade turbo to a version that satisfies \"turboVersion\" in %s", tj.TurboVersion, turboVersion, configFile)
+	}
+	return nil
+}
+
+// ResolveEnvGroups adds the env vars of each of the task's "envGroups" to its
+// EnvVarDependencies, so that they are hashed like the task's own "env"
+func (tj *TurboJSON) ResolveEnvGroups(taskDefinition *TaskDefinition) error {
+	if len(taskDefinition.EnvGroups) == 0 {
+		return nil
+	}
+	envVarDependencies := util.SetFromStrings(taskDefinition.EnvVarDependencies)
+	for _, name := range taskDefinition.EnvGroups {
+		envVars, ok := tj.EnvGroups[name]
+		if !ok {
+			return fmt.Errorf("env group \"%s\" is not defined in \"envGroups\" in the root %s", name, configFile)
+		}
+		for _, envVar := range envVars {
+			envVarDependencies.Add(envVar)
+		}
+	}
+	taskDefinition.EnvVarDependencies = envVarDependencies.UnsafeListOfStrings()
+	sort.Strings(taskDefinition.EnvVarDependencies)
+	return nil
+}
+
 // TaskOutputs represents the patterns for including and excluding files from outputs
 type TaskOutputs struct {
 	Inclusions []string
@@ -251,7 +474,7 @@ func (to TaskOutputs) Sort() TaskOutputs {
 	return TaskOutputs{Inclusions: inclusions, Exclusions: exclusions}
 }
 
-// readTurboConfig reads turbo.json from a provided path
+// readTurboConfig reads turbo.json from a provided path, along with the configs it extends
 func readTurboConfig(turboJSONPath turbopath.AbsoluteSystemPath) (*TurboJSON, error) {
 	// If the configFile exists, use that
 	if turboJSONPath.FileExists() {
@@ -259,6 +482,9 @@ func readTurboConfig(turboJSONPath turbopath.AbsoluteSystemPath) (*TurboJSON, er
 		if err != nil {
 			return nil, fmt.Errorf("%s: %w", configFile, err)
 		}
+		if err := turboJSON.mergeExtendedConfigs(turboJSONPath, []string{turboJSONPath.ToString()}); err != nil {
+			return nil, fmt.Errorf("%s: %w", configFile, err)
+		}
 
 		return turboJSON, nil
 	}
@@ -279,6 +505,14 @@ func readTurboJSON(path turbopath.AbsoluteSystemPath) (*TurboJSON, error) {
 		return nil, err
 	}
 
+	if problems := ValidateTurboJSON(data); len(problems) > 0 {
+		fullError := errors.New("invalid configuration")
+		for _, problem := range problems {
+			fullError = fmt.Errorf("%w\n - %v:%v", fullError, path, problem)
+		}
+		return nil, fullError
+	}
+
 	err = jsonc.Unmarshal(data, &turboJSON)
 
 	if err != nil {
@@ -288,6 +522,127 @@ func readTurboJSON(path turbopath.AbsoluteSystemPath) (*TurboJSON, error) {
 	return turboJSON, nil
 }
 
+// mergeExtendedConfigs merges the configs that tj extends, other than the root workspace,
+// into tj. The configs are merged in the order they are listed, so later ones take precedence,
+// and tj's own settings take precedence over all of them. extending holds the configs that
+// are being read, so that a config extending itself is an error rather than a loop.
+func (tj *TurboJSON) mergeExtendedConfigs(path turbopath.AbsoluteSystemPath, extending []string) error {
+	workspaceExtends := []string{}
+	var merged *TurboJSON
+	for _, entry := range tj.Extends {
+		if entry == util.RootPkgName {
+			workspaceExtends = append(workspaceExtends, entry)
+			continue
+		}
+		basePath, err := resolveExtendedConfig(path.Dir(), entry)
+		if err != nil {
+			return err
+		}
+		for _, ancestor := range extending {
+			if ancestor == basePath.ToString() {
+				return fmt.Errorf("\"extends\" refers back to %s", basePath)
+			}
+		}
+		base, err := readTurboJSON(basePath)
+		if err != nil {
+			return fmt.Errorf("%s: %w", basePath, err)
+		}
+		if err := base.mergeExtendedConfigs(basePath, append(append([]string{}, extending...), basePath.ToString())); err != nil {
+			return err
+		}
+		if merged == nil {
+			merged = &TurboJSON{Pipeline: make(Pipeline)}
+		}
+		merged.mergeConfig(base)
+	}
+	if merged == nil {
+		return nil
+	}
+	merged.mergeConfig(tj)
+	tj.GlobalDeps = merged.GlobalDeps
+	tj.GlobalEnv = merged.GlobalEnv
+	tj.GlobalPassThroughEnv = merged.GlobalPassThroughEnv
+	tj.GlobalToolVersions = merged.GlobalToolVersions
+	tj.Pipeline = merged.Pipeline
+	tj.EnvGroups = merged.EnvGroups
+	tj.Extends = workspaceExtends
+	return nil
+}
+
+// resolveExtendedConfig finds the config that an "extends" entry refers to. Entries starting
+// with "." and absolute paths name a config file, or a directory containing turbo.json,
+// relative to dir. Any other entry is the name of an installed package, whose turbo.json is
+// looked up in the node_modules of dir and each of its parents, like Node resolves packages.
+func resolveExtendedConfig(dir turbopath.AbsoluteSystemPath, entry string) (turbopath.AbsoluteSystemPath, error) {
+	if strings.HasPrefix(entry, ".") || filepath.IsAbs(entry) {
+		path := turbopath.AbsoluteSystemPath(filepath.FromSlash(entry))
+		if !filepath.IsAbs(entry) {
+			path = dir.UntypedJoin(filepath.FromSlash(entry))
+		}
+		if path.DirExists() {
+			path = path.UntypedJoin(configFile)
+		}
+		if !path.FileExists() {
+			return "", fmt.Errorf("could not find %s to extend from", entry)
+		}
+		return path, nil
+	}
+	for current := dir; ; current = current.Dir() {
+		if candidate := current.UntypedJoin("node_modules", filepath.FromSlash(entry), configFile); candidate.FileExists() {
+			return candidate, nil
+		}
+		if current.Dir() == current {
+			return "", fmt.Errorf("could not find %s in package %s to extend from, is it installed?", configFile, entry)
+		}
+	}
+}
+
+// mergeConfig merges other into tj. The global dependencies of both are kept, and the
+// task definitions and env groups of other take precedence over those of tj.
+func (tj *TurboJSON) mergeConfig(other *TurboJSON) {
+	tj.GlobalDeps = mergeSortedStrings(tj.GlobalDeps, other.GlobalDeps)
+	tj.GlobalEnv = mergeSortedStrings(tj.GlobalEnv, other.GlobalEnv)
+	tj.GlobalPassThroughEnv = mergeSortedStrings(tj.GlobalPassThroughEnv, other.GlobalPassThroughEnv)
+	tj.GlobalToolVersions = mergeSortedStrings(tj.GlobalToolVersions, other.GlobalToolVersions)
+	for taskID, taskDefinition := range other.Pipeline {
+		if base, ok := tj.Pipeline[taskID]; ok {
+			taskDefinition = base.overriddenBy(taskDefinition)
+		}
+		tj.Pipeline[taskID] = taskDefinition
+	}
+	for name, envVars := range other.EnvGroups {
+		if tj.EnvGroups == nil {
+			tj.EnvGroups = make(map[string][]string)
+		}
+		tj.EnvGroups[name] = envVars
+	}
+}
+
+// overriddenBy returns btd with the fields defined in override replaced by override's
+func (btd BookkeepingTaskDefinition) overriddenBy(override BookkeepingTaskDefinition) BookkeepingTaskDefinition {
+	// MergeTaskDefinitions never fails
+	merged, _ := MergeTaskDefinitions([]BookkeepingTaskDefinition{btd, override})
+	definedFields := btd.definedFields.Copy()
+	for _, field := range override.definedFields.UnsafeListOfStrings() {
+		definedFields.Add(field)
+	}
+	return BookkeepingTaskDefinition{
+		definedFields:  definedFields,
+		TaskDefinition: *merged,
+	}
+}
+
+// mergeSortedStrings returns the sorted union of a and b
+func mergeSortedStrings(a []string, b []string) []string {
+	union := util.SetFromStrings(a)
+	for _, value := range b {
+		union.Add(value)
+	}
+	merged := union.UnsafeListOfStrings()
+	sort.Strings(merged)
+	return merged
+}
+
 // GetTaskDefinition returns a TaskDefinition from a serialized definition in configFile
 func (pc Pipeline) GetTaskDefinition(taskID string) (TaskDefinition, bool) {
 	if entry, ok := pc[taskID]; ok {
@@ -350,6 +705,10 @@ func MergeTaskDefinitions(taskDefinitions []BookkeepingTaskDefinition) (*TaskDef
 			mergedTaskDefinition.Outputs = taskDef.Outputs
 		}
 
+		if bookkeepingTaskDef.hasField("RestoreOnlyOutputs") {
+			mergedTaskDefinition.RestoreOnlyOutputs = taskDef.RestoreOnlyOutputs
+		}
+
 		if bookkeepingTaskDef.hasField("ShouldCache") {
 			mergedTaskDefinition.ShouldCache = taskDef.ShouldCache
 		}
@@ -358,6 +717,13 @@ func MergeTaskDefinitions(taskDefinitions []BookkeepingTaskDefinition) (*TaskDef
 			mergedTaskDefinition.EnvVarDependencies = taskDef.EnvVarDependencies
 		}
 
+		if bookkeepingTaskDef.hasField("EnvGroups") {
+			mergedTaskDefinition.EnvGroups = taskDef.EnvGroups
+		}
+		if bookkeepingTaskDef.hasField("PassThroughEnv") {
+			mergedTaskDefinition.PassThroughEnv = taskDef.PassThroughEnv
+		}
+
 		if bookkeepingTaskDef.hasField("DependsOn") {
 			mergedTaskDefinition.TopologicalDependencies = taskDef.TopologicalDependencies
 		}
@@ -370,17 +736,73 @@ func MergeTaskDefinitions(taskDefinitions []BookkeepingTaskDefinition) (*TaskDef
 			mergedTaskDefinition.Inputs = taskDef.Inputs
 		}
 
+		if bookkeepingTaskDef.hasField("RootInputs") {
+			mergedTaskDefinition.RootInputs = taskDef.RootInputs
+		}
+
 		if bookkeepingTaskDef.hasField("OutputMode") {
 			mergedTaskDefinition.OutputMode = taskDef.OutputMode
 		}
 		if bookkeepingTaskDef.hasField("Persistent") {
 			mergedTaskDefinition.Persistent = taskDef.Persistent
 		}
+		if bookkeepingTaskDef.hasField("Timeout") {
+			mergedTaskDefinition.Timeout = taskDef.Timeout
+		}
+		if bookkeepingTaskDef.hasField("Retries") {
+			mergedTaskDefinition.Retries = taskDef.Retries
+		}
+		if bookkeepingTaskDef.hasField("RetryBackoff") {
+			mergedTaskDefinition.RetryBackoff = taskDef.RetryBackoff
+		}
+		if bookkeepingTaskDef.hasField("Framework") {
+			mergedTaskDefinition.Framework = taskDef.Framework
+		}
+		if bookkeepingTaskDef.hasField("HashInputsFrom") {
+			mergedTaskDefinition.HashInputsFrom = taskDef.HashInputsFrom
+		}
+		if bookkeepingTaskDef.hasField("DotEnv") {
+			mergedTaskDefinition.DotEnv = taskDef.DotEnv
+		}
+		if bookkeepingTaskDef.hasField("OutputTransform") {
+			mergedTaskDefinition.OutputTransform = taskDef.OutputTransform
+		}
+		if bookkeepingTaskDef.hasField("PostCacheRestore") {
+			mergedTaskDefinition.PostCacheRestore = taskDef.PostCacheRestore
+		}
+		if bookkeepingTaskDef.hasField("OutputsFromLog") {
+			mergedTaskDefinition.OutputsFromLog = taskDef.OutputsFromLog
+		}
+		if bookkeepingTaskDef.hasField("Interactive") {
+			mergedTaskDefinition.Interactive = taskDef.Interactive
+		}
+		if bookkeepingTaskDef.hasField("ConcurrencyGroup") {
+			mergedTaskDefinition.ConcurrencyGroup = taskDef.ConcurrencyGroup
+		}
+		if bookkeepingTaskDef.hasField("ParallelUnsafe") {
+			mergedTaskDefinition.ParallelUnsafe = taskDef.ParallelUnsafe
+		}
+	}
+
+	// The fields can come from different turbo.json files
+	if mergedTaskDefinition.Persistent && mergedTaskDefinition.ParallelUnsafe {
+		return nil, errPersistentParallelUnsafe
+	}
+
+	// The output of interactive tasks goes straight to the terminal, so there is no log
+	// to cache or replay
+	if mergedTaskDefinition.Interactive {
+		mergedTaskDefinition.ShouldCache = false
 	}
 
 	return mergedTaskDefinition, nil
 }
 
+// errPersistentParallelUnsafe is returned for a task that is both persistent and not parallel
+// safe. It would keep the tasks that aren't parallel safe from running for as long as it runs,
+// which is forever.
+var errPersistentParallelUnsafe = errors.New("\"persistent\" tasks can't set \"parallelSafe\": false, since they never exit and the other tasks that aren't parallel safe would never run")
+
 // UnmarshalJSON deserializes a single task definition from
 // turbo.json into a TaskDefinition struct
 func (btd *BookkeepingTaskDefinition) UnmarshalJSON(data []byte) error {
@@ -421,6 +843,17 @@ func (btd *BookkeepingTaskDefinition) UnmarshalJSON(data []byte) error {
 		sort.Strings(btd.TaskDefinition.Outputs.Exclusions)
 	}
 
+	if task.RestoreOnlyOutputs != nil {
+		btd.definedFields.Add("RestoreOnlyOutputs")
+		for _, glob := range task.RestoreOnlyOutputs {
+			if filepath.IsAbs(glob) {
+				log.Printf("[WARNING] Using an absolute path in \"restoreOnlyOutputs\" (%v) will not work and will be an error in a future version", glob)
+			}
+		}
+		btd.TaskDefinition.RestoreOnlyOutputs = task.RestoreOnlyOutputs
+		sort.Strings(btd.TaskDefinition.RestoreOnlyOutputs)
+	}
+
 	if task.Cache == nil {
 		btd.TaskDefinition.ShouldCache = true
 	} else {
@@ -473,6 +906,28 @@ func (btd *BookkeepingTaskDefinition) UnmarshalJSON(data []byte) error {
 
 	sort.Strings(btd.TaskDefinition.EnvVarDependencies)
 
+	if task.EnvGroups != nil {
+		btd.definedFields.Add("EnvGroups")
+		btd.TaskDefinition.EnvGroups = task.EnvGroups
+		sort.Strings(btd.TaskDefinition.EnvGroups)
+	}
+
+	if task.PassThroughEnv != nil {
+		btd.definedFields.Add("PassThroughEnv")
+		passThroughEnv := make(util.Set)
+		for _, value := range task.PassThroughEnv {
+			if strings.HasPrefix(value, envPipelineDelimiter) {
+				return fmt.Errorf("You specified \"%s\" in the \"passThroughEnv\" key. You should not prefix your environment variables with \"$\"", value)
+			}
+			if envVarDependencies.Includes(value) {
+				return fmt.Errorf("\"%s\" is in both \"env\" and \"passThroughEnv\". Remove it from \"env\" if it doesn't affect the task's outputs", value)
+			}
+			passThroughEnv.Add(value)
+		}
+		btd.TaskDefinition.PassThroughEnv = passThroughEnv.UnsafeListOfStrings()
+		sort.Strings(btd.TaskDefinition.PassThroughEnv)
+	}
+
 	if task.Inputs != nil {
 		// Note that we don't require Inputs to be sorted, we're going to
 		// hash the resulting files and sort that instead
@@ -486,6 +941,16 @@ func (btd *BookkeepingTaskDefinition) UnmarshalJSON(data []byte) error {
 		btd.TaskDefinition.Inputs = task.Inputs
 	}
 
+	if task.RootInputs != nil {
+		btd.definedFields.Add("RootInputs")
+		for _, input := range task.RootInputs {
+			if filepath.IsAbs(strings.TrimPrefix(input, "!")) {
+				log.Printf("[WARNING] Using an absolute path in \"rootInputs\" (%v) will not work and will be an error in a future version", input)
+			}
+		}
+		btd.TaskDefinition.RootInputs = task.RootInputs
+	}
+
 	if task.OutputMode != nil {
 		btd.definedFields.Add("OutputMode")
 		btd.TaskDefinition.OutputMode = *task.OutputMode
@@ -497,9 +962,132 @@ func (btd *BookkeepingTaskDefinition) UnmarshalJSON(data []byte) error {
 	} else {
 		btd.TaskDefinition.Persistent = false
 	}
+
+	if task.Timeout != nil {
+		timeout, err := time.ParseDuration(*task.Timeout)
+		if err != nil {
+			return fmt.Errorf("invalid \"timeout\" %q, use a duration like \"120s\" or \"5m\"", *task.Timeout)
+		}
+		if timeout < 0 {
+			return fmt.Errorf("invalid \"timeout\" %q, it must not be negative", *task.Timeout)
+		}
+		btd.definedFields.Add("Timeout")
+		btd.TaskDefinition.Timeout = timeout
+	}
+
+	if task.Retries != nil {
+		if *task.Retries < 0 {
+			return fmt.Errorf("invalid \"retries\" %v, it must not be negative", *task.Retries)
+		}
+		btd.definedFields.Add("Retries")
+		btd.TaskDefinition.Retries = *task.Retries
+	}
+
+	if task.RetryBackoff != nil {
+		retryBackoff, err := time.ParseDuration(*task.RetryBackoff)
+		if err != nil {
+			return fmt.Errorf("invalid \"retryBackoff\" %q, use a duration like \"5s\"", *task.RetryBackoff)
+		}
+		if retryBackoff < 0 {
+			return fmt.Errorf("invalid \"retryBackoff\" %q, it must not be negative", *task.RetryBackoff)
+		}
+		btd.definedFields.Add("RetryBackoff")
+		btd.TaskDefinition.RetryBackoff = retryBackoff
+	}
+
+	if task.Framework != nil {
+		btd.definedFields.Add("Framework")
+		btd.TaskDefinition.Framework = *task.Framework
+	}
+
+	if task.HashInputsFrom != nil {
+		btd.definedFields.Add("HashInputsFrom")
+		btd.TaskDefinition.HashInputsFrom = *task.HashInputsFrom
+	}
+
+	if task.DotEnv != nil {
+		for _, dotEnvFile := range task.DotEnv {
+			if dotEnvFile == "" || filepath.IsAbs(dotEnvFile) || strings.HasPrefix(dotEnvFile, "/") {
+				return fmt.Errorf("invalid \"dotEnv\" file %q, use a path relative to the package", dotEnvFile)
+			}
+		}
+		btd.definedFields.Add("DotEnv")
+		// The order is kept, since later files take precedence
+		btd.TaskDefinition.DotEnv = task.DotEnv
+	}
+
+	if task.OutputTransform != nil {
+		if strings.HasPrefix(*task.OutputTransform, OutputTransformBuiltinPrefix) {
+			known := false
+			names := make([]string, len(_outputTransformBuiltins))
+			for i, builtin := range _outputTransformBuiltins {
+				known = known || *task.OutputTransform == OutputTransformBuiltinPrefix+builtin
+				names[i] = OutputTransformBuiltinPrefix + builtin
+			}
+			if !known {
+				return fmt.Errorf("unknown \"outputTransform\" %q, built-in transforms are: %v", *task.OutputTransform, strings.Join(names, ", "))
+			}
+		}
+		btd.definedFields.Add("OutputTransform")
+		btd.TaskDefinition.OutputTransform = *task.OutputTransform
+	}
+
+	if task.PostCacheRestore != nil {
+		if task.PostCacheRestore.Command == "" {
+			return fmt.Errorf("\"postCacheRestore\" requires a \"command\"")
+		}
+		btd.definedFields.Add("PostCacheRestore")
+		btd.TaskDefinition.PostCacheRestore = task.PostCacheRestore
+	}
+
+	if task.OutputsFromLog != nil {
+		pattern, err := regexp.Compile(*task.OutputsFromLog)
+		if err != nil {
+			return fmt.Errorf("invalid \"outputsFromLog\": %w", err)
+		}
+		if pattern.NumSubexp() < 1 {
+			return fmt.Errorf("\"outputsFromLog\" must capture the path of the output in a group, found %v", *task.OutputsFromLog)
+		}
+		btd.definedFields.Add("OutputsFromLog")
+		btd.TaskDefinition.OutputsFromLog = *task.OutputsFromLog
+	}
+
+	if task.Interactive != nil {
+		if *task.Interactive && task.Cache != nil && *task.Cache {
+			return fmt.Errorf("\"interactive\" tasks can't be cached, since their output isn't captured. Remove \"cache\": true")
+		}
+		btd.definedFields.Add("Interactive")
+		btd.TaskDefinition.Interactive = *task.Interactive
+	}
+
+	if task.ConcurrencyGroup != nil {
+		btd.definedFields.Add("ConcurrencyGroup")
+		btd.TaskDefinition.ConcurrencyGroup = *task.ConcurrencyGroup
+	}
+
+	if task.ParallelSafe != nil {
+		btd.definedFields.Add("ParallelUnsafe")
+		btd.TaskDefinition.ParallelUnsafe = !*task.ParallelSafe
+	}
+	if btd.TaskDefinition.Persistent && btd.TaskDefinition.ParallelUnsafe {
+		return errPersistentParallelUnsafe
+	}
 	return nil
 }
 
+// UnhashedGlobalPassThroughEnv returns the env vars of "globalPassThroughEnv" that are left out
+// of the hash of the Task. A var that the Task lists in its own "env" is hashed into it.
+func (c TaskDefinition) UnhashedGlobalPassThroughEnv(globalPassThroughEnv []string) []string {
+	envVarDependencies := util.SetFromStrings(c.EnvVarDependencies)
+	unhashed := []string{}
+	for _, name := range globalPassThroughEnv {
+		if !envVarDependencies.Includes(name) {
+			unhashed = append(unhashed, name)
+		}
+	}
+	return unhashed
+}
+
 // MarshalJSON serializes TaskDefinition struct into json
 func (c TaskDefinition) MarshalJSON() ([]byte, error) {
 	// Initialize with empty arrays, so we get empty arrays serialized into JSON
@@ -511,17 +1099,46 @@ func (c TaskDefinition) MarshalJSON() ([]byte, error) {
 	}
 
 	task.Persistent = c.Persistent
+	if c.Timeout > 0 {
+		task.Timeout = c.Timeout.String()
+	}
+	task.Retries = c.Retries
+	if c.RetryBackoff > 0 {
+		task.RetryBackoff = c.RetryBackoff.String()
+	}
 	task.Cache = &c.ShouldCache
 	task.OutputMode = c.OutputMode
+	task.Framework = c.Framework
+	task.HashInputsFrom = c.HashInputsFrom
+	task.DotEnv = c.DotEnv
+	task.OutputTransform = c.OutputTransform
+	task.PostCacheRestore = c.PostCacheRestore
+	task.OutputsFromLog = c.OutputsFromLog
+	task.Interactive = c.Interactive
+	task.ConcurrencyGroup = c.ConcurrencyGroup
+	if c.ParallelUnsafe {
+		parallelSafe := false
+		task.ParallelSafe = &parallelSafe
+	}
 
 	if len(c.Inputs) > 0 {
 		task.Inputs = c.Inputs
 	}
 
+	task.RootInputs = c.RootInputs
+
 	if len(c.EnvVarDependencies) > 0 {
 		task.Env = append(task.Env, c.EnvVarDependencies...)
 	}
 
+	if len(c.EnvGroups) > 0 {
+		task.EnvGroups = c.EnvGroups
+	}
+
+	if len(c.PassThroughEnv) > 0 {
+		task.PassThroughEnv = c.PassThroughEnv
+	}
+
 	if len(c.Outputs.Inclusions) > 0 {
 		task.Outputs = append(task.Outputs, c.Outputs.Inclusions...)
 	}
@@ -530,6 +1147,10 @@ func (c TaskDefinition) MarshalJSON() ([]byte, error) {
 		task.Outputs = append(task.Outputs, "!"+i)
 	}
 
+	if len(c.RestoreOnlyOutputs) > 0 {
+		task.RestoreOnlyOutputs = c.RestoreOnlyOutputs
+	}
+
 	if len(c.TaskDependencies) > 0 {
 		task.DependsOn = append(task.DependsOn, c.TaskDependencies...)
 	}
@@ -545,6 +1166,8 @@ func (c TaskDefinition) MarshalJSON() ([]byte, error) {
 	sort.Strings(task.Outputs)
 	sort.Strings(task.Env)
 	sort.Strings(task.Inputs)
+	sort.Strings(task.RootInputs)
+	sort.Strings(task.RestoreOnlyOutputs)
 
 	return json.Marshal(task)
 }
@@ -582,16 +1205,64 @@ func (c *TurboJSON) UnmarshalJSON(data []byte) error {
 		}
 	}
 
+	globalPassThroughEnv := make(util.Set)
+	for _, value := range raw.GlobalPassThroughEnv {
+		if strings.HasPrefix(value, envPipelineDelimiter) {
+			return fmt.Errorf("You specified \"%s\" in the \"globalPassThroughEnv\" key. You should not prefix your environment variables with \"%s\"", value, envPipelineDelimiter)
+		}
+		if envVarDependencies.Includes(value) {
+			return fmt.Errorf("\"%s\" is in both \"globalEnv\" and \"globalPassThroughEnv\". Remove it from \"globalEnv\" if it doesn't affect the outputs of tasks", value)
+		}
+		globalPassThroughEnv.Add(value)
+	}
+
 	// turn the set into an array and assign to the TurboJSON struct fields.
 	c.GlobalEnv = envVarDependencies.UnsafeListOfStrings()
 	sort.Strings(c.GlobalEnv)
 	c.GlobalDeps = globalFileDependencies.UnsafeListOfStrings()
 	sort.Strings(c.GlobalDeps)
+	if globalPassThroughEnv.Len() > 0 {
+		c.GlobalPassThroughEnv = globalPassThroughEnv.UnsafeListOfStrings()
+		sort.Strings(c.GlobalPassThroughEnv)
+	}
+	if len(raw.GlobalToolVersions) > 0 {
+		c.GlobalToolVersions = util.SetFromStrings(raw.GlobalToolVersions).UnsafeListOfStrings()
+		sort.Strings(c.GlobalToolVersions)
+	}
 
 	// copy these over, we don't need any changes here.
 	c.Pipeline = raw.Pipeline
 	c.RemoteCacheOptions = raw.RemoteCacheOptions
 	c.Extends = raw.Extends
+	c.TurboVersion = raw.TurboVersion
+	if raw.TaskHooks != nil && (raw.TaskHooks.Before != "" || raw.TaskHooks.After != "") {
+		c.TaskHooks = raw.TaskHooks
+	}
+
+	if len(raw.EnvGroups) > 0 {
+		c.EnvGroups = make(map[string][]string, len(raw.EnvGroups))
+		for name, envVars := range raw.EnvGroups {
+			for _, value := range envVars {
+				if strings.HasPrefix(value, envPipelineDelimiter) {
+					return fmt.Errorf("You specified \"%s\" in the \"%s\" env group. You should not prefix your environment variables with \"%s\"", value, name, envPipelineDelimiter)
+				}
+			}
+			sorted := append([]string{}, envVars...)
+			sort.Strings(sorted)
+			c.EnvGroups[name] = sorted
+		}
+	}
+
+	if len(raw.ExitCodeCategories) > 0 {
+		c.ExitCodeCategories = make(map[int]string, len(raw.ExitCodeCategories))
+		for rawCode, category := range raw.ExitCodeCategories {
+			code, err := strconv.Atoi(rawCode)
+			if err != nil {
+				return fmt.Errorf("invalid exit code %q in \"exitCodeCategories\", keys must be integers", rawCode)
+			}
+			c.ExitCodeCategories[code] = category
+		}
+	}
 
 	return nil
 }
@@ -602,8 +1273,19 @@ func (c *TurboJSON) MarshalJSON() ([]byte, error) {
 	raw := pristineTurboJSON{}
 	raw.GlobalDependencies = c.GlobalDeps
 	raw.GlobalEnv = c.GlobalEnv
+	raw.GlobalPassThroughEnv = c.GlobalPassThroughEnv
+	raw.GlobalToolVersions = c.GlobalToolVersions
 	raw.Pipeline = c.Pipeline.Pristine()
 	raw.RemoteCacheOptions = c.RemoteCacheOptions
+	raw.TurboVersion = c.TurboVersion
+	raw.EnvGroups = c.EnvGroups
+	raw.TaskHooks = c.TaskHooks
+	if len(c.ExitCodeCategories) > 0 {
+		raw.ExitCodeCategories = make(map[string]string, len(c.ExitCodeCategories))
+		for code, category := range c.ExitCodeCategories {
+			raw.ExitCodeCategories[strconv.Itoa(code)] = category
+		}
+	}
 
 	return json.Marshal(&raw)
 }
@@ -0,0 +1,407 @@
+package fs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/vercel/turbo/cli/internal/doublestar"
+	"github.com/vercel/turbo/cli/internal/util"
+)
+
+// _schemaKey is the key that editors read the JSON schema of turbo.json from. It isn't
+// part of the config, but every turbo.json may have it.
+const _schemaKey = "$schema"
+
+// _globKeys are the keys whose values are lists of globs, which are checked for valid syntax
+var _globKeys = util.SetFromStrings([]string{"globalDependencies", "outputs", "restoreOnlyOutputs", "inputs", "rootInputs"})
+
+// _schemaTypes maps the types turbo.json is unmarshaled into to the types that describe
+// their JSON, for the types that unmarshal themselves from a different structure
+var _schemaTypes = map[reflect.Type]reflect.Type{
+	reflect.TypeOf(BookkeepingTaskDefinition{}): reflect.TypeOf(rawTask{}),
+	reflect.TypeOf(util.TaskOutputMode(0)):      reflect.TypeOf(""),
+}
+
+// ConfigError is a problem at a position in a config file
+type ConfigError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("%v:%v: %v", e.Line, e.Column, e.Message)
+}
+
+// ValidateTurboJSON checks the contents of a turbo.json, which may have comments, against
+// the keys and types that turbo.json supports. It returns every unknown key, value of the
+// wrong type and invalid glob, with the line and column it is at, or the syntax error that
+// stops the contents from being read.
+func ValidateTurboJSON(data []byte) []error {
+	stripped := stripComments(data)
+	v := &schemaValidator{data: data, stripped: stripped}
+	v.decoder = json.NewDecoder(bytes.NewReader(stripped))
+	v.decoder.UseNumber()
+	if err := v.validate(reflect.TypeOf(rawTurboJSON{}), ""); err != nil {
+		return []error{v.syntaxError(err)}
+	}
+	return v.problems
+}
+
+type schemaValidator struct {
+	data []byte
+	// stripped is data without comments, at the same offsets
+	stripped []byte
+	decoder  *json.Decoder
+	problems []error
+}
+
+// validate reads the next value, and records the problems it has as a value of type t.
+// path is the keys leading to the value, for messages. An error is returned if the
+// contents aren't valid JSON.
+func (v *schemaValidator) validate(t reflect.Type, path string) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if schemaType, ok := _schemaTypes[t]; ok {
+		t = schemaType
+	}
+	start := v.tokenStart()
+	token, err := v.decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if token != json.Delim('{') {
+			v.problem(start, "%v must be an object", describe(path))
+			return v.skip(token)
+		}
+		fields := jsonFields(t)
+		for v.decoder.More() {
+			keyStart := v.tokenStart()
+			keyToken, err := v.decoder.Token()
+			if err != nil {
+				return err
+			}
+			key := keyToken.(string)
+			field, ok := fields[key]
+			if !ok && path == "" && key == _schemaKey {
+				field, ok = reflect.TypeOf(""), true
+			}
+			if !ok {
+				v.problem(keyStart, "unknown key \"%v\" in %v%v", key, describe(path), suggest(key, fields))
+				if err := v.skipValue(); err != nil {
+					return err
+				}
+				continue
+			}
+			if _globKeys.Includes(key) {
+				if err := v.validateGlobs(joinPath(path, key)); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := v.validate(field, joinPath(path, key)); err != nil {
+				return err
+			}
+		}
+		_, err := v.decoder.Token()
+		return err
+	case reflect.Map:
+		if token != json.Delim('{') {
+			v.problem(start, "%v must be an object", describe(path))
+			return v.skip(token)
+		}
+		for v.decoder.More() {
+			keyToken, err := v.decoder.Token()
+			if err != nil {
+				return err
+			}
+			if err := v.validate(t.Elem(), joinPath(path, keyToken.(string))); err != nil {
+				return err
+			}
+		}
+		_, err := v.decoder.Token()
+		return err
+	case reflect.Slice:
+		if token != json.Delim('[') {
+			v.problem(start, "%v must be an array of %vs", describe(path), kindName(t.Elem()))
+			return v.skip(token)
+		}
+		for v.decoder.More() {
+			if err := v.validate(t.Elem(), path); err != nil {
+				return err
+			}
+		}
+		_, err := v.decoder.Token()
+		return err
+	case reflect.String:
+		if _, ok := token.(string); !ok {
+			v.problem(start, "%v must be a string", describe(path))
+		}
+	case reflect.Bool:
+		if _, ok := token.(bool); !ok {
+			v.problem(start, "%v must be a boolean", describe(path))
+		}
+	case reflect.Int:
+		if number, ok := token.(json.Number); !ok {
+			v.problem(start, "%v must be an integer", describe(path))
+		} else if _, err := number.Int64(); err != nil {
+			v.problem(start, "%v must be an integer", describe(path))
+		}
+	}
+	return v.skip(token)
+}
+
+// validateGlobs reads a list of globs, and records the globs that aren't valid. Globs may be
+// prefixed with "!" to exclude the files they match.
+func (v *schemaValidator) validateGlobs(path string) error {
+	start := v.tokenStart()
+	token, err := v.decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != json.Delim('[') {
+		v.problem(start, "%v must be an array of strings", describe(path))
+		return v.skip(token)
+	}
+	for v.decoder.More() {
+		globStart := v.tokenStart()
+		token, err := v.decoder.Token()
+		if err != nil {
+			return err
+		}
+		glob, ok := token.(string)
+		if !ok {
+			v.problem(globStart, "%v must be an array of strings", describe(path))
+			if err := v.skip(token); err != nil {
+				return err
+			}
+			continue
+		}
+		if !doublestar.ValidatePattern(strings.TrimPrefix(glob, "!")) {
+			v.problem(globStart, "invalid glob \"%v\" in %v", glob, describe(path))
+		}
+	}
+	_, err = v.decoder.Token()
+	return err
+}
+
+// skipValue reads the next value without checking it
+func (v *schemaValidator) skipValue() error {
+	token, err := v.decoder.Token()
+	if err != nil {
+		return err
+	}
+	return v.skip(token)
+}
+
+// skip reads the rest of the value that token starts, if it is an object or an array
+func (v *schemaValidator) skip(token json.Token) error {
+	if token != json.Delim('{') && token != json.Delim('[') {
+		return nil
+	}
+	for depth := 1; depth > 0; {
+		token, err := v.decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+	return nil
+}
+
+// tokenStart returns the offset of the next token, skipping the whitespace and separators
+// that the decoder reads before it
+func (v *schemaValidator) tokenStart() int64 {
+	offset := v.decoder.InputOffset()
+	for offset < int64(len(v.stripped)) {
+		switch v.stripped[offset] {
+		case ' ', '\t', '\r', '\n', ':', ',':
+			offset++
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+func (v *schemaValidator) problem(offset int64, format string, args ...interface{}) {
+	line, column := v.position(offset)
+	v.problems = append(v.problems, &ConfigError{Line: line, Column: column, Message: fmt.Sprintf(format, args...)})
+}
+
+// syntaxError adds the position of the error to errors from reading invalid JSON
+func (v *schemaValidator) syntaxError(err error) error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		// The offset is just past the character that isn't valid
+		line, column := v.position(syntaxErr.Offset - 1)
+		return &ConfigError{Line: line, Column: column, Message: syntaxErr.Error()}
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		line, column := v.position(int64(len(v.data)))
+		return &ConfigError{Line: line, Column: column, Message: "unexpected end of JSON input"}
+	}
+	return err
+}
+
+// position returns the 1-based line and column of an offset into the contents
+func (v *schemaValidator) position(offset int64) (int, int) {
+	if offset > int64(len(v.data)) {
+		offset = int64(len(v.data))
+	}
+	before := v.data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := len(before) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
+
+// jsonFields returns the types of the fields of a struct, keyed by their names in JSON
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// suggest returns a hint naming the known key that the given key is most likely a typo of,
+// if there is one
+func suggest(key string, fields map[string]reflect.Type) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	best, bestDistance := "", 3
+	for _, name := range names {
+		if distance := editDistance(strings.ToLower(key), strings.ToLower(name)); distance < bestDistance {
+			best, bestDistance = name, distance
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(", did you mean \"%v\"?", best)
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(minInt(previous[j]+1, current[j-1]+1), previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func describe(path string) string {
+	if path == "" {
+		return configFile
+	}
+	return fmt.Sprintf("\"%v\"", path)
+}
+
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func kindName(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int:
+		return "integer"
+	case reflect.Struct, reflect.Map:
+		return "object"
+	}
+	return "string"
+}
+
+// stripComments replaces the comments of JSON with comments by spaces, keeping line breaks,
+// so that offsets into the result are offsets into the original contents
+func stripComments(data []byte) []byte {
+	out := make([]byte, len(data))
+	copy(out, data)
+	inString := false
+	for i := 0; i < len(out); i++ {
+		c := out[i]
+		if inString {
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+		if c == '"' {
+			inString = true
+			continue
+		}
+		if c != '/' || i+1 >= len(out) {
+			continue
+		}
+		switch out[i+1] {
+		case '/':
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				out[i] = ' '
+			}
+		case '*':
+			end := bytes.Index(out[i+2:], []byte("*/"))
+			last := len(out)
+			if end >= 0 {
+				last = i + 2 + end + 2
+			}
+			for ; i < last; i++ {
+				if out[i] != '\n' {
+					out[i] = ' '
+				}
+			}
+			i--
+		}
+	}
+	return out
+}
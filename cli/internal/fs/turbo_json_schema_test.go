@@ -0,0 +1,87 @@
+package fs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ValidateTurboJSON(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config string
+		want   []string
+	}{
+		{
+			name: "valid config with comments",
+			config: `{
+  "$schema": "https://turbo.build/schema.json",
+  // comments are allowed
+  "globalEnv": ["CI"],
+  "pipeline": {
+    "build": {"outputs": ["dist/**", "!dist/cache/**"], "cache": true, "retries": 2, "outputMode": "new-only"}
+  }
+}`,
+			want: nil,
+		},
+		{
+			name: "unknown keys",
+			config: `{
+  "globlaEnv": ["CI"],
+  "pipeline": {
+    "build": {"output": ["dist/**"], "ignored": true}
+  }
+}`,
+			want: []string{
+				`2:3: unknown key "globlaEnv" in turbo.json, did you mean "globalEnv"?`,
+				`4:15: unknown key "output" in "pipeline.build", did you mean "outputs"?`,
+				`4:38: unknown key "ignored" in "pipeline.build"`,
+			},
+		},
+		{
+			name: "wrong types",
+			config: `{
+  "globalEnv": "CI",
+  "pipeline": {
+    "build": {"cache": "false", "dependsOn": [1], "retries": 1.5, "remoteCache": {}}
+  },
+  "remoteCache": {"signature": "yes"}
+}`,
+			want: []string{
+				`2:16: "globalEnv" must be an array of strings`,
+				`4:24: "pipeline.build.cache" must be a boolean`,
+				`4:47: "pipeline.build.dependsOn" must be a string`,
+				`4:62: "pipeline.build.retries" must be an integer`,
+				`4:67: unknown key "remoteCache" in "pipeline.build"`,
+				`6:32: "remoteCache.signature" must be a boolean`,
+			},
+		},
+		{
+			name:   "invalid globs",
+			config: `{"globalDependencies": ["tsconfig.json", "[abc"], "pipeline": {"build": {"inputs": ["src/{a,b"]}}}`,
+			want: []string{
+				`1:42: invalid glob "[abc" in "globalDependencies"`,
+				`1:85: invalid glob "src/{a,b" in "pipeline.build.inputs"`,
+			},
+		},
+		{
+			name:   "syntax error",
+			config: "{\n  \"pipeline\": {\n    \"build\": {,}\n  }\n}",
+			want:   []string{`3:15: invalid character ',' looking for beginning of value`},
+		},
+		{
+			name:   "truncated",
+			config: "{\n  \"pipeline\": {",
+			want:   []string{`2:15: unexpected end of JSON input`},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, problem := range ValidateTurboJSON([]byte(tc.config)) {
+				got = append(got, problem.Error())
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -1,11 +1,14 @@
 package fs
 
 import (
+	"encoding/json"
 	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/vercel/turbo/cli/internal/turbopath"
@@ -37,9 +40,10 @@ func Test_ReadTurboConfig(t *testing.T) {
 
 	pipelineExpected := map[string]BookkeepingTaskDefinition{
 		"build": {
-			definedFields: util.SetFromStrings([]string{"Outputs", "OutputMode", "DependsOn"}),
+			definedFields: util.SetFromStrings([]string{"Outputs", "RestoreOnlyOutputs", "OutputMode", "DependsOn"}),
 			TaskDefinition: TaskDefinition{
 				Outputs:                 TaskOutputs{Inclusions: []string{".next/**", "dist/**"}, Exclusions: []string{"dist/assets/**"}},
+				RestoreOnlyOutputs:      []string{"src/generated/**"},
 				TopologicalDependencies: []string{"build"},
 				EnvVarDependencies:      []string{},
 				TaskDependencies:        []string{},
@@ -70,7 +74,7 @@ func Test_ReadTurboConfig(t *testing.T) {
 			},
 		},
 		"publish": {
-			definedFields: util.SetFromStrings([]string{"Inputs", "Outputs", "DependsOn", "ShouldCache"}),
+			definedFields: util.SetFromStrings([]string{"Inputs", "RootInputs", "Outputs", "DependsOn", "ShouldCache"}),
 			TaskDefinition: TaskDefinition{
 				Outputs:                 TaskOutputs{Inclusions: []string{"dist/**"}},
 				TopologicalDependencies: []string{"build", "publish"},
@@ -78,6 +82,7 @@ func Test_ReadTurboConfig(t *testing.T) {
 				TaskDependencies:        []string{"admin#lint", "build"},
 				ShouldCache:             false,
 				Inputs:                  []string{"build/**/*"},
+				RootInputs:              []string{"schemas/**", "!schemas/README.md"},
 				OutputMode:              util.FullTaskOutput,
 			},
 		},
@@ -196,6 +201,367 @@ func Test_TaskOutputsSort(t *testing.T) {
 	assert.False(t, cmp.DeepEqual(taskOutputs, sortedOutputs)().Success())
 }
 
+func Test_CheckTurboVersion(t *testing.T) {
+	testCases := []struct {
+		name         string
+		turboVersion string
+		version      string
+		wantErr      bool
+	}{
+		{name: "no requirement", turboVersion: "", version: "1.8.0"},
+		{name: "satisfied", turboVersion: ">=1.7.0", version: "1.8.0"},
+		{name: "not satisfied", turboVersion: "^1.9.0", version: "1.8.0", wantErr: true},
+		{name: "canary of satisfying release", turboVersion: ">=1.8.0", version: "1.8.0-canary.2"},
+		{name: "unparseable version", turboVersion: ">=1.8.0", version: "dev"},
+		{name: "invalid range", turboVersion: "not a range", version: "1.8.0", wantErr: true},
+	}
+	for _, tc := range testCases {
+		turboJSON := &TurboJSON{TurboVersion: tc.turboVersion}
+		err := turboJSON.CheckTurboVersion(tc.version)
+		if tc.wantErr {
+			assert.Error(t, err, tc.name)
+		} else {
+			assert.NoError(t, err, tc.name)
+		}
+	}
+}
+
+func Test_ExitCodeCategories(t *testing.T) {
+	turboJSON := &TurboJSON{}
+	err := json.Unmarshal([]byte(`{"pipeline": {}, "exitCodeCategories": {"2": "test failure", "127": "missing binary"}}`), turboJSON)
+	assert.NoError(t, err, "unmarshal")
+	assert.EqualValues(t, map[int]string{2: "test failure", 127: "missing binary"}, turboJSON.ExitCodeCategories)
+
+	marshalled, err := json.Marshal(turboJSON)
+	assert.NoError(t, err, "marshal")
+	roundTripped := &TurboJSON{}
+	assert.NoError(t, json.Unmarshal(marshalled, roundTripped), "unmarshal round trip")
+	assert.EqualValues(t, turboJSON.ExitCodeCategories, roundTripped.ExitCodeCategories)
+
+	err = json.Unmarshal([]byte(`{"pipeline": {}, "exitCodeCategories": {"two": "test failure"}}`), &TurboJSON{})
+	assert.Error(t, err, "non-integer exit code")
+}
+
+func Test_TaskHooks(t *testing.T) {
+	turboJSON := &TurboJSON{}
+	err := json.Unmarshal([]byte(`{"pipeline": {}, "taskHooks": {"before": "./trace.sh start", "after": "./trace.sh end"}}`), turboJSON)
+	assert.NoError(t, err, "unmarshal")
+	assert.EqualValues(t, &TaskHooks{Before: "./trace.sh start", After: "./trace.sh end"}, turboJSON.TaskHooks)
+
+	marshalled, err := json.Marshal(turboJSON)
+	assert.NoError(t, err, "marshal")
+	roundTripped := &TurboJSON{}
+	assert.NoError(t, json.Unmarshal(marshalled, roundTripped), "unmarshal round trip")
+	assert.EqualValues(t, turboJSON.TaskHooks, roundTripped.TaskHooks)
+
+	empty := &TurboJSON{}
+	assert.NoError(t, json.Unmarshal([]byte(`{"pipeline": {}, "taskHooks": {}}`), empty), "unmarshal empty hooks")
+	assert.Nil(t, empty.TaskHooks)
+}
+
+func Test_EnvGroups(t *testing.T) {
+	turboJSON := &TurboJSON{}
+	err := json.Unmarshal([]byte(`{
+		"envGroups": {"buildEnv": ["API_URL", "NODE_ENV"], "testEnv": ["CI"]},
+		"pipeline": {
+			"build": {"env": ["SENTRY_DSN"], "envGroups": ["buildEnv"]},
+			"test": {"envGroups": ["testEnv", "buildEnv"]},
+			"lint": {"envGroups": ["lintEnv"]}
+		}
+	}`), turboJSON)
+	assert.NoError(t, err, "unmarshal")
+	assert.EqualValues(t, map[string][]string{"buildEnv": {"API_URL", "NODE_ENV"}, "testEnv": {"CI"}}, turboJSON.EnvGroups)
+
+	build := turboJSON.Pipeline["build"].TaskDefinition
+	assert.NoError(t, turboJSON.ResolveEnvGroups(&build), "resolve build")
+	assert.EqualValues(t, []string{"API_URL", "NODE_ENV", "SENTRY_DSN"}, build.EnvVarDependencies)
+
+	test := turboJSON.Pipeline["test"].TaskDefinition
+	assert.EqualValues(t, []string{"buildEnv", "testEnv"}, test.EnvGroups)
+	assert.NoError(t, turboJSON.ResolveEnvGroups(&test), "resolve test")
+	assert.EqualValues(t, []string{"API_URL", "CI", "NODE_ENV"}, test.EnvVarDependencies)
+
+	lint := turboJSON.Pipeline["lint"].TaskDefinition
+	assert.EqualError(t, turboJSON.ResolveEnvGroups(&lint), "env group \"lintEnv\" is not defined in \"envGroups\" in the root turbo.json")
+
+	marshalled, err := json.Marshal(turboJSON)
+	assert.NoError(t, err, "marshal")
+	roundTripped := &TurboJSON{}
+	assert.NoError(t, json.Unmarshal(marshalled, roundTripped), "unmarshal round trip")
+	assert.EqualValues(t, turboJSON.EnvGroups, roundTripped.EnvGroups)
+	assert.EqualValues(t, test.EnvGroups, roundTripped.Pipeline["test"].TaskDefinition.EnvGroups)
+
+	err = json.Unmarshal([]byte(`{"pipeline": {}, "envGroups": {"buildEnv": ["$API_URL"]}}`), &TurboJSON{})
+	assert.EqualError(t, err, "You specified \"$API_URL\" in the \"buildEnv\" env group. You should not prefix your environment variables with \"$\"")
+}
+
+func Test_PostCacheRestore(t *testing.T) {
+	turboJSON := &TurboJSON{}
+	err := json.Unmarshal([]byte(`{
+		"pipeline": {
+			"build": {"postCacheRestore": {"command": "./relink.sh", "fatal": true}},
+			"test": {}
+		}
+	}`), turboJSON)
+	assert.NoError(t, err, "unmarshal")
+	assert.Equal(t, &PostCacheRestoreHook{Command: "./relink.sh", Fatal: true}, turboJSON.Pipeline["build"].TaskDefinition.PostCacheRestore)
+	assert.Nil(t, turboJSON.Pipeline["test"].TaskDefinition.PostCacheRestore)
+
+	merged, err := MergeTaskDefinitions([]BookkeepingTaskDefinition{turboJSON.Pipeline["build"], turboJSON.Pipeline["test"]})
+	assert.NoError(t, err, "merge")
+	assert.Equal(t, "./relink.sh", merged.PostCacheRestore.Command)
+
+	err = json.Unmarshal([]byte(`{"pipeline": {"build": {"postCacheRestore": {"fatal": true}}}}`), &TurboJSON{})
+	assert.EqualError(t, err, "\"postCacheRestore\" requires a \"command\"")
+}
+
+func Test_PassThroughEnv(t *testing.T) {
+	turboJSON := &TurboJSON{}
+	err := json.Unmarshal([]byte(`{
+		"pipeline": {
+			"build": {"env": ["API_URL"], "passThroughEnv": ["TELEMETRY", "LOG_LEVEL", "TELEMETRY"]}
+		}
+	}`), turboJSON)
+	assert.NoError(t, err, "unmarshal")
+	build := turboJSON.Pipeline["build"].TaskDefinition
+	assert.EqualValues(t, []string{"API_URL"}, build.EnvVarDependencies)
+	assert.EqualValues(t, []string{"LOG_LEVEL", "TELEMETRY"}, build.PassThroughEnv)
+
+	err = json.Unmarshal([]byte(`{"pipeline": {"build": {"passThroughEnv": ["$TELEMETRY"]}}}`), &TurboJSON{})
+	assert.EqualError(t, err, "You specified \"$TELEMETRY\" in the \"passThroughEnv\" key. You should not prefix your environment variables with \"$\"")
+
+	err = json.Unmarshal([]byte(`{"pipeline": {"build": {"env": ["TELEMETRY"], "passThroughEnv": ["TELEMETRY"]}}}`), &TurboJSON{})
+	assert.EqualError(t, err, "\"TELEMETRY\" is in both \"env\" and \"passThroughEnv\". Remove it from \"env\" if it doesn't affect the task's outputs")
+}
+
+func Test_GlobalPassThroughEnv(t *testing.T) {
+	turboJSON := &TurboJSON{}
+	err := json.Unmarshal([]byte(`{
+		"globalEnv": ["API_URL"],
+		"globalPassThroughEnv": ["HOME", "CI", "HOME"],
+		"pipeline": {"build": {}}
+	}`), turboJSON)
+	assert.NoError(t, err, "unmarshal")
+	assert.EqualValues(t, []string{"API_URL"}, turboJSON.GlobalEnv)
+	assert.EqualValues(t, []string{"CI", "HOME"}, turboJSON.GlobalPassThroughEnv)
+
+	marshalled, err := json.Marshal(turboJSON)
+	assert.NoError(t, err, "marshal")
+	assert.Contains(t, string(marshalled), `"globalPassThroughEnv":["CI","HOME"]`)
+
+	err = json.Unmarshal([]byte(`{"globalPassThroughEnv": ["$CI"], "pipeline": {}}`), &TurboJSON{})
+	assert.EqualError(t, err, "You specified \"$CI\" in the \"globalPassThroughEnv\" key. You should not prefix your environment variables with \"$\"")
+
+	err = json.Unmarshal([]byte(`{"globalEnv": ["CI"], "globalPassThroughEnv": ["CI"], "pipeline": {}}`), &TurboJSON{})
+	assert.EqualError(t, err, "\"CI\" is in both \"globalEnv\" and \"globalPassThroughEnv\". Remove it from \"globalEnv\" if it doesn't affect the outputs of tasks")
+}
+
+func Test_UnhashedGlobalPassThroughEnv(t *testing.T) {
+	taskDefinition := TaskDefinition{EnvVarDependencies: []string{"API_URL", "CI"}}
+	assert.EqualValues(t, []string{"HOME"}, taskDefinition.UnhashedGlobalPassThroughEnv([]string{"CI", "HOME"}))
+	assert.EqualValues(t, []string{"CI", "HOME"}, TaskDefinition{}.UnhashedGlobalPassThroughEnv([]string{"CI", "HOME"}))
+}
+
+func Test_Timeout(t *testing.T) {
+	turboJSON := &TurboJSON{}
+	err := json.Unmarshal([]byte(`{"pipeline": {"test": {"timeout": "120s"}, "build": {}}}`), turboJSON)
+	assert.NoError(t, err, "unmarshal")
+	assert.Equal(t, 2*time.Minute, turboJSON.Pipeline["test"].TaskDefinition.Timeout)
+	assert.Equal(t, time.Duration(0), turboJSON.Pipeline["build"].TaskDefinition.Timeout)
+
+	marshalled, err := json.Marshal(turboJSON.Pipeline["test"].TaskDefinition)
+	assert.NoError(t, err, "marshal")
+	assert.Contains(t, string(marshalled), `"timeout":"2m0s"`)
+
+	err = json.Unmarshal([]byte(`{"pipeline": {"test": {"timeout": "forever"}}}`), &TurboJSON{})
+	assert.EqualError(t, err, "invalid \"timeout\" \"forever\", use a duration like \"120s\" or \"5m\"")
+	err = json.Unmarshal([]byte(`{"pipeline": {"test": {"timeout": "-5s"}}}`), &TurboJSON{})
+	assert.EqualError(t, err, "invalid \"timeout\" \"-5s\", it must not be negative")
+}
+
+func Test_Retries(t *testing.T) {
+	turboJSON := &TurboJSON{}
+	err := json.Unmarshal([]byte(`{"pipeline": {"test": {"retries": 2, "retryBackoff": "5s"}, "build": {}}}`), turboJSON)
+	assert.NoError(t, err, "unmarshal")
+	assert.Equal(t, 2, turboJSON.Pipeline["test"].TaskDefinition.Retries)
+	assert.Equal(t, 5*time.Second, turboJSON.Pipeline["test"].TaskDefinition.RetryBackoff)
+	assert.Equal(t, 0, turboJSON.Pipeline["build"].TaskDefinition.Retries)
+	assert.Equal(t, time.Duration(0), turboJSON.Pipeline["build"].TaskDefinition.RetryBackoff)
+
+	marshalled, err := json.Marshal(turboJSON.Pipeline["test"].TaskDefinition)
+	assert.NoError(t, err, "marshal")
+	assert.Contains(t, string(marshalled), `"retries":2`)
+	assert.Contains(t, string(marshalled), `"retryBackoff":"5s"`)
+
+	err = json.Unmarshal([]byte(`{"pipeline": {"test": {"retries": -1}}}`), &TurboJSON{})
+	assert.EqualError(t, err, "invalid \"retries\" -1, it must not be negative")
+	err = json.Unmarshal([]byte(`{"pipeline": {"test": {"retryBackoff": "soon"}}}`), &TurboJSON{})
+	assert.EqualError(t, err, "invalid \"retryBackoff\" \"soon\", use a duration like \"5s\"")
+	err = json.Unmarshal([]byte(`{"pipeline": {"test": {"retryBackoff": "-5s"}}}`), &TurboJSON{})
+	assert.EqualError(t, err, "invalid \"retryBackoff\" \"-5s\", it must not be negative")
+}
+
+func Test_DotEnv(t *testing.T) {
+	turboJSON := &TurboJSON{}
+	err := json.Unmarshal([]byte(`{"pipeline": {"build": {"dotEnv": [".env.local", ".env"]}, "test": {}}}`), turboJSON)
+	assert.NoError(t, err, "unmarshal")
+	// The order is kept, later files take precedence
+	assert.Equal(t, []string{".env.local", ".env"}, turboJSON.Pipeline["build"].TaskDefinition.DotEnv)
+	assert.Empty(t, turboJSON.Pipeline["test"].TaskDefinition.DotEnv)
+
+	marshalled, err := json.Marshal(turboJSON.Pipeline["build"].TaskDefinition)
+	assert.NoError(t, err, "marshal")
+	assert.Contains(t, string(marshalled), `"dotEnv":[".env.local",".env"]`)
+
+	err = json.Unmarshal([]byte(`{"pipeline": {"build": {"dotEnv": ["/etc/app.env"]}}}`), &TurboJSON{})
+	assert.EqualError(t, err, "invalid \"dotEnv\" file \"/etc/app.env\", use a path relative to the package")
+}
+
+func Test_OutputsFromLog(t *testing.T) {
+	turboJSON := &TurboJSON{}
+	err := json.Unmarshal([]byte(`{
+		"pipeline": {
+			"build": {"outputsFromLog": "^wrote (.+)$"},
+			"test": {}
+		}
+	}`), turboJSON)
+	assert.NoError(t, err, "unmarshal")
+	assert.Equal(t, "^wrote (.+)$", turboJSON.Pipeline["build"].TaskDefinition.OutputsFromLog)
+
+	merged, err := MergeTaskDefinitions([]BookkeepingTaskDefinition{turboJSON.Pipeline["build"], turboJSON.Pipeline["test"]})
+	assert.NoError(t, err, "merge")
+	assert.Equal(t, "^wrote (.+)$", merged.OutputsFromLog)
+
+	err = json.Unmarshal([]byte(`{"pipeline": {"build": {"outputsFromLog": "^wrote .+$"}}}`), &TurboJSON{})
+	assert.EqualError(t, err, "\"outputsFromLog\" must capture the path of the output in a group, found ^wrote .+$")
+
+	err = json.Unmarshal([]byte(`{"pipeline": {"build": {"outputsFromLog": "^wrote (.+$"}}}`), &TurboJSON{})
+	assert.ErrorContains(t, err, "invalid \"outputsFromLog\"")
+}
+
+func Test_OutputTransform(t *testing.T) {
+	turboJSON := &TurboJSON{}
+	err := json.Unmarshal([]byte(`{
+		"pipeline": {
+			"build": {"outputTransform": "builtin:repo-root"},
+			"docs": {"outputTransform": "node scripts/strip-timestamps.js"}
+		}
+	}`), turboJSON)
+	assert.NoError(t, err, "unmarshal")
+	assert.Equal(t, "builtin:repo-root", turboJSON.Pipeline["build"].TaskDefinition.OutputTransform)
+	assert.Equal(t, "node scripts/strip-timestamps.js", turboJSON.Pipeline["docs"].TaskDefinition.OutputTransform)
+
+	err = json.Unmarshal([]byte(`{"pipeline": {"build": {"outputTransform": "builtin:timestamps"}}}`), &TurboJSON{})
+	assert.EqualError(t, err, "unknown \"outputTransform\" \"builtin:timestamps\", built-in transforms are: builtin:repo-root")
+}
+
+func Test_ParallelSafe(t *testing.T) {
+	turboJSON := &TurboJSON{}
+	err := json.Unmarshal([]byte(`{"pipeline": {"dev": {"parallelSafe": false}, "build": {}}}`), turboJSON)
+	assert.NoError(t, err, "unmarshal")
+	assert.True(t, turboJSON.Pipeline["dev"].TaskDefinition.ParallelUnsafe)
+	assert.False(t, turboJSON.Pipeline["build"].TaskDefinition.ParallelUnsafe)
+
+	// A workspace can mark the task as parallel safe again
+	workspace := &TurboJSON{}
+	err = json.Unmarshal([]byte(`{"pipeline": {"dev": {"parallelSafe": true}}}`), workspace)
+	assert.NoError(t, err, "unmarshal")
+	merged, err := MergeTaskDefinitions([]BookkeepingTaskDefinition{turboJSON.Pipeline["dev"], workspace.Pipeline["dev"]})
+	assert.NoError(t, err, "merge")
+	assert.False(t, merged.ParallelUnsafe)
+
+	marshalled, err := json.Marshal(turboJSON.Pipeline["dev"].TaskDefinition)
+	assert.NoError(t, err, "marshal")
+	assert.Contains(t, string(marshalled), `"parallelSafe":false`)
+	marshalled, err = json.Marshal(turboJSON.Pipeline["build"].TaskDefinition)
+	assert.NoError(t, err, "marshal")
+	assert.NotContains(t, string(marshalled), `"parallelSafe"`)
+
+	// A persistent task would hold the lock of the tasks that aren't parallel safe forever
+	err = json.Unmarshal([]byte(`{"pipeline": {"dev": {"persistent": true, "parallelSafe": false}}}`), &TurboJSON{})
+	assert.EqualError(t, err, errPersistentParallelUnsafe.Error())
+	persistent := &TurboJSON{}
+	err = json.Unmarshal([]byte(`{"pipeline": {"dev": {"persistent": true}}}`), persistent)
+	assert.NoError(t, err, "unmarshal")
+	_, err = MergeTaskDefinitions([]BookkeepingTaskDefinition{turboJSON.Pipeline["dev"], persistent.Pipeline["dev"]})
+	assert.ErrorIs(t, err, errPersistentParallelUnsafe)
+}
+
+func Test_Interactive(t *testing.T) {
+	turboJSON := &TurboJSON{}
+	err := json.Unmarshal([]byte(`{"pipeline": {"dev": {"interactive": true}, "build": {}}}`), turboJSON)
+	assert.NoError(t, err, "unmarshal")
+	assert.True(t, turboJSON.Pipeline["dev"].TaskDefinition.Interactive)
+
+	// Interactive tasks aren't cached, even when another turbo.json caches the task
+	workspace := &TurboJSON{}
+	err = json.Unmarshal([]byte(`{"pipeline": {"dev": {"cache": true}}}`), workspace)
+	assert.NoError(t, err, "unmarshal")
+	merged, err := MergeTaskDefinitions([]BookkeepingTaskDefinition{turboJSON.Pipeline["dev"], workspace.Pipeline["dev"]})
+	assert.NoError(t, err, "merge")
+	assert.True(t, merged.Interactive)
+	assert.False(t, merged.ShouldCache)
+
+	merged, err = MergeTaskDefinitions([]BookkeepingTaskDefinition{turboJSON.Pipeline["build"]})
+	assert.NoError(t, err, "merge")
+	assert.True(t, merged.ShouldCache)
+
+	err = json.Unmarshal([]byte(`{"pipeline": {"dev": {"interactive": true, "cache": true}}}`), &TurboJSON{})
+	assert.EqualError(t, err, "\"interactive\" tasks can't be cached, since their output isn't captured. Remove \"cache\": true")
+}
+
+func Test_ReadTurboConfig_Extends(t *testing.T) {
+	repoRoot := turbopath.AbsoluteSystemPath(t.TempDir())
+	files := map[string]string{
+		"turbo.json": `{
+			"extends": ["@acme/turbo-config", "./config/ci.json"],
+			"globalEnv": ["CI"],
+			"globalToolVersions": ["node --version"],
+			"pipeline": {"build": {"outputs": ["build/**"]}}
+		}`,
+		"node_modules/@acme/turbo-config/turbo.json": `{
+			"globalDependencies": ["tsconfig.json"],
+			"globalEnv": ["NODE_ENV"],
+			"globalToolVersions": ["pnpm --version", "node --version"],
+			"envGroups": {"deploy": ["DEPLOY_TOKEN"]},
+			"pipeline": {
+				"build": {"dependsOn": ["^build"], "outputs": ["dist/**"]},
+				"lint": {"cache": false}
+			}
+		}`,
+		"config/ci.json": `{
+			"globalEnv": ["CI", "GITHUB_SHA"],
+			"pipeline": {"lint": {"cache": true}}
+		}`,
+	}
+	for name, contents := range files {
+		path := repoRoot.UntypedJoin(filepath.FromSlash(name))
+		assert.NoError(t, path.EnsureDir(), "EnsureDir")
+		assert.NoError(t, path.WriteFile([]byte(contents), 0644), "WriteFile")
+	}
+
+	turboJSON, err := readTurboConfig(repoRoot.UntypedJoin("turbo.json"))
+	assert.NoError(t, err, "readTurboConfig")
+	assert.Equal(t, []string{"tsconfig.json"}, turboJSON.GlobalDeps)
+	assert.Equal(t, []string{"CI", "GITHUB_SHA", "NODE_ENV"}, turboJSON.GlobalEnv)
+	assert.Equal(t, []string{"node --version", "pnpm --version"}, turboJSON.GlobalToolVersions)
+	assert.Equal(t, map[string][]string{"deploy": {"DEPLOY_TOKEN"}}, turboJSON.EnvGroups)
+	assert.Empty(t, turboJSON.Extends)
+
+	build := turboJSON.Pipeline["build"].TaskDefinition
+	assert.Equal(t, []string{"build/**"}, build.Outputs.Inclusions)
+	assert.Equal(t, []string{"build"}, build.TopologicalDependencies)
+	assert.True(t, turboJSON.Pipeline["lint"].TaskDefinition.ShouldCache)
+
+	assert.NoError(t, repoRoot.UntypedJoin("config", "ci.json").WriteFile([]byte(`{"extends": ["../turbo.json"]}`), 0644), "WriteFile")
+	_, err = readTurboConfig(repoRoot.UntypedJoin("turbo.json"))
+	assert.ErrorContains(t, err, "\"extends\" refers back to")
+
+	assert.NoError(t, repoRoot.UntypedJoin("turbo.json").WriteFile([]byte(`{"extends": ["@acme/missing"]}`), 0644), "WriteFile")
+	_, err = readTurboConfig(repoRoot.UntypedJoin("turbo.json"))
+	assert.EqualError(t, err, "turbo.json: could not find turbo.json in package @acme/missing to extend from, is it installed?")
+}
+
 // Helpers
 func validateOutput(t *testing.T, turboJSON *TurboJSON, expectedPipeline Pipeline) {
 	t.Helper()
@@ -414,10 +414,38 @@ func TestGlobFilesFs(t *testing.T) {
 			wantAll: []string{
 				"/repos/some-app/dist",
 				"/repos/some-app/dist/index.html",
+			},
+			wantFiles: []string{
+				"/repos/some-app/dist/index.html",
+			},
+		},
+		{
+			name: "excludes win over includes for deeply nested folders",
+			files: []string{
+				"/repos/some-app/dist/index.html",
+				"/repos/some-app/dist/cache/manifest.json",
+				"/repos/some-app/dist/cache/a/b/c/stale.js",
+				"/repos/some-app/dist/cachefile.js",
+				"/repos/some-app/dist/js/lib.js",
+				"/repos/some-app/dist/js/vendor/.cache/chunk.js",
+			},
+			args: args{
+				basePath:        "/repos/some-app/",
+				includePatterns: []string{"dist/**"},
+				excludePatterns: []string{"dist/cache/**", "dist/**/.cache/**"},
+			},
+			wantAll: []string{
+				"/repos/some-app/dist",
+				"/repos/some-app/dist/cachefile.js",
+				"/repos/some-app/dist/index.html",
 				"/repos/some-app/dist/js",
+				"/repos/some-app/dist/js/lib.js",
+				"/repos/some-app/dist/js/vendor",
 			},
 			wantFiles: []string{
+				"/repos/some-app/dist/cachefile.js",
 				"/repos/some-app/dist/index.html",
+				"/repos/some-app/dist/js/lib.js",
 			},
 		},
 		{
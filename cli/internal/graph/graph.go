@@ -5,9 +5,12 @@ import (
 	gocontext "context"
 	"fmt"
 	"path/filepath"
+	"sort"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/pyr-sh/dag"
+	"github.com/vercel/turbo/cli/internal/env"
 	"github.com/vercel/turbo/cli/internal/fs"
 	"github.com/vercel/turbo/cli/internal/nodes"
 	"github.com/vercel/turbo/cli/internal/runsummary"
@@ -31,6 +34,11 @@ type CompleteGraph struct {
 
 	// GlobalHash is the hash of all global dependencies
 	GlobalHash string
+	// GlobalEnvVars are the env vars that are part of GlobalHash
+	GlobalEnvVars env.EnvironmentVariableMap
+	// GlobalPassThroughEnv are the env vars that every task is given but that are left out of
+	// GlobalHash and the hash of every task
+	GlobalPassThroughEnv []string
 
 	RootNode string
 
@@ -39,6 +47,9 @@ type CompleteGraph struct {
 	RepoRoot        turbopath.AbsoluteSystemPath
 
 	TaskHashTracker *taskhash.Tracker
+
+	// CompressLogs is whether task logs are stored gzipped, as .log.gz files
+	CompressLogs bool
 }
 
 // GetPackageTaskVisitor wraps a `visitor` function that is used for walking the TaskGraph
@@ -73,8 +84,10 @@ func (g *CompleteGraph) GetPackageTaskVisitor(
 			TaskDefinition:  taskDefinition,
 			Outputs:         taskDefinition.Outputs.Inclusions,
 			ExcludedOutputs: taskDefinition.Outputs.Exclusions,
+			CompressedLog:   g.CompressLogs,
 		}
 
+		hashStart := time.Now()
 		hash, err := g.TaskHashTracker.CalculateTaskHash(
 			packageTask,
 			taskGraph.DownEdges(taskID),
@@ -84,14 +97,27 @@ func (g *CompleteGraph) GetPackageTaskVisitor(
 
 		// Not being able to construct the task hash is a hard error
 		if err != nil {
-			return fmt.Errorf("Hashing error: %v", err)
+			return &TaskHashError{TaskID: taskID, PackageName: packageName, Err: err}
 		}
 
 		pkgDir := pkg.Dir
 		packageTask.Hash = hash
+		packageTask.HashDuration = time.Since(hashStart)
 		envVars := g.TaskHashTracker.GetEnvVars(taskID)
 		expandedInputs := g.TaskHashTracker.GetExpandedInputs(packageTask)
 		framework := g.TaskHashTracker.GetFramework(taskID)
+		dependencyHashes := g.TaskHashTracker.GetDependencyHashes(taskID)
+		// The task passes through the env vars that every task does as well as its own, except
+		// for those it hashes because they are in its "env"
+		passThroughEnv := taskDefinition.PassThroughEnv
+		if len(g.GlobalPassThroughEnv) > 0 {
+			names := util.SetFromStrings(taskDefinition.UnhashedGlobalPassThroughEnv(g.GlobalPassThroughEnv))
+			for _, name := range taskDefinition.PassThroughEnv {
+				names.Add(name)
+			}
+			passThroughEnv = names.UnsafeListOfStrings()
+			sort.Strings(passThroughEnv)
+		}
 
 		// Assign remaining fields to packageTask
 		var command string
@@ -99,9 +125,13 @@ func (g *CompleteGraph) GetPackageTaskVisitor(
 			command = cmd
 		}
 
-		logFile := repoRelativeLogFile(pkgDir, taskName)
+		logFile := repoRelativeLogFile(pkgDir, packageTask.LogFileName())
 		packageTask.LogFile = logFile
 		packageTask.Command = command
+		// The output of a task that isn't cached goes straight to the terminal, there is no log file
+		if !taskDefinition.ShouldCache {
+			logFile = ""
+		}
 
 		summary := &runsummary.TaskSummary{
 			TaskID:                 taskID,
@@ -116,9 +146,11 @@ func (g *CompleteGraph) GetPackageTaskVisitor(
 			ExpandedInputs:         expandedInputs,
 			Command:                command,
 			Framework:              framework,
+			DependencyHashes:       dependencyHashes,
 			EnvVars: runsummary.TaskEnvVarSummary{
-				Configured: envVars.BySource.Explicit.ToSecretHashable(),
-				Inferred:   envVars.BySource.Matching.ToSecretHashable(),
+				Configured:  envVars.BySource.Explicit.ToSecretHashable(),
+				Inferred:    envVars.BySource.Matching.ToSecretHashable(),
+				PassThrough: passThroughEnv,
 			},
 		}
 
@@ -126,6 +158,21 @@ func (g *CompleteGraph) GetPackageTaskVisitor(
 	}
 }
 
+// TaskHashError is returned by the task visitor when the hash of a task can't be calculated
+type TaskHashError struct {
+	TaskID      string
+	PackageName string
+	Err         error
+}
+
+func (e *TaskHashError) Error() string {
+	return fmt.Sprintf("Hashing error: %v", e.Err)
+}
+
+func (e *TaskHashError) Unwrap() error {
+	return e.Err
+}
+
 // GetPipelineFromWorkspace returns the Unmarshaled fs.Pipeline struct from turbo.json in the given workspace.
 func (g *CompleteGraph) GetPipelineFromWorkspace(workspaceName string, isSinglePackage bool) (fs.Pipeline, error) {
 	turboConfig, err := g.GetTurboConfigFromWorkspace(workspaceName, isSinglePackage)
@@ -179,6 +226,6 @@ func (g *CompleteGraph) GetPackageJSONFromWorkspace(workspaceName string) (*fs.P
 
 // repoRelativeLogFile returns the path to the log file for this task execution as a
 // relative path from the root of the monorepo.
-func repoRelativeLogFile(dir turbopath.AnchoredSystemPath, taskName string) string {
-	return filepath.Join(dir.ToStringDuringMigration(), ".turbo", fmt.Sprintf("turbo-%v.log", taskName))
+func repoRelativeLogFile(dir turbopath.AnchoredSystemPath, logFileName string) string {
+	return filepath.Join(dir.ToStringDuringMigration(), ".turbo", logFileName)
 }
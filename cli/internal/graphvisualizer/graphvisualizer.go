@@ -129,7 +129,7 @@ func (g *GraphVisualizer) GenerateGraphFile(outputName string) error {
 		ext = ".jpg"
 		outputFilename = g.repoRoot.UntypedJoin(outputName + ext)
 	}
-	if ext == ".mermaid" {
+	if ext == ".mermaid" || ext == ".mmd" {
 		f, err := outputFilename.Create()
 		if err != nil {
 			return fmt.Errorf("error creating file: %w", err)
@@ -142,6 +142,14 @@ func (g *GraphVisualizer) GenerateGraphFile(outputName string) error {
 		return nil
 	}
 	graphString := g.generateDotString()
+	// write dot files directly, they don't need Graphviz
+	if ext == ".dot" || ext == ".gv" {
+		if err := outputFilename.WriteFile([]byte(graphString), 0644); err != nil {
+			return fmt.Errorf("error writing graph contents: %w", err)
+		}
+		g.ui.Output(fmt.Sprintf("✔ Generated task graph in %s", ui.Bold(outputFilename.ToString())))
+		return nil
+	}
 	if ext == ".html" {
 		f, err := outputFilename.Create()
 		if err != nil {
@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"io"
 	"os/exec"
+	"path"
 	"path/filepath"
 	"strings"
 	"sync"
 
 	"github.com/pkg/errors"
+	"github.com/vercel/turbo/cli/internal/doublestar"
 	"github.com/vercel/turbo/cli/internal/encoding/gitoutput"
 	"github.com/vercel/turbo/cli/internal/fs"
 	"github.com/vercel/turbo/cli/internal/globby"
@@ -33,100 +35,265 @@ func GetPackageDeps(rootPath turbopath.AbsoluteSystemPath, p *PackageDepsOptions
 	var result map[turbopath.AnchoredUnixPath]string
 
 	// make a copy of the inputPatterns array, because we may be appending to it later.
-	calculatedInputs := make([]string, len(p.InputPatterns))
-	copy(calculatedInputs, p.InputPatterns)
-
-	if len(calculatedInputs) == 0 {
-		gitLsTreeOutput, err := gitLsTree(pkgPath)
-		if err != nil {
-			return nil, fmt.Errorf("could not get git hashes for files in package %s: %w", p.PackagePath, err)
+	// $TURBO_DEFAULT isn't a pattern, it adds the default files to those of the patterns.
+	calculatedInputs := make([]string, 0, len(p.InputPatterns))
+	includeDefault := false
+	for _, pattern := range p.InputPatterns {
+		if pattern == fs.TurboDefaultInput {
+			includeDefault = true
+		} else {
+			calculatedInputs = append(calculatedInputs, pattern)
 		}
-		result = gitLsTreeOutput
+	}
 
-		// Update the checked in hashes with the current repo status
-		// The paths returned from this call are anchored at the package directory
-		gitStatusOutput, err := gitStatus(pkgPath, calculatedInputs)
+	// Inputs that only exclude files are applied to all of the files in the package
+	if includeDefault || !hasIncludePatterns(calculatedInputs) {
+		defaultHashes, err := getDefaultPackageDeps(pkgPath, p.PackagePath)
 		if err != nil {
-			return nil, fmt.Errorf("Could not get git hashes from git status: %v", err)
+			return nil, err
 		}
+		result = defaultHashes
 
-		var filesToHash []turbopath.AnchoredSystemPath
-		for filePath, status := range gitStatusOutput {
-			if status.isDelete() {
-				delete(result, filePath)
-			} else {
-				filesToHash = append(filesToHash, filePath.ToSystemPath())
+		if hasIncludePatterns(calculatedInputs) {
+			hashes, err := globPackageDeps(rootPath, pkgPath, calculatedInputs)
+			if err != nil {
+				return nil, err
+			}
+			for filePath, hash := range hashes {
+				result[filePath] = hash
 			}
 		}
 
-		hashes, err := gitHashObject(turbopath.AbsoluteSystemPathFromUpstream(pkgPath.ToString()), filesToHash)
+		if err := removeExcludedFiles(result, calculatedInputs); err != nil {
+			return nil, errors.Wrapf(err, "failed to resolve input globs %v", calculatedInputs)
+		}
+	} else {
+		hashes, err := globPackageDeps(rootPath, pkgPath, calculatedInputs)
 		if err != nil {
 			return nil, err
 		}
+		result = hashes
+	}
 
-		// Zip up file paths and hashes together
-		for filePath, hash := range hashes {
-			result[filePath] = hash
+	return result, nil
+}
+
+// getDefaultPackageDeps hashes every file in the package that isn't ignored by git, with its
+// current contents. The paths are relative to the package.
+func getDefaultPackageDeps(pkgPath turbopath.AbsoluteSystemPath, packagePath turbopath.AnchoredSystemPath) (map[turbopath.AnchoredUnixPath]string, error) {
+	result, err := gitLsTree(pkgPath)
+	if err != nil {
+		return nil, fmt.Errorf("could not get git hashes for files in package %s: %w", packagePath, err)
+	}
+
+	// Update the checked in hashes with the current repo status
+	// The paths returned from this call are anchored at the package directory
+	gitStatusOutput, err := gitStatus(pkgPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Could not get git hashes from git status: %v", err)
+	}
+
+	var filesToHash []turbopath.AnchoredSystemPath
+	for filePath, status := range gitStatusOutput {
+		if status.isDelete() {
+			delete(result, filePath)
+		} else {
+			filesToHash = append(filesToHash, filePath.ToSystemPath())
 		}
-	} else {
-		// Add in package.json and turbo.json to input patterns. Both file paths are relative to pkgPath
-		//
-		// - package.json is an input because if the `scripts` in
-		// 		the package.json change (i.e. the tasks that turbo executes), we want
-		// 		a cache miss, since any existing cache could be invalid.
-		// - turbo.json because it's the definition of the tasks themselves. The root turbo.json
-		// 		is similarly included in the global hash. This file may not exist in the workspace, but
-		// 		that is ok, because it will get ignored downstream.
-		calculatedInputs = append(calculatedInputs, "package.json")
-		calculatedInputs = append(calculatedInputs, "turbo.json")
-
-		// The input patterns are relative to the package.
-		// However, we need to change the globbing to be relative to the repo root.
-		// Prepend the package path to each of the input patterns.
-		prefixedInputPatterns := []string{}
-		prefixedExcludePatterns := []string{}
-		for _, pattern := range calculatedInputs {
-			if len(pattern) > 0 && pattern[0] == '!' {
-				rerooted, err := rootPath.PathTo(pkgPath.UntypedJoin(pattern[1:]))
-				if err != nil {
-					return nil, err
-				}
-				prefixedExcludePatterns = append(prefixedExcludePatterns, rerooted)
-			} else {
-				rerooted, err := rootPath.PathTo(pkgPath.UntypedJoin(pattern))
-				if err != nil {
-					return nil, err
-				}
-				prefixedInputPatterns = append(prefixedInputPatterns, rerooted)
+	}
+
+	hashes, err := gitHashObject(turbopath.AbsoluteSystemPathFromUpstream(pkgPath.ToString()), filesToHash)
+	if err != nil {
+		return nil, err
+	}
+
+	// Zip up file paths and hashes together
+	for filePath, hash := range hashes {
+		result[filePath] = hash
+	}
+	return result, nil
+}
+
+// globPackageDeps hashes the files matched by the input patterns, which are relative to the
+// package and may reach outside of it, e.g. "../shared/**". The paths are relative to the
+// package, so files outside of it start with "../". Patterns that reach outside of the
+// repository are an error.
+func globPackageDeps(rootPath turbopath.AbsoluteSystemPath, pkgPath turbopath.AbsoluteSystemPath, inputPatterns []string) (map[turbopath.AnchoredUnixPath]string, error) {
+	// Add in package.json and turbo.json to input patterns. Both file paths are relative to pkgPath
+	//
+	// - package.json is an input because if the `scripts` in
+	// 		the package.json change (i.e. the tasks that turbo executes), we want
+	// 		a cache miss, since any existing cache could be invalid.
+	// - turbo.json because it's the definition of the tasks themselves. The root turbo.json
+	// 		is similarly included in the global hash. This file may not exist in the workspace, but
+	// 		that is ok, because it will get ignored downstream.
+	calculatedInputs := append(append([]string{}, inputPatterns...), "package.json", "turbo.json")
+
+	// The input patterns are relative to the package.
+	// However, we need to change the globbing to be relative to the repo root.
+	// Prepend the package path to each of the input patterns.
+	prefixedInputPatterns := []string{}
+	prefixedExcludePatterns := []string{}
+	for _, pattern := range calculatedInputs {
+		if len(pattern) > 0 && pattern[0] == '!' {
+			rerooted, err := rerootInputPattern(rootPath, pkgPath, pattern[1:])
+			if err != nil {
+				return nil, err
+			}
+			prefixedExcludePatterns = append(prefixedExcludePatterns, rerooted)
+		} else {
+			rerooted, err := rerootInputPattern(rootPath, pkgPath, pattern)
+			if err != nil {
+				return nil, err
 			}
+			prefixedInputPatterns = append(prefixedInputPatterns, rerooted)
 		}
-		absoluteFilesToHash, err := globby.GlobFiles(rootPath.ToStringDuringMigration(), prefixedInputPatterns, prefixedExcludePatterns)
+	}
+	absoluteFilesToHash, err := globby.GlobFiles(rootPath.ToStringDuringMigration(), prefixedInputPatterns, prefixedExcludePatterns)
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve input globs %v", calculatedInputs)
+	}
+
+	filesToHash := make([]turbopath.AnchoredSystemPath, len(absoluteFilesToHash))
+	for i, rawPath := range absoluteFilesToHash {
+		relativePathString, err := pkgPath.RelativePathString(rawPath)
 
 		if err != nil {
-			return nil, errors.Wrapf(err, "failed to resolve input globs %v", calculatedInputs)
+			return nil, errors.Wrapf(err, "not relative to package: %v", rawPath)
 		}
 
-		filesToHash := make([]turbopath.AnchoredSystemPath, len(absoluteFilesToHash))
-		for i, rawPath := range absoluteFilesToHash {
-			relativePathString, err := pkgPath.RelativePathString(rawPath)
+		filesToHash[i] = turbopath.AnchoredSystemPathFromUpstream(relativePathString)
+	}
+
+	hashes, err := gitHashObject(turbopath.AbsoluteSystemPathFromUpstream(pkgPath.ToStringDuringMigration()), filesToHash)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed hashing resolved inputs globs")
+	}
+	// Note that in this scenario, we don't need to check git status, we're using hash-object directly which
+	// hashes the current state, not state at a commit
+	return hashes, nil
+}
+
+// rerootInputPattern makes an input pattern that is relative to the package relative to the
+// repository root instead
+func rerootInputPattern(rootPath turbopath.AbsoluteSystemPath, pkgPath turbopath.AbsoluteSystemPath, pattern string) (string, error) {
+	if err := CheckInputPattern(rootPath, pkgPath, pattern); err != nil {
+		return "", err
+	}
+	return rootPath.PathTo(pkgPath.UntypedJoin(pattern))
+}
+
+// CheckInputPattern returns an error if an input pattern of the package at pkgPath, with or
+// without a leading "!", reaches outside of the repository at rootPath
+func CheckInputPattern(rootPath turbopath.AbsoluteSystemPath, pkgPath turbopath.AbsoluteSystemPath, pattern string) error {
+	if pattern == fs.TurboDefaultInput {
+		return nil
+	}
+	pattern = strings.TrimPrefix(pattern, "!")
+	inRepo, err := rootPath.ContainsPath(pkgPath.UntypedJoin(pattern))
+	if err != nil {
+		return err
+	}
+	if !inRepo {
+		return fmt.Errorf("input %q is outside of the repository, inputs must be within %v", pattern, rootPath)
+	}
+	return nil
+}
+
+// MayMatchInputs is whether file, relative to the repository root, is selected by the input
+// patterns of the package at packagePath the way GetPackageDeps selects files: every file of
+// the package with $TURBO_DEFAULT or without patterns that select files, and otherwise the
+// files the patterns match along with package.json and turbo.json, less the files that "!"
+// patterns exclude. Files that git ignores aren't left out, so GetPackageDeps may still not
+// hash a file that matches.
+func MayMatchInputs(packagePath turbopath.AnchoredUnixPath, inputPatterns []string, file turbopath.AnchoredUnixPath) (bool, error) {
+	pkg := packagePath.ToString()
+	includeDefault := false
+	includes := []string{}
+	excludes := []string{}
+	for _, pattern := range inputPatterns {
+		if pattern == fs.TurboDefaultInput {
+			includeDefault = true
+		} else if len(pattern) > 0 && pattern[0] == '!' {
+			excludes = append(excludes, path.Join(pkg, pattern[1:]))
+		} else {
+			includes = append(includes, path.Join(pkg, pattern))
+		}
+	}
 
+	matched := false
+	if includeDefault || len(includes) == 0 {
+		matched = pkg == "" || pkg == "." || strings.HasPrefix(file.ToString(), pkg+"/")
+	}
+	if !matched && len(includes) > 0 {
+		includes = append(includes, path.Join(pkg, "package.json"), path.Join(pkg, "turbo.json"))
+		for _, include := range includes {
+			ok, err := doublestar.Match(include, file.ToString())
 			if err != nil {
-				return nil, errors.Wrapf(err, "not relative to package: %v", rawPath)
+				return false, err
+			}
+			if ok {
+				matched = true
+				break
+			}
+		}
+	}
+	if !matched {
+		return false, nil
+	}
+	// Like removeExcludedFiles, a pattern that matches a directory excludes everything in it
+	for _, exclude := range excludes {
+		for _, pattern := range []string{exclude, exclude + "/**"} {
+			ok, err := doublestar.Match(pattern, file.ToString())
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				return false, nil
 			}
-
-			filesToHash[i] = turbopath.AnchoredSystemPathFromUpstream(relativePathString)
 		}
+	}
+	return true, nil
+}
 
-		hashes, err := gitHashObject(turbopath.AbsoluteSystemPathFromUpstream(pkgPath.ToStringDuringMigration()), filesToHash)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed hashing resolved inputs globs")
+// hasIncludePatterns returns whether any of the input patterns selects files, rather than
+// excluding them with a leading "!"
+func hasIncludePatterns(patterns []string) bool {
+	for _, pattern := range patterns {
+		if len(pattern) == 0 || pattern[0] != '!' {
+			return true
 		}
-		result = hashes
-		// Note that in this scenario, we don't need to check git status, we're using hash-object directly which
-		// hashes the current state, not state at a commit
 	}
+	return false
+}
 
-	return result, nil
+// removeExcludedFiles removes the files matched by the "!" patterns from hashes, which is
+// keyed by paths relative to the package. Like the exclusions of globby.GlobFiles, a pattern
+// that matches a directory excludes everything in it.
+func removeExcludedFiles(hashes map[turbopath.AnchoredUnixPath]string, patterns []string) error {
+	for _, pattern := range patterns {
+		if len(pattern) == 0 || pattern[0] != '!' {
+			continue
+		}
+		exclude := filepath.ToSlash(filepath.Clean(pattern[1:]))
+		for file := range hashes {
+			matched, err := doublestar.Match(exclude, file.ToString())
+			if err != nil {
+				return err
+			}
+			if !matched {
+				matched, err = doublestar.Match(exclude+"/**", file.ToString())
+				if err != nil {
+					return err
+				}
+			}
+			if matched {
+				delete(hashes, file)
+			}
+		}
+	}
+	return nil
 }
 
 func manuallyHashFiles(rootPath turbopath.AbsoluteSystemPath, files []turbopath.AnchoredSystemPath) (map[turbopath.AnchoredUnixPath]string, error) {
@@ -338,6 +505,16 @@ func gitLsTree(rootPath turbopath.AbsoluteSystemPath) (map[turbopath.AnchoredUni
 	return output, nil
 }
 
+// GetTrackedFiles returns the files under packagePath that are committed to git, anchored
+// at packagePath, mapped to their git object hashes
+func GetTrackedFiles(rootPath turbopath.AbsoluteSystemPath, packagePath turbopath.AnchoredSystemPath) (map[turbopath.AnchoredUnixPath]string, error) {
+	gitLsTreeOutput, err := gitLsTree(packagePath.RestoreAnchor(rootPath))
+	if err != nil {
+		return nil, fmt.Errorf("could not get tracked files in package %s: %w", packagePath, err)
+	}
+	return gitLsTreeOutput, nil
+}
+
 // getTraversePath gets the distance of the current working directory to the repository root.
 // This is used to convert repo-relative paths to cwd-relative paths.
 //
@@ -365,6 +365,56 @@ func TestGetPackageDeps(t *testing.T) {
 				"uncommitted-file": "4e56ad89387e6379e4e91ddfe9872cf6a72c9976",
 			},
 		},
+		// inputs that only exclude files apply to all files
+		{
+			opts: &PackageDepsOptions{
+				PackagePath:   "my-pkg",
+				InputPatterns: []string{"!dir", "!uncommitted-*"},
+			},
+			expected: map[turbopath.AnchoredUnixPath]string{
+				"committed-file": "3a29e62ea9ba15c4a4009d1f605d391cdd262033",
+				"package.json":   "9e26dfeeb6e641a33dae4961196235bdb965b21b",
+			},
+		},
+		// exclusions apply after the inclusions
+		{
+			opts: &PackageDepsOptions{
+				PackagePath:   "my-pkg",
+				InputPatterns: []string{"**/*-file", "!**/nested-*"},
+			},
+			expected: map[turbopath.AnchoredUnixPath]string{
+				"committed-file":   "3a29e62ea9ba15c4a4009d1f605d391cdd262033",
+				"package.json":     "9e26dfeeb6e641a33dae4961196235bdb965b21b",
+				"uncommitted-file": "4e56ad89387e6379e4e91ddfe9872cf6a72c9976",
+			},
+		},
+		// $TURBO_DEFAULT adds all files of the package to those matched by other inputs
+		{
+			opts: &PackageDepsOptions{
+				PackagePath:   "my-pkg",
+				InputPatterns: []string{"$TURBO_DEFAULT", "../new-root-file"},
+			},
+			expected: map[turbopath.AnchoredUnixPath]string{
+				"../new-root-file": "8906ddcdd634706188bd8ef1c98ac07b9be3425e",
+				"committed-file":   "3a29e62ea9ba15c4a4009d1f605d391cdd262033",
+				"uncommitted-file": "4e56ad89387e6379e4e91ddfe9872cf6a72c9976",
+				"package.json":     "9e26dfeeb6e641a33dae4961196235bdb965b21b",
+				"dir/nested-file":  "bfe53d766e64d78f80050b73cd1c88095bc70abb",
+			},
+		},
+		// exclusions apply to the default files too
+		{
+			opts: &PackageDepsOptions{
+				PackagePath:   "my-pkg",
+				InputPatterns: []string{"$TURBO_DEFAULT", "../new-root-file", "!dir"},
+			},
+			expected: map[turbopath.AnchoredUnixPath]string{
+				"../new-root-file": "8906ddcdd634706188bd8ef1c98ac07b9be3425e",
+				"committed-file":   "3a29e62ea9ba15c4a4009d1f605d391cdd262033",
+				"uncommitted-file": "4e56ad89387e6379e4e91ddfe9872cf6a72c9976",
+				"package.json":     "9e26dfeeb6e641a33dae4961196235bdb965b21b",
+			},
+		},
 	}
 	for _, tt := range tests {
 		got, err := GetPackageDeps(repoRoot, tt.opts)
@@ -374,6 +424,15 @@ func TestGetPackageDeps(t *testing.T) {
 		}
 		assert.DeepEqual(t, got, tt.expected)
 	}
+
+	// inputs can't reach outside of the repository
+	_, err = GetPackageDeps(repoRoot, &PackageDepsOptions{
+		PackagePath:   "my-pkg",
+		InputPatterns: []string{"../../**"},
+	})
+	assert.ErrorContains(t, err, `input "../../**" is outside of the repository`)
+	assert.NilError(t, CheckInputPattern(repoRoot, myPkgDir, "../shared/**"))
+	assert.ErrorContains(t, CheckInputPattern(repoRoot, myPkgDir, "!../../secrets"), `input "../../secrets" is outside of the repository`)
 }
 
 func Test_memoizedGetTraversePath(t *testing.T) {
@@ -384,3 +443,30 @@ func Test_memoizedGetTraversePath(t *testing.T) {
 
 	assert.Check(t, gotOne == gotTwo, "The strings are identical.")
 }
+
+func TestMayMatchInputs(t *testing.T) {
+	testCases := []struct {
+		name          string
+		inputPatterns []string
+		file          turbopath.AnchoredUnixPath
+		want          bool
+	}{
+		{name: "default inputs", file: "packages/ui/src/button.ts", want: true},
+		{name: "outside of the package", file: "packages/uikit/index.ts", want: false},
+		{name: "matching glob", inputPatterns: []string{"src/**/*.ts"}, file: "packages/ui/src/button.ts", want: true},
+		{name: "glob that doesn't match", inputPatterns: []string{"src/**/*.ts"}, file: "packages/ui/README.md", want: false},
+		{name: "package.json with globs", inputPatterns: []string{"src/**"}, file: "packages/ui/package.json", want: true},
+		{name: "glob outside of the package", inputPatterns: []string{"../shared/**"}, file: "packages/shared/util.ts", want: true},
+		{name: "excluded file", inputPatterns: []string{"src/**", "!src/**/*.test.ts"}, file: "packages/ui/src/button.test.ts", want: false},
+		{name: "excluded directory", inputPatterns: []string{"!dist"}, file: "packages/ui/dist/index.js", want: false},
+		{name: "only exclusions", inputPatterns: []string{"!dist"}, file: "packages/ui/src/button.ts", want: true},
+		{name: "default inputs and globs", inputPatterns: []string{fs.TurboDefaultInput, "../shared/**"}, file: "packages/shared/util.ts", want: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := MayMatchInputs("packages/ui", tc.inputPatterns, tc.file)
+			assert.NilError(t, err, "MayMatchInputs")
+			assert.Equal(t, got, tc.want)
+		})
+	}
+}
@@ -151,6 +151,20 @@ func (f Framework) match(pkg *fs.PackageJSON) bool {
 	return f.DependencyMatch.match(pkg)
 }
 
+// NoFramework is the framework override that disables framework inference for a task
+const NoFramework = "none"
+
+// FrameworkBySlug returns a reference to the framework with the given slug, or nil if
+// there is no such framework
+func FrameworkBySlug(slug string) *Framework {
+	for _, framework := range _frameworks {
+		if framework.Slug == slug {
+			return &framework
+		}
+	}
+	return nil
+}
+
 // InferFramework returns a reference to a matched framework
 func InferFramework(pkg *fs.PackageJSON) *Framework {
 	if pkg == nil {
@@ -8,14 +8,21 @@ import (
 )
 
 func getFrameworkBySlug(slug string) *Framework {
-	for _, framework := range _frameworks {
-		if framework.Slug == slug {
-			return &framework
-		}
+	if framework := FrameworkBySlug(slug); framework != nil {
+		return framework
 	}
 	panic("that framework doesn't exist")
 }
 
+func TestFrameworkBySlug(t *testing.T) {
+	if got := FrameworkBySlug("gatsby"); got == nil || got.EnvMatcher != "^GATSBY_" {
+		t.Errorf("FrameworkBySlug(\"gatsby\") = %v, want the gatsby framework", got)
+	}
+	if got := FrameworkBySlug(NoFramework); got != nil {
+		t.Errorf("FrameworkBySlug(%q) = %v, want nil", NoFramework, got)
+	}
+}
+
 func TestInferFramework(t *testing.T) {
 	tests := []struct {
 		name string
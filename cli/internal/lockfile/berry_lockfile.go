@@ -2,6 +2,7 @@ package lockfile
 
 import (
 	"bytes"
+	"encoding/gob"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -87,6 +88,106 @@ type BerryDependencyMetaEntry struct {
 
 var _ Lockfile = (*BerryLockfile)(nil)
 
+// gobBerryLocator and gobBerryDescriptor mirror _Locator and _Descriptor with exported fields
+// so that they can be stored in the parsed lockfile cache
+type gobBerryLocator struct {
+	Scope     string
+	Name      string
+	Reference string
+}
+
+type gobBerryDescriptor struct {
+	Scope        string
+	Name         string
+	VersionRange string
+}
+
+// gobBerryLockfile is the representation of a BerryLockfile in the parsed lockfile cache
+type gobBerryLockfile struct {
+	Packages          map[gobBerryLocator]*BerryLockfileEntry
+	Version           int
+	CacheKey          string
+	Descriptors       map[gobBerryDescriptor]gobBerryLocator
+	Patches           map[gobBerryLocator]gobBerryLocator
+	PackageExtensions []gobBerryDescriptor
+	HasCRLF           bool
+}
+
+func (l _Locator) toGob() gobBerryLocator {
+	return gobBerryLocator{Scope: l.scope, Name: l.name, Reference: l.reference}
+}
+
+func (l gobBerryLocator) toLocator() _Locator {
+	return _Locator{_Ident{scope: l.Scope, name: l.Name}, l.Reference}
+}
+
+func (d _Descriptor) toGob() gobBerryDescriptor {
+	return gobBerryDescriptor{Scope: d.scope, Name: d.name, VersionRange: d.versionRange}
+}
+
+func (d gobBerryDescriptor) toDescriptor() _Descriptor {
+	return _Descriptor{_Ident{scope: d.Scope, name: d.Name}, d.VersionRange}
+}
+
+// GobEncode encodes the lockfile for the parsed lockfile cache
+func (l *BerryLockfile) GobEncode() ([]byte, error) {
+	encoded := gobBerryLockfile{
+		Packages:          make(map[gobBerryLocator]*BerryLockfileEntry, len(l.packages)),
+		Version:           l.version,
+		CacheKey:          l.cacheKey,
+		Descriptors:       make(map[gobBerryDescriptor]gobBerryLocator, len(l.descriptors)),
+		Patches:           make(map[gobBerryLocator]gobBerryLocator, len(l.patches)),
+		PackageExtensions: make([]gobBerryDescriptor, 0, len(l.packageExtensions)),
+		HasCRLF:           l.hasCRLF,
+	}
+	for locator, entry := range l.packages {
+		encoded.Packages[locator.toGob()] = entry
+	}
+	for descriptor, locator := range l.descriptors {
+		encoded.Descriptors[descriptor.toGob()] = locator.toGob()
+	}
+	for original, patch := range l.patches {
+		encoded.Patches[original.toGob()] = patch.toGob()
+	}
+	for descriptor := range l.packageExtensions {
+		encoded.PackageExtensions = append(encoded.PackageExtensions, descriptor.toGob())
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&encoded); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode decodes a lockfile from the parsed lockfile cache
+func (l *BerryLockfile) GobDecode(data []byte) error {
+	var decoded gobBerryLockfile
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&decoded); err != nil {
+		return err
+	}
+	l.packages = make(map[_Locator]*BerryLockfileEntry, len(decoded.Packages))
+	for locator, entry := range decoded.Packages {
+		l.packages[locator.toLocator()] = entry
+	}
+	l.version = decoded.Version
+	l.cacheKey = decoded.CacheKey
+	l.descriptors = make(map[_Descriptor]_Locator, len(decoded.Descriptors))
+	for descriptor, locator := range decoded.Descriptors {
+		l.descriptors[descriptor.toDescriptor()] = locator.toLocator()
+	}
+	l.patches = make(map[_Locator]_Locator, len(decoded.Patches))
+	for original, patch := range decoded.Patches {
+		l.patches[original.toLocator()] = patch.toLocator()
+	}
+	l.packageExtensions = make(map[_Descriptor]_void, len(decoded.PackageExtensions))
+	for _, descriptor := range decoded.PackageExtensions {
+		l.packageExtensions[descriptor.toDescriptor()] = _void{}
+	}
+	l.hasCRLF = decoded.HasCRLF
+	return nil
+}
+
 // ResolvePackage Given a package and version returns the key, resolved version, and if it was found
 func (l *BerryLockfile) ResolvePackage(_workspace turbopath.AnchoredUnixPath, name string, version string) (Package, error) {
 	for _, key := range berryPossibleKeys(name, version) {
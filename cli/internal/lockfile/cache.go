@@ -0,0 +1,92 @@
+package lockfile
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"os"
+	"strings"
+
+	"github.com/vercel/turbo/cli/internal/turbopath"
+)
+
+// _parseCacheVersion must be bumped whenever the in-memory representation of a
+// lockfile changes, so that entries written by older versions of turbo are ignored.
+const _parseCacheVersion = "1"
+
+const _parseCachePrefix = "lockfile-"
+const _parseCacheSuffix = ".gob"
+
+func init() {
+	gob.Register(&NpmLockfile{})
+	gob.Register(&PnpmLockfile{})
+	gob.Register(&YarnLockfile{})
+	gob.Register(&BerryLockfile{})
+	// Untyped values that can appear in decoded npm lockfiles
+	gob.Register(map[string]interface{}{})
+	gob.Register([]interface{}{})
+}
+
+// cachedLockfile wraps a Lockfile so that gob records its concrete type
+type cachedLockfile struct {
+	Lockfile Lockfile
+}
+
+// DecodeCached returns the lockfile parsed from contents, reusing the result of a previous
+// parse stored in cacheDir if the contents haven't changed. Parsing and writing the cache
+// entry otherwise falls back to decode. Failures to read or write the cache are not errors,
+// the lockfile is simply parsed again.
+func DecodeCached(cacheDir turbopath.AbsoluteSystemPath, contents []byte, decode func([]byte) (Lockfile, error)) (Lockfile, error) {
+	sum := sha256.Sum256(contents)
+	cachePath := cacheDir.UntypedJoin(_parseCachePrefix + hex.EncodeToString(sum[:]) + "-v" + _parseCacheVersion + _parseCacheSuffix)
+
+	if lockfile, ok := readParseCache(cachePath); ok {
+		return lockfile, nil
+	}
+
+	lockfile, err := decode(contents)
+	if err != nil {
+		return nil, err
+	}
+	writeParseCache(cacheDir, cachePath, lockfile)
+	return lockfile, nil
+}
+
+func readParseCache(cachePath turbopath.AbsoluteSystemPath) (Lockfile, bool) {
+	data, err := cachePath.ReadFile()
+	if err != nil {
+		return nil, false
+	}
+	var cached cachedLockfile
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&cached); err != nil || IsNil(cached.Lockfile) {
+		return nil, false
+	}
+	// Anything that isn't serialized needs to be reconstructed
+	if pnpm, ok := cached.Lockfile.(*PnpmLockfile); ok {
+		if err := pnpm.setKeyFormat(); err != nil {
+			return nil, false
+		}
+	}
+	return cached.Lockfile, true
+}
+
+func writeParseCache(cacheDir turbopath.AbsoluteSystemPath, cachePath turbopath.AbsoluteSystemPath, lockfile Lockfile) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&cachedLockfile{Lockfile: lockfile}); err != nil {
+		return
+	}
+	if err := cacheDir.MkdirAll(0755); err != nil {
+		return
+	}
+	// Only the entry for the current lockfile is ever useful, remove any others
+	if entries, err := os.ReadDir(cacheDir.ToString()); err == nil {
+		for _, entry := range entries {
+			name := entry.Name()
+			if strings.HasPrefix(name, _parseCachePrefix) && strings.HasSuffix(name, _parseCacheSuffix) {
+				_ = cacheDir.UntypedJoin(name).Remove()
+			}
+		}
+	}
+	_ = cachePath.WriteFile(buf.Bytes(), 0644)
+}
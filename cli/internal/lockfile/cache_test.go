@@ -0,0 +1,64 @@
+package lockfile
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"gotest.tools/v3/assert"
+)
+
+func Test_DecodeCached(t *testing.T) {
+	decodeNpm := func(contents []byte) (Lockfile, error) { return DecodeNpmLockfile(contents) }
+	decodePnpm := func(contents []byte) (Lockfile, error) { return DecodePnpmLockfile(contents) }
+	decodeYarn := func(contents []byte) (Lockfile, error) { return DecodeYarnLockfile(contents) }
+	decodeBerry := func(contents []byte) (Lockfile, error) { return DecodeBerryLockfile(contents) }
+
+	testCases := []struct {
+		fixture string
+		decode  func([]byte) (Lockfile, error)
+	}{
+		{fixture: "npm-lock.json", decode: decodeNpm},
+		{fixture: "npm-lock-workspace-variation.json", decode: decodeNpm},
+		{fixture: "pnpm7-workspace.yaml", decode: decodePnpm},
+		{fixture: "pnpm8.yaml", decode: decodePnpm},
+		{fixture: "pnpm-patch-v6.yaml", decode: decodePnpm},
+		{fixture: "yarn.lock", decode: decodeYarn},
+		{fixture: "berry.lock", decode: decodeBerry},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.fixture, func(t *testing.T) {
+			contents, err := getFixture(t, tc.fixture)
+			assert.NilError(t, err, "getFixture")
+			cacheDir := turbopath.AbsoluteSystemPath(t.TempDir())
+
+			decodes := 0
+			decode := func(contents []byte) (Lockfile, error) {
+				decodes++
+				return tc.decode(contents)
+			}
+
+			parsed, err := DecodeCached(cacheDir, contents, decode)
+			assert.NilError(t, err, "DecodeCached")
+			cached, err := DecodeCached(cacheDir, contents, decode)
+			assert.NilError(t, err, "DecodeCached")
+			assert.Equal(t, decodes, 1, "expected the second read to use the cache")
+
+			var parsedEncoding, cachedEncoding bytes.Buffer
+			assert.NilError(t, parsed.Encode(&parsedEncoding), "Encode")
+			assert.NilError(t, cached.Encode(&cachedEncoding), "Encode")
+			assert.Equal(t, cachedEncoding.String(), parsedEncoding.String())
+			assert.DeepEqual(t, cached.Patches(), parsed.Patches())
+			assert.Assert(t, !cached.GlobalChange(parsed), "cached lockfile differs from parsed lockfile")
+			if pnpm, ok := cached.(*PnpmLockfile); ok {
+				assert.Assert(t, pnpm.formatKey != nil, "expected key format to be restored")
+			}
+
+			// Changing the lockfile invalidates the cache
+			_, err = DecodeCached(cacheDir, append(contents, '\n'), decode)
+			assert.NilError(t, err, "DecodeCached")
+			assert.Equal(t, decodes, 2, "expected changed contents to be parsed")
+		})
+	}
+}
@@ -197,24 +197,32 @@ func DecodePnpmLockfile(contents []byte) (*PnpmLockfile, error) {
 		return nil, errors.Wrap(err, "could not unmarshal lockfile: ")
 	}
 
-	switch lockfile.Version.(type) {
+	if err := lockfile.setKeyFormat(); err != nil {
+		return nil, err
+	}
+
+	return &lockfile, nil
+}
+
+// setKeyFormat configures how lockfile keys are handled based on the lockfile version
+func (p *PnpmLockfile) setKeyFormat() error {
+	switch p.Version.(type) {
 	case float64:
-		lockfile.isV6 = false
+		p.isV6 = false
 	case string:
-		lockfile.isV6 = true
+		p.isV6 = true
 	default:
-		return nil, fmt.Errorf("Unexpected type of lockfileVersion: '%T', expected float64 or string", lockfile.Version)
+		return fmt.Errorf("Unexpected type of lockfileVersion: '%T', expected float64 or string", p.Version)
 	}
 
-	if lockfile.isV6 {
-		lockfile.formatKey = formatPnpmKeyV6
-		lockfile.extractVersion = getVersionFromKeyV6
+	if p.isV6 {
+		p.formatKey = formatPnpmKeyV6
+		p.extractVersion = getVersionFromKeyV6
 	} else {
-		lockfile.formatKey = formatPnpmKey
-		lockfile.extractVersion = getVersionFromKey
+		p.formatKey = formatPnpmKey
+		p.extractVersion = getVersionFromKey
 	}
-
-	return &lockfile, nil
+	return nil
 }
 
 // ResolvePackage Given a package and version returns the key, resolved version, and if it was found
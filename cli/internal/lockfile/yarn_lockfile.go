@@ -2,6 +2,7 @@ package lockfile
 
 import (
 	"bytes"
+	"encoding/gob"
 	"fmt"
 	"io"
 
@@ -21,6 +22,32 @@ type YarnLockfile struct {
 
 var _ Lockfile = (*YarnLockfile)(nil)
 
+// gobYarnLockfile is the representation of a YarnLockfile in the parsed lockfile cache
+type gobYarnLockfile struct {
+	Inner   yarnlock.LockFile
+	HasCRLF bool
+}
+
+// GobEncode encodes the lockfile for the parsed lockfile cache
+func (l *YarnLockfile) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(gobYarnLockfile{Inner: l.inner, HasCRLF: l.hasCRLF}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode decodes a lockfile from the parsed lockfile cache
+func (l *YarnLockfile) GobDecode(data []byte) error {
+	var decoded gobYarnLockfile
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&decoded); err != nil {
+		return err
+	}
+	l.inner = decoded.Inner
+	l.hasCRLF = decoded.HasCRLF
+	return nil
+}
+
 // ResolvePackage Given a package and version returns the key, resolved version, and if it was found
 func (l *YarnLockfile) ResolvePackage(_workspacePath turbopath.AnchoredUnixPath, name string, version string) (Package, error) {
 	for _, key := range yarnPossibleKeys(name, version) {
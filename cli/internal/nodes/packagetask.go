@@ -3,6 +3,7 @@ package nodes
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/vercel/turbo/cli/internal/fs"
 )
@@ -20,6 +21,10 @@ type PackageTask struct {
 	ExcludedOutputs []string
 	LogFile         string
 	Hash            string
+	// HashDuration is how long calculating Hash took
+	HashDuration time.Duration
+	// CompressedLog is whether LogFile is stored gzipped
+	CompressedLog bool
 }
 
 // OutputPrefix returns the prefix to be used for logging and ui for this task
@@ -30,11 +35,20 @@ func (pt *PackageTask) OutputPrefix(isSinglePackage bool) string {
 	return fmt.Sprintf("%v:%v", pt.PackageName, pt.Task)
 }
 
+// LogFileName returns the name of the task's log file within the package's .turbo directory
+func (pt *PackageTask) LogFileName() string {
+	if pt.CompressedLog {
+		return fmt.Sprintf("turbo-%v.log.gz", pt.Task)
+	}
+	return fmt.Sprintf("turbo-%v.log", pt.Task)
+}
+
 // HashableOutputs returns the package-relative globs for files to be considered outputs
-// of this task
+// of this task, including those that are only ever restored from the cache
 func (pt *PackageTask) HashableOutputs() fs.TaskOutputs {
-	inclusionOutputs := []string{fmt.Sprintf(".turbo/turbo-%v.log", pt.Task)}
+	inclusionOutputs := []string{".turbo/" + pt.LogFileName()}
 	inclusionOutputs = append(inclusionOutputs, pt.TaskDefinition.Outputs.Inclusions...)
+	inclusionOutputs = append(inclusionOutputs, pt.TaskDefinition.RestoreOnlyOutputs...)
 
 	return fs.TaskOutputs{
 		Inclusions: inclusionOutputs,
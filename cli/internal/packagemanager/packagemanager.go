@@ -172,8 +172,9 @@ func (pm PackageManager) CanPrune(projectDirectory turbopath.AbsoluteSystemPath)
 	return false, nil
 }
 
-// ReadLockfile will read the applicable lockfile into memory
-func (pm PackageManager) ReadLockfile(projectDirectory turbopath.AbsoluteSystemPath) (lockfile.Lockfile, error) {
+// ReadLockfile will read the applicable lockfile into memory. If cacheDir is set,
+// the parsed lockfile is cached there and reused for as long as the lockfile is unchanged.
+func (pm PackageManager) ReadLockfile(projectDirectory turbopath.AbsoluteSystemPath, cacheDir turbopath.AbsoluteSystemPath) (lockfile.Lockfile, error) {
 	if pm.UnmarshalLockfile == nil {
 		return nil, nil
 	}
@@ -181,7 +182,10 @@ func (pm PackageManager) ReadLockfile(projectDirectory turbopath.AbsoluteSystemP
 	if err != nil {
 		return nil, fmt.Errorf("reading %s: %w", pm.Lockfile, err)
 	}
-	return pm.UnmarshalLockfile(contents)
+	if cacheDir == "" {
+		return pm.UnmarshalLockfile(contents)
+	}
+	return lockfile.DecodeCached(cacheDir, contents, pm.UnmarshalLockfile)
 }
 
 // PrunePatchedPackages will alter the provided pkgJSON to only reference the provided patches
@@ -0,0 +1,209 @@
+package packagemanager
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/vercel/turbo/cli/internal/doublestar"
+	"github.com/vercel/turbo/cli/internal/turbopath"
+)
+
+// _workspaceCacheFile must be renamed whenever the format of the cache entry changes,
+// so that entries written by older versions of turbo are ignored.
+const _workspaceCacheFile = "workspaces-v2.json"
+
+// _absentDirModTime is recorded for a directory that a workspace glob names but that doesn't
+// exist, so that creating it invalidates the entry
+const _absentDirModTime = int64(-1)
+
+// workspaceCacheEntry records the package.json files found by a previous workspace discovery
+type workspaceCacheEntry struct {
+	// Key is a hash of the files that configure workspaces
+	Key string `json:"key"`
+	// Workspaces are the repo-relative paths to the discovered package.json files
+	Workspaces []string `json:"workspaces"`
+	// DirModTimes are the modification times, in nanoseconds, of the repo-relative directories
+	// that the workspace globs can match, where new workspaces would be added. Directories
+	// that don't exist have _absentDirModTime.
+	DirModTimes map[string]int64 `json:"dirModTimes"`
+}
+
+// GetWorkspacesCached is GetWorkspaces, reusing the package.json files found by a previous
+// discovery stored in cacheDir. The entry is only used while the root package.json, the
+// lockfile and the workspace configuration are unchanged, and no directory that the
+// workspace globs can match has been added, removed or changed. If cacheDir is empty,
+// workspaces are always discovered.
+func (pm PackageManager) GetWorkspacesCached(rootpath turbopath.AbsoluteSystemPath, cacheDir turbopath.AbsoluteSystemPath) ([]string, error) {
+	if cacheDir == "" {
+		return pm.GetWorkspaces(rootpath)
+	}
+	key := pm.workspaceCacheKey(rootpath)
+	cachePath := cacheDir.UntypedJoin(_workspaceCacheFile)
+	if workspaces, ok := readWorkspaceCache(rootpath, cachePath, key); ok {
+		return workspaces, nil
+	}
+
+	workspaces, err := pm.GetWorkspaces(rootpath)
+	if err != nil {
+		return nil, err
+	}
+	globs, err := pm.getWorkspaceGlobs(rootpath)
+	if err != nil {
+		return nil, err
+	}
+	writeWorkspaceCache(rootpath, cacheDir, cachePath, key, globs, workspaces)
+	return workspaces, nil
+}
+
+// workspaceCacheKey hashes the contents of every file that determines the set of workspaces
+func (pm PackageManager) workspaceCacheKey(rootpath turbopath.AbsoluteSystemPath) string {
+	hash := sha256.New()
+	hash.Write([]byte(pm.Slug))
+	for _, file := range []string{pm.Specfile, pm.Lockfile, pm.WorkspaceConfigurationPath} {
+		if file == "" {
+			continue
+		}
+		// A missing file hashes the same as an empty one, its absence is still part of the key
+		contents, _ := rootpath.UntypedJoin(file).ReadFile()
+		contentsHash := sha256.Sum256(contents)
+		hash.Write([]byte(file))
+		hash.Write(contentsHash[:])
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func readWorkspaceCache(rootpath turbopath.AbsoluteSystemPath, cachePath turbopath.AbsoluteSystemPath, key string) ([]string, bool) {
+	data, err := cachePath.ReadFile()
+	if err != nil {
+		return nil, false
+	}
+	var entry workspaceCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Key != key {
+		return nil, false
+	}
+	for dir, modTime := range entry.DirModTimes {
+		info, err := os.Stat(rootpath.UntypedJoin(filepath.FromSlash(dir)).ToString())
+		if err != nil {
+			if modTime != _absentDirModTime || !os.IsNotExist(err) {
+				return nil, false
+			}
+		} else if info.ModTime().UnixNano() != modTime {
+			return nil, false
+		}
+	}
+	workspaces := make([]string, len(entry.Workspaces))
+	for i, workspace := range entry.Workspaces {
+		path := rootpath.UntypedJoin(filepath.FromSlash(workspace))
+		if !path.FileExists() {
+			return nil, false
+		}
+		workspaces[i] = path.ToString()
+	}
+	return workspaces, true
+}
+
+func writeWorkspaceCache(rootpath turbopath.AbsoluteSystemPath, cacheDir turbopath.AbsoluteSystemPath, cachePath turbopath.AbsoluteSystemPath, key string, globs []string, workspaces []string) {
+	entry := workspaceCacheEntry{
+		Key:         key,
+		Workspaces:  make([]string, 0, len(workspaces)),
+		DirModTimes: make(map[string]int64),
+	}
+	for _, workspace := range workspaces {
+		relative, err := filepath.Rel(rootpath.ToString(), workspace)
+		if err != nil {
+			return
+		}
+		entry.Workspaces = append(entry.Workspaces, filepath.ToSlash(relative))
+	}
+	for _, glob := range globs {
+		if strings.HasPrefix(glob, "!") {
+			continue
+		}
+		base, segments := splitGlob(glob)
+		recordGlobDirs(rootpath, base, segments, entry.DirModTimes)
+	}
+
+	data, err := json.Marshal(&entry)
+	if err != nil {
+		return
+	}
+	if err := cacheDir.MkdirAll(0755); err != nil {
+		return
+	}
+	_ = cachePath.WriteFile(data, 0644)
+}
+
+// recordGlobDirs records the modification time of every directory below dir that the segments
+// of a workspace glob are matched against, since a workspace added to one of them changes its
+// modification time. Directories the whole glob matches are only recorded while they have no
+// package.json, so that files a task writes into an existing workspace don't invalidate the
+// entry. Directories that don't exist are recorded as absent, and node_modules directories,
+// which workspaces are never discovered in, aren't walked.
+func recordGlobDirs(rootpath turbopath.AbsoluteSystemPath, dir string, segments []string, modTimes map[string]int64) {
+	absolute := rootpath.UntypedJoin(filepath.FromSlash(dir)).ToString()
+	info, err := os.Stat(absolute)
+	if err != nil {
+		modTimes[dir] = _absentDirModTime
+		return
+	}
+	for len(segments) > 0 && (segments[0] == "" || segments[0] == ".") {
+		segments = segments[1:]
+	}
+	if !info.IsDir() {
+		return
+	}
+	if len(segments) == 0 {
+		if !rootpath.UntypedJoin(filepath.FromSlash(dir), "package.json").FileExists() {
+			modTimes[dir] = info.ModTime().UnixNano()
+		}
+		return
+	}
+	modTimes[dir] = info.ModTime().UnixNano()
+
+	segment := segments[0]
+	if !strings.ContainsAny(segment, "*?[{") {
+		recordGlobDirs(rootpath, path.Join(dir, segment), segments[1:], modTimes)
+		return
+	}
+	if segment == "**" {
+		// ** also matches no directory at all
+		recordGlobDirs(rootpath, dir, segments[1:], modTimes)
+	}
+	entries, err := os.ReadDir(absolute)
+	if err != nil {
+		return
+	}
+	for _, dirEntry := range entries {
+		if !dirEntry.IsDir() || dirEntry.Name() == "node_modules" {
+			continue
+		}
+		child := path.Join(dir, dirEntry.Name())
+		if segment == "**" {
+			recordGlobDirs(rootpath, child, segments, modTimes)
+		} else if matched, err := doublestar.Match(segment, dirEntry.Name()); err == nil && matched {
+			recordGlobDirs(rootpath, child, segments[1:], modTimes)
+		}
+	}
+}
+
+// splitGlob splits a workspace glob into its leading directories that contain no wildcards,
+// and the segments after them
+func splitGlob(glob string) (string, []string) {
+	segments := strings.Split(filepath.ToSlash(glob), "/")
+	base := []string{}
+	for _, segment := range segments {
+		if strings.ContainsAny(segment, "*?[{") {
+			break
+		}
+		base = append(base, segment)
+	}
+	if len(base) == 0 {
+		return ".", segments
+	}
+	return path.Clean(strings.Join(base, "/")), segments[len(base):]
+}
@@ -0,0 +1,138 @@
+package packagemanager
+
+import (
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"gotest.tools/v3/assert"
+)
+
+// workspaceCacheFixture is a repository whose workspaces are discovered through the cache
+type workspaceCacheFixture struct {
+	t        *testing.T
+	repoRoot turbopath.AbsoluteSystemPath
+	cacheDir turbopath.AbsoluteSystemPath
+}
+
+func newWorkspaceCacheFixture(t *testing.T) *workspaceCacheFixture {
+	repoRoot := turbopath.AbsoluteSystemPath(t.TempDir())
+	return &workspaceCacheFixture{
+		t:        t,
+		repoRoot: repoRoot,
+		cacheDir: repoRoot.UntypedJoin("node_modules", ".cache", "turbo"),
+	}
+}
+
+func (f *workspaceCacheFixture) writeFile(file string, contents string) {
+	path := f.repoRoot.UntypedJoin(filepath.FromSlash(file))
+	assert.NilError(f.t, path.EnsureDir(), "EnsureDir")
+	assert.NilError(f.t, path.WriteFile([]byte(contents), 0644), "WriteFile")
+}
+
+func (f *workspaceCacheFixture) mkdir(dir string) {
+	assert.NilError(f.t, f.repoRoot.UntypedJoin(filepath.FromSlash(dir)).MkdirAll(0755), "MkdirAll")
+}
+
+func (f *workspaceCacheFixture) getWorkspaces() []string {
+	workspaces, err := nodejsNpm.GetWorkspacesCached(f.repoRoot, f.cacheDir)
+	assert.NilError(f.t, err, "GetWorkspacesCached")
+	relative := make([]string, len(workspaces))
+	for i, workspace := range workspaces {
+		rel, err := filepath.Rel(f.repoRoot.ToString(), workspace)
+		assert.NilError(f.t, err, "Rel")
+		relative[i] = filepath.ToSlash(rel)
+	}
+	sort.Strings(relative)
+	return relative
+}
+
+// isCached returns whether the stored entry can be reused as is
+func (f *workspaceCacheFixture) isCached() bool {
+	key := nodejsNpm.workspaceCacheKey(f.repoRoot)
+	_, ok := readWorkspaceCache(f.repoRoot, f.cacheDir.UntypedJoin(_workspaceCacheFile), key)
+	return ok
+}
+
+func Test_GetWorkspacesCached(t *testing.T) {
+	f := newWorkspaceCacheFixture(t)
+	f.writeFile("package.json", `{"workspaces": ["packages/*"]}`)
+	f.writeFile("packages/a/package.json", `{"name": "a"}`)
+	assert.DeepEqual(t, f.getWorkspaces(), []string{"packages/a/package.json"})
+	assert.Assert(t, f.cacheDir.UntypedJoin(_workspaceCacheFile).FileExists(), "expected discovered workspaces to be cached")
+	assert.DeepEqual(t, f.getWorkspaces(), []string{"packages/a/package.json"})
+
+	// Adding a workspace alongside an existing one
+	f.writeFile("packages/b/package.json", `{"name": "b"}`)
+	assert.DeepEqual(t, f.getWorkspaces(), []string{"packages/a/package.json", "packages/b/package.json"})
+
+	// Changing the workspace globs
+	f.writeFile("apps/web/package.json", `{"name": "web"}`)
+	f.writeFile("package.json", `{"workspaces": ["packages/*", "apps/*"]}`)
+	assert.DeepEqual(t, f.getWorkspaces(), []string{"apps/web/package.json", "packages/a/package.json", "packages/b/package.json"})
+
+	// Removing a workspace
+	assert.NilError(t, f.repoRoot.UntypedJoin("packages", "a", "package.json").Remove(), "Remove")
+	assert.DeepEqual(t, f.getWorkspaces(), []string{"apps/web/package.json", "packages/b/package.json"})
+
+	// Adding a workspace to a directory that existed without a package.json
+	f.mkdir("packages/new")
+	assert.DeepEqual(t, f.getWorkspaces(), []string{"apps/web/package.json", "packages/b/package.json"})
+	f.writeFile("packages/new/package.json", `{"name": "new"}`)
+	assert.DeepEqual(t, f.getWorkspaces(), []string{"apps/web/package.json", "packages/b/package.json", "packages/new/package.json"})
+}
+
+func Test_GetWorkspacesCachedMissingDirs(t *testing.T) {
+	f := newWorkspaceCacheFixture(t)
+	// The base directory of a glob that doesn't exist yet
+	f.writeFile("package.json", `{"workspaces": ["packages/*", "apps/*", "libs/**"]}`)
+	f.writeFile("packages/a/package.json", `{"name": "a"}`)
+	assert.DeepEqual(t, f.getWorkspaces(), []string{"packages/a/package.json"})
+	f.writeFile("apps/web/package.json", `{"name": "web"}`)
+	assert.DeepEqual(t, f.getWorkspaces(), []string{"apps/web/package.json", "packages/a/package.json"})
+
+	// A directory nested below a ** glob that existed without a package.json
+	f.mkdir("libs/group/util")
+	assert.DeepEqual(t, f.getWorkspaces(), []string{"apps/web/package.json", "packages/a/package.json"})
+	f.writeFile("libs/group/util/package.json", `{"name": "util"}`)
+	assert.DeepEqual(t, f.getWorkspaces(), []string{"apps/web/package.json", "libs/group/util/package.json", "packages/a/package.json"})
+}
+
+func Test_GetWorkspacesCachedIgnoresWorkspaceContents(t *testing.T) {
+	f := newWorkspaceCacheFixture(t)
+	f.writeFile("package.json", `{"workspaces": ["packages/*", "tooling/eslint"]}`)
+	f.writeFile("packages/a/package.json", `{"name": "a"}`)
+	f.writeFile("tooling/eslint/package.json", `{"name": "eslint"}`)
+	assert.DeepEqual(t, f.getWorkspaces(), []string{"packages/a/package.json", "tooling/eslint/package.json"})
+	assert.Assert(t, f.isCached(), "expected discovered workspaces to be cached")
+
+	// Files written into existing workspaces, like build outputs and logs
+	f.writeFile("packages/a/dist/index.js", "")
+	f.mkdir("packages/a/.turbo")
+	f.mkdir("tooling/eslint/dist")
+	assert.Assert(t, f.isCached(), "expected the contents of workspaces not to invalidate the cache")
+
+	// A directory added next to the workspaces still does
+	f.mkdir("packages/b")
+	assert.Assert(t, !f.isCached(), "expected a new directory to invalidate the cache")
+}
+
+func Test_splitGlob(t *testing.T) {
+	testCases := map[string]struct {
+		base     string
+		segments []string
+	}{
+		"packages/*":        {base: "packages", segments: []string{"*"}},
+		"apps/**/*":         {base: "apps", segments: []string{"**", "*"}},
+		"tooling/eslint":    {base: "tooling/eslint", segments: []string{}},
+		"./packages/*":      {base: "packages", segments: []string{"*"}},
+		"*":                 {base: ".", segments: []string{"*"}},
+		"libs/{a,b}/nested": {base: "libs", segments: []string{"{a,b}", "nested"}},
+	}
+	for glob, want := range testCases {
+		base, segments := splitGlob(glob)
+		assert.Equal(t, base, want.base, glob)
+		assert.DeepEqual(t, segments, want.segments)
+	}
+}
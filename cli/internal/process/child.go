@@ -101,6 +101,10 @@ type NewInput struct {
 
 	// Logger receives debug log lines about the process state and transitions
 	Logger hclog.Logger
+
+	// Foreground keeps the process in the process group of its parent, rather than
+	// starting a new one, so that it can read from the terminal
+	Foreground bool
 }
 
 // New creates a new child process for management with high-level APIs for
@@ -117,7 +121,7 @@ func newChild(i NewInput) (*Child, error) {
 		killTimeout: i.KillTimeout,
 		splay:       i.Splay,
 		stopCh:      make(chan struct{}, 1),
-		setpgid:     true,
+		setpgid:     !i.Foreground,
 		Label:       label,
 		logger:      i.Logger.Named(label),
 	}
@@ -350,7 +354,10 @@ func (c *Child) kill(immediately bool) {
 	defer func() {
 		if !exited {
 			c.logger.Debug("PKill")
-			c.cmd.Process.Kill()
+			// Kill the whole process group, so that no grandchildren are left behind
+			if err := c.signal(os.Kill); err != nil {
+				c.cmd.Process.Kill()
+			}
 		}
 		c.cmd = nil
 	}()
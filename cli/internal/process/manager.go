@@ -1,11 +1,12 @@
 package process
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"os"
 	"os/exec"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
@@ -16,39 +17,91 @@ import (
 // child processes will be stopped with this error.
 var ErrClosing = errors.New("process manager is already closing")
 
+// ExitCodeTimeout is the exit code reported for a child process that was stopped because
+// it ran past its deadline. It is the code coreutils' timeout exits with.
+const ExitCodeTimeout = 124
+
+// ExitCodeRunTimeout is the exit code of a run that was stopped because it ran past its
+// --run-timeout, to tell it apart from tasks that ran past their own timeouts
+const ExitCodeRunTimeout = 125
+
+// DefaultKillTimeout is how long child processes are given to exit after being sent
+// SIGTERM, before they are killed, unless changed with SetKillTimeout
+const DefaultKillTimeout = 10 * time.Second
+
 // ChildExit is returned when a child process exits with a non-zero exit code
 type ChildExit struct {
 	ExitCode int
 	Command  string
+	// Category optionally labels what kind of failure the exit code represents
+	Category string
+	// TimedOut is set when the child was stopped because it ran past its deadline
+	TimedOut bool
 }
 
 func (ce *ChildExit) Error() string {
+	if ce.TimedOut {
+		return fmt.Sprintf("command %s timed out", ce.Command)
+	}
+	if ce.Category != "" {
+		return fmt.Sprintf("command %s exited (%d, %s)", ce.Command, ce.ExitCode, ce.Category)
+	}
 	return fmt.Sprintf("command %s exited (%d)", ce.Command, ce.ExitCode)
 }
 
 // Manager tracks all of the child processes that have been spawned
 type Manager struct {
 	done     bool
+	reason   string
 	children map[*Child]struct{}
 	mu       sync.Mutex
 	doneCh   chan struct{}
 	logger   hclog.Logger
+	// killTimeout is the grace period between SIGTERM and SIGKILL when stopping children
+	killTimeout time.Duration
 }
 
 // NewManager creates a new properly-initialized Manager instance
 func NewManager(logger hclog.Logger) *Manager {
 	return &Manager{
-		children: make(map[*Child]struct{}),
-		doneCh:   make(chan struct{}),
-		logger:   logger,
+		children:    make(map[*Child]struct{}),
+		doneCh:      make(chan struct{}),
+		logger:      logger,
+		killTimeout: DefaultKillTimeout,
 	}
 }
 
+// SetKillTimeout sets how long child processes are given to exit after being sent
+// SIGTERM, before they are killed. It applies to children started afterwards.
+func (m *Manager) SetKillTimeout(timeout time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.killTimeout = timeout
+}
+
 // Exec spawns a child process to run the given command, then blocks
 // until it completes. Returns a nil error if the child process finished
 // successfully, ErrClosing if the manager closed during execution, and
 // a ChildExit error if the child process exited with a non-zero exit code.
 func (m *Manager) Exec(cmd *exec.Cmd) error {
+	return m.ExecContext(context.Background(), cmd)
+}
+
+// ExecContext is Exec, additionally stopping the child process, the same way Close does,
+// once ctx is done. If ctx's deadline passed, a ChildExit error with TimedOut set and
+// ExitCodeTimeout is returned, otherwise ErrClosing is.
+func (m *Manager) ExecContext(ctx context.Context, cmd *exec.Cmd) error {
+	return m.execContext(ctx, cmd, false)
+}
+
+// ExecInteractiveContext is ExecContext for a command that reads from the terminal. The
+// child process stays in turbo's process group, since processes in a background group
+// are stopped when they read from the terminal.
+func (m *Manager) ExecInteractiveContext(ctx context.Context, cmd *exec.Cmd) error {
+	return m.execContext(ctx, cmd, true)
+}
+
+func (m *Manager) execContext(ctx context.Context, cmd *exec.Cmd, foreground bool) error {
 	m.mu.Lock()
 	if m.done {
 		m.mu.Unlock()
@@ -59,11 +112,12 @@ func (m *Manager) Exec(cmd *exec.Cmd) error {
 		Cmd: cmd,
 		// Run forever by default
 		Timeout: 0,
-		// When it's time to exit, give a 10 second timeout
-		KillTimeout: 10 * time.Second,
-		// Send SIGINT to stop children
-		KillSignal: os.Interrupt,
+		// When it's time to exit, give children the grace period to clean up
+		KillTimeout: m.killTimeout,
+		// Send SIGTERM to stop children
+		KillSignal: syscall.SIGTERM,
 		Logger:     m.logger,
+		Foreground: foreground,
 	})
 	if err != nil {
 		return err
@@ -79,8 +133,25 @@ func (m *Manager) Exec(cmd *exec.Cmd) error {
 		return err
 	}
 	err = nil
-	exitCode, ok := <-child.ExitCh()
-	if !ok {
+	var exitCode int
+	var ok bool
+	select {
+	case exitCode, ok = <-child.ExitCh():
+	case <-ctx.Done():
+		// A stopped child doesn't report its exit code
+		child.Stop()
+	}
+	if ctx.Err() != nil && !ok {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			err = &ChildExit{
+				ExitCode: ExitCodeTimeout,
+				Command:  child.Command(),
+				TimedOut: true,
+			}
+		} else {
+			err = ErrClosing
+		}
+	} else if !ok {
 		err = ErrClosing
 	} else if exitCode != ExitCodeOK {
 		err = &ChildExit{
@@ -95,8 +166,35 @@ func (m *Manager) Exec(cmd *exec.Cmd) error {
 	return err
 }
 
-// Close sends SIGINT to all child processes if it hasn't been done yet,
-// and in either case blocks until they all exit or timeout
+// IsClosing returns true if Close has been called, meaning that in-flight child
+// processes may have been stopped before they completed
+func (m *Manager) IsClosing() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.done
+}
+
+// CancellationReason returns why the manager was closed, as given to CloseWithReason
+func (m *Manager) CancellationReason() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.reason
+}
+
+// CloseWithReason is Close, additionally recording why in-flight child processes are
+// being stopped. Only the reason given when the manager is first closed is kept.
+func (m *Manager) CloseWithReason(reason string) {
+	m.mu.Lock()
+	if !m.done {
+		m.reason = reason
+	}
+	m.mu.Unlock()
+	m.Close()
+}
+
+// Close sends SIGTERM to all child processes if it hasn't been done yet, killing those
+// that haven't exited once the kill timeout passes, and in either case blocks until
+// they all exit
 func (m *Manager) Close() {
 	m.mu.Lock()
 	if m.done {
@@ -1,8 +1,11 @@
 package process
 
 import (
+	"context"
 	"errors"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -80,6 +83,19 @@ func TestClose_alreadyClosed(t *testing.T) {
 	}
 }
 
+func TestCloseWithReason(t *testing.T) {
+	mgr := newManager()
+	mgr.CloseWithReason("first")
+
+	// only the reason given when first closing is kept
+	mgr.CloseWithReason("second")
+	mgr.Close()
+
+	if reason := mgr.CancellationReason(); reason != "first" {
+		t.Errorf("expected cancellation reason %q, found %q", "first", reason)
+	}
+}
+
 func TestExitCode(t *testing.T) {
 	mgr := newManager()
 
@@ -92,3 +108,112 @@ func TestExitCode(t *testing.T) {
 		t.Error("expected non-zero exit code , got 0")
 	}
 }
+
+func TestChildExitCategory(t *testing.T) {
+	exitErr := &ChildExit{ExitCode: 2, Command: "npm run test"}
+	if got, want := exitErr.Error(), "command npm run test exited (2)"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	exitErr.Category = "test failure"
+	if got, want := exitErr.Error(), "command npm run test exited (2, test failure)"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	timeoutErr := &ChildExit{ExitCode: ExitCodeTimeout, Command: "npm run test", TimedOut: true}
+	if got, want := timeoutErr.Error(), "command npm run test timed out"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestExecContext_timeout(t *testing.T) {
+	mgr := newManager()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := mgr.ExecContext(ctx, exec.Command("sleep", "10"))
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the child to be stopped at its deadline, it ran for %v", elapsed)
+	}
+	exitErr := &ChildExit{}
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("expected a ChildExit err, got %q", err)
+	}
+	if !exitErr.TimedOut || exitErr.ExitCode != ExitCodeTimeout {
+		t.Errorf("expected a timeout with exit code %v, got %+v", ExitCodeTimeout, exitErr)
+	}
+	if mgr.IsClosing() {
+		t.Error("a timeout must not close the manager")
+	}
+
+	// Commands that finish in time are unaffected
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := mgr.ExecContext(ctx, exec.Command("true")); err != nil {
+		t.Errorf("expected %q to be nil", err)
+	}
+}
+
+func TestExecContext_canceled(t *testing.T) {
+	mgr := newManager()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+	}()
+	err := mgr.ExecContext(ctx, exec.Command("sleep", "10"))
+	if !errors.Is(err, ErrClosing) {
+		t.Errorf("expected ErrClosing, got %q", err)
+	}
+}
+
+func TestClose_gracePeriod(t *testing.T) {
+	mgr := newManager()
+	mgr.SetKillTimeout(5 * time.Second)
+
+	cleanedUp := filepath.Join(t.TempDir(), "cleaned-up")
+	cmd := exec.Command("sh", "-c", "trap 'echo done > \"$0\"; exit 0' TERM; sleep 10", cleanedUp)
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- mgr.Exec(cmd)
+	}()
+	// let the shell install its trap
+	time.Sleep(200 * time.Millisecond)
+	mgr.Close()
+
+	if err := <-errCh; err != ErrClosing {
+		t.Errorf("expected manager closing error, found %q", err)
+	}
+	if _, err := os.Stat(cleanedUp); err != nil {
+		t.Errorf("expected the child to clean up after SIGTERM: %v", err)
+	}
+}
+
+func TestClose_killAfterTimeout(t *testing.T) {
+	mgr := newManager()
+	mgr.SetKillTimeout(200 * time.Millisecond)
+
+	// The shell, and the sleep it starts, ignore SIGTERM. Exec only returns once both
+	// exited and closed their output.
+	cmd := exec.Command("sh", "-c", "trap '' TERM; sleep 10")
+	cmd.Stdout = gatedio.NewByteBuffer()
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- mgr.Exec(cmd)
+	}()
+	time.Sleep(200 * time.Millisecond)
+	start := time.Now()
+	mgr.Close()
+
+	select {
+	case err := <-errCh:
+		if err != ErrClosing {
+			t.Errorf("expected manager closing error, found %q", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the child to be killed once the kill timeout passed")
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("expected the child to be given the kill timeout, it was stopped after %v", elapsed)
+	}
+}
@@ -0,0 +1,15 @@
+package process
+
+import (
+	"os/exec"
+	"runtime"
+)
+
+// ShellCommand builds a command that runs the given command line via the system shell:
+// cmd.exe on Windows, and sh everywhere else
+func ShellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/C", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
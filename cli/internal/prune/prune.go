@@ -65,7 +65,7 @@ func (p *prune) prune(opts *turbostate.PrunePayload) error {
 	if err != nil {
 		return fmt.Errorf("failed to read package.json: %w", err)
 	}
-	ctx, err := context.BuildPackageGraph(p.base.RepoRoot, rootPackageJSON)
+	ctx, err := context.BuildPackageGraph(p.base.RepoRoot, rootPackageJSON, "", "")
 	if err != nil {
 		return errors.Wrap(err, "could not construct graph")
 	}
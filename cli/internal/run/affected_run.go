@@ -0,0 +1,98 @@
+// Package run implements `turbo run`
+// This file implements the logic for `turbo run --affected-output`
+package run
+
+import (
+	gocontext "context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vercel/turbo/cli/internal/cache"
+	"github.com/vercel/turbo/cli/internal/cmdutil"
+	"github.com/vercel/turbo/cli/internal/core"
+	"github.com/vercel/turbo/cli/internal/fs"
+	"github.com/vercel/turbo/cli/internal/graph"
+	"github.com/vercel/turbo/cli/internal/nodes"
+	"github.com/vercel/turbo/cli/internal/runsummary"
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"github.com/vercel/turbo/cli/internal/ui"
+	"github.com/vercel/turbo/cli/internal/util"
+)
+
+// AffectedRun calculates the hash of every task without executing any of them, and writes
+// the packages that have at least one task that would not be restored from the cache to
+// the --affected-output file. Tasks without a command never run, so they don't count, and
+// tasks that aren't cached, or whose cache is bypassed, always do.
+func AffectedRun(
+	ctx gocontext.Context,
+	g *graph.CompleteGraph,
+	rs *runSpec,
+	engine *core.Engine,
+	turboCache cache.Cache,
+	base *cmdutil.CmdBase,
+) error {
+	defer turboCache.Shutdown()
+
+	forcedReruns := make(util.Set)
+	if len(rs.Opts.runOpts.rerunDependentsOf) > 0 {
+		forced, _, err := rerunDependents(engine, rs.Opts.runOpts.rerunDependentsOf)
+		if err != nil {
+			return err
+		}
+		forcedReruns = forced
+	}
+
+	affected := make(util.Set)
+	cached := []*runsummary.TaskSummary{}
+	affectedFunc := func(ctx gocontext.Context, packageTask *nodes.PackageTask, taskSummary *runsummary.TaskSummary) error {
+		if packageTask.Command == "" {
+			return nil
+		}
+		if !packageTask.TaskDefinition.ShouldCache || rs.Opts.runcacheOpts.SkipReads || forcedReruns.Includes(packageTask.TaskID) {
+			affected.Add(packageTask.PackageName)
+			return nil
+		}
+		cached = append(cached, taskSummary)
+		return nil
+	}
+
+	getArgs := func(taskID string) []string {
+		return rs.ArgsForTask(taskID)
+	}
+	// Like a dry run, the walk isn't parallelized, only the cache lookups are
+	visitorFn := g.GetPackageTaskVisitor(ctx, engine.TaskGraph, getArgs, base.Logger, affectedFunc)
+	errs := engine.Execute(visitorFn, core.EngineExecutionOptions{Concurrency: 1})
+	if len(errs) > 0 {
+		return errs[0]
+	}
+
+	populateCacheState(turboCache, cached)
+	for _, taskSummary := range cached {
+		if !taskSummary.CacheState.Local && !taskSummary.CacheState.Remote {
+			affected.Add(taskSummary.Package)
+		}
+	}
+
+	outputFile := fs.ResolveUnknownPath(base.RepoRoot, rs.Opts.runOpts.affectedOutput)
+	if err := writeAffectedPackages(outputFile, affected); err != nil {
+		return fmt.Errorf("failed to write affected packages to %v: %w", outputFile, err)
+	}
+	base.UI.Output(ui.Dim(fmt.Sprintf("• Wrote %v affected package(s) to %v", affected.Len(), outputFile)))
+	return nil
+}
+
+// writeAffectedPackages writes the sorted names of the given packages to outputFile, one
+// per line. The file is empty when there are none.
+func writeAffectedPackages(outputFile turbopath.AbsoluteSystemPath, packages util.Set) error {
+	names := packages.UnsafeListOfStrings()
+	sort.Strings(names)
+	contents := ""
+	if len(names) > 0 {
+		contents = strings.Join(names, "\n") + "\n"
+	}
+	if err := outputFile.EnsureDir(); err != nil {
+		return err
+	}
+	return outputFile.WriteFile([]byte(contents), 0644)
+}
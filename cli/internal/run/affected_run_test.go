@@ -0,0 +1,23 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"github.com/vercel/turbo/cli/internal/util"
+	"gotest.tools/v3/assert"
+)
+
+func Test_writeAffectedPackages(t *testing.T) {
+	outputFile := turbopath.AbsoluteSystemPath(t.TempDir()).UntypedJoin("out", "packages.txt")
+
+	assert.NilError(t, writeAffectedPackages(outputFile, util.SetFromStrings([]string{"web", "docs", "ui"})))
+	contents, err := outputFile.ReadFile()
+	assert.NilError(t, err)
+	assert.Equal(t, string(contents), "docs\nui\nweb\n")
+
+	assert.NilError(t, writeAffectedPackages(outputFile, make(util.Set)))
+	contents, err = outputFile.ReadFile()
+	assert.NilError(t, err)
+	assert.Equal(t, string(contents), "")
+}
@@ -0,0 +1,238 @@
+package run
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mitchellh/cli"
+	"github.com/vercel/turbo/cli/internal/graph"
+	"github.com/vercel/turbo/cli/internal/nodes"
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"github.com/vercel/turbo/cli/internal/ui"
+	"github.com/vercel/turbo/cli/internal/util"
+)
+
+// fileAccess is a file opened by a traced task. Path is either absolute, or relative to
+// the directory the task was started in.
+type fileAccess struct {
+	path  string
+	write bool
+}
+
+// ioDiscrepancies are the files a task accessed that its configuration doesn't account for
+type ioDiscrepancies struct {
+	// undeclaredInputs are files that were read, but aren't part of the task's hash
+	undeclaredInputs []string
+	// undeclaredOutputs are files that were written, but aren't cached
+	undeclaredOutputs []string
+}
+
+// Files read by the package manager itself whenever it runs a script
+var _packageManagerFiles = util.SetFromStrings([]string{
+	"package.json",
+	"package-lock.json",
+	"npm-shrinkwrap.json",
+	"yarn.lock",
+	"pnpm-lock.yaml",
+	"pnpm-workspace.yaml",
+	".npmrc",
+	".yarnrc",
+	".yarnrc.yml",
+	".pnp.cjs",
+	".pnp.loader.mjs",
+})
+
+// ioAuditor compares the files accessed by tasks run with --audit-io against the
+// inputs and outputs they declare
+type ioAuditor struct {
+	repoRoot turbopath.AbsoluteSystemPath
+	g        *graph.CompleteGraph
+	// globalFiles are the repo-relative files included in the global hash
+	globalFiles map[turbopath.AnchoredUnixPath]string
+}
+
+// audit classifies the file accesses of a task. inputs are the package-relative files
+// that were hashed for the task.
+func (a *ioAuditor) audit(packageTask *nodes.PackageTask, inputs map[turbopath.AnchoredUnixPath]string, accesses []fileAccess) (*ioDiscrepancies, error) {
+	pkgDir := packageTask.Pkg.Dir.ToUnixPath().ToString()
+	taskDir := packageTask.Pkg.Dir.RestoreAnchor(a.repoRoot).ToString()
+	dependencyDirs := a.dependencyDirs(packageTask.PackageName)
+	outputs := packageTask.HashableOutputs()
+
+	reads := make(util.Set)
+	writes := make(util.Set)
+	for _, access := range accesses {
+		path := access.path
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(taskDir, path)
+		}
+		repoRelative, err := filepath.Rel(a.repoRoot.ToString(), filepath.Clean(path))
+		if err != nil || repoRelative == "." || strings.HasPrefix(repoRelative, "..") {
+			// Only files within the repository can be declared
+			continue
+		}
+		repoRelative = filepath.ToSlash(repoRelative)
+		if isIgnoredAuditPath(repoRelative) {
+			continue
+		}
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			continue
+		}
+		if access.write {
+			writes.Add(repoRelative)
+		} else {
+			reads.Add(repoRelative)
+		}
+	}
+
+	discrepancies := &ioDiscrepancies{}
+	for _, file := range writes.UnsafeListOfStrings() {
+		packageRelative, inPackage := relativeTo(pkgDir, file)
+		if inPackage {
+			cached, err := matchesAny(outputs.Inclusions, packageRelative)
+			if err != nil {
+				return nil, err
+			}
+			excluded, err := matchesAny(outputs.Exclusions, packageRelative)
+			if err != nil {
+				return nil, err
+			}
+			if cached && !excluded {
+				continue
+			}
+		}
+		discrepancies.undeclaredOutputs = append(discrepancies.undeclaredOutputs, file)
+	}
+	for _, file := range reads.UnsafeListOfStrings() {
+		// A task reading back what it wrote isn't an input
+		if writes.Includes(file) {
+			continue
+		}
+		declared, err := a.isDeclaredInput(file, pkgDir, inputs, dependencyDirs, packageTask)
+		if err != nil {
+			return nil, err
+		}
+		if !declared {
+			discrepancies.undeclaredInputs = append(discrepancies.undeclaredInputs, file)
+		}
+	}
+	sort.Strings(discrepancies.undeclaredInputs)
+	sort.Strings(discrepancies.undeclaredOutputs)
+	return discrepancies, nil
+}
+
+func (a *ioAuditor) isDeclaredInput(file string, pkgDir string, inputs map[turbopath.AnchoredUnixPath]string, dependencyDirs []string, packageTask *nodes.PackageTask) (bool, error) {
+	if packageRelative, inPackage := relativeTo(pkgDir, file); inPackage {
+		_, ok := inputs[turbopath.AnchoredUnixPath(packageRelative)]
+		return ok || _packageManagerFiles.Includes(packageRelative), nil
+	}
+	if _, ok := a.globalFiles[turbopath.AnchoredUnixPath(file)]; ok {
+		return true, nil
+	}
+	if !strings.Contains(file, "/") && _packageManagerFiles.Includes(file) {
+		return true, nil
+	}
+	// Changes to dependencies are reflected in the hashes of their tasks
+	for _, dir := range dependencyDirs {
+		if _, inDependency := relativeTo(dir, file); inDependency {
+			return true, nil
+		}
+	}
+	return matchesAny(packageTask.TaskDefinition.RootInputs, file)
+}
+
+// dependencyDirs returns the repo-relative directories of the transitive workspace
+// dependencies of a package
+func (a *ioAuditor) dependencyDirs(packageName string) []string {
+	dirs := []string{}
+	seen := make(util.Set)
+	pending := []string{packageName}
+	for len(pending) > 0 {
+		pkg, ok := a.g.WorkspaceInfos.PackageJSONs[pending[0]]
+		pending = pending[1:]
+		if !ok {
+			continue
+		}
+		for _, dep := range pkg.InternalDeps {
+			if seen.Includes(dep) {
+				continue
+			}
+			seen.Add(dep)
+			pending = append(pending, dep)
+			if depPkg, ok := a.g.WorkspaceInfos.PackageJSONs[dep]; ok {
+				dirs = append(dirs, depPkg.Dir.ToUnixPath().ToString())
+			}
+		}
+	}
+	return dirs
+}
+
+// isIgnoredAuditPath reports whether accesses to a repo-relative path are never discrepancies:
+// installed dependencies are covered by the lockfile, and turbo manages its own files.
+func isIgnoredAuditPath(repoRelative string) bool {
+	for _, segment := range strings.Split(repoRelative, "/") {
+		if segment == "node_modules" || segment == ".git" || segment == ".turbo" {
+			return true
+		}
+	}
+	return false
+}
+
+// relativeTo returns file relative to dir, if file is within dir. Both are repo-relative unix paths.
+func relativeTo(dir string, file string) (string, bool) {
+	if dir == "" || dir == "." {
+		return file, true
+	}
+	if strings.HasPrefix(file, dir+"/") {
+		return strings.TrimPrefix(file, dir+"/"), true
+	}
+	return "", false
+}
+
+// auditIO compares the files a task accessed, according to the trace in traceDir, against
+// its declared inputs and outputs, and records any discrepancies
+func (ec *execContext) auditIO(packageTask *nodes.PackageTask, traceDir string, prefixedUI *cli.PrefixedUi) error {
+	accesses, err := readTrace(traceDir)
+	if err != nil {
+		return err
+	}
+	inputs := ec.taskHashTracker.GetExpandedInputs(packageTask)
+	discrepancies, err := ec.ioAuditor.audit(packageTask, inputs, accesses)
+	if err != nil {
+		return err
+	}
+	if len(discrepancies.undeclaredInputs) == 0 && len(discrepancies.undeclaredOutputs) == 0 {
+		return nil
+	}
+	prefixedUI.Warn(fmt.Sprintf("accessed %v undeclared input(s) and %v undeclared output(s)", len(discrepancies.undeclaredInputs), len(discrepancies.undeclaredOutputs)))
+	ec.ioDiscrepanciesMu.Lock()
+	defer ec.ioDiscrepanciesMu.Unlock()
+	ec.ioDiscrepancies[packageTask.TaskID] = discrepancies
+	return nil
+}
+
+// printIODiscrepancies reports every task that accessed files its configuration doesn't
+// account for when running with --audit-io
+func (ec *execContext) printIODiscrepancies(terminal cli.Ui) {
+	taskIDs := make([]string, 0, len(ec.ioDiscrepancies))
+	for taskID := range ec.ioDiscrepancies {
+		taskIDs = append(taskIDs, taskID)
+	}
+	sort.Strings(taskIDs)
+
+	terminal.Output("")
+	terminal.Warn(fmt.Sprintf("%v task(s) accessed files that are not declared in turbo.json:", len(taskIDs)))
+	for _, taskID := range taskIDs {
+		discrepancies := ec.ioDiscrepancies[taskID]
+		terminal.Output(fmt.Sprintf("  %s", ui.Bold(taskID)))
+		for _, file := range discrepancies.undeclaredInputs {
+			terminal.Output(fmt.Sprintf("    read, not an input:   %s", file))
+		}
+		for _, file := range discrepancies.undeclaredOutputs {
+			terminal.Output(fmt.Sprintf("    written, not cached:  %s", file))
+		}
+	}
+}
@@ -0,0 +1,94 @@
+//go:build darwin
+// +build darwin
+
+package run
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Flags from <sys/fcntl.h> that indicate a file is opened for writing
+const (
+	_oWronly = 0x1
+	_oRdwr   = 0x2
+	_oCreat  = 0x200
+	_oTrunc  = 0x400
+)
+
+// _dtraceScript prints the flags and path of every file opened by the task
+// and its descendants
+const _dtraceScript = `
+syscall::open:entry, syscall::open_nocancel:entry /progenyof($target)/ {
+	printf("%d\t%s\n", arg1, copyinstr(arg0));
+}
+syscall::openat:entry, syscall::openat_nocancel:entry /progenyof($target)/ {
+	printf("%d\t%s\n", arg2, copyinstr(arg1));
+}`
+
+// ioAuditSupported returns an error if tasks can't be traced with --audit-io
+func ioAuditSupported() error {
+	if _, err := exec.LookPath("dtrace"); err != nil {
+		return fmt.Errorf("--audit-io requires dtrace: %w", err)
+	}
+	if os.Geteuid() != 0 {
+		return fmt.Errorf("--audit-io traces tasks with dtrace, which must be run as root (e.g. with sudo)")
+	}
+	return nil
+}
+
+// traceCommand returns a command that runs cmd under dtrace, writing the trace into traceDir.
+// dtrace splits the command given to -c on whitespace, without any quoting, so cmd is run
+// from a script written into traceDir instead.
+func traceCommand(cmd *exec.Cmd, traceDir string) (*exec.Cmd, error) {
+	quoted := make([]string, 0, len(cmd.Args))
+	quoted = append(quoted, shellQuote(cmd.Path))
+	for _, arg := range cmd.Args[1:] {
+		quoted = append(quoted, shellQuote(arg))
+	}
+	script := filepath.Join(traceDir, "command.sh")
+	if strings.ContainsAny(script, _dtraceWhitespace) {
+		return nil, fmt.Errorf("dtrace can't run %v, its path contains whitespace", script)
+	}
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nexec "+strings.Join(quoted, " ")+"\n"), 0755); err != nil {
+		return nil, err
+	}
+	traced := exec.Command("dtrace", "-q", "-o", filepath.Join(traceDir, "trace"), "-n", _dtraceScript, "-c", script)
+	traced.Dir = cmd.Dir
+	traced.Env = cmd.Env
+	return traced, nil
+}
+
+// _dtraceWhitespace are the characters dtrace splits the command given to -c on
+const _dtraceWhitespace = "\f\n\r\t\v "
+
+// shellQuote quotes s as a single word for /bin/sh
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// readTrace returns the files opened according to the dtrace output in traceDir
+func readTrace(traceDir string) ([]fileAccess, error) {
+	contents, err := os.ReadFile(filepath.Join(traceDir, "trace"))
+	if err != nil {
+		return nil, err
+	}
+	accesses := []fileAccess{}
+	for _, line := range strings.Split(string(contents), "\n") {
+		fields := strings.SplitN(line, "\t", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		flags, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			continue
+		}
+		write := flags&(_oWronly|_oRdwr|_oCreat|_oTrunc) != 0
+		accesses = append(accesses, fileAccess{path: fields[1], write: write})
+	}
+	return accesses, nil
+}
@@ -0,0 +1,26 @@
+//go:build darwin
+// +build darwin
+
+package run
+
+import (
+	"os/exec"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func Test_traceCommand(t *testing.T) {
+	cmd := exec.Command("printf", `%s\n`, "with space", "it's", `"quoted"`, "$HOME")
+	cmd.Dir = t.TempDir()
+	traced, err := traceCommand(cmd, t.TempDir())
+	assert.NilError(t, err, "traceCommand")
+	assert.Equal(t, traced.Dir, cmd.Dir)
+
+	// dtrace runs the script given to -c, which must run the command with its arguments intact
+	script := traced.Args[len(traced.Args)-1]
+	assert.Equal(t, traced.Args[len(traced.Args)-2], "-c")
+	output, err := exec.Command("/bin/sh", script).Output()
+	assert.NilError(t, err, "running %v", script)
+	assert.Equal(t, string(output), "with space\nit's\n\"quoted\"\n$HOME\n")
+}
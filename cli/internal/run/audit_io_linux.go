@@ -0,0 +1,92 @@
+//go:build linux
+// +build linux
+
+package run
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ioAuditSupported returns an error if tasks can't be traced with --audit-io
+func ioAuditSupported() error {
+	if _, err := exec.LookPath("strace"); err != nil {
+		return fmt.Errorf("--audit-io requires strace to be installed: %w", err)
+	}
+	return nil
+}
+
+// traceCommand returns a command that runs cmd under strace, writing a trace file per
+// process into traceDir
+func traceCommand(cmd *exec.Cmd, traceDir string) (*exec.Cmd, error) {
+	args := []string{
+		// follow child processes, each into its own file, so their calls aren't interleaved
+		"-f", "-ff",
+		"-qq",
+		// print the resolved path of every file descriptor
+		"-y",
+		"-e", "trace=open,openat,creat",
+		"-o", filepath.Join(traceDir, "trace"),
+		"--", cmd.Path,
+	}
+	traced := exec.Command("strace", append(args, cmd.Args[1:]...)...)
+	traced.Dir = cmd.Dir
+	traced.Env = cmd.Env
+	return traced, nil
+}
+
+// _straceOpenLine matches a successful open syscall, e.g.
+// openat(AT_FDCWD</repo>, "src/index.js", O_RDONLY|O_CLOEXEC) = 3</repo/src/index.js>
+var _straceOpenLine = regexp.MustCompile(`^(open|openat|creat)\((.*)\) = (\d+)<(.*)>$`)
+
+// _straceFlags matches the flags argument of an open syscall
+var _straceFlags = regexp.MustCompile(`, (O_[A-Z_|]+)`)
+
+// readTrace returns the files opened according to the strace output in traceDir
+func readTrace(traceDir string) ([]fileAccess, error) {
+	entries, err := os.ReadDir(traceDir)
+	if err != nil {
+		return nil, err
+	}
+	accesses := []fileAccess{}
+	for _, entry := range entries {
+		contents, err := os.ReadFile(filepath.Join(traceDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		accesses = append(accesses, parseStrace(string(contents))...)
+	}
+	return accesses, nil
+}
+
+func parseStrace(trace string) []fileAccess {
+	accesses := []fileAccess{}
+	for _, line := range strings.Split(trace, "\n") {
+		match := _straceOpenLine.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		path, err := strconv.Unquote(`"` + match[4] + `"`)
+		if err != nil {
+			path = match[4]
+		}
+		write := match[1] == "creat"
+		if flags := _straceFlags.FindStringSubmatch(match[2]); flags != nil {
+			if strings.Contains(flags[1], "O_DIRECTORY") {
+				continue
+			}
+			for _, flag := range []string{"O_WRONLY", "O_RDWR", "O_CREAT", "O_TRUNC"} {
+				if strings.Contains(flags[1], flag) {
+					write = true
+				}
+			}
+		}
+		accesses = append(accesses, fileAccess{path: path, write: write})
+	}
+	return accesses
+}
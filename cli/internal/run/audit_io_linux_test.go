@@ -0,0 +1,26 @@
+//go:build linux
+// +build linux
+
+package run
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"gotest.tools/v3/assert"
+)
+
+func Test_parseStrace(t *testing.T) {
+	trace := `openat(AT_FDCWD</repo/apps/web>, "src/index.ts", O_RDONLY|O_CLOEXEC) = 3</repo/apps/web/src/index.ts>
+openat(AT_FDCWD</repo/apps/web>, "dist", O_RDONLY|O_NONBLOCK|O_CLOEXEC|O_DIRECTORY) = 4</repo/apps/web/dist>
+openat(AT_FDCWD</repo/apps/web>, "dist/index.js", O_WRONLY|O_CREAT|O_TRUNC|O_CLOEXEC, 0666) = 5</repo/apps/web/dist/index.js>
+openat(AT_FDCWD</repo/apps/web>, "missing.ts", O_RDONLY|O_CLOEXEC) = -1 ENOENT (No such file or directory)
+creat("/repo/apps/web/out.txt", 0644) = 6</repo/apps/web/out.txt>
++++ exited with 0 +++
+`
+	assert.DeepEqual(t, parseStrace(trace), []fileAccess{
+		{path: "/repo/apps/web/src/index.ts"},
+		{path: "/repo/apps/web/dist/index.js", write: true},
+		{path: "/repo/apps/web/out.txt", write: true},
+	}, cmp.AllowUnexported(fileAccess{}))
+}
@@ -0,0 +1,22 @@
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package run
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// ioAuditSupported returns an error if tasks can't be traced with --audit-io
+func ioAuditSupported() error {
+	return errors.New("--audit-io is only supported on Linux and macOS")
+}
+
+func traceCommand(cmd *exec.Cmd, traceDir string) (*exec.Cmd, error) {
+	return cmd, nil
+}
+
+func readTrace(traceDir string) ([]fileAccess, error) {
+	return nil, errors.New("--audit-io is only supported on Linux and macOS")
+}
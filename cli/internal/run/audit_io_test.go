@@ -0,0 +1,80 @@
+package run
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vercel/turbo/cli/internal/fs"
+	"github.com/vercel/turbo/cli/internal/graph"
+	"github.com/vercel/turbo/cli/internal/nodes"
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"github.com/vercel/turbo/cli/internal/workspace"
+	"gotest.tools/v3/assert"
+)
+
+func Test_ioAuditor_audit(t *testing.T) {
+	repoRoot := turbopath.AbsoluteSystemPath(t.TempDir())
+	for _, file := range []string{
+		"package.json",
+		"tsconfig.json",
+		".env",
+		"apps/web/package.json",
+		"apps/web/src/index.ts",
+		"apps/web/fixtures/data.json",
+		"apps/web/dist/index.js",
+		"apps/web/tmp/scratch.txt",
+		"apps/web/node_modules/dep/index.js",
+		"packages/ui/src/button.ts",
+		"packages/other/src/index.ts",
+	} {
+		path := repoRoot.UntypedJoin(filepath.FromSlash(file))
+		assert.NilError(t, path.EnsureDir(), "EnsureDir")
+		assert.NilError(t, path.WriteFile([]byte(file), 0644), "WriteFile")
+	}
+
+	web := &fs.PackageJSON{Name: "web", Dir: turbopath.AnchoredUnixPath("apps/web").ToSystemPath(), InternalDeps: []string{"ui"}}
+	ui := &fs.PackageJSON{Name: "ui", Dir: turbopath.AnchoredUnixPath("packages/ui").ToSystemPath()}
+	other := &fs.PackageJSON{Name: "other", Dir: turbopath.AnchoredUnixPath("packages/other").ToSystemPath()}
+	auditor := &ioAuditor{
+		repoRoot: repoRoot,
+		g: &graph.CompleteGraph{
+			WorkspaceInfos: workspace.Catalog{
+				PackageJSONs: map[string]*fs.PackageJSON{"web": web, "ui": ui, "other": other},
+			},
+		},
+		globalFiles: map[turbopath.AnchoredUnixPath]string{"tsconfig.json": "hash"},
+	}
+	packageTask := &nodes.PackageTask{
+		TaskID:      "web#build",
+		Task:        "build",
+		PackageName: "web",
+		Pkg:         web,
+		TaskDefinition: &fs.TaskDefinition{
+			Outputs: fs.TaskOutputs{Inclusions: []string{"dist/**"}},
+		},
+	}
+	inputs := map[turbopath.AnchoredUnixPath]string{
+		"package.json": "hash",
+		"src/index.ts": "hash",
+	}
+	accesses := []fileAccess{
+		{path: "src/index.ts"},
+		{path: repoRoot.UntypedJoin("package.json").ToString()},
+		{path: repoRoot.UntypedJoin("tsconfig.json").ToString()},
+		{path: repoRoot.UntypedJoin(".env").ToString()},
+		{path: "fixtures/data.json"},
+		{path: "node_modules/dep/index.js"},
+		{path: repoRoot.UntypedJoin("packages", "ui", "src", "button.ts").ToString()},
+		{path: repoRoot.UntypedJoin("packages", "other", "src", "index.ts").ToString()},
+		{path: "missing.ts"},
+		{path: os.TempDir()},
+		{path: "dist/index.js", write: true},
+		{path: "tmp/scratch.txt", write: true},
+	}
+
+	discrepancies, err := auditor.audit(packageTask, inputs, accesses)
+	assert.NilError(t, err, "audit")
+	assert.DeepEqual(t, discrepancies.undeclaredInputs, []string{".env", "apps/web/fixtures/data.json", "packages/other/src/index.ts"})
+	assert.DeepEqual(t, discrepancies.undeclaredOutputs, []string{"apps/web/tmp/scratch.txt"})
+}
@@ -0,0 +1,44 @@
+package run
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// _completionWebhookTimeout is how long the request to the completion webhook may take,
+// so that a slow receiver doesn't hold up the end of the run
+const _completionWebhookTimeout = 10 * time.Second
+
+// _completionWebhookSignatureHeader holds the HMAC-SHA256 of the request body, keyed with
+// TURBO_WEBHOOK_SECRET, as "sha256=<hex digest>"
+const _completionWebhookSignatureHeader = "X-Turbo-Signature"
+
+// postCompletionWebhook POSTs the JSON summary of a run to url. When secret is set, the body
+// is signed so that the receiver can check that the request comes from turbo.
+func postCompletionWebhook(client *http.Client, url string, summary []byte, secret string) error {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(summary))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		_, _ = mac.Write(summary)
+		req.Header.Set(_completionWebhookSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%v responded with %v", url, resp.Status)
+	}
+	return nil
+}
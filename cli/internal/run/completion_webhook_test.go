@@ -0,0 +1,35 @@
+package run
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func Test_postCompletionWebhook(t *testing.T) {
+	var body []byte
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get("X-Turbo-Signature")
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	summary := []byte(`{"executionSummary":{"exitCode":1}}`)
+	assert.NilError(t, postCompletionWebhook(server.Client(), server.URL, summary, "secret"), "postCompletionWebhook")
+	assert.Equal(t, string(body), string(summary))
+	// echo -n '{"executionSummary":{"exitCode":1}}' | openssl dgst -sha256 -hmac secret
+	assert.Equal(t, signature, "sha256=a89a9e6eb25411bc0f3bea21ae8c427a2f722beeb17270186a8fd5fabcb3e6ff")
+
+	assert.NilError(t, postCompletionWebhook(server.Client(), server.URL, summary, ""), "postCompletionWebhook")
+	assert.Equal(t, signature, "")
+
+	err := postCompletionWebhook(server.Client(), server.URL+"/broken", summary, "")
+	assert.ErrorContains(t, err, "responded with 500 Internal Server Error")
+}
@@ -0,0 +1,71 @@
+package run
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pyr-sh/dag"
+	"github.com/vercel/turbo/cli/internal/core"
+	"github.com/vercel/turbo/cli/internal/fs"
+)
+
+// parseConcurrencyGroups parses the <group>=<limit> values given to --concurrency-group
+// into the limit of each group. A group given more than once keeps its last limit.
+func parseConcurrencyGroups(values []string) (map[string]int, error) {
+	concurrencyGroups := make(map[string]int, len(values))
+	for _, value := range values {
+		group, rawLimit, ok := strings.Cut(value, "=")
+		if !ok || group == "" {
+			return nil, fmt.Errorf("invalid value %q for --concurrency-group, use <group>=<limit>", value)
+		}
+		limit, err := strconv.Atoi(rawLimit)
+		if err != nil || limit < 1 {
+			return nil, fmt.Errorf("invalid limit %q for --concurrency-group %v, it must be a positive integer", rawLimit, group)
+		}
+		concurrencyGroups[group] = limit
+	}
+	return concurrencyGroups, nil
+}
+
+// validateConcurrencyGroups checks that the persistent tasks of each concurrency group,
+// which never exit and so never give their slot back, leave room for the group's other
+// tasks. Otherwise the run would wait forever for those tasks to start.
+func validateConcurrencyGroups(engine *core.Engine, taskDefinitions map[string]*fs.TaskDefinition, concurrencyGroups map[string]int) error {
+	if len(concurrencyGroups) == 0 {
+		return nil
+	}
+	tasks := make(map[string]int)
+	persistent := make(map[string]int)
+	for _, v := range engine.TaskGraph.Vertices() {
+		taskID := dag.VertexName(v)
+		taskDefinition, ok := taskDefinitions[taskID]
+		if !ok || taskDefinition.ConcurrencyGroup == "" {
+			continue
+		}
+		tasks[taskDefinition.ConcurrencyGroup]++
+		if taskDefinition.Persistent {
+			persistent[taskDefinition.ConcurrencyGroup]++
+		}
+	}
+	groups := make([]string, 0, len(persistent))
+	for group := range persistent {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	for _, group := range groups {
+		limit, ok := concurrencyGroups[group]
+		if !ok {
+			continue
+		}
+		required := persistent[group]
+		if tasks[group] > required {
+			required++
+		}
+		if limit < required {
+			return fmt.Errorf("concurrency group %q has %v persistent tasks, which never exit, but --concurrency-group limits it to %v at a time. Set --concurrency-group %v=%v or higher", group, persistent[group], limit, group, required)
+		}
+	}
+	return nil
+}
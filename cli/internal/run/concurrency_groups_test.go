@@ -0,0 +1,48 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/pyr-sh/dag"
+	"github.com/vercel/turbo/cli/internal/core"
+	"github.com/vercel/turbo/cli/internal/fs"
+	"gotest.tools/v3/assert"
+)
+
+func Test_parseConcurrencyGroups(t *testing.T) {
+	concurrencyGroups, err := parseConcurrencyGroups([]string{"heavy=2", "e2e=1", "heavy=3"})
+	assert.NilError(t, err, "parseConcurrencyGroups")
+	assert.DeepEqual(t, concurrencyGroups, map[string]int{"heavy": 3, "e2e": 1})
+
+	_, err = parseConcurrencyGroups([]string{"heavy"})
+	assert.Error(t, err, "invalid value \"heavy\" for --concurrency-group, use <group>=<limit>")
+	_, err = parseConcurrencyGroups([]string{"=2"})
+	assert.Error(t, err, "invalid value \"=2\" for --concurrency-group, use <group>=<limit>")
+	_, err = parseConcurrencyGroups([]string{"heavy=0"})
+	assert.Error(t, err, "invalid limit \"0\" for --concurrency-group heavy, it must be a positive integer")
+}
+
+func Test_validateConcurrencyGroups(t *testing.T) {
+	engine := core.NewEngine(nil, false)
+	for _, taskID := range []string{core.ROOT_NODE_NAME, "web#dev", "docs#dev", "ui#build"} {
+		engine.TaskGraph.Add(taskID)
+		if taskID != core.ROOT_NODE_NAME {
+			engine.TaskGraph.Connect(dag.BasicEdge(taskID, core.ROOT_NODE_NAME))
+		}
+	}
+	taskDefinitions := map[string]*fs.TaskDefinition{
+		"web#dev":  {Persistent: true, ConcurrencyGroup: "servers"},
+		"docs#dev": {Persistent: true, ConcurrencyGroup: "servers"},
+		"ui#build": {ConcurrencyGroup: "servers"},
+	}
+
+	assert.NilError(t, validateConcurrencyGroups(engine, taskDefinitions, nil))
+	assert.NilError(t, validateConcurrencyGroups(engine, taskDefinitions, map[string]int{"servers": 3}))
+	assert.NilError(t, validateConcurrencyGroups(engine, taskDefinitions, map[string]int{"other": 1}))
+	err := validateConcurrencyGroups(engine, taskDefinitions, map[string]int{"servers": 2})
+	assert.Error(t, err, "concurrency group \"servers\" has 2 persistent tasks, which never exit, but --concurrency-group limits it to 2 at a time. Set --concurrency-group servers=3 or higher")
+
+	// A group of persistent tasks only needs a slot for each of them
+	delete(taskDefinitions, "ui#build")
+	assert.NilError(t, validateConcurrencyGroups(engine, taskDefinitions, map[string]int{"servers": 2}))
+}
@@ -55,6 +55,12 @@ func DryRun(
 	// Do this _after_ walking the graph.
 	populateCacheState(turboCache, taskSummaries)
 
+	if rs.Opts.runOpts.dumpInputs != "" {
+		if err := dumpInputs(base.RepoRoot, rs.Opts.runOpts.dumpInputs, taskSummaries); err != nil {
+			base.UI.Warn(fmt.Sprintf("Failed to dump task inputs: %s", err))
+		}
+	}
+
 	// Assign the Task Summaries to the main summary
 	summary.Tasks = taskSummaries
 
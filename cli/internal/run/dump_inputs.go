@@ -0,0 +1,52 @@
+package run
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/vercel/turbo/cli/internal/fs"
+	"github.com/vercel/turbo/cli/internal/runsummary"
+	"github.com/vercel/turbo/cli/internal/turbopath"
+)
+
+// dumpedInputs is the content of the file written for each task with --dump-inputs
+type dumpedInputs struct {
+	TaskID string `json:"taskId"`
+	Hash   string `json:"hash"`
+	// Dir is the repo-relative directory of the task's package
+	Dir string `json:"dir"`
+	// Inputs are the hashes of the files that went into the task's hash, keyed by their
+	// path relative to Dir. Keys are serialized in sorted order.
+	Inputs map[turbopath.AnchoredUnixPath]string `json:"inputs"`
+}
+
+// dumpInputs writes the expanded inputs of each task into <dir>/<package>/<task>.json, so that
+// exactly what went into each hash can be archived. Files from previous runs are overwritten,
+// but the directory is otherwise left as is.
+func dumpInputs(repoRoot turbopath.AbsoluteSystemPath, dir string, taskSummaries []*runsummary.TaskSummary) error {
+	dumpDir := fs.ResolveUnknownPath(repoRoot, dir)
+	for _, taskSummary := range taskSummaries {
+		inputs := taskSummary.ExpandedInputs
+		if inputs == nil {
+			inputs = map[turbopath.AnchoredUnixPath]string{}
+		}
+		contents, err := json.MarshalIndent(&dumpedInputs{
+			TaskID: taskSummary.TaskID,
+			Hash:   taskSummary.Hash,
+			Dir:    filepath.ToSlash(taskSummary.Dir),
+			Inputs: inputs,
+		}, "", "  ")
+		if err != nil {
+			return err
+		}
+		dumpFile := dumpDir.UntypedJoin(taskSummary.Package, taskSummary.Task+".json")
+		if err := dumpFile.EnsureDir(); err != nil {
+			return fmt.Errorf("could not create directory for inputs of %v: %w", taskSummary.TaskID, err)
+		}
+		if err := dumpFile.WriteFile(append(contents, '\n'), 0644); err != nil {
+			return fmt.Errorf("could not write inputs of %v: %w", taskSummary.TaskID, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/vercel/turbo/cli/internal/runsummary"
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"gotest.tools/v3/assert"
+)
+
+func Test_dumpInputs(t *testing.T) {
+	repoRoot := turbopath.AbsoluteSystemPath(t.TempDir())
+	taskSummaries := []*runsummary.TaskSummary{
+		{
+			TaskID:  "@scope/web#build",
+			Task:    "build",
+			Package: "@scope/web",
+			Hash:    "abc123",
+			Dir:     turbopath.AnchoredUnixPath("apps/web").ToSystemPath().ToString(),
+			ExpandedInputs: map[turbopath.AnchoredUnixPath]string{
+				"src/index.ts": "2222",
+				"package.json": "1111",
+			},
+		},
+		{
+			TaskID:  "ui#lint",
+			Task:    "lint",
+			Package: "ui",
+			Hash:    "def456",
+			Dir:     turbopath.AnchoredUnixPath("packages/ui").ToSystemPath().ToString(),
+		},
+	}
+
+	assert.NilError(t, dumpInputs(repoRoot, "ci-inputs", taskSummaries), "dumpInputs")
+
+	contents, err := repoRoot.UntypedJoin("ci-inputs", "@scope", "web", "build.json").ReadFile()
+	assert.NilError(t, err, "ReadFile")
+	assert.Equal(t, string(contents), `{
+  "taskId": "@scope/web#build",
+  "hash": "abc123",
+  "dir": "apps/web",
+  "inputs": {
+    "package.json": "1111",
+    "src/index.ts": "2222"
+  }
+}
+`)
+
+	contents, err = repoRoot.UntypedJoin("ci-inputs", "ui", "lint.json").ReadFile()
+	assert.NilError(t, err, "ReadFile")
+	assert.Equal(t, string(contents), `{
+  "taskId": "ui#lint",
+  "hash": "def456",
+  "dir": "packages/ui",
+  "inputs": {}
+}
+`)
+}
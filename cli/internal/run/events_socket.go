@@ -0,0 +1,60 @@
+package run
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/cli"
+)
+
+// _eventsSocketTimeout is how long connecting to the --events-socket, or writing an event to
+// it, may take, so that a stalled monitor doesn't hold up the tasks that emit events
+const _eventsSocketTimeout = time.Second
+
+// eventsSocket writes the lines of JSON that describe task events to the Unix socket given
+// to --events-socket. When a write fails, a warning is printed and later events are dropped,
+// rather than failing the run.
+type eventsSocket struct {
+	mu   sync.Mutex
+	conn net.Conn
+	path string
+	ui   cli.Ui
+}
+
+// connectEventsSocket connects to the Unix socket that a monitor listens on at path
+func connectEventsSocket(path string, ui cli.Ui) (*eventsSocket, error) {
+	conn, err := net.DialTimeout("unix", path, _eventsSocketTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return &eventsSocket{conn: conn, path: path, ui: ui}, nil
+}
+
+func (s *eventsSocket) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return len(p), nil
+	}
+	_ = s.conn.SetWriteDeadline(time.Now().Add(_eventsSocketTimeout))
+	if _, err := s.conn.Write(p); err != nil {
+		s.ui.Warn(fmt.Sprintf("WARNING: stopped writing task events to %v: %v", s.path, err))
+		_ = s.conn.Close()
+		s.conn = nil
+	}
+	return len(p), nil
+}
+
+// Close closes the connection to the socket, if writing to it hasn't already failed
+func (s *eventsSocket) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn = nil
+	return err
+}
@@ -0,0 +1,47 @@
+package run
+
+import (
+	"bufio"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"gotest.tools/v3/assert"
+)
+
+func Test_eventsSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.sock")
+	_, err := connectEventsSocket(path, cli.NewMockUi())
+	assert.ErrorContains(t, err, "events.sock")
+
+	listener, err := net.Listen("unix", path)
+	assert.NilError(t, err, "Listen")
+	defer func() { _ = listener.Close() }()
+
+	ui := cli.NewMockUi()
+	events, err := connectEventsSocket(path, ui)
+	assert.NilError(t, err, "connectEventsSocket")
+	defer func() { _ = events.Close() }()
+	conn, err := listener.Accept()
+	assert.NilError(t, err, "Accept")
+
+	line := []byte(`{"event":"start","taskId":"web#build"}` + "\n")
+	n, err := events.Write(line)
+	assert.NilError(t, err, "Write")
+	assert.Equal(t, n, len(line))
+	received, err := bufio.NewReader(conn).ReadString('\n')
+	assert.NilError(t, err, "ReadString")
+	assert.Equal(t, received, string(line))
+
+	// Once the monitor goes away, events are dropped with a single warning
+	assert.NilError(t, conn.Close(), "Close")
+	for i := 0; i < 100 && ui.ErrorWriter.String() == ""; i++ {
+		_, err := events.Write(line)
+		assert.NilError(t, err, "Write")
+	}
+	_, err = events.Write(line)
+	assert.NilError(t, err, "Write")
+	assert.Equal(t, strings.Count(ui.ErrorWriter.String(), "WARNING: stopped writing task events"), 1)
+}
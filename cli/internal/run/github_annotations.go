@@ -0,0 +1,71 @@
+package run
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// githubAnnotations groups the output of each task, and annotates failed tasks, using
+// GitHub Actions workflow commands. The output of a task is buffered while it runs and
+// printed as a single group once it finishes, so that the groups of concurrent tasks
+// don't interleave.
+type githubAnnotations struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// githubGroup holds the output of a task until it finishes. It is written to by a single
+// log.Logger, which serializes writes from the task's stdout and stderr.
+type githubGroup struct {
+	bytes.Buffer
+	title string
+}
+
+func newGithubAnnotations(w io.Writer) *githubAnnotations {
+	return &githubAnnotations{w: w}
+}
+
+// flush prints the buffered output of a task as a collapsible group. Tasks that printed
+// nothing get no group.
+func (ga *githubAnnotations) flush(group *githubGroup) error {
+	if group.Len() == 0 {
+		return nil
+	}
+	ga.mu.Lock()
+	defer ga.mu.Unlock()
+	if _, err := fmt.Fprintf(ga.w, "::group::%v\n", escapeWorkflowData(group.title)); err != nil {
+		return err
+	}
+	if _, err := group.WriteTo(ga.w); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(ga.w, "::endgroup::")
+	return err
+}
+
+// taskFailed prints an error annotation for a task, pointing at the file its logs were
+// written to
+func (ga *githubAnnotations) taskFailed(packageName string, taskID string, logFile string, err error) error {
+	ga.mu.Lock()
+	defer ga.mu.Unlock()
+	title := fmt.Sprintf("%v failed", taskID)
+	message := fmt.Sprintf("Task %v failed in package %v: %v\nLogs: %v", taskID, packageName, err, logFile)
+	_, writeErr := fmt.Fprintf(ga.w, "::error title=%v::%v\n", escapeWorkflowProperty(title), escapeWorkflowData(message))
+	return writeErr
+}
+
+var _workflowDataEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+var _workflowPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+
+// escapeWorkflowData escapes the message of a workflow command
+func escapeWorkflowData(value string) string {
+	return _workflowDataEscaper.Replace(value)
+}
+
+// escapeWorkflowProperty escapes the value of a workflow command property, such as title
+func escapeWorkflowProperty(value string) string {
+	return _workflowPropertyEscaper.Replace(value)
+}
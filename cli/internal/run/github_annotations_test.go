@@ -0,0 +1,40 @@
+package run
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func Test_githubAnnotations(t *testing.T) {
+	output := &bytes.Buffer{}
+	annotations := newGithubAnnotations(output)
+
+	web := &githubGroup{title: "web#build"}
+	docs := &githubGroup{title: "docs#build"}
+	_, _ = web.WriteString("web:build: compiling\n")
+	_, _ = docs.WriteString("docs:build: compiling\n")
+	_, _ = web.WriteString("web:build: done\n")
+	assert.NilError(t, annotations.flush(docs), "flush")
+	assert.NilError(t, annotations.flush(web), "flush")
+	assert.NilError(t, annotations.flush(&githubGroup{title: "ui#build"}), "flush")
+	err := annotations.taskFailed("web", "web#build", "apps/web/.turbo/turbo-build.log", errors.New("command exited (1)"))
+	assert.NilError(t, err, "taskFailed")
+
+	assert.Equal(t, output.String(), `::group::docs#build
+docs:build: compiling
+::endgroup::
+::group::web#build
+web:build: compiling
+web:build: done
+::endgroup::
+::error title=web#build failed::Task web#build failed in package web: command exited (1)%0ALogs: apps/web/.turbo/turbo-build.log
+`)
+}
+
+func Test_escapeWorkflowCommands(t *testing.T) {
+	assert.Equal(t, escapeWorkflowData("100% done\r\nnext: a, b"), "100%25 done%0D%0Anext: a, b")
+	assert.Equal(t, escapeWorkflowProperty("100% done\r\nnext: a, b"), "100%25 done%0D%0Anext%3A a%2C b")
+}
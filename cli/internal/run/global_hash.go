@@ -1,8 +1,18 @@
 package run
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"os/exec"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
 	"github.com/vercel/turbo/cli/internal/env"
@@ -17,6 +27,17 @@ import (
 
 const _globalCacheKey = "Buffalo buffalo Buffalo buffalo buffalo buffalo Buffalo buffalo"
 
+// _cacheBustEnvVar invalidates every cache for a single invocation when set, by being
+// folded into the global cache key
+const _cacheBustEnvVar = "TURBO_CACHE_BUST"
+
+// _cacheKeySaltEnvVar sets the salt of the global cache key when --cache-key-salt isn't
+// given, to invalidate every cache for as long as it is set, such as after a toolchain upgrade
+const _cacheKeySaltEnvVar = "TURBO_CACHE_KEY_SALT"
+
+// _toolVersionTimeout is how long each of the globalToolVersions commands may run
+const _toolVersionTimeout = 10 * time.Second
+
 // Variables that we always include
 var _defaultEnvVars = []string{
 	"VERCEL_ANALYTICS_ID",
@@ -29,6 +50,8 @@ type GlobalHashable struct {
 	envVars              env.DetailedMap
 	globalCacheKey       string
 	pipeline             fs.PristinePipeline
+	// toolVersions maps each of the globalToolVersions commands to its output
+	toolVersions map[string]string
 }
 
 // getGlobalHashable converts GlobalHashable into an anonymous struct.
@@ -44,21 +67,114 @@ func getGlobalHashable(named GlobalHashable) struct {
 	rootExternalDepsHash string
 	hashedSortedEnvPairs env.EnvironmentVariablePairs
 	globalCacheKey       string
-	pipeline             fs.PristinePipeline
+	pipeline             map[string]hashableTaskDefinition
 } {
 	return struct {
 		globalFileHashMap    map[turbopath.AnchoredUnixPath]string
 		rootExternalDepsHash string
 		hashedSortedEnvPairs env.EnvironmentVariablePairs
 		globalCacheKey       string
-		pipeline             fs.PristinePipeline
+		pipeline             map[string]hashableTaskDefinition
 	}{
 		globalFileHashMap:    named.globalFileHashMap,
 		rootExternalDepsHash: named.rootExternalDepsHash,
 		hashedSortedEnvPairs: named.envVars.All.ToHashable(),
-		globalCacheKey:       named.globalCacheKey,
-		pipeline:             named.pipeline,
+		globalCacheKey:       withTaskOptions(withToolVersions(named.globalCacheKey, named.toolVersions), named.pipeline),
+		pipeline:             getHashablePipeline(named.pipeline),
+	}
+}
+
+// hashableTaskDefinition has exactly the fields, in the same order, that fs.TaskDefinition
+// had when the pipeline was added to the global hash. Since the pipeline is printed into the
+// hash, every field added to fs.TaskDefinition would otherwise change the global hash for
+// everyone. Options added since are folded into the global cache key by withTaskOptions.
+type hashableTaskDefinition struct {
+	Outputs                 fs.TaskOutputs
+	ShouldCache             bool
+	EnvVarDependencies      []string
+	TopologicalDependencies []string
+	TaskDependencies        []string
+	Inputs                  []string
+	OutputMode              util.TaskOutputMode
+	Persistent              bool
+}
+
+// getHashablePipeline converts each of the pipeline's task definitions to a hashableTaskDefinition
+func getHashablePipeline(pipeline fs.PristinePipeline) map[string]hashableTaskDefinition {
+	hashable := make(map[string]hashableTaskDefinition, len(pipeline))
+	for taskName, taskDefinition := range pipeline {
+		hashable[taskName] = hashableTaskDefinition{
+			Outputs:                 taskDefinition.Outputs,
+			ShouldCache:             taskDefinition.ShouldCache,
+			EnvVarDependencies:      taskDefinition.EnvVarDependencies,
+			TopologicalDependencies: taskDefinition.TopologicalDependencies,
+			TaskDependencies:        taskDefinition.TaskDependencies,
+			Inputs:                  taskDefinition.Inputs,
+			OutputMode:              taskDefinition.OutputMode,
+			Persistent:              taskDefinition.Persistent,
+		}
 	}
+	return hashable
+}
+
+// withTaskOptions folds the task options that aren't part of hashableTaskDefinition into the
+// global cache key, for the tasks that set any, so that the global hash of pipelines that
+// don't use them is unchanged. Options that only change how a task is run, such as its
+// timeout or retries, are left out, since they don't change what it hashes or caches.
+func withTaskOptions(globalCacheKey string, pipeline fs.PristinePipeline) string {
+	taskOptions := make(map[string]map[string]interface{})
+	for taskName, taskDefinition := range pipeline {
+		options := make(map[string]interface{})
+		if len(taskDefinition.RestoreOnlyOutputs) > 0 {
+			options["restoreOnlyOutputs"] = taskDefinition.RestoreOnlyOutputs
+		}
+		if len(taskDefinition.EnvGroups) > 0 {
+			options["envGroups"] = taskDefinition.EnvGroups
+		}
+		if len(taskDefinition.PassThroughEnv) > 0 {
+			options["passThroughEnv"] = taskDefinition.PassThroughEnv
+		}
+		if len(taskDefinition.RootInputs) > 0 {
+			options["rootInputs"] = taskDefinition.RootInputs
+		}
+		if taskDefinition.Framework != "" {
+			options["framework"] = taskDefinition.Framework
+		}
+		if taskDefinition.HashInputsFrom != "" {
+			options["hashInputsFrom"] = taskDefinition.HashInputsFrom
+		}
+		if len(taskDefinition.DotEnv) > 0 {
+			options["dotEnv"] = taskDefinition.DotEnv
+		}
+		if taskDefinition.OutputTransform != "" {
+			options["outputTransform"] = taskDefinition.OutputTransform
+		}
+		if taskDefinition.PostCacheRestore != nil {
+			options["postCacheRestore"] = *taskDefinition.PostCacheRestore
+		}
+		if taskDefinition.OutputsFromLog != "" {
+			options["outputsFromLog"] = taskDefinition.OutputsFromLog
+		}
+		if len(options) > 0 {
+			taskOptions[taskName] = options
+		}
+	}
+	if len(taskOptions) == 0 {
+		return globalCacheKey
+	}
+	// maps are printed with sorted keys
+	return fmt.Sprintf("%v %v", globalCacheKey, taskOptions)
+}
+
+// withToolVersions folds the outputs of the globalToolVersions commands into the global
+// cache key. Like TURBO_CACHE_BUST, they change the key rather than the hashable struct,
+// so the global hash of repositories without globalToolVersions is unchanged.
+func withToolVersions(globalCacheKey string, toolVersions map[string]string) string {
+	if len(toolVersions) == 0 {
+		return globalCacheKey
+	}
+	// maps are printed with sorted keys
+	return fmt.Sprintf("%v %v", globalCacheKey, toolVersions)
 }
 
 func calculateGlobalHash(
@@ -66,7 +182,10 @@ func calculateGlobalHash(
 	rootPackageJSON *fs.PackageJSON,
 	pipeline fs.Pipeline,
 	envVarDependencies []string,
+	passThroughEnv []string,
 	globalFileDependencies []string,
+	toolVersionCommands []string,
+	cacheKeySalt string,
 	packageManager *packagemanager.PackageManager,
 	lockFile lockfile.Lockfile,
 	logger hclog.Logger,
@@ -79,6 +198,10 @@ func calculateGlobalHash(
 	if err != nil {
 		return GlobalHashable{}, err
 	}
+	// Env vars passed through to every task aren't hashed, even if they are in _defaultEnvVars
+	if len(passThroughEnv) > 0 {
+		globalHashableEnvVars = globalHashableEnvVars.Without(passThroughEnv)
+	}
 
 	logger.Debug("global hash env vars", "vars", globalHashableEnvVars.All.Names())
 
@@ -100,7 +223,14 @@ func calculateGlobalHash(
 		}
 	}
 
-	if lockFile == nil {
+	// With a lockfile, only the transitive closure of the root package's dependencies is part
+	// of the global hash, through rootExternalDepsHash, so changes to the dependencies of other
+	// packages don't invalidate every task. If the closure couldn't be resolved, fall back to
+	// hashing the whole lockfile rather than leaving the root's dependencies out of the hash.
+	if lockFile == nil || rootPackageJSON.ExternalDepsHash == "" {
+		if lockFile != nil {
+			logger.Debug("couldn't resolve the root package's dependencies, hashing the whole lockfile")
+		}
 		// If we don't have lockfile information available, add the specfile and lockfile to global deps
 		globalDeps.Add(filepath.Join(rootpath.ToStringDuringMigration(), packageManager.Specfile))
 		globalDeps.Add(filepath.Join(rootpath.ToStringDuringMigration(), packageManager.Lockfile))
@@ -118,11 +248,163 @@ func calculateGlobalHash(
 		return GlobalHashable{}, fmt.Errorf("error hashing files: %w", err)
 	}
 
+	toolVersions, err := getToolVersions(rootpath, toolVersionCommands, _toolVersionTimeout)
+	if err != nil {
+		return GlobalHashable{}, err
+	}
+	logger.Debug("global hash tool versions", "versions", toolVersions)
+
 	return GlobalHashable{
 		globalFileHashMap:    globalFileHashMap,
 		rootExternalDepsHash: rootPackageJSON.ExternalDepsHash,
 		envVars:              globalHashableEnvVars,
-		globalCacheKey:       _globalCacheKey,
+		globalCacheKey:       getGlobalCacheKey(cacheKeySalt, logger),
 		pipeline:             pipeline.Pristine(),
+		toolVersions:         toolVersions,
 	}, nil
 }
+
+// getToolVersions runs each command from the root of the repository and returns its
+// stdout, with surrounding whitespace trimmed. Commands are split on whitespace and run
+// without a shell. A command that fails, or runs for longer than timeout, is an error,
+// since leaving its output out of the hash could restore artifacts built with another tool.
+func getToolVersions(rootpath turbopath.AbsoluteSystemPath, commands []string, timeout time.Duration) (map[string]string, error) {
+	if len(commands) == 0 {
+		return nil, nil
+	}
+	toolVersions := make(map[string]string, len(commands))
+	for _, command := range commands {
+		args := strings.Fields(command)
+		if len(args) == 0 {
+			return nil, errors.New("empty command in \"globalToolVersions\"")
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+		cmd.Dir = rootpath.ToString()
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		err := cmd.Run()
+		timedOut := ctx.Err() != nil
+		cancel()
+		if timedOut {
+			return nil, fmt.Errorf("getting the tool version with %q timed out after %v", command, timeout)
+		}
+		if err != nil {
+			if output := strings.TrimSpace(stderr.String()); output != "" {
+				return nil, fmt.Errorf("getting the tool version with %q failed: %w: %v", command, err, output)
+			}
+			return nil, fmt.Errorf("getting the tool version with %q failed: %w", command, err)
+		}
+		toolVersions[command] = strings.TrimSpace(stdout.String())
+	}
+	return toolVersions, nil
+}
+
+// getGlobalCacheKey returns the key that is part of every global hash. The key is only
+// changed when a salt is given, or TURBO_CACHE_BUST is set, so that the hashes of everyone
+// else are unchanged. A salt and TURBO_CACHE_BUST are both folded into the key, so neither
+// overrides the other.
+func getGlobalCacheKey(cacheKeySalt string, logger hclog.Logger) string {
+	key := _globalCacheKey
+	if cacheKeySalt != "" {
+		logger.Debug("salting the global cache key", "salt", cacheKeySalt)
+		key = fmt.Sprintf("%v salt:%v", key, cacheKeySalt)
+	}
+	cacheBust := os.Getenv(_cacheBustEnvVar)
+	if cacheBust == "" {
+		return key
+	}
+	logger.Debug("busting caches", "env", _cacheBustEnvVar, "value", cacheBust)
+	return fmt.Sprintf("%v %v", key, cacheBust)
+}
+
+// globalFileHashesPath is where the global file hashes of the previous run are recorded,
+// within the local cache directory, so that the next run can report which global files changed.
+func globalFileHashesPath(cacheDir turbopath.AbsoluteSystemPath) turbopath.AbsoluteSystemPath {
+	return cacheDir.UntypedJoin("global-file-hashes.json")
+}
+
+// readGlobalFileHashes reads the global file hashes recorded by a previous run.
+// Returns a nil map if no run has been recorded yet.
+func readGlobalFileHashes(cacheDir turbopath.AbsoluteSystemPath) (map[turbopath.AnchoredUnixPath]string, error) {
+	statePath := globalFileHashesPath(cacheDir)
+	if !statePath.FileExists() {
+		return nil, nil
+	}
+	contents, err := statePath.ReadFile()
+	if err != nil {
+		return nil, err
+	}
+	var previous map[turbopath.AnchoredUnixPath]string
+	if err := json.Unmarshal(contents, &previous); err != nil {
+		return nil, fmt.Errorf("parsing %v: %w", statePath, err)
+	}
+	return previous, nil
+}
+
+// writeGlobalFileHashes records the global file hashes of this run for the next one
+func writeGlobalFileHashes(cacheDir turbopath.AbsoluteSystemPath, current map[turbopath.AnchoredUnixPath]string) error {
+	contents, err := json.MarshalIndent(current, "", "  ")
+	if err != nil {
+		return err
+	}
+	statePath := globalFileHashesPath(cacheDir)
+	if err := statePath.EnsureDir(); err != nil {
+		return err
+	}
+	return statePath.WriteFile(contents, 0644)
+}
+
+// diffGlobalFileHashes returns the sorted list of global files that were added, removed,
+// or modified between two runs.
+func diffGlobalFileHashes(previous map[turbopath.AnchoredUnixPath]string, current map[turbopath.AnchoredUnixPath]string) []string {
+	changed := []string{}
+	for file, hash := range current {
+		if previousHash, ok := previous[file]; !ok || previousHash != hash {
+			changed = append(changed, file.ToString())
+		}
+	}
+	for file := range previous {
+		if _, ok := current[file]; !ok {
+			changed = append(changed, file.ToString())
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// globalHashInputs is what --summarize-global-hash prints. Values of environment variables
+// are hashed, so that the output can be shared without leaking secrets.
+type globalHashInputs struct {
+	GlobalHash           string                                `json:"globalHash"`
+	GlobalCacheKey       string                                `json:"globalCacheKey"`
+	GlobalFileHashMap    map[turbopath.AnchoredUnixPath]string `json:"globalFileHashMap"`
+	RootExternalDepsHash string                                `json:"rootExternalDepsHash"`
+	EnvVars              env.EnvironmentVariablePairs          `json:"environmentVariables"`
+	Pipeline             fs.PristinePipeline                   `json:"pipeline"`
+	ToolVersions         map[string]string                     `json:"toolVersions,omitempty"`
+}
+
+// printGlobalHashInputs writes the inputs of the global hash as JSON. Object keys and
+// environment variables are sorted, so that the output of two runs can be diffed.
+func printGlobalHashInputs(w io.Writer, globalHash string, globalHashable GlobalHashable) error {
+	inputs := globalHashInputs{
+		GlobalHash:           globalHash,
+		GlobalCacheKey:       globalHashable.globalCacheKey,
+		GlobalFileHashMap:    globalHashable.globalFileHashMap,
+		RootExternalDepsHash: globalHashable.rootExternalDepsHash,
+		EnvVars:              globalHashable.envVars.All.ToSecretHashable(),
+		Pipeline:             globalHashable.pipeline,
+		ToolVersions:         globalHashable.toolVersions,
+	}
+	if inputs.GlobalFileHashMap == nil {
+		inputs.GlobalFileHashMap = map[turbopath.AnchoredUnixPath]string{}
+	}
+	contents, err := json.MarshalIndent(inputs, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(contents))
+	return err
+}
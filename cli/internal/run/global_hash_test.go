@@ -0,0 +1,167 @@
+package run
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/vercel/turbo/cli/internal/env"
+	"github.com/vercel/turbo/cli/internal/fs"
+	"github.com/vercel/turbo/cli/internal/lockfile"
+	"github.com/vercel/turbo/cli/internal/packagemanager"
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"gotest.tools/v3/assert"
+)
+
+func Test_getGlobalCacheKey(t *testing.T) {
+	t.Setenv(_cacheBustEnvVar, "")
+	assert.Equal(t, getGlobalCacheKey("", hclog.NewNullLogger()), _globalCacheKey)
+
+	t.Setenv(_cacheBustEnvVar, "2023-04-01")
+	busted := getGlobalCacheKey("", hclog.NewNullLogger())
+	assert.Equal(t, busted, _globalCacheKey+" 2023-04-01")
+
+	t.Setenv(_cacheBustEnvVar, "2023-04-02")
+	assert.Assert(t, getGlobalCacheKey("", hclog.NewNullLogger()) != busted, "a new value busts the cache again")
+}
+
+func Test_getGlobalCacheKeySalt(t *testing.T) {
+	t.Setenv(_cacheBustEnvVar, "")
+	salted := getGlobalCacheKey("2023-06", hclog.NewNullLogger())
+	assert.Equal(t, salted, _globalCacheKey+" salt:2023-06")
+	assert.Assert(t, getGlobalCacheKey("2023-07", hclog.NewNullLogger()) != salted, "a new salt invalidates every cache")
+
+	t.Setenv(_cacheBustEnvVar, "2023-04-01")
+	saltedAndBusted := getGlobalCacheKey("2023-06", hclog.NewNullLogger())
+	assert.Equal(t, saltedAndBusted, _globalCacheKey+" salt:2023-06 2023-04-01")
+	assert.Assert(t, saltedAndBusted != salted, "busting a salted key changes it")
+	assert.Assert(t, saltedAndBusted != getGlobalCacheKey("", hclog.NewNullLogger()), "salting a busted key changes it")
+}
+
+func Test_printGlobalHashInputs(t *testing.T) {
+	output := &bytes.Buffer{}
+	err := printGlobalHashInputs(output, "abc123", GlobalHashable{
+		globalFileHashMap: map[turbopath.AnchoredUnixPath]string{
+			"tsconfig.json": "2",
+			".env":          "1",
+		},
+		rootExternalDepsHash: "deps",
+		envVars: env.DetailedMap{
+			All: env.EnvironmentVariableMap{"NODE_ENV": "production", "CI": "1"},
+		},
+		globalCacheKey: _globalCacheKey,
+	})
+	assert.NilError(t, err, "printGlobalHashInputs")
+
+	var inputs map[string]interface{}
+	assert.NilError(t, json.Unmarshal(output.Bytes(), &inputs), "Unmarshal")
+	assert.Equal(t, inputs["globalHash"], "abc123")
+	assert.Equal(t, inputs["rootExternalDepsHash"], "deps")
+	assert.DeepEqual(t, inputs["environmentVariables"], []interface{}{
+		"CI=6b86b273ff34fce19d6b804eff5a3f5747ada4eaa22f1d49c01e52ddb7875b4b",
+		"NODE_ENV=ab8e18ef4ebebeddc0b3152ce9c9006e14fc05242e3fc9ce32246ea6a9543074",
+	})
+	assert.Assert(t, strings.Index(output.String(), `".env"`) < strings.Index(output.String(), `"tsconfig.json"`), "files are sorted")
+}
+
+func Test_getToolVersions(t *testing.T) {
+	rootpath := turbopath.AbsoluteSystemPath(t.TempDir())
+	toolVersions, err := getToolVersions(rootpath, []string{"go  version"}, time.Minute)
+	assert.NilError(t, err, "getToolVersions")
+	assert.Assert(t, strings.HasPrefix(toolVersions["go  version"], "go version go"), "output is recorded, trimmed")
+
+	_, err = getToolVersions(rootpath, []string{"go version", "go not-a-command"}, time.Minute)
+	assert.ErrorContains(t, err, `getting the tool version with "go not-a-command" failed`)
+
+	_, err = getToolVersions(rootpath, []string{"turbo-missing-tool --version"}, time.Minute)
+	assert.ErrorContains(t, err, `getting the tool version with "turbo-missing-tool --version" failed`)
+}
+
+func Test_withToolVersions(t *testing.T) {
+	assert.Equal(t, withToolVersions(_globalCacheKey, nil), _globalCacheKey, "the key is unchanged without tools")
+	key := withToolVersions(_globalCacheKey, map[string]string{"pnpm --version": "8.6.0", "node --version": "v18.16.0"})
+	assert.Equal(t, key, _globalCacheKey+" map[node --version:v18.16.0 pnpm --version:8.6.0]")
+	upgraded := withToolVersions(_globalCacheKey, map[string]string{"pnpm --version": "8.6.0", "node --version": "v20.2.0"})
+	assert.Assert(t, upgraded != key, "a new version changes the key")
+}
+
+func Test_getHashablePipeline(t *testing.T) {
+	var turboJSON fs.TurboJSON
+	assert.NilError(t, json.Unmarshal([]byte(`{"pipeline":{"build":{"outputs":["dist/**"]}}}`), &turboJSON), "Unmarshal")
+	pipelineHash, err := fs.HashObject(getHashablePipeline(turboJSON.Pipeline.Pristine()))
+	assert.NilError(t, err, "HashObject")
+	assert.Equal(t, pipelineHash, "15acb7586cc4c57c", "options added to TaskDefinition don't change the global hash")
+}
+
+func Test_withTaskOptions(t *testing.T) {
+	var turboJSON fs.TurboJSON
+	assert.NilError(t, json.Unmarshal([]byte(`{"pipeline":{"build":{"outputs":["dist/**"],"timeout":"5m","retries":2,"interactive":true}}}`), &turboJSON), "Unmarshal")
+	assert.Equal(t, withTaskOptions(_globalCacheKey, turboJSON.Pipeline.Pristine()), _globalCacheKey, "the key is unchanged by options that don't affect hashing")
+
+	assert.NilError(t, json.Unmarshal([]byte(`{"pipeline":{"build":{"outputs":["dist/**"],"rootInputs":["schemas/**"],"framework":"none"}}}`), &turboJSON), "Unmarshal")
+	assert.Equal(t, withTaskOptions(_globalCacheKey, turboJSON.Pipeline.Pristine()), _globalCacheKey+" map[build:map[framework:none rootInputs:[schemas/**]]]")
+}
+
+func Test_calculateGlobalHashLockfile(t *testing.T) {
+	rootpath := turbopath.AbsoluteSystemPath(t.TempDir())
+	for _, file := range []string{"package.json", "package-lock.json"} {
+		assert.NilError(t, rootpath.UntypedJoin(file).WriteFile([]byte("{}"), 0644), "WriteFile")
+	}
+	packageManager := &packagemanager.PackageManager{Specfile: "package.json", Lockfile: "package-lock.json"}
+
+	globalHashable, err := calculateGlobalHash(rootpath, &fs.PackageJSON{ExternalDepsHash: "deps"}, fs.Pipeline{}, nil, nil, nil, nil, "", packageManager, &lockfile.NpmLockfile{}, hclog.NewNullLogger())
+	assert.NilError(t, err, "calculateGlobalHash")
+	assert.Equal(t, len(globalHashable.globalFileHashMap), 0, "only the root's dependencies are hashed")
+	assert.Equal(t, globalHashable.rootExternalDepsHash, "deps")
+
+	globalHashable, err = calculateGlobalHash(rootpath, &fs.PackageJSON{}, fs.Pipeline{}, nil, nil, nil, nil, "", packageManager, &lockfile.NpmLockfile{}, hclog.NewNullLogger())
+	assert.NilError(t, err, "calculateGlobalHash")
+	assert.Equal(t, len(globalHashable.globalFileHashMap), 2, "the whole lockfile is hashed when the root's dependencies aren't resolved")
+}
+
+func Test_calculateGlobalHashPassThroughEnv(t *testing.T) {
+	t.Setenv("API_URL", "https://example.com")
+	t.Setenv("VERCEL_ANALYTICS_ID", "analytics")
+	rootpath := turbopath.AbsoluteSystemPath(t.TempDir())
+	packageManager := &packagemanager.PackageManager{Specfile: "package.json", Lockfile: "package-lock.json"}
+
+	globalHashable, err := calculateGlobalHash(rootpath, &fs.PackageJSON{ExternalDepsHash: "deps"}, fs.Pipeline{}, []string{"API_URL"}, nil, nil, nil, "", packageManager, &lockfile.NpmLockfile{}, hclog.NewNullLogger())
+	assert.NilError(t, err, "calculateGlobalHash")
+	assert.DeepEqual(t, globalHashable.envVars.All.Names(), []string{"API_URL", "VERCEL_ANALYTICS_ID"})
+
+	globalHashable, err = calculateGlobalHash(rootpath, &fs.PackageJSON{ExternalDepsHash: "deps"}, fs.Pipeline{}, []string{"API_URL"}, []string{"VERCEL_ANALYTICS_ID"}, nil, nil, "", packageManager, &lockfile.NpmLockfile{}, hclog.NewNullLogger())
+	assert.NilError(t, err, "calculateGlobalHash")
+	assert.DeepEqual(t, globalHashable.envVars.All.Names(), []string{"API_URL"})
+}
+
+func Test_diffGlobalFileHashes(t *testing.T) {
+	previous := map[turbopath.AnchoredUnixPath]string{
+		"tsconfig.json": "a",
+		".env":          "b",
+		"removed.json":  "c",
+	}
+	current := map[turbopath.AnchoredUnixPath]string{
+		"tsconfig.json": "a",
+		".env":          "changed",
+		"added.json":    "d",
+	}
+	assert.DeepEqual(t, diffGlobalFileHashes(previous, current), []string{".env", "added.json", "removed.json"})
+	assert.DeepEqual(t, diffGlobalFileHashes(current, current), []string{})
+}
+
+func Test_globalFileHashesRoundTrip(t *testing.T) {
+	cacheDir := turbopath.AbsoluteSystemPath(t.TempDir()).UntypedJoin("custom-cache")
+	previous, err := readGlobalFileHashes(cacheDir)
+	assert.NilError(t, err, "readGlobalFileHashes")
+	assert.Assert(t, previous == nil, "nothing is recorded before the first run")
+
+	current := map[turbopath.AnchoredUnixPath]string{"tsconfig.json": "a"}
+	assert.NilError(t, writeGlobalFileHashes(cacheDir, current), "writeGlobalFileHashes")
+	assert.Assert(t, globalFileHashesPath(cacheDir).FileExists(), "hashes are recorded in the cache directory")
+	previous, err = readGlobalFileHashes(cacheDir)
+	assert.NilError(t, err, "readGlobalFileHashes")
+	assert.DeepEqual(t, previous, current)
+}
@@ -0,0 +1,47 @@
+package run
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/mitchellh/cli"
+)
+
+// groupedLogs prints the output of each task at once when the task finishes, for
+// --log-order=grouped. The output of a task is buffered while it runs, so that the
+// output of concurrent tasks doesn't interleave.
+type groupedLogs struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func newGroupedLogs(w io.Writer) *groupedLogs {
+	return &groupedLogs{w: w}
+}
+
+// flush prints the buffered output of a task, after the output of the tasks that
+// finished before it
+func (gl *groupedLogs) flush(output *bytes.Buffer) error {
+	gl.mu.Lock()
+	defer gl.mu.Unlock()
+	_, err := output.WriteTo(gl.w)
+	return err
+}
+
+// bufferedOutputUi collects the output and info messages of a task in a buffer, so that the
+// output replayed for a cache hit can be flushed at once. Errors and warnings are shown as
+// they happen.
+type bufferedOutputUi struct {
+	cli.Ui
+	output *bytes.Buffer
+}
+
+func (u *bufferedOutputUi) Output(message string) {
+	fmt.Fprintln(u.output, message)
+}
+
+func (u *bufferedOutputUi) Info(message string) {
+	u.Output(message)
+}
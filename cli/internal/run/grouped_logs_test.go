@@ -0,0 +1,44 @@
+package run
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"gotest.tools/v3/assert"
+)
+
+func Test_groupedLogs(t *testing.T) {
+	output := &bytes.Buffer{}
+	logs := newGroupedLogs(output)
+
+	web := &bytes.Buffer{}
+	docs := &bytes.Buffer{}
+	_, _ = web.WriteString("web:build: compiling\n")
+	_, _ = docs.WriteString("docs:build: compiling\n")
+	_, _ = web.WriteString("web:build: done\n")
+	_, _ = docs.WriteString("docs:build: done\n")
+	assert.NilError(t, logs.flush(docs), "flush")
+	assert.NilError(t, logs.flush(web), "flush")
+	assert.NilError(t, logs.flush(&bytes.Buffer{}), "flush")
+
+	assert.Equal(t, output.String(), `docs:build: compiling
+docs:build: done
+web:build: compiling
+web:build: done
+`)
+}
+
+func Test_bufferedOutputUi(t *testing.T) {
+	errors := &bytes.Buffer{}
+	output := &bytes.Buffer{}
+	ui := &bufferedOutputUi{Ui: &cli.BasicUi{Writer: &bytes.Buffer{}, ErrorWriter: errors}, output: output}
+	prefixedUI := &cli.PrefixedUi{Ui: ui, OutputPrefix: "web:build: ", InfoPrefix: "web:build: ", WarnPrefix: "web:build: "}
+
+	prefixedUI.Output("cache hit, replaying logs")
+	prefixedUI.Info("compiling")
+	prefixedUI.Warn("could not copy the log")
+
+	assert.Equal(t, output.String(), "web:build: cache hit, replaying logs\nweb:build: compiling\n")
+	assert.Equal(t, errors.String(), "web:build: could not copy the log\n")
+}
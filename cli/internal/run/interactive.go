@@ -0,0 +1,34 @@
+package run
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pyr-sh/dag"
+	"github.com/vercel/turbo/cli/internal/core"
+	"github.com/vercel/turbo/cli/internal/fs"
+)
+
+// validateInteractiveTasks checks that tasks with "interactive" set won't have to share the
+// terminal with other tasks. That is the case when the run has a single task, or runs its
+// tasks one at a time.
+func validateInteractiveTasks(engine *core.Engine, taskDefinitions map[string]*fs.TaskDefinition, concurrency int, parallel bool) error {
+	tasks := 0
+	interactive := []string{}
+	for _, v := range engine.TaskGraph.Vertices() {
+		taskID := dag.VertexName(v)
+		if taskID == core.ROOT_NODE_NAME {
+			continue
+		}
+		tasks++
+		if taskDefinition, ok := taskDefinitions[taskID]; ok && taskDefinition.Interactive {
+			interactive = append(interactive, taskID)
+		}
+	}
+	if len(interactive) == 0 || tasks == 1 || (concurrency == 1 && !parallel) {
+		return nil
+	}
+	sort.Strings(interactive)
+	return fmt.Errorf("interactive tasks can't share the terminal with other tasks, run them on their own or with --concurrency=1: %v", strings.Join(interactive, ", "))
+}
@@ -0,0 +1,36 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/pyr-sh/dag"
+	"github.com/vercel/turbo/cli/internal/core"
+	"github.com/vercel/turbo/cli/internal/fs"
+	"gotest.tools/v3/assert"
+)
+
+func Test_validateInteractiveTasks(t *testing.T) {
+	engine := core.NewEngine(nil, false)
+	for _, taskID := range []string{core.ROOT_NODE_NAME, "web#dev", "ui#build"} {
+		engine.TaskGraph.Add(taskID)
+	}
+	// An edge goes from a task to the task it depends on
+	engine.TaskGraph.Connect(dag.BasicEdge("ui#build", core.ROOT_NODE_NAME))
+	engine.TaskGraph.Connect(dag.BasicEdge("web#dev", "ui#build"))
+	taskDefinitions := map[string]*fs.TaskDefinition{
+		"web#dev":  {Interactive: true},
+		"ui#build": {},
+	}
+
+	assert.NilError(t, validateInteractiveTasks(engine, taskDefinitions, 1, false))
+	err := validateInteractiveTasks(engine, taskDefinitions, 10, false)
+	assert.ErrorContains(t, err, "run them on their own or with --concurrency=1: web#dev")
+	err = validateInteractiveTasks(engine, taskDefinitions, 1, true)
+	assert.ErrorContains(t, err, "web#dev")
+
+	alone := core.NewEngine(nil, false)
+	alone.TaskGraph.Add(core.ROOT_NODE_NAME)
+	alone.TaskGraph.Add("web#dev")
+	alone.TaskGraph.Connect(dag.BasicEdge("web#dev", core.ROOT_NODE_NAME))
+	assert.NilError(t, validateInteractiveTasks(alone, taskDefinitions, 10, true))
+}
@@ -0,0 +1,48 @@
+package run
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/vercel/turbo/cli/internal/nodes"
+)
+
+// _logPrefixToken matches the tokens of a --log-prefix-template, like {package} or {hash:8}
+var _logPrefixToken = regexp.MustCompile(`\{([A-Za-z]+)(?::(\d+))?\}`)
+
+// validateLogPrefixTemplate checks that a --log-prefix-template only uses the tokens that
+// renderLogPrefix replaces
+func validateLogPrefixTemplate(template string) error {
+	for _, match := range _logPrefixToken.FindAllStringSubmatch(template, -1) {
+		switch name, length := match[1], match[2]; {
+		case name == "hash":
+		case length == "" && (name == "package" || name == "task" || name == "taskId"):
+		default:
+			return fmt.Errorf("unknown token %v in --log-prefix-template, use {package}, {task}, {taskId}, {hash} or {hash:<length>}", match[0])
+		}
+	}
+	return nil
+}
+
+// renderLogPrefix replaces the tokens of a --log-prefix-template with the values of the task.
+// {hash:N} is the first N characters of the task's hash.
+func renderLogPrefix(template string, packageTask *nodes.PackageTask) string {
+	return _logPrefixToken.ReplaceAllStringFunc(template, func(token string) string {
+		match := _logPrefixToken.FindStringSubmatch(token)
+		switch match[1] {
+		case "package":
+			return packageTask.PackageName
+		case "task":
+			return packageTask.Task
+		case "taskId":
+			return packageTask.TaskID
+		case "hash":
+			if length, err := strconv.Atoi(match[2]); err == nil && length < len(packageTask.Hash) {
+				return packageTask.Hash[:length]
+			}
+			return packageTask.Hash
+		}
+		return token
+	})
+}
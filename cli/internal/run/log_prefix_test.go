@@ -0,0 +1,37 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/vercel/turbo/cli/internal/nodes"
+	"gotest.tools/v3/assert"
+)
+
+func Test_renderLogPrefix(t *testing.T) {
+	packageTask := &nodes.PackageTask{
+		TaskID:      "web#build",
+		Task:        "build",
+		PackageName: "web",
+		Hash:        "0123456789abcdef",
+	}
+	testCases := []struct {
+		template string
+		want     string
+	}{
+		{template: "{package} › {task}", want: "web › build"},
+		{template: "{taskId}", want: "web#build"},
+		{template: "{task} {hash:8}", want: "build 01234567"},
+		{template: "{hash}", want: "0123456789abcdef"},
+		{template: "{hash:32}", want: "0123456789abcdef"},
+		{template: "[{package}]", want: "[web]"},
+	}
+	for _, tc := range testCases {
+		assert.NilError(t, validateLogPrefixTemplate(tc.template), tc.template)
+		assert.Equal(t, renderLogPrefix(tc.template, packageTask), tc.want, tc.template)
+	}
+
+	err := validateLogPrefixTemplate("{pkg}:{task}")
+	assert.ErrorContains(t, err, "unknown token {pkg}")
+	err = validateLogPrefixTemplate("{task:3}")
+	assert.ErrorContains(t, err, "unknown token {task:3}")
+}
@@ -0,0 +1,72 @@
+package run
+
+import (
+	gocontext "context"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/mitchellh/cli"
+	"github.com/vercel/turbo/cli/internal/nodes"
+	"github.com/vercel/turbo/cli/internal/process"
+	"github.com/vercel/turbo/cli/internal/turbostate"
+	"gotest.tools/v3/assert"
+)
+
+func Test_optsFromArgs_noOp(t *testing.T) {
+	noOp := func(value *string) (*Opts, error) {
+		return optsFromArgs(&turbostate.ParsedArgsFromRust{
+			Command: turbostate.Command{Run: &turbostate.RunPayload{NoOp: value}},
+		})
+	}
+	duration := func(value string) *string { return &value }
+
+	opts, err := noOp(nil)
+	assert.NilError(t, err, "optsFromArgs")
+	assert.Assert(t, !opts.runOpts.noOp)
+
+	opts, err = noOp(duration(""))
+	assert.NilError(t, err, "optsFromArgs")
+	assert.Assert(t, opts.runOpts.noOp)
+	assert.Equal(t, opts.runOpts.noOpDuration, time.Duration(0))
+	// Nothing runs, so nothing is restored from or saved to the cache
+	assert.Assert(t, opts.runcacheOpts.SkipReads)
+	assert.Assert(t, opts.runcacheOpts.SkipWrites)
+
+	opts, err = noOp(duration("250ms"))
+	assert.NilError(t, err, "optsFromArgs")
+	assert.Equal(t, opts.runOpts.noOpDuration, 250*time.Millisecond)
+
+	_, err = noOp(duration("-1s"))
+	assert.Error(t, err, "invalid no-op duration: -1s")
+	_, err = noOp(duration("soon"))
+	assert.Error(t, err, "invalid no-op duration: soon")
+}
+
+func Test_simulate(t *testing.T) {
+	newExecContext := func(duration time.Duration) *execContext {
+		return &execContext{
+			processes: process.NewManager(hclog.NewNullLogger()),
+			rs:        &runSpec{Opts: &Opts{runOpts: runOpts{noOp: true, noOpDuration: duration}}},
+		}
+	}
+	packageTask := &nodes.PackageTask{TaskID: "web#build", Command: "next build"}
+
+	terminal := cli.NewMockUi()
+	ec := newExecContext(20 * time.Millisecond)
+	start := time.Now()
+	assert.Assert(t, ec.simulate(gocontext.Background(), packageTask, &cli.PrefixedUi{Ui: terminal}))
+	assert.Assert(t, time.Since(start) >= 20*time.Millisecond, "expected the simulated execution to take its duration")
+	assert.Assert(t, terminal.OutputWriter.String() != "", "expected the simulated command to be printed")
+
+	// A run that is cancelled cuts the simulated execution short
+	ctx, cancel := gocontext.WithCancel(gocontext.Background())
+	cancel()
+	ec = newExecContext(time.Hour)
+	assert.Assert(t, !ec.simulate(ctx, packageTask, &cli.PrefixedUi{Ui: cli.NewMockUi()}))
+
+	// As does a run that is shutting down
+	ec = newExecContext(0)
+	ec.processes.Close()
+	assert.Assert(t, !ec.simulate(gocontext.Background(), packageTask, &cli.PrefixedUi{Ui: cli.NewMockUi()}))
+}
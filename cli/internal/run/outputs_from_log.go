@@ -0,0 +1,77 @@
+package run
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+
+	"github.com/vercel/turbo/cli/internal/turbopath"
+)
+
+// logOutputMatcher collects the paths that a task with outputsFromLog prints to stdout.
+// It is written to by the single goroutine that copies the task's stdout.
+type logOutputMatcher struct {
+	pattern *regexp.Regexp
+	// partial is the end of the output that isn't a complete line yet
+	partial []byte
+	paths   []string
+}
+
+func newLogOutputMatcher(pattern *regexp.Regexp) *logOutputMatcher {
+	return &logOutputMatcher{pattern: pattern}
+}
+
+// Write matches each complete line of output against the pattern
+func (m *logOutputMatcher) Write(p []byte) (int, error) {
+	m.partial = append(m.partial, p...)
+	for {
+		end := bytes.IndexByte(m.partial, '\n')
+		if end < 0 {
+			break
+		}
+		m.matchLine(m.partial[:end])
+		m.partial = m.partial[end+1:]
+	}
+	return len(p), nil
+}
+
+func (m *logOutputMatcher) matchLine(line []byte) {
+	match := m.pattern.FindSubmatch(bytes.TrimSuffix(line, []byte("\r")))
+	if len(match) > 1 && len(match[1]) > 0 {
+		m.paths = append(m.paths, string(match[1]))
+	}
+}
+
+// outputs resolves the collected paths, relative to the task's package directory unless
+// they are absolute, into files within the repository. Paths that don't name a file in the
+// repository are left out, and are described by the returned problems.
+func (m *logOutputMatcher) outputs(repoRoot turbopath.AbsoluteSystemPath, pkgDir turbopath.AnchoredSystemPath) ([]turbopath.AnchoredSystemPath, []string) {
+	if len(m.partial) > 0 {
+		m.matchLine(m.partial)
+		m.partial = nil
+	}
+	outputs := []turbopath.AnchoredSystemPath{}
+	problems := []string{}
+	for _, path := range m.paths {
+		file := turbopath.AbsoluteSystemPath(path)
+		if !filepath.IsAbs(path) {
+			file = pkgDir.RestoreAnchor(repoRoot).UntypedJoin(path)
+		}
+		if inRepo, err := repoRoot.ContainsPath(file); err != nil || !inRepo {
+			problems = append(problems, fmt.Sprintf("%v is outside of the repository", path))
+			continue
+		}
+		if !file.FileExists() {
+			problems = append(problems, fmt.Sprintf("%v is not a file", path))
+			continue
+		}
+		relativePath, err := file.RelativeTo(repoRoot)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%v: %v", path, err))
+			continue
+		}
+		outputs = append(outputs, relativePath)
+	}
+	return outputs, problems
+}
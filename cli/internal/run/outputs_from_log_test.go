@@ -0,0 +1,34 @@
+package run
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"gotest.tools/v3/assert"
+)
+
+func Test_logOutputMatcher(t *testing.T) {
+	repoRoot := turbopath.AbsoluteSystemPath(t.TempDir())
+	pkgDir := turbopath.AnchoredUnixPath("apps/web").ToSystemPath()
+	for _, file := range []string{"apps/web/dist/app-1f3a.js", "apps/web/report.html"} {
+		path := repoRoot.UntypedJoin(turbopath.AnchoredUnixPath(file).ToSystemPath().ToString())
+		assert.NilError(t, path.EnsureDir(), "EnsureDir")
+		assert.NilError(t, path.WriteFile([]byte("output"), 0644), "WriteFile")
+	}
+
+	matcher := newLogOutputMatcher(regexp.MustCompile(`^wrote (.+)$`))
+	_, _ = matcher.Write([]byte("compiling\nwrote dist/app"))
+	_, _ = matcher.Write([]byte("-1f3a.js\r\nwrote ../../../outside.txt\nwrote missing.js\n"))
+	_, _ = matcher.Write([]byte("wrote " + repoRoot.UntypedJoin("apps", "web", "report.html").ToString()))
+
+	outputs, problems := matcher.outputs(repoRoot, pkgDir)
+	assert.DeepEqual(t, outputs, []turbopath.AnchoredSystemPath{
+		turbopath.AnchoredUnixPath("apps/web/dist/app-1f3a.js").ToSystemPath(),
+		turbopath.AnchoredUnixPath("apps/web/report.html").ToSystemPath(),
+	})
+	assert.DeepEqual(t, problems, []string{
+		"../../../outside.txt is outside of the repository",
+		"missing.js is not a file",
+	})
+}
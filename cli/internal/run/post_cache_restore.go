@@ -0,0 +1,54 @@
+package run
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/mitchellh/cli"
+	"github.com/pkg/errors"
+	"github.com/vercel/turbo/cli/internal/fs"
+	"github.com/vercel/turbo/cli/internal/nodes"
+	"github.com/vercel/turbo/cli/internal/process"
+)
+
+// runPostCacheRestore runs the task's postCacheRestore hook, if it has one, after its
+// outputs were restored from the cache. The hook's output is only shown if it fails.
+func (ec *execContext) runPostCacheRestore(packageTask *nodes.PackageTask, prefixedUI *cli.PrefixedUi, progressLogger hclog.Logger) error {
+	hook := packageTask.TaskDefinition.PostCacheRestore
+	if hook == nil {
+		return nil
+	}
+
+	progressLogger.Debug("running postCacheRestore", "command", hook.Command)
+	cmd := postCacheRestoreCommand(hook, packageTask.Pkg.Dir.ToSystemPath().RestoreAnchor(ec.repoRoot).ToString(), packageTask)
+	output := &bytes.Buffer{}
+	cmd.Stdout = output
+	cmd.Stderr = output
+	if err := ec.processes.Exec(cmd); err != nil {
+		if errors.Is(err, process.ErrClosing) {
+			return err
+		}
+		if trimmed := strings.TrimSpace(output.String()); trimmed != "" {
+			prefixedUI.Output(trimmed)
+		}
+		return fmt.Errorf("postCacheRestore command \"%v\" failed: %w", hook.Command, err)
+	}
+	return nil
+}
+
+// postCacheRestoreCommand builds the command that runs a postCacheRestore hook via the
+// system shell in the given package directory. The hook is told which task and hash were
+// restored through TURBO_TASK and TURBO_HASH.
+func postCacheRestoreCommand(hook *fs.PostCacheRestoreHook, pkgDir string, packageTask *nodes.PackageTask) *exec.Cmd {
+	cmd := process.ShellCommand(hook.Command)
+	cmd.Dir = pkgDir
+	cmd.Env = append(os.Environ(),
+		fmt.Sprintf("TURBO_HASH=%v", packageTask.Hash),
+		fmt.Sprintf("TURBO_TASK=%v", packageTask.TaskID),
+	)
+	return cmd
+}
@@ -0,0 +1,32 @@
+package run
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/vercel/turbo/cli/internal/fs"
+	"github.com/vercel/turbo/cli/internal/nodes"
+	"gotest.tools/v3/assert"
+)
+
+func Test_postCacheRestoreCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the hook uses a POSIX shell command")
+	}
+	// pwd prints the path with symlinks resolved
+	pkgDir, err := filepath.EvalSymlinks(t.TempDir())
+	assert.NilError(t, err)
+	packageTask := &nodes.PackageTask{TaskID: "web#build", Hash: "abc123"}
+	hook := &fs.PostCacheRestoreHook{Command: "echo $TURBO_TASK $TURBO_HASH && pwd"}
+
+	output, err := postCacheRestoreCommand(hook, pkgDir, packageTask).Output()
+	assert.NilError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	assert.DeepEqual(t, lines, []string{"web#build abc123", pkgDir})
+
+	failing := &fs.PostCacheRestoreHook{Command: "exit 3"}
+	err = postCacheRestoreCommand(failing, pkgDir, packageTask).Run()
+	assert.ErrorContains(t, err, "exit status 3")
+}
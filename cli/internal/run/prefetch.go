@@ -0,0 +1,42 @@
+package run
+
+import (
+	gocontext "context"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/vercel/turbo/cli/internal/cache"
+	"github.com/vercel/turbo/cli/internal/core"
+	"github.com/vercel/turbo/cli/internal/graph"
+	"github.com/vercel/turbo/cli/internal/nodes"
+	"github.com/vercel/turbo/cli/internal/runsummary"
+	"github.com/vercel/turbo/cli/internal/util"
+)
+
+// prefetchArtifacts calculates the hash of every task up front, walking the task graph in
+// the order the run visits it, and starts downloading the artifacts of the tasks that can
+// be restored from the cache. The downloads happen in the background, so that they overlap
+// with the execution of the first tasks. Tasks in forcedReruns are skipped, since they
+// bypass the cache. Returns the number of tasks whose artifacts are prefetched.
+func prefetchArtifacts(ctx gocontext.Context, g *graph.CompleteGraph, engine *core.Engine, rs *runSpec, turboCache cache.Cache, forcedReruns util.Set, logger hclog.Logger) (int, error) {
+	prefetched := 0
+	prefetchFunc := func(ctx gocontext.Context, packageTask *nodes.PackageTask, taskSummary *runsummary.TaskSummary) error {
+		if packageTask.Command == "" || !packageTask.TaskDefinition.ShouldCache || forcedReruns.Includes(packageTask.TaskID) {
+			return nil
+		}
+		cache.Prefetch(turboCache, packageTask.Hash)
+		prefetched++
+		return nil
+	}
+
+	getArgs := func(taskID string) []string {
+		return rs.ArgsForTask(taskID)
+	}
+	// Like a dry run, the walk isn't parallelized: hashing is cheap, and the hashes of
+	// dependencies are calculated before those of their dependents either way.
+	visitorFn := g.GetPackageTaskVisitor(ctx, engine.TaskGraph, getArgs, logger, prefetchFunc)
+	errs := engine.Execute(visitorFn, core.EngineExecutionOptions{Concurrency: 1})
+	if len(errs) > 0 {
+		return 0, errs[0]
+	}
+	return prefetched, nil
+}
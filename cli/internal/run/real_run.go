@@ -1,12 +1,18 @@
 package run
 
 import (
+	"bytes"
 	gocontext "context"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
 	"os"
 	"os/exec"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/fatih/color"
@@ -18,6 +24,8 @@ import (
 	"github.com/vercel/turbo/cli/internal/cmdutil"
 	"github.com/vercel/turbo/cli/internal/colorcache"
 	"github.com/vercel/turbo/cli/internal/core"
+	"github.com/vercel/turbo/cli/internal/env"
+	"github.com/vercel/turbo/cli/internal/fs"
 	"github.com/vercel/turbo/cli/internal/graph"
 	"github.com/vercel/turbo/cli/internal/logstreamer"
 	"github.com/vercel/turbo/cli/internal/nodes"
@@ -29,6 +37,7 @@ import (
 	"github.com/vercel/turbo/cli/internal/taskhash"
 	"github.com/vercel/turbo/cli/internal/turbopath"
 	"github.com/vercel/turbo/cli/internal/ui"
+	"github.com/vercel/turbo/cli/internal/util"
 )
 
 // RealRun executes a set of tasks
@@ -47,27 +56,49 @@ func RealRun(
 ) error {
 	singlePackage := rs.Opts.runOpts.singlePackage
 
-	if singlePackage {
-		base.UI.Output(fmt.Sprintf("%s %s", ui.Dim("• Running"), ui.Dim(ui.Bold(strings.Join(rs.Targets, ", ")))))
-	} else {
-		base.UI.Output(fmt.Sprintf(ui.Dim("• Packages in scope: %v"), strings.Join(packagesInScope, ", ")))
-		base.UI.Output(fmt.Sprintf("%s %s %s", ui.Dim("• Running"), ui.Dim(ui.Bold(strings.Join(rs.Targets, ", "))), ui.Dim(fmt.Sprintf("in %v packages", rs.FilteredPkgs.Len()))))
+	// Fail before printing anything when the remote cache has to be used
+	useHTTPCache := !rs.Opts.cacheOpts.SkipRemote || rs.Opts.cacheOpts.Backend != ""
+	if rs.Opts.runOpts.requireRemoteCache {
+		if !useHTTPCache {
+			return errors.New("Remote caching is disabled, but --require-remote-cache was passed. Run `turbo login` and `turbo link`, or set TURBO_TOKEN and TURBO_TEAM")
+		} else if rs.Opts.cacheOpts.RemoteReadOnly {
+			return errors.New("Remote caching is read-only, but --require-remote-cache was passed")
+		} else if err := cache.CheckRemote(turboCache); err != nil {
+			return fmt.Errorf("Remote caching is unavailable, but --require-remote-cache was passed: %w", err)
+		}
+	}
+	if !rs.Opts.runOpts.noPreamble {
+		printPreamble(base, rs, packagesInScope, useHTTPCache)
 	}
 
-	// Log whether remote cache is enabled
-	useHTTPCache := !rs.Opts.cacheOpts.SkipRemote
-	if useHTTPCache {
-		base.UI.Info(ui.Dim("• Remote caching enabled"))
-	} else {
-		base.UI.Info(ui.Dim("• Remote caching disabled"))
+	colorCache := colorcache.New()
+	if rs.Opts.runOpts.sequentialPrefixColors {
+		colorCache = colorcache.NewSequential()
 	}
 
+	runcacheOpts := rs.Opts.runcacheOpts
+	if rs.Opts.runOpts.isolateOutputs {
+		runcacheOpts.IsolationDir = base.RepoRoot.UntypedJoin(".turbo", "isolated", runSummary.ID.String())
+	} else if runcacheOpts.DeferWrites {
+		runcacheOpts.StagingDir = base.RepoRoot.UntypedJoin(".turbo", "deferred", runSummary.ID.String())
+	}
+	runCache := runcache.New(turboCache, base.RepoRoot, runcacheOpts, colorCache)
+	// Registered before the cache shutdown so that it runs after all pending cache writes
 	defer func() {
-		_ = spinner.WaitFor(ctx, turboCache.Shutdown, base.UI, "...writing to cache...", 1500*time.Millisecond)
+		if err := runCache.RemoveIsolationDir(); err != nil {
+			base.LogWarning("Failed to remove isolation workspace", err)
+		}
 	}()
-	colorCache := colorcache.New()
 
-	runCache := runcache.New(turboCache, base.RepoRoot, rs.Opts.runcacheOpts, colorCache)
+	// The cache is shut down before the run summary is printed, so that it can report
+	// on uploads that finished in the background
+	var shutdownOnce sync.Once
+	shutdownCache := func() {
+		shutdownOnce.Do(func() {
+			_ = spinner.WaitFor(ctx, turboCache.Shutdown, base.UI, "...writing to cache...", 1500*time.Millisecond)
+		})
+	}
+	defer shutdownCache()
 
 	ec := &execContext{
 		colorCache:      colorCache,
@@ -81,23 +112,135 @@ func RealRun(
 		taskHashTracker: taskHashTracker,
 		repoRoot:        base.RepoRoot,
 		isSinglePackage: singlePackage,
+		turboCache:      turboCache,
+		useRemoteCache:  useHTTPCache,
+		nonReproducible: make(map[string][]string),
+		ioDiscrepancies: make(map[string]*ioDiscrepancies),
+		globalEnvVars:   g.GlobalEnvVars,
+
+		globalPassThroughEnv: g.GlobalPassThroughEnv,
+	}
+	if rs.Opts.runOpts.githubAnnotations {
+		ec.githubAnnotations = newGithubAnnotations(runCache.Stdout())
+	} else if rs.Opts.runOpts.groupLogs {
+		ec.groupedLogs = newGroupedLogs(runCache.Stdout())
+	}
+	if len(rs.Opts.runOpts.rerunDependentsOf) > 0 {
+		forced, unmatched, err := rerunDependents(engine, rs.Opts.runOpts.rerunDependentsOf)
+		if err != nil {
+			return err
+		}
+		for _, task := range unmatched {
+			base.UI.Warn(fmt.Sprintf("WARNING: %v does not match any task in this run (--rerun-dependents-of)", task))
+		}
+		base.UI.Output(ui.Dim(fmt.Sprintf("• Forcing %v dependent task(s) to rerun (--rerun-dependents-of)", forced.Len())))
+		ec.forcedReruns = forced
+	}
+	if rs.Opts.runOpts.prefetch && useHTTPCache && !runcacheOpts.SkipReads {
+		prefetched, err := prefetchArtifacts(ctx, g, engine, rs, turboCache, ec.forcedReruns, base.Logger)
+		if err != nil {
+			return err
+		}
+		base.UI.Output(ui.Dim(fmt.Sprintf("• Prefetching remote cache artifacts of %v task(s) (--prefetch)", prefetched)))
+	}
+	if rs.Opts.runOpts.auditIO {
+		base.UI.Output(ui.Dim("• Auditing task file accesses (--audit-io), tasks will run slower than usual"))
+		ec.ioAuditor = &ioAuditor{
+			repoRoot:    base.RepoRoot,
+			g:           g,
+			globalFiles: runSummary.GlobalHashSummary.GlobalFileHashMap,
+		}
 	}
 
 	// run the thing
 	execOpts := core.EngineExecutionOptions{
-		Parallel:    rs.Opts.runOpts.parallel,
-		Concurrency: rs.Opts.runOpts.concurrency,
+		Parallel:            rs.Opts.runOpts.parallel,
+		Concurrency:         rs.Opts.runOpts.concurrency,
+		SerialWithinPackage: rs.Opts.runOpts.serialWithinPackage,
+	}
+	if len(rs.Opts.runOpts.concurrencyGroups) > 0 {
+		execOpts.ConcurrencyGroups = rs.Opts.runOpts.concurrencyGroups
+		execOpts.TaskConcurrencyGroups = make(map[string]string)
+		for taskID, taskDefinition := range g.TaskDefinitions {
+			if taskDefinition.ConcurrencyGroup != "" {
+				execOpts.TaskConcurrencyGroups[taskID] = taskDefinition.ConcurrencyGroup
+			}
+		}
+	}
+	for taskID, taskDefinition := range g.TaskDefinitions {
+		if taskDefinition.Persistent {
+			if execOpts.PersistentTasks == nil {
+				execOpts.PersistentTasks = make(util.Set)
+			}
+			execOpts.PersistentTasks.Add(taskID)
+		}
+		if taskDefinition.ParallelUnsafe {
+			if execOpts.ParallelUnsafeTasks == nil {
+				execOpts.ParallelUnsafeTasks = make(util.Set)
+			}
+			execOpts.ParallelUnsafeTasks.Add(taskID)
+		}
+	}
+	if rs.Opts.runOpts.taskOrderFile != "" {
+		taskOrderFile := fs.ResolveUnknownPath(base.RepoRoot, rs.Opts.runOpts.taskOrderFile)
+		contents, err := taskOrderFile.ReadFile()
+		if err != nil {
+			return fmt.Errorf("failed to read --task-order-file: %w", err)
+		}
+		taskPriorities, err := parseTaskOrder(string(contents), singlePackage)
+		if err != nil {
+			return err
+		}
+		execOpts.TaskPriorities = taskPriorities
+	}
+
+	if rs.Opts.runOpts.outputNDJSON {
+		runSummary.StreamTasks(os.Stdout, singlePackage)
+	}
+	if rs.Opts.runOpts.eventsSocket != "" {
+		socketPath := fs.ResolveUnknownPath(base.RepoRoot, rs.Opts.runOpts.eventsSocket).ToString()
+		if events, err := connectEventsSocket(socketPath, base.UI); err != nil {
+			base.UI.Warn(fmt.Sprintf("WARNING: not writing task events, --events-socket is unavailable: %v", err))
+		} else {
+			defer func() { _ = events.Close() }()
+			runSummary.EmitTaskEvents(events)
+		}
 	}
 
 	taskSummaries := []*runsummary.TaskSummary{}
+	// failedTasks are the IDs of the tasks that failed, for finding the tasks skipped because of them
+	failedTasks := make(util.Set)
+	var failedTasksMu sync.Mutex
 	execFunc := func(ctx gocontext.Context, packageTask *nodes.PackageTask, taskSummary *runsummary.TaskSummary) error {
+		// The tasks that turbo watch doesn't re-run are unchanged since they last ran
+		if rs.RerunTasks != nil && !rs.RerunTasks.Includes(packageTask.TaskID) {
+			return nil
+		}
 		deps := engine.TaskGraph.DownEdges(packageTask.TaskID)
 		taskSummaries = append(taskSummaries, taskSummary)
 
+		taskSummary.Timings = &runsummary.TaskTimings{Hashing: packageTask.HashDuration}
+		if streamedLogFile := ec.streamedLogFile(packageTask); streamedLogFile != "" {
+			// Like the cached log file, the path is relative to the repository when it is within it
+			taskSummary.LogFile = streamedLogFile.ToString()
+			if inRepo, err := base.RepoRoot.ContainsPath(streamedLogFile); err == nil && inRepo {
+				if repoRelative, err := streamedLogFile.RelativeTo(base.RepoRoot); err == nil {
+					taskSummary.LogFile = repoRelative.ToString()
+				}
+			}
+		}
 		// deps here are passed in to calculate the task hash
-		taskExecutionSummary, err := ec.exec(ctx, packageTask, deps)
+		taskExecutionSummary, err := ec.exec(ctx, packageTask, deps, taskSummary)
+		if taskExecutionSummary != nil {
+			if streamErr := runSummary.TaskFinished(taskSummary, taskExecutionSummary); streamErr != nil {
+				base.UI.Warn(fmt.Sprintf("Failed to stream %v: %s", packageTask.TaskID, streamErr))
+			}
+		}
 		if err != nil {
-			return err
+			failedTasksMu.Lock()
+			failedTasks.Add(packageTask.TaskID)
+			failedTasksMu.Unlock()
+			return &taskError{packageTask: packageTask, err: err}
 		}
 		taskSummary.Execution = taskExecutionSummary
 		return nil
@@ -107,8 +250,11 @@ func RealRun(
 		return rs.ArgsForTask(taskID)
 	}
 
-	visitorFn := g.GetPackageTaskVisitor(ctx, engine.TaskGraph, getArgs, base.Logger, execFunc)
+	// With --run-timeout, the run is stopped like an interrupted run once the timeout passes
+	execCtx, deadline := startRunDeadline(ctx, rs.Opts.runOpts.runTimeout, processes)
+	visitorFn := g.GetPackageTaskVisitor(execCtx, engine.TaskGraph, getArgs, base.Logger, execFunc)
 	errs := engine.Execute(visitorFn, execOpts)
+	runTimedOut := deadline.stop()
 
 	// Track if we saw any child with a non-zero exit code
 	exitCode := 0
@@ -117,6 +263,21 @@ func RealRun(
 	// Assign tasks after execution
 	runSummary.Tasks = taskSummaries
 
+	// Without --continue, the tasks left over after a failure are skipped as part of stopping the run
+	if rs.Opts.runOpts.continueOnError {
+		visited := make(util.Set)
+		for _, taskSummary := range taskSummaries {
+			visited.Add(taskSummary.TaskID)
+		}
+		// The tasks that turbo watch doesn't re-run didn't run, but no task is skipped because of them
+		for _, v := range engine.TaskGraph.Vertices() {
+			if taskID := dag.VertexName(v); rs.RerunTasks != nil && !rs.RerunTasks.Includes(taskID) {
+				visited.Add(taskID)
+			}
+		}
+		runSummary.RecordSkippedTasks(skippedTasks(engine, visited, failedTasks))
+	}
+
 	for _, err := range errs {
 		if errors.As(err, &exitCodeErr) {
 			if exitCodeErr.ExitCode > exitCode {
@@ -126,10 +287,57 @@ func RealRun(
 			// We hit some error, it shouldn't be exit code 0
 			exitCode = 1
 		}
-		base.UI.Error(err.Error())
+		if !rs.Opts.runOpts.errorsJSON {
+			base.UI.Error(err.Error())
+		}
+	}
+	if runTimedOut {
+		base.LogError("%v (--run-timeout)", deadline.reason)
+		exitCode = deadline.exitCode(exitCode)
+	}
+	if rs.Opts.runOpts.errorsJSON {
+		if err := writeStructuredErrors(os.Stderr, errs); err != nil {
+			base.UI.Warn(fmt.Sprintf("Failed to write errors: %s", err))
+		}
 	}
 
-	runSummary.Close(base.UI)
+	if rs.Opts.runcacheOpts.DeferWrites {
+		// Only publish artifacts if every task succeeded, and the run wasn't interrupted
+		if exitCode == 0 && !processes.IsClosing() {
+			if err := runCache.CommitDeferredWrites(ctx, base.Logger, base.UI); err != nil {
+				base.LogError("%v", err)
+			}
+		} else if discarded := runCache.DiscardDeferredWrites(); discarded > 0 {
+			base.UI.Warn(ui.Dim(fmt.Sprintf("• Run did not succeed, discarding %v deferred cache write(s)", discarded)))
+		}
+	}
+
+	if rs.Opts.runOpts.dumpInputs != "" {
+		if err := dumpInputs(base.RepoRoot, rs.Opts.runOpts.dumpInputs, taskSummaries); err != nil {
+			base.UI.Warn(fmt.Sprintf("Failed to dump task inputs: %s", err))
+		}
+	}
+
+	if len(ec.ioDiscrepancies) > 0 {
+		ec.printIODiscrepancies(base.UI)
+	}
+
+	if len(ec.nonReproducible) > 0 {
+		ec.printNonReproducible(base.UI)
+		if exitCode == 0 {
+			exitCode = 1
+		}
+	}
+
+	shutdownCache()
+	runSummary.Close(exitCode, base.UI)
+
+	// Simulated runs say nothing about how long tasks take
+	if !rs.Opts.runOpts.noOp {
+		if err := recordStats(base.RepoRoot, runSummary); err != nil {
+			base.UI.Warn(fmt.Sprintf("Failed to record run stats: %s", err))
+		}
+	}
 
 	// Write Run Summary if we wanted to
 	if rs.Opts.runOpts.summarize {
@@ -137,6 +345,23 @@ func RealRun(
 			base.UI.Warn(fmt.Sprintf("Failed to write run summary: %s", err))
 		}
 	}
+	if rs.Opts.runOpts.summaryPath != "" {
+		summaryPath := fs.ResolveUnknownPath(base.RepoRoot, rs.Opts.runOpts.summaryPath)
+		if err := runSummary.SaveTo(summaryPath, singlePackage); err != nil {
+			base.UI.Warn(fmt.Sprintf("Failed to write run summary to %v: %s", summaryPath, err))
+		}
+	}
+	if rs.Opts.runOpts.completionWebhook != "" {
+		// Like the summary file, the webhook never changes the outcome of the run
+		summary, err := runSummary.FormatJSON(singlePackage)
+		if err == nil {
+			client := &http.Client{Timeout: _completionWebhookTimeout}
+			err = postCompletionWebhook(client, rs.Opts.runOpts.completionWebhook, summary, os.Getenv("TURBO_WEBHOOK_SECRET"))
+		}
+		if err != nil {
+			base.UI.Warn(fmt.Sprintf("Failed to notify the completion webhook: %s", err))
+		}
+	}
 
 	if exitCode != 0 {
 		return &process.ChildExit{
@@ -146,6 +371,31 @@ func RealRun(
 	return nil
 }
 
+// printPreamble describes the run before any task runs: the packages in scope, the
+// tasks being run and whether remote caching is enabled
+func printPreamble(base *cmdutil.CmdBase, rs *runSpec, packagesInScope []string, useHTTPCache bool) {
+	if rs.Opts.runOpts.singlePackage {
+		base.UI.Output(fmt.Sprintf("%s %s", ui.Dim("• Running"), ui.Dim(ui.Bold(strings.Join(rs.Targets, ", ")))))
+	} else {
+		base.UI.Output(fmt.Sprintf(ui.Dim("• Packages in scope: %v"), strings.Join(packagesInScope, ", ")))
+		base.UI.Output(fmt.Sprintf("%s %s %s", ui.Dim("• Running"), ui.Dim(ui.Bold(strings.Join(rs.Targets, ", "))), ui.Dim(fmt.Sprintf("in %v packages", rs.FilteredPkgs.Len()))))
+	}
+
+	if rs.Opts.runOpts.noOp {
+		base.UI.Output(ui.Dim("• Simulating tasks (--no-op), no commands will be executed"))
+	}
+
+	if rs.Opts.runOpts.offline {
+		base.UI.Info(ui.Dim("• Offline mode: remote caching disabled"))
+	} else if useHTTPCache && rs.Opts.cacheOpts.RemoteReadOnly {
+		base.UI.Info(ui.Dim("• Remote caching enabled (read-only)"))
+	} else if useHTTPCache {
+		base.UI.Info(ui.Dim("• Remote caching enabled"))
+	} else {
+		base.UI.Info(ui.Dim("• Remote caching disabled"))
+	}
+}
+
 type execContext struct {
 	colorCache      *colorcache.ColorCache
 	runSummary      *runsummary.RunSummary
@@ -158,6 +408,35 @@ type execContext struct {
 	taskHashTracker *taskhash.Tracker
 	repoRoot        turbopath.AbsoluteSystemPath
 	isSinglePackage bool
+	turboCache      cache.Cache
+	useRemoteCache  bool
+
+	// nonReproducible maps task IDs to the output files that differed between executions
+	// when running with --check-reproducible
+	nonReproducible   map[string][]string
+	nonReproducibleMu sync.Mutex
+
+	// forcedReruns are the IDs of the tasks that bypass the cache when running
+	// with --rerun-dependents-of
+	forcedReruns util.Set
+
+	// ioAuditor is set when running with --audit-io, and ioDiscrepancies maps task IDs
+	// to the undeclared files they accessed
+	ioAuditor         *ioAuditor
+	ioDiscrepancies   map[string]*ioDiscrepancies
+	ioDiscrepanciesMu sync.Mutex
+
+	// githubAnnotations is set when running with --github-annotations
+	githubAnnotations *githubAnnotations
+	// groupedLogs is set when running with --log-order=grouped
+	groupedLogs *groupedLogs
+
+	// globalEnvVars are the env vars of the global hash, which every task is given
+	// when running with --env-mode=strict
+	globalEnvVars env.EnvironmentVariableMap
+	// globalPassThroughEnv are the env vars from "globalPassThroughEnv" in turbo.json, which
+	// every task is given along with the env vars it passes through itself
+	globalPassThroughEnv []string
 }
 
 func (ec *execContext) logError(log hclog.Logger, prefix string, err error) {
@@ -170,17 +449,20 @@ func (ec *execContext) logError(log hclog.Logger, prefix string, err error) {
 	ec.ui.Error(fmt.Sprintf("%s%s%s", ui.ERROR_PREFIX, prefix, color.RedString(" %v", err)))
 }
 
-func (ec *execContext) exec(ctx gocontext.Context, packageTask *nodes.PackageTask, deps dag.Set) (*runsummary.TaskExecutionSummary, error) {
+// exec runs a single task, recording how long each of its phases takes and the outputs
+// it restored from or saved to the cache in its summary
+func (ec *execContext) exec(ctx gocontext.Context, packageTask *nodes.PackageTask, deps dag.Set, taskSummary *runsummary.TaskSummary) (*runsummary.TaskExecutionSummary, error) {
+	timings := taskSummary.Timings
 	cmdTime := time.Now()
 
 	progressLogger := ec.logger.Named("")
 	progressLogger.Debug("start")
 
 	// Setup tracer
-	tracer, taskExecutionSummary := ec.runSummary.TrackTask(packageTask.TaskID)
+	hash := packageTask.Hash
+	tracer, taskExecutionSummary := ec.runSummary.TrackTask(packageTask.TaskID, hash)
 
 	passThroughArgs := ec.rs.ArgsForTask(packageTask.Task)
-	hash := packageTask.Hash
 	ec.logger.Debug("task hash", "value", hash)
 	// TODO(gsoltis): if/when we fix https://github.com/vercel/turbo/issues/937
 	// the following block should never get hit. In the meantime, keep it after hashing
@@ -190,6 +472,7 @@ func (ec *execContext) exec(ctx gocontext.Context, packageTask *nodes.PackageTas
 	if packageTask.Command == "" {
 		progressLogger.Debug("no task in package, skipping")
 		progressLogger.Debug("done", "status", "skipped", "duration", time.Since(cmdTime))
+		tracer(runsummary.TargetNoop, nil)
 		return taskExecutionSummary, nil
 	}
 
@@ -197,6 +480,8 @@ func (ec *execContext) exec(ctx gocontext.Context, packageTask *nodes.PackageTas
 	var prettyPrefix string
 	if ec.rs.Opts.runOpts.logPrefix == "none" {
 		prefix = ""
+	} else if ec.rs.Opts.runOpts.logPrefixTemplate != "" {
+		prefix = renderLogPrefix(ec.rs.Opts.runOpts.logPrefixTemplate, packageTask)
 	} else {
 		prefix = packageTask.OutputPrefix(ec.isSinglePackage)
 	}
@@ -213,38 +498,153 @@ func (ec *execContext) exec(ctx gocontext.Context, packageTask *nodes.PackageTas
 		ErrorPrefix:  prettyPrefix,
 		WarnPrefix:   prettyPrefix,
 	}
-	hit, err := taskCache.RestoreOutputs(ctx, prefixedUI, progressLogger)
+	skipReads := ec.rs.Opts.runcacheOpts.SkipReads
+	hit := false
+	var err error
+	if ec.forcedReruns.Includes(packageTask.TaskID) {
+		taskExecutionSummary.ForcedRerun = true
+		skipReads = true
+		taskCache.ReportBypass(prefixedUI, "a dependency was given to --rerun-dependents-of")
+	} else {
+		// The output replayed from the cache is printed at once too, with --log-order=grouped
+		var replayOutput *bytes.Buffer
+		if ec.groupedLogs != nil {
+			replayOutput = &bytes.Buffer{}
+			prefixedUI.Ui = &bufferedOutputUi{Ui: ec.ui, output: replayOutput}
+		}
+		restoreStart := time.Now()
+		hit, taskSummary.ExpandedOutputs, err = taskCache.RestoreOutputs(ctx, prefixedUI, progressLogger)
+		timings.CacheRestore = time.Since(restoreStart)
+		if replayOutput != nil {
+			prefixedUI.Ui = ec.ui
+			if flushErr := ec.groupedLogs.flush(replayOutput); flushErr != nil {
+				prefixedUI.Warn(fmt.Sprintf("could not print the output replayed from the cache: %v", flushErr))
+			}
+		}
+	}
 	if err != nil {
 		prefixedUI.Error(fmt.Sprintf("error fetching from cache: %s", err))
 	} else if hit {
+		if err := ec.runPostCacheRestore(packageTask, prefixedUI, progressLogger); err != nil {
+			if errors.Is(err, process.ErrClosing) {
+				tracer(runsummary.TargetCanceled, ec.cancellationReason())
+				return taskExecutionSummary, nil
+			}
+			if packageTask.TaskDefinition.PostCacheRestore.Fatal {
+				tracer(runsummary.TargetBuildFailed, err)
+				progressLogger.Error(fmt.Sprintf("Error: %v", err))
+				if !ec.rs.Opts.runOpts.continueOnError {
+					prefixedUI.Error(fmt.Sprintf("ERROR: %s", err))
+					ec.processes.CloseWithReason(fmt.Sprintf("%v failed", packageTask.TaskID))
+				} else {
+					prefixedUI.Warn("postCacheRestore failed, but continuing...")
+				}
+				return taskExecutionSummary, &cacheError{err: err}
+			}
+			prefixedUI.Warn(fmt.Sprintf("WARNING: %s", err))
+		}
+		if err := ec.copyRestoredLog(packageTask, taskCache.LogFileName); err != nil {
+			prefixedUI.Warn(fmt.Sprintf("could not stream logs: %v", err))
+		}
 		tracer(runsummary.TargetCached, nil)
 		return taskExecutionSummary, nil
 	}
 
-	// Setup command execution
-	argsactual := append([]string{"run"}, packageTask.Task)
-	if len(passThroughArgs) > 0 {
-		// This will be either '--' or a typed nil
-		argsactual = append(argsactual, ec.packageManager.ArgSeparator...)
-		argsactual = append(argsactual, passThroughArgs...)
+	// With --force, a task can run even though its outputs are in the remote cache. Its
+	// upload then replaces the existing artifact rather than adding a new one.
+	if ec.useRemoteCache && skipReads && !ec.rs.Opts.runcacheOpts.SkipWrites && !ec.rs.Opts.cacheOpts.RemoteReadOnly && packageTask.TaskDefinition.ShouldCache {
+		if ec.turboCache.Exists(hash).Remote {
+			ec.runSummary.RecordExistingUpload(hash)
+		}
 	}
 
-	cmd := exec.Command(ec.packageManager.Command, argsactual...)
-	cmd.Dir = packageTask.Pkg.Dir.ToSystemPath().RestoreAnchor(ec.repoRoot).ToString()
-	envs := fmt.Sprintf("TURBO_HASH=%v", hash)
-	cmd.Env = append(os.Environ(), envs)
+	if ec.rs.Opts.runOpts.noOp {
+		// if we're in the process of exiting, the simulated execution was cut short
+		if ec.simulate(ctx, packageTask, prefixedUI) {
+			tracer(runsummary.TargetBuilt, nil)
+			progressLogger.Debug("done", "status", "simulated", "duration", time.Since(cmdTime))
+		} else {
+			tracer(runsummary.TargetCanceled, ec.cancellationReason())
+		}
+		return taskExecutionSummary, nil
+	}
+
+	if hooks := ec.rs.Opts.runOpts.taskHooks; hooks != nil {
+		if hooks.Before != "" {
+			if err := ec.runTaskHook("before", hooks.Before, packageTask, prettyPrefix, progressLogger); err != nil {
+				if errors.Is(err, process.ErrClosing) {
+					tracer(runsummary.TargetCanceled, ec.cancellationReason())
+					return taskExecutionSummary, nil
+				}
+				tracer(runsummary.TargetBuildFailed, err)
+				progressLogger.Error(fmt.Sprintf("Error: %v", err))
+				if !ec.rs.Opts.runOpts.continueOnError {
+					prefixedUI.Error(fmt.Sprintf("ERROR: %s", err))
+					ec.processes.CloseWithReason(fmt.Sprintf("%v failed", packageTask.TaskID))
+				} else {
+					prefixedUI.Warn(fmt.Sprintf("%s, but continuing...", err))
+				}
+				return taskExecutionSummary, err
+			}
+		}
+		if hooks.After != "" {
+			// Like a deferred call, the after hook runs however the task's command ended
+			defer func() {
+				if err := ec.runTaskHook("after", hooks.After, packageTask, prettyPrefix, progressLogger); err != nil && !errors.Is(err, process.ErrClosing) {
+					prefixedUI.Warn(fmt.Sprintf("WARNING: %s", err))
+				}
+			}()
+		}
+	}
+
+	// Setup command execution
+	traceDir := ""
+	if ec.ioAuditor != nil {
+		traceDir, err = os.MkdirTemp("", "turbo-audit-io")
+		if err != nil {
+			traceDir = ""
+			ec.logError(progressLogger, prettyPrefix, fmt.Errorf("could not trace task: %w", err))
+		} else {
+			defer func() { _ = os.RemoveAll(traceDir) }()
+		}
+	}
 
 	// Setup stdout/stderr
-	// If we are not caching anything, then we don't need to write logs to disk
-	// be careful about this conditional given the default of cache = true
-	writer, err := taskCache.OutputWriter(prettyPrefix)
+	// If we are not caching anything, then we don't need to write logs to disk:
+	// the writer of a task with "cache": false only writes to the terminal
+	var writer io.WriteCloser
+	var group *githubGroup
+	var groupedOutput *bytes.Buffer
+	// Persistent tasks don't finish, so their output is never held back in a group
+	if ec.githubAnnotations != nil && !packageTask.TaskDefinition.Persistent {
+		group = &githubGroup{title: packageTask.TaskID}
+		writer, err = taskCache.OutputWriterTo(group, prettyPrefix)
+	} else if ec.groupedLogs != nil && !packageTask.TaskDefinition.Persistent {
+		groupedOutput = &bytes.Buffer{}
+		writer, err = taskCache.OutputWriterTo(groupedOutput, prettyPrefix)
+	} else {
+		writer, err = taskCache.OutputWriter(prettyPrefix)
+	}
 	if err != nil {
 		tracer(runsummary.TargetBuildFailed, err)
 
 		ec.logError(progressLogger, prettyPrefix, err)
 		if !ec.rs.Opts.runOpts.continueOnError {
-			os.Exit(1)
+			ec.processes.CloseWithReason(fmt.Sprintf("%v failed", packageTask.TaskID))
 		}
+		return taskExecutionSummary, &cacheError{err: err}
+	}
+
+	if ec.rs.Opts.runOpts.streamLogsTo != "" {
+		streamWriter, err := ec.streamLogs(packageTask, writer)
+		if err != nil {
+			ec.logError(progressLogger, prettyPrefix, fmt.Errorf("could not stream logs: %w", err))
+		} else {
+			writer = streamWriter
+		}
+	}
+	if ec.rs.Opts.runOpts.maxLogBytesPerTask > 0 {
+		writer = newTruncatingWriter(writer, ec.rs.Opts.runOpts.maxLogBytesPerTask)
 	}
 
 	// Create a logger
@@ -253,8 +653,43 @@ func (ec *execContext) exec(ctx gocontext.Context, packageTask *nodes.PackageTas
 	logStreamerOut := logstreamer.NewLogstreamer(logger, prettyPrefix, false)
 	// Setup a streamer that we'll pipe cmd.Stderr to.
 	logStreamerErr := logstreamer.NewLogstreamer(logger, prettyPrefix, false)
-	cmd.Stderr = logStreamerErr
-	cmd.Stdout = logStreamerOut
+	// The pattern was validated when turbo.json was read
+	var outputsFromLog *regexp.Regexp
+	if packageTask.TaskDefinition.OutputsFromLog != "" {
+		outputsFromLog = regexp.MustCompile(packageTask.TaskDefinition.OutputsFromLog)
+	}
+	var logOutputs *logOutputMatcher
+	// lastCmd is the command of the latest attempt, whose process state says what it used
+	var lastCmd *exec.Cmd
+	// exec.Cmd can't be reused, every attempt at running the task gets a new one
+	newCmd := func() *exec.Cmd {
+		cmd := ec.taskCommand(packageTask, passThroughArgs)
+		if traceDir != "" {
+			traced, err := traceCommand(cmd, traceDir)
+			if err != nil {
+				// Run the task untraced, as when its trace directory couldn't be created
+				traceDir = ""
+				ec.logError(progressLogger, prettyPrefix, fmt.Errorf("could not trace task: %w", err))
+			} else {
+				cmd = traced
+			}
+		}
+		lastCmd = cmd
+		cmd.Stderr = logStreamerErr
+		cmd.Stdout = logStreamerOut
+		if outputsFromLog != nil {
+			// Only the outputs reported by the last attempt are cached
+			logOutputs = newLogOutputMatcher(outputsFromLog)
+			cmd.Stdout = io.MultiWriter(logStreamerOut, logOutputs)
+		}
+		if packageTask.TaskDefinition.Interactive {
+			// The command is given the terminal itself, so that it behaves as if run directly
+			cmd.Stdin = os.Stdin
+			cmd.Stdout = ec.runCache.Stdout()
+			cmd.Stderr = os.Stderr
+		}
+		return cmd
+	}
 	// Flush/Reset any error we recorded
 	logStreamerErr.FlushRecord()
 	logStreamerOut.FlushRecord()
@@ -272,6 +707,16 @@ func (ec *execContext) exec(ctx gocontext.Context, packageTask *nodes.PackageTas
 		if err := writer.Close(); err != nil {
 			closeErrors = append(closeErrors, errors.Wrap(err, "log file"))
 		}
+		if group != nil {
+			if err := ec.githubAnnotations.flush(group); err != nil {
+				closeErrors = append(closeErrors, errors.Wrap(err, "github annotations"))
+			}
+		}
+		if groupedOutput != nil {
+			if err := ec.groupedLogs.flush(groupedOutput); err != nil {
+				closeErrors = append(closeErrors, errors.Wrap(err, "grouped logs"))
+			}
+		}
 		if len(closeErrors) > 0 {
 			msgs := make([]string, len(closeErrors))
 			for i, err := range closeErrors {
@@ -283,20 +728,42 @@ func (ec *execContext) exec(ctx gocontext.Context, packageTask *nodes.PackageTas
 	}
 
 	// Run the command
-	if err := ec.processes.Exec(cmd); err != nil {
+	execStart := time.Now()
+	err = ec.execWithRetries(ctx, packageTask, newCmd, prefixedUI, taskExecutionSummary)
+	timings.Execution = time.Since(execStart)
+	if lastCmd != nil {
+		taskSummary.Resources = processResources(lastCmd.ProcessState)
+	}
+	if err != nil {
 		// close off our outputs. We errored, so we mostly don't care if we fail to close
 		_ = closeOutputs()
-		// if we already know we're in the process of exiting,
-		// we don't need to record an error to that effect.
+		// if we already know we're in the process of exiting, this isn't a
+		// failure of the task, record why it was interrupted instead.
 		if errors.Is(err, process.ErrClosing) {
+			tracer(runsummary.TargetCanceled, ec.cancellationReason())
 			return taskExecutionSummary, nil
 		}
-		tracer(runsummary.TargetBuildFailed, err)
+		// Label the failure with the category configured for its exit code, if any
+		childExit := &process.ChildExit{}
+		if errors.As(err, &childExit) {
+			childExit.Category = ec.rs.Opts.runOpts.exitCodeCategories[childExit.ExitCode]
+		}
+		if childExit.TimedOut {
+			err = fmt.Errorf("task did not finish within its timeout of %v: %w", packageTask.TaskDefinition.Timeout, err)
+			tracer(runsummary.TargetBuildTimeout, err)
+		} else {
+			tracer(runsummary.TargetBuildFailed, err)
+		}
 
 		progressLogger.Error(fmt.Sprintf("Error: command finished with error: %v", err))
+		if ec.githubAnnotations != nil {
+			if err := ec.githubAnnotations.taskFailed(packageTask.PackageName, packageTask.TaskID, packageTask.LogFile, err); err != nil {
+				ec.logError(progressLogger, prettyPrefix, fmt.Errorf("could not annotate failure: %w", err))
+			}
+		}
 		if !ec.rs.Opts.runOpts.continueOnError {
 			prefixedUI.Error(fmt.Sprintf("ERROR: command finished with error: %s", err))
-			ec.processes.Close()
+			ec.processes.CloseWithReason(fmt.Sprintf("%v failed", packageTask.TaskID))
 		} else {
 			prefixedUI.Warn("command finished with error, but continuing...")
 		}
@@ -308,12 +775,41 @@ func (ec *execContext) exec(ctx gocontext.Context, packageTask *nodes.PackageTas
 	}
 
 	duration := time.Since(cmdTime)
+	if logOutputs != nil {
+		outputs, problems := logOutputs.outputs(ec.repoRoot, packageTask.Pkg.Dir)
+		for _, problem := range problems {
+			prefixedUI.Warn(fmt.Sprintf("not caching an output reported in the logs: %v", problem))
+		}
+		taskCache.AddLogOutputs(outputs)
+	}
 	// Close off our outputs and cache them
 	if err := closeOutputs(); err != nil {
 		ec.logError(progressLogger, "", err)
 	} else {
-		if err = taskCache.SaveOutputs(ctx, progressLogger, prefixedUI, int(duration.Milliseconds())); err != nil {
+		saveStart := time.Now()
+		if taskSummary.ExpandedOutputs, err = taskCache.SaveOutputs(ctx, progressLogger, prefixedUI, int(duration.Milliseconds())); err != nil {
 			ec.logError(progressLogger, "", fmt.Errorf("error caching output: %w", err))
+		} else if excluded, err := taskCache.ExcludedOutputs(); err != nil {
+			progressLogger.Warn(fmt.Sprintf("Failed to list the excluded outputs of %v: %v", packageTask.TaskID, err))
+		} else if excluded != nil {
+			// The summary lists the files that were left out, rather than the exclusion globs
+			taskSummary.ExcludedOutputs = make([]string, len(excluded))
+			for i, file := range excluded {
+				taskSummary.ExcludedOutputs[i] = file.ToString()
+			}
+		}
+		timings.CacheSave = time.Since(saveStart)
+	}
+
+	if traceDir != "" {
+		if err := ec.auditIO(packageTask, traceDir, prefixedUI); err != nil {
+			ec.logError(progressLogger, prettyPrefix, fmt.Errorf("error auditing file accesses: %w", err))
+		}
+	}
+
+	if ec.rs.Opts.runOpts.checkReproducible && packageTask.TaskDefinition.ShouldCache {
+		if err := ec.checkReproducible(taskCache, packageTask, passThroughArgs, prefixedUI, progressLogger); err != nil {
+			ec.logError(progressLogger, prettyPrefix, fmt.Errorf("error checking reproducibility: %w", err))
 		}
 	}
 
@@ -322,3 +818,110 @@ func (ec *execContext) exec(ctx gocontext.Context, packageTask *nodes.PackageTas
 	progressLogger.Debug("done", "status", "complete", "duration", duration)
 	return taskExecutionSummary, nil
 }
+
+// cancellationReason returns why the run is shutting down, for recording tasks that were
+// stopped as canceled. It is nil if no reason was given.
+func (ec *execContext) cancellationReason() error {
+	if reason := ec.processes.CancellationReason(); reason != "" {
+		return errors.New(reason)
+	}
+	return nil
+}
+
+// simulate stands in for executing a task when running with --no-op. Instead of running
+// the task's command, it waits for the configured duration. It returns false if the run
+// is shutting down.
+func (ec *execContext) simulate(ctx gocontext.Context, packageTask *nodes.PackageTask, prefixedUI *cli.PrefixedUi) bool {
+	prefixedUI.Output(ui.Dim(fmt.Sprintf("simulating: %v", packageTask.Command)))
+	if duration := ec.rs.Opts.runOpts.noOpDuration; duration > 0 {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(duration):
+		}
+	}
+	return !ec.processes.IsClosing()
+}
+
+// taskCommand builds the command that executes the given task via the package manager
+func (ec *execContext) taskCommand(packageTask *nodes.PackageTask, passThroughArgs []string) *exec.Cmd {
+	argsactual := append([]string{"run"}, packageTask.Task)
+	if len(passThroughArgs) > 0 {
+		// This will be either '--' or a typed nil
+		argsactual = append(argsactual, ec.packageManager.ArgSeparator...)
+		argsactual = append(argsactual, passThroughArgs...)
+	}
+
+	cmd := exec.Command(ec.packageManager.Command, argsactual...)
+	cmd.Dir = packageTask.Pkg.Dir.ToSystemPath().RestoreAnchor(ec.repoRoot).ToString()
+	envs := fmt.Sprintf("TURBO_HASH=%v", packageTask.Hash)
+	if ec.rs.Opts.runOpts.strictEnv {
+		hashedEnv := ec.taskHashTracker.GetEnvVars(packageTask.TaskID).All
+		passThroughEnv := append(append([]string{}, ec.globalPassThroughEnv...), packageTask.TaskDefinition.PassThroughEnv...)
+		cmd.Env = append(strictTaskEnv(hashedEnv, ec.globalEnvVars, passThroughEnv), envs)
+	} else {
+		cmd.Env = append(os.Environ(), envs)
+	}
+	return cmd
+}
+
+// checkReproducible executes a task a second time, starting with its outputs removed, and
+// compares the files it produces against those of the first execution. Any file that is
+// missing, new, or has different contents is recorded as non-reproducible.
+func (ec *execContext) checkReproducible(taskCache runcache.TaskCache, packageTask *nodes.PackageTask, passThroughArgs []string, prefixedUI *cli.PrefixedUi, progressLogger hclog.Logger) error {
+	firstHashes, err := taskCache.OutputHashes(progressLogger, prefixedUI)
+	if err != nil {
+		return err
+	}
+	if err := taskCache.RemoveOutputs(progressLogger, prefixedUI); err != nil {
+		return err
+	}
+
+	prefixedUI.Output(ui.Dim("re-executing to check reproducibility"))
+	// The output of the second execution is discarded, it has already been shown and cached once
+	if err := ec.processes.Exec(ec.taskCommand(packageTask, passThroughArgs)); err != nil {
+		if errors.Is(err, process.ErrClosing) {
+			return nil
+		}
+		return err
+	}
+	// The first execution's outputs were transformed when they were cached
+	if err := taskCache.TransformOutputs(progressLogger, prefixedUI); err != nil {
+		return err
+	}
+
+	secondHashes, err := taskCache.OutputHashes(progressLogger, prefixedUI)
+	if err != nil {
+		return err
+	}
+
+	mismatched := runcache.DiffOutputHashes(firstHashes, secondHashes)
+	if len(mismatched) == 0 {
+		return nil
+	}
+
+	prefixedUI.Warn(fmt.Sprintf("outputs are not reproducible, %v file(s) differ between executions", len(mismatched)))
+	ec.nonReproducibleMu.Lock()
+	defer ec.nonReproducibleMu.Unlock()
+	ec.nonReproducible[packageTask.TaskID] = mismatched
+	return nil
+}
+
+// printNonReproducible reports every task whose outputs differed between the two executions
+// performed by --check-reproducible, along with the offending files.
+func (ec *execContext) printNonReproducible(terminal cli.Ui) {
+	taskIDs := make([]string, 0, len(ec.nonReproducible))
+	for taskID := range ec.nonReproducible {
+		taskIDs = append(taskIDs, taskID)
+	}
+	sort.Strings(taskIDs)
+
+	terminal.Output("")
+	terminal.Warn(fmt.Sprintf("%v task(s) produced non-reproducible outputs:", len(taskIDs)))
+	for _, taskID := range taskIDs {
+		terminal.Output(fmt.Sprintf("  %s", ui.Bold(taskID)))
+		for _, file := range ec.nonReproducible[taskID] {
+			terminal.Output(fmt.Sprintf("    %s", file))
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package run
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/vercel/turbo/cli/internal/cmdutil"
+	"github.com/vercel/turbo/cli/internal/util"
+	"gotest.tools/v3/assert"
+)
+
+func Test_printPreamble(t *testing.T) {
+	terminal := cli.NewMockUi()
+	base := &cmdutil.CmdBase{UI: terminal}
+	rs := &runSpec{
+		Targets:      []string{"build"},
+		FilteredPkgs: util.SetFromStrings([]string{"web", "docs"}),
+		Opts:         &Opts{runOpts: runOpts{noOp: true}},
+	}
+	rs.Opts.cacheOpts.RemoteReadOnly = true
+
+	printPreamble(base, rs, []string{"docs", "web"}, true)
+	output := terminal.OutputWriter.String()
+	assert.Assert(t, strings.Contains(output, "Packages in scope: docs, web"), output)
+	assert.Assert(t, strings.Contains(output, "in 2 packages"), output)
+	assert.Assert(t, strings.Contains(output, "Simulating tasks (--no-op)"), output)
+	assert.Assert(t, strings.Contains(output, "Remote caching enabled (read-only)"), output)
+	assert.Equal(t, terminal.ErrorWriter.String(), "")
+
+	// A single package repo has no packages in scope to list
+	terminal = cli.NewMockUi()
+	base.UI = terminal
+	rs.Opts.runOpts = runOpts{singlePackage: true}
+	rs.Opts.cacheOpts.RemoteReadOnly = false
+	printPreamble(base, rs, nil, false)
+	output = terminal.OutputWriter.String()
+	assert.Assert(t, !strings.Contains(output, "Packages in scope"), output)
+	assert.Assert(t, strings.Contains(output, "Running"), output)
+	assert.Assert(t, strings.Contains(output, "Remote caching disabled"), output)
+}
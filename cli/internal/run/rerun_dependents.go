@@ -0,0 +1,47 @@
+package run
+
+import (
+	"sort"
+
+	"github.com/vercel/turbo/cli/internal/core"
+	"github.com/vercel/turbo/cli/internal/util"
+)
+
+// rerunDependents returns the IDs of the tasks that must execute, regardless of the cache,
+// because they depend on a task given to --rerun-dependents-of. A task is given either by
+// name (e.g. "build"), matching it in every package, or by ID (e.g. "web#build").
+// The tasks that are given themselves still use the cache, unless they are also dependents.
+// Also returns the sorted tasks that matched nothing in the task graph.
+func rerunDependents(engine *core.Engine, tasks []string) (util.Set, []string, error) {
+	forced := make(util.Set)
+	matched := make(util.Set)
+	for _, v := range engine.TaskGraph.Vertices() {
+		taskID, ok := v.(string)
+		if !ok || taskID == core.ROOT_NODE_NAME {
+			continue
+		}
+		_, taskName := util.GetPackageTaskFromId(taskID)
+		for _, task := range tasks {
+			if task != taskID && task != taskName {
+				continue
+			}
+			matched.Add(task)
+			dependents, err := engine.GetTaskGraphDescendants(taskID)
+			if err != nil {
+				return nil, nil, err
+			}
+			for _, dependent := range dependents {
+				forced.Add(dependent)
+			}
+		}
+	}
+
+	unmatched := []string{}
+	for _, task := range tasks {
+		if !matched.Includes(task) {
+			unmatched = append(unmatched, task)
+		}
+	}
+	sort.Strings(unmatched)
+	return forced, unmatched, nil
+}
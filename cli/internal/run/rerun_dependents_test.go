@@ -0,0 +1,65 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/pyr-sh/dag"
+	"github.com/vercel/turbo/cli/internal/core"
+	"gotest.tools/v3/assert"
+)
+
+func Test_rerunDependents(t *testing.T) {
+	engine := core.NewEngine(nil, false)
+	for _, taskID := range []string{core.ROOT_NODE_NAME, "ui#build", "web#build", "web#test", "docs#build", "ui#lint"} {
+		engine.TaskGraph.Add(taskID)
+	}
+	// An edge goes from a task to the task it depends on
+	engine.TaskGraph.Connect(dag.BasicEdge("ui#build", core.ROOT_NODE_NAME))
+	engine.TaskGraph.Connect(dag.BasicEdge("docs#build", core.ROOT_NODE_NAME))
+	engine.TaskGraph.Connect(dag.BasicEdge("ui#lint", core.ROOT_NODE_NAME))
+	engine.TaskGraph.Connect(dag.BasicEdge("web#build", "ui#build"))
+	engine.TaskGraph.Connect(dag.BasicEdge("web#test", "web#build"))
+
+	testCases := []struct {
+		name          string
+		tasks         []string
+		wantForced    []string
+		wantUnmatched []string
+	}{
+		{
+			name:          "task id",
+			tasks:         []string{"ui#build"},
+			wantForced:    []string{"web#build", "web#test"},
+			wantUnmatched: []string{},
+		},
+		{
+			name:          "task name matches every package",
+			tasks:         []string{"build"},
+			wantForced:    []string{"web#build", "web#test"},
+			wantUnmatched: []string{},
+		},
+		{
+			name:          "no dependents",
+			tasks:         []string{"web#test", "lint"},
+			wantForced:    []string{},
+			wantUnmatched: []string{},
+		},
+		{
+			name:          "unmatched",
+			tasks:         []string{"web#build", "typecheck", "api#build"},
+			wantForced:    []string{"web#test"},
+			wantUnmatched: []string{"api#build", "typecheck"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			forced, unmatched, err := rerunDependents(engine, tc.tasks)
+			assert.NilError(t, err, "rerunDependents")
+			assert.Equal(t, forced.Len(), len(tc.wantForced))
+			for _, taskID := range tc.wantForced {
+				assert.Assert(t, forced.Includes(taskID), "expected %v to be forced", taskID)
+			}
+			assert.DeepEqual(t, unmatched, tc.wantUnmatched)
+		})
+	}
+}
@@ -0,0 +1,28 @@
+//go:build linux
+// +build linux
+
+package run
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/vercel/turbo/cli/internal/runsummary"
+)
+
+// processResources returns what a finished process used, or nil if it didn't start or exit
+func processResources(state *os.ProcessState) *runsummary.TaskResources {
+	if state == nil {
+		return nil
+	}
+	rusage, ok := state.SysUsage().(*syscall.Rusage)
+	if !ok || rusage == nil {
+		return nil
+	}
+	return &runsummary.TaskResources{
+		// Linux reports the peak RSS in kilobytes
+		PeakRSS:   rusage.Maxrss * 1024,
+		UserCPU:   state.UserTime(),
+		SystemCPU: state.SystemTime(),
+	}
+}
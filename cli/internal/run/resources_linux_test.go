@@ -0,0 +1,21 @@
+//go:build linux
+// +build linux
+
+package run
+
+import (
+	"os/exec"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func Test_processResources(t *testing.T) {
+	assert.Assert(t, processResources(nil) == nil)
+
+	cmd := exec.Command("true")
+	assert.NilError(t, cmd.Run())
+	resources := processResources(cmd.ProcessState)
+	assert.Assert(t, resources != nil)
+	assert.Assert(t, resources.PeakRSS > 0)
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+// +build !linux
+
+package run
+
+import (
+	"os"
+
+	"github.com/vercel/turbo/cli/internal/runsummary"
+)
+
+// processResources isn't supported off Linux, tasks' summaries leave out their resources
+func processResources(state *os.ProcessState) *runsummary.TaskResources {
+	return nil
+}
@@ -0,0 +1,60 @@
+package run
+
+import (
+	gocontext "context"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"github.com/pkg/errors"
+	"github.com/vercel/turbo/cli/internal/fs"
+	"github.com/vercel/turbo/cli/internal/nodes"
+	"github.com/vercel/turbo/cli/internal/process"
+	"github.com/vercel/turbo/cli/internal/runsummary"
+)
+
+// execWithRetries runs a task's command, stopping each attempt that runs past the task's
+// timeout. An attempt that exits with an error, or times out, is retried after the task's
+// retryBackoff, as long as the task has retries left. Attempts stopped because the run is
+// shutting down are never retried. For tasks with retries, the number of attempts is
+// recorded in the task's summary.
+func (ec *execContext) execWithRetries(ctx gocontext.Context, packageTask *nodes.PackageTask, newCmd func() *exec.Cmd, prefixedUI cli.Ui, summary *runsummary.TaskExecutionSummary) error {
+	taskDefinition := packageTask.TaskDefinition
+	for attempt := 1; ; attempt++ {
+		if taskDefinition.Retries > 0 {
+			summary.Attempts = attempt
+		}
+		err := ec.execAttempt(ctx, taskDefinition, newCmd())
+		childExit := &process.ChildExit{}
+		if err == nil || attempt > taskDefinition.Retries || !errors.As(err, &childExit) {
+			return err
+		}
+
+		prefixedUI.Warn(fmt.Sprintf("%v, retrying (attempt %v of %v)", err, attempt+1, taskDefinition.Retries+1))
+		if taskDefinition.RetryBackoff > 0 {
+			select {
+			case <-ctx.Done():
+				return process.ErrClosing
+			case <-time.After(taskDefinition.RetryBackoff):
+			}
+		}
+		if ec.processes.IsClosing() {
+			return process.ErrClosing
+		}
+	}
+}
+
+// execAttempt runs a single attempt at a task's command, stopping it if it runs past the
+// task's timeout. A timeout of zero lets the command run forever.
+func (ec *execContext) execAttempt(ctx gocontext.Context, taskDefinition *fs.TaskDefinition, cmd *exec.Cmd) error {
+	if taskDefinition.Timeout > 0 {
+		var cancel gocontext.CancelFunc
+		ctx, cancel = gocontext.WithTimeout(ctx, taskDefinition.Timeout)
+		defer cancel()
+	}
+	if taskDefinition.Interactive {
+		return ec.processes.ExecInteractiveContext(ctx, cmd)
+	}
+	return ec.processes.ExecContext(ctx, cmd)
+}
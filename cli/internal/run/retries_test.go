@@ -0,0 +1,97 @@
+package run
+
+import (
+	gocontext "context"
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/mitchellh/cli"
+	"github.com/vercel/turbo/cli/internal/fs"
+	"github.com/vercel/turbo/cli/internal/nodes"
+	"github.com/vercel/turbo/cli/internal/process"
+	"github.com/vercel/turbo/cli/internal/runsummary"
+	"gotest.tools/v3/assert"
+)
+
+func Test_execWithRetries(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the commands use a POSIX shell")
+	}
+	// Each attempt appends a line to a file, and the command fails until it has run
+	// succeedOn times
+	command := func(counter string, succeedOn int) func() *exec.Cmd {
+		return func() *exec.Cmd {
+			return exec.Command("sh", "-c", `echo . >> "$0"; [ "$(wc -l < "$0")" -ge "$1" ]`, counter, string(rune('0'+succeedOn)))
+		}
+	}
+
+	testCases := []struct {
+		name         string
+		definition   fs.TaskDefinition
+		succeedOn    int
+		wantErr      bool
+		wantAttempts int
+	}{
+		{
+			name:       "no retries",
+			definition: fs.TaskDefinition{},
+			succeedOn:  2,
+			wantErr:    true,
+			// Not recorded for tasks without retries
+			wantAttempts: 0,
+		},
+		{
+			name:         "succeeds on a retry",
+			definition:   fs.TaskDefinition{Retries: 2, RetryBackoff: 10 * time.Millisecond},
+			succeedOn:    2,
+			wantAttempts: 2,
+		},
+		{
+			name:         "runs out of retries",
+			definition:   fs.TaskDefinition{Retries: 2},
+			succeedOn:    5,
+			wantErr:      true,
+			wantAttempts: 3,
+		},
+		{
+			name:         "succeeds right away",
+			definition:   fs.TaskDefinition{Retries: 2},
+			succeedOn:    1,
+			wantAttempts: 1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ec := &execContext{processes: process.NewManager(hclog.NewNullLogger())}
+			definition := tc.definition
+			packageTask := &nodes.PackageTask{TaskID: "web#test", TaskDefinition: &definition}
+			summary := &runsummary.TaskExecutionSummary{}
+			counter := filepath.Join(t.TempDir(), "attempts")
+
+			err := ec.execWithRetries(gocontext.Background(), packageTask, command(counter, tc.succeedOn), cli.NewMockUi(), summary)
+			if tc.wantErr {
+				childExit := &process.ChildExit{}
+				assert.Assert(t, errors.As(err, &childExit), "expected a ChildExit, got %v", err)
+			} else {
+				assert.NilError(t, err)
+			}
+			assert.Equal(t, summary.Attempts, tc.wantAttempts)
+		})
+	}
+}
+
+func Test_execWithRetries_closing(t *testing.T) {
+	ec := &execContext{processes: process.NewManager(hclog.NewNullLogger())}
+	ec.processes.Close()
+	packageTask := &nodes.PackageTask{TaskID: "web#test", TaskDefinition: &fs.TaskDefinition{Retries: 3}}
+	summary := &runsummary.TaskExecutionSummary{}
+
+	err := ec.execWithRetries(gocontext.Background(), packageTask, func() *exec.Cmd { return exec.Command("true") }, cli.NewMockUi(), summary)
+	assert.ErrorIs(t, err, process.ErrClosing)
+	assert.Equal(t, summary.Attempts, 1)
+}
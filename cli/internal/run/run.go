@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -23,6 +24,7 @@ import (
 	"github.com/vercel/turbo/cli/internal/scope"
 	"github.com/vercel/turbo/cli/internal/signals"
 	"github.com/vercel/turbo/cli/internal/taskhash"
+	"github.com/vercel/turbo/cli/internal/turbopath"
 	"github.com/vercel/turbo/cli/internal/turbostate"
 	"github.com/vercel/turbo/cli/internal/ui"
 	"github.com/vercel/turbo/cli/internal/util"
@@ -50,13 +52,19 @@ func ExecuteRun(ctx gocontext.Context, helper *cmdutil.Helper, signalWatcher *si
 	}
 	tasks := args.Command.Run.Tasks
 	passThroughArgs := args.Command.Run.PassThroughArgs
-	if len(tasks) == 0 {
-		return errors.New("at least one task must be specified")
-	}
 	opts, err := optsFromArgs(args)
 	if err != nil {
 		return err
 	}
+	// Validating the config doesn't run tasks, so none have to be specified
+	if len(tasks) == 0 && !opts.runOpts.validateConfig {
+		return errors.New("at least one task must be specified")
+	}
+	if opts.runOpts.outputNDJSON {
+		// Keep stdout for the stream of tasks
+		base.UI = ui.OutputToStderr(base.UI)
+		opts.runcacheOpts.Stdout = os.Stderr
+	}
 
 	opts.runOpts.passThroughArgs = passThroughArgs
 	run := configureRun(base, opts, signalWatcher)
@@ -77,13 +85,42 @@ func optsFromArgs(args *turbostate.ParsedArgsFromRust) (*Opts, error) {
 	// Cache flags
 	opts.clientOpts.Timeout = args.RemoteCacheTimeout
 	opts.cacheOpts.SkipFilesystem = runPayload.RemoteOnly
+	opts.cacheOpts.RemoteReadOnly = runPayload.RemoteCacheReadOnly
 	opts.cacheOpts.OverrideDir = runPayload.CacheDir
 	opts.cacheOpts.Workers = runPayload.CacheWorkers
+	opts.cacheOpts.UploadConcurrency = runPayload.CacheUploadConcurrency
+	opts.cacheOpts.Backend = runPayload.CacheBackend
+	opts.runOpts.cacheKeySalt = runPayload.CacheKeySalt
+	opts.cacheOpts.SigningKey = os.Getenv(_cacheSigningKeyEnvVar)
+	if runPayload.CacheSigning == _cacheSigningRequiredValue {
+		if opts.cacheOpts.SigningKey == "" {
+			return nil, fmt.Errorf("--cache-signing=required requires a signing key, set it in the %v environment variable", _cacheSigningKeyEnvVar)
+		}
+		opts.cacheOpts.RequireSignatures = true
+	}
+	if runPayload.MaxCacheSize != "" {
+		maxSize, err := util.ParseSize(runPayload.MaxCacheSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for --max-cache-size CLI flag: %w", err)
+		}
+		opts.cacheOpts.MaxSize = maxSize
+	}
 	opts.runOpts.logPrefix = runPayload.LogPrefix
+	if runPayload.LogPrefixTemplate != "" {
+		if runPayload.LogPrefix == "none" {
+			return nil, errors.New("--log-prefix-template can't be used with --log-prefix=none")
+		}
+		if err := validateLogPrefixTemplate(runPayload.LogPrefixTemplate); err != nil {
+			return nil, err
+		}
+		opts.runOpts.logPrefixTemplate = runPayload.LogPrefixTemplate
+	}
 
 	// Runcache flags
 	opts.runcacheOpts.SkipReads = runPayload.Force
 	opts.runcacheOpts.SkipWrites = runPayload.NoCache
+	opts.runcacheOpts.DeferWrites = runPayload.DeferCacheWrites
+	opts.runcacheOpts.VerifyOutputs = runPayload.VerifyCacheOutputs
 
 	if runPayload.OutputLogs != "" {
 		err := opts.runcacheOpts.SetTaskOutputMode(runPayload.OutputLogs)
@@ -100,12 +137,128 @@ func optsFromArgs(args *turbostate.ParsedArgsFromRust) (*Opts, error) {
 		}
 		opts.runOpts.concurrency = concurrency
 	}
+	if len(runPayload.ConcurrencyGroup) > 0 {
+		concurrencyGroups, err := parseConcurrencyGroups(runPayload.ConcurrencyGroup)
+		if err != nil {
+			return nil, err
+		}
+		opts.runOpts.concurrencyGroups = concurrencyGroups
+	}
+	opts.runOpts.compressLogs = runPayload.CompressLogs
 	opts.runOpts.parallel = runPayload.Parallel
+	opts.runOpts.serialWithinPackage = runPayload.SerialWithinPackage
+	if opts.runOpts.serialWithinPackage && opts.runOpts.parallel {
+		return nil, errors.New("--serial-within-package can't be used with --parallel, which runs every task at once")
+	}
 	opts.runOpts.profile = runPayload.Profile
 	opts.runOpts.continueOnError = runPayload.ContinueExecution
 	opts.runOpts.only = runPayload.Only
 	opts.runOpts.noDaemon = runPayload.NoDaemon
+	opts.runOpts.noLockfileCache = runPayload.NoLockfileCache
+	opts.runOpts.noWorkspaceCache = runPayload.NoWorkspaceCache
 	opts.runOpts.singlePackage = args.Command.Run.SinglePackage
+	opts.runOpts.checkReproducible = runPayload.CheckReproducible
+	opts.runOpts.affectedOutput = runPayload.AffectedOutput
+	opts.runOpts.isolateOutputs = runPayload.IsolateOutputs
+	opts.runOpts.streamLogsTo = runPayload.StreamLogsTo
+	if runPayload.LogFileName != "" {
+		if runPayload.StreamLogsTo == "" {
+			return nil, fmt.Errorf("--log-file-name requires --stream-logs-to")
+		}
+		if !isPerTaskLogFileName(runPayload.LogFileName) {
+			return nil, fmt.Errorf("--log-file-name must contain {hash}, or both {package} and {task}, so that each task writes its own file")
+		}
+	}
+	opts.runOpts.logFileName = runPayload.LogFileName
+	opts.runOpts.outputNDJSON = runPayload.Output == _outputNDJSONValue
+	// The preamble isn't part of the stream of tasks
+	opts.runOpts.noPreamble = runPayload.NoPreamble || opts.runOpts.outputNDJSON
+	opts.runOpts.errorsJSON = runPayload.Errors == _errorFormatJSONValue
+	if runPayload.GithubAnnotations != nil {
+		opts.runOpts.githubAnnotations = *runPayload.GithubAnnotations
+	}
+	opts.runOpts.detectGithubAnnotations = runPayload.GithubAnnotations == nil && runPayload.LogOrder == ""
+	if opts.runOpts.githubAnnotations && runPayload.LogOrder == _logOrderStreamValue {
+		return nil, errors.New("--github-annotations can't be used with --log-order=stream, since annotations group the output of each task")
+	}
+	opts.runOpts.groupLogs = runPayload.LogOrder == _logOrderGroupedValue
+	opts.runOpts.dumpInputs = runPayload.DumpInputs
+	opts.runOpts.summarizeGlobalHash = runPayload.SummarizeGlobalHash
+	opts.runOpts.summaryPath = runPayload.SummaryPath
+	opts.runOpts.taskOrderFile = runPayload.TaskOrderFile
+	opts.runOpts.completionWebhook = runPayload.CompletionWebhook
+	opts.runOpts.eventsSocket = runPayload.EventsSocket
+	opts.runOpts.sequentialPrefixColors = runPayload.SequentialPrefixColors
+	opts.runOpts.errorOnEmpty = runPayload.ErrorOnEmpty
+	opts.runOpts.strictEnv = runPayload.EnvMode == _envModeStrictValue
+	opts.runOpts.rerunDependentsOf = runPayload.RerunDependentsOf
+	opts.runOpts.requireRemoteCache = runPayload.RequireRemoteCache
+	opts.runOpts.prefetch = runPayload.Prefetch
+	if runPayload.Offline {
+		if flag := offlineConflict(runPayload); flag != "" {
+			return nil, fmt.Errorf("%v can't be used with --offline, which doesn't allow network requests", flag)
+		}
+		opts.runOpts.offline = true
+		// Remote caching is disabled whatever else is configured, including a cache backend
+		opts.cacheOpts.SkipRemote = true
+		opts.cacheOpts.Backend = ""
+		opts.runOpts.prefetch = false
+	}
+	opts.runOpts.strict = runPayload.Strict
+	opts.runOpts.failOnUndeclaredEnv = runPayload.StrictEnv
+	opts.runOpts.validateConfig = runPayload.ValidateConfig
+	if runPayload.CheckReproducible {
+		if runPayload.DeferCacheWrites {
+			return nil, fmt.Errorf("--check-reproducible can't be used with --defer-cache-writes")
+		}
+		// Tasks need to actually execute to be checked, so cache reads are bypassed
+		opts.runcacheOpts.SkipReads = true
+	}
+	if runPayload.AuditIO {
+		if err := ioAuditSupported(); err != nil {
+			return nil, err
+		}
+		opts.runOpts.auditIO = true
+		// Tasks need to actually execute to be traced
+		opts.runcacheOpts.SkipReads = true
+	}
+	if runPayload.NoOp != nil {
+		opts.runOpts.noOp = true
+		if *runPayload.NoOp != "" {
+			duration, err := time.ParseDuration(*runPayload.NoOp)
+			if err != nil || duration < 0 {
+				return nil, fmt.Errorf("invalid no-op duration: %v", *runPayload.NoOp)
+			}
+			opts.runOpts.noOpDuration = duration
+		}
+		// Nothing is executed, so there is nothing to restore or to save
+		opts.runcacheOpts.SkipReads = true
+		opts.runcacheOpts.SkipWrites = true
+	}
+
+	if runPayload.KillTimeout != "" {
+		killTimeout, err := time.ParseDuration(runPayload.KillTimeout)
+		if err != nil || killTimeout < 0 {
+			return nil, fmt.Errorf("invalid kill timeout: %v", runPayload.KillTimeout)
+		}
+		opts.runOpts.killTimeout = killTimeout
+	}
+
+	if runPayload.RunTimeout != "" {
+		runTimeout, err := time.ParseDuration(runPayload.RunTimeout)
+		if err != nil || runTimeout <= 0 {
+			return nil, fmt.Errorf("invalid run timeout: %v", runPayload.RunTimeout)
+		}
+		opts.runOpts.runTimeout = runTimeout
+	}
+
+	if runPayload.MaxLogBytesPerTask != "" {
+		maxLogBytes, err := util.ParseSize(runPayload.MaxLogBytesPerTask)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for --max-log-bytes-per-task CLI flag: %w", err)
+		}
+		opts.runOpts.maxLogBytesPerTask = maxLogBytes
+	}
 
 	// See comment on Graph in turbostate.go for an explanation on Graph's representation.
 	// If flag is passed...
@@ -128,6 +281,9 @@ func optsFromArgs(args *turbostate.ParsedArgsFromRust) (*Opts, error) {
 		} else {
 			return nil, fmt.Errorf("invalid dry-run mode: %v", runPayload.DryRun)
 		}
+		if opts.runOpts.outputNDJSON {
+			return nil, errors.New("--output=ndjson streams the tasks of a real run, use --dry=json to print the tasks of a dry run")
+		}
 	}
 
 	return opts, nil
@@ -138,16 +294,31 @@ func configureRun(base *cmdutil.CmdBase, opts *Opts, signalWatcher *signals.Watc
 		opts.runcacheOpts.SkipReads = true
 	}
 
-	if os.Getenv("TURBO_REMOTE_ONLY") == "true" {
+	if os.Getenv("TURBO_REMOTE_ONLY") == "true" && !opts.runOpts.offline {
 		opts.cacheOpts.SkipFilesystem = true
 	}
 
+	if os.Getenv("TURBO_REMOTE_CACHE_READ_ONLY") == "true" {
+		opts.cacheOpts.RemoteReadOnly = true
+	}
+
+	if opts.runOpts.cacheKeySalt == "" {
+		opts.runOpts.cacheKeySalt = os.Getenv(_cacheKeySaltEnvVar)
+	}
+
 	if os.Getenv("TURBO_RUN_SUMMARY") == "true" {
 		opts.runOpts.summarize = true
 	}
 
+	if opts.runOpts.detectGithubAnnotations && os.Getenv("GITHUB_ACTIONS") == "true" {
+		opts.runOpts.githubAnnotations = true
+	}
+
 	processes := process.NewManager(base.Logger.Named("processes"))
-	signalWatcher.AddOnClose(processes.Close)
+	processes.SetKillTimeout(opts.runOpts.killTimeout)
+	signalWatcher.AddOnClose(func() {
+		processes.CloseWithReason("interrupted")
+	})
 	return &run{
 		base:      base,
 		opts:      opts,
@@ -159,6 +330,8 @@ type run struct {
 	base      *cmdutil.CmdBase
 	opts      *Opts
 	processes *process.Manager
+	// watched is the state of turbo watch across runs, nil for turbo run
+	watched *watchState
 }
 
 func (r *run) run(ctx gocontext.Context, targets []string) error {
@@ -170,10 +343,21 @@ func (r *run) run(ctx gocontext.Context, targets []string) error {
 	}
 
 	var pkgDepGraph *context.Context
-	if r.opts.runOpts.singlePackage {
+	if r.watched != nil && r.watched.pkgDepGraph != nil {
+		// turbo watch reuses the package graph until a file it is built from changes
+		pkgDepGraph = r.watched.pkgDepGraph
+	} else if r.opts.runOpts.singlePackage {
 		pkgDepGraph, err = context.SinglePackageGraph(r.base.RepoRoot, rootPackageJSON)
 	} else {
-		pkgDepGraph, err = context.BuildPackageGraph(r.base.RepoRoot, rootPackageJSON)
+		var lockfileCacheDir turbopath.AbsoluteSystemPath
+		if !r.opts.runOpts.noLockfileCache {
+			lockfileCacheDir = cache.DefaultLocation(r.base.RepoRoot)
+		}
+		var workspaceCacheDir turbopath.AbsoluteSystemPath
+		if !r.opts.runOpts.noWorkspaceCache {
+			workspaceCacheDir = cache.DefaultLocation(r.base.RepoRoot)
+		}
+		pkgDepGraph, err = context.BuildPackageGraph(r.base.RepoRoot, rootPackageJSON, lockfileCacheDir, workspaceCacheDir)
 	}
 	if err != nil {
 		var warnings *context.Warnings
@@ -183,6 +367,9 @@ func (r *run) run(ctx gocontext.Context, targets []string) error {
 			return err
 		}
 	}
+	if r.watched != nil {
+		r.watched.pkgDepGraph = pkgDepGraph
+	}
 
 	if ui.IsCI && !r.opts.runOpts.noDaemon {
 		r.base.Logger.Info("skipping turbod since we appear to be in a non-interactive context")
@@ -210,6 +397,7 @@ func (r *run) run(ctx gocontext.Context, targets []string) error {
 		RootNode:        pkgDepGraph.RootNode,
 		TaskDefinitions: map[string]*fs.TaskDefinition{},
 		RepoRoot:        r.base.RepoRoot,
+		CompressLogs:    r.opts.runOpts.compressLogs,
 	}
 
 	turboJSON, err := g.GetTurboConfigFromWorkspace(util.RootPkgName, r.opts.runOpts.singlePackage)
@@ -217,8 +405,18 @@ func (r *run) run(ctx gocontext.Context, targets []string) error {
 		return err
 	}
 
+	if err := turboJSON.CheckTurboVersion(r.base.TurboVersion); err != nil {
+		return err
+	}
+
+	if r.opts.runOpts.validateConfig {
+		return r.validateConfig(g)
+	}
+
 	// TODO: these values come from a config file, hopefully viper can help us merge these
 	r.opts.cacheOpts.RemoteCacheOpts = turboJSON.RemoteCacheOptions
+	r.opts.runOpts.exitCodeCategories = turboJSON.ExitCodeCategories
+	r.opts.runOpts.taskHooks = turboJSON.TaskHooks
 
 	pipeline := turboJSON.Pipeline
 	g.Pipeline = pipeline
@@ -245,34 +443,62 @@ func (r *run) run(ctx gocontext.Context, targets []string) error {
 			}
 		}
 	}
+	var rerunTasks util.Set
+	if r.watched != nil && r.watched.rerunTasks != nil {
+		rerunTasks = r.watched.rerunTasks
+		filteredPkgs = filteredPkgs.Intersection(r.watched.rerunPackages())
+	}
 
-	globalHashable, err := calculateGlobalHash(
-		r.base.RepoRoot,
-		rootPackageJSON,
-		pipeline,
-		turboJSON.GlobalEnv,
-		turboJSON.GlobalDeps,
-		pkgDepGraph.PackageManager,
-		pkgDepGraph.Lockfile,
-		r.base.Logger,
-	)
+	var globalHashable GlobalHashable
+	if r.watched != nil && r.watched.globalHashable != nil {
+		// turbo watch reuses the global hash until a file it is built from changes
+		globalHashable = *r.watched.globalHashable
+	} else {
+		globalHashable, err = calculateGlobalHash(
+			r.base.RepoRoot,
+			rootPackageJSON,
+			pipeline,
+			turboJSON.GlobalEnv,
+			turboJSON.GlobalPassThroughEnv,
+			turboJSON.GlobalDeps,
+			turboJSON.GlobalToolVersions,
+			r.opts.runOpts.cacheKeySalt,
+			pkgDepGraph.PackageManager,
+			pkgDepGraph.Lockfile,
+			r.base.Logger,
+		)
 
-	if err != nil {
-		return fmt.Errorf("failed to collect global hash inputs: %v", err)
+		if err != nil {
+			return fmt.Errorf("failed to collect global hash inputs: %v", err)
+		}
+		if r.watched != nil {
+			r.watched.globalHashable = &globalHashable
+		}
 	}
 
 	if globalHash, err := fs.HashObject(getGlobalHashable(globalHashable)); err == nil {
 		r.base.Logger.Debug("global hash", "value", globalHash)
 		g.GlobalHash = globalHash
+		g.GlobalEnvVars = globalHashable.envVars.All
+		g.GlobalPassThroughEnv = turboJSON.GlobalPassThroughEnv
 	} else {
 		return fmt.Errorf("failed to calculate global hash: %v", err)
 	}
 
+	if r.opts.runOpts.summarizeGlobalHash {
+		return printGlobalHashInputs(os.Stdout, g.GlobalHash, globalHashable)
+	}
+
+	if !r.opts.runOpts.dryRun && r.opts.runOpts.affectedOutput == "" {
+		r.reportGlobalFileChanges(globalHashable.globalFileHashMap)
+	}
+
 	r.base.Logger.Debug("local cache folder", "path", r.opts.cacheOpts.OverrideDir)
 
 	rs := &runSpec{
 		Targets:      targets,
 		FilteredPkgs: filteredPkgs,
+		RerunTasks:   rerunTasks,
 		Opts:         r.opts,
 	}
 	packageManager := pkgDepGraph.PackageManager
@@ -287,11 +513,29 @@ func (r *run) run(ctx gocontext.Context, targets []string) error {
 		return errors.Wrap(err, "error preparing engine")
 	}
 
+	if r.watched != nil {
+		if err := validateWatchedTasks(engine, g.TaskDefinitions); err != nil {
+			return err
+		}
+	}
+
+	// Dry runs, graphs and affected packages of an empty run are still meaningful, but
+	// executing one would only print banners, which makes a broken filter look like a
+	// successful run
+	if !rs.Opts.runOpts.dryRun && !rs.Opts.runOpts.graphDot && rs.Opts.runOpts.graphFile == "" && rs.Opts.runOpts.affectedOutput == "" && countTasks(engine) == 0 {
+		if rs.Opts.runOpts.errorOnEmpty {
+			return fmt.Errorf("no tasks in scope after filtering (%v packages in scope)", filteredPkgs.Len())
+		}
+		r.base.UI.Warn(fmt.Sprintf("• No tasks in scope after filtering (%v packages in scope), nothing to run", filteredPkgs.Len()))
+		return nil
+	}
+
 	taskHashTracker := taskhash.NewTracker(
 		g.RootNode,
 		g.GlobalHash,
 		// TODO(mehulkar): remove g,Pipeline, because we need to get task definitions from CompleteGaph instead
 		g.Pipeline,
+		g.GlobalPassThroughEnv,
 	)
 
 	g.TaskHashTracker = taskHashTracker
@@ -309,6 +553,16 @@ func (r *run) run(ctx gocontext.Context, targets []string) error {
 		return errors.Wrap(err, "error hashing package files")
 	}
 
+	if err := r.checkTrackedOutputs(g, engine.TaskGraph.Vertices()); err != nil {
+		return err
+	}
+
+	if rs.Opts.runOpts.strictEnv || rs.Opts.runOpts.failOnUndeclaredEnv {
+		if err := r.checkUndeclaredEnv(g, engine.TaskGraph.Vertices(), turboJSON.GlobalEnv); err != nil {
+			return err
+		}
+	}
+
 	// If we are running in parallel, then we remove all the edges in the graph
 	// except for the root. Rebuild the task graph for backwards compatibility.
 	// We still use dependencies specified by the pipeline configuration.
@@ -328,6 +582,12 @@ func (r *run) run(ctx gocontext.Context, targets []string) error {
 		}
 	}
 
+	if r.watched != nil {
+		if err := r.watched.record(g, engine, taskHashTracker, globalHashable.globalFileHashMap); err != nil {
+			return err
+		}
+	}
+
 	// Graph Run
 	if rs.Opts.runOpts.graphFile != "" || rs.Opts.runOpts.graphDot {
 		return GraphRun(ctx, rs, engine, r.base)
@@ -335,18 +595,6 @@ func (r *run) run(ctx gocontext.Context, targets []string) error {
 
 	packagesInScope := rs.FilteredPkgs.UnsafeListOfStrings()
 	sort.Strings(packagesInScope)
-	// Initiate analytics and cache
-	analyticsClient := r.initAnalyticsClient(ctx)
-	defer analyticsClient.CloseWithTimeout(50 * time.Millisecond)
-	turboCache, err := r.initCache(ctx, rs, analyticsClient)
-
-	if err != nil {
-		if errors.Is(err, cache.ErrNoCachesEnabled) {
-			r.base.UI.Warn("No caches are enabled. You can try \"turbo login\", \"turbo link\", or ensuring you are not passing --remote-only to enable caching")
-		} else {
-			return errors.Wrap(err, "failed to set up caching")
-		}
-	}
 
 	// RunSummary contains information that is statically analyzable about
 	// the tasks that we expect to run based on the user command.
@@ -363,6 +611,25 @@ func (r *run) run(ctx gocontext.Context, targets []string) error {
 			globalHashable.pipeline,
 		),
 	)
+	summary.Simulated = rs.Opts.runOpts.noOp
+
+	// Initiate analytics and cache
+	analyticsClient := r.initAnalyticsClient(ctx)
+	defer analyticsClient.CloseWithTimeout(50 * time.Millisecond)
+	rs.Opts.cacheOpts.OnUpload = summary.RecordUpload
+	turboCache, err := r.initCache(ctx, rs, analyticsClient)
+
+	if err != nil {
+		if errors.Is(err, cache.ErrNoCachesEnabled) {
+			r.base.UI.Warn("No caches are enabled. You can try \"turbo login\", \"turbo link\", or ensuring you are not passing --remote-only to enable caching")
+		} else {
+			return errors.Wrap(err, "failed to set up caching")
+		}
+	}
+
+	if rs.Opts.runOpts.affectedOutput != "" {
+		return AffectedRun(ctx, g, rs, engine, turboCache, r.base)
+	}
 
 	// Dry Run
 	if rs.Opts.runOpts.dryRun {
@@ -395,10 +662,46 @@ func (r *run) run(ctx gocontext.Context, targets []string) error {
 	)
 }
 
+// reportGlobalFileChanges prints the global files whose hashes changed since the previous
+// run, which explains why every task missed the cache. The current hashes are then recorded
+// for the next run. Like the rest of the preamble, nothing is printed with --no-preamble.
+func (r *run) reportGlobalFileChanges(globalFileHashMap map[turbopath.AnchoredUnixPath]string) {
+	cacheDir := r.opts.cacheOpts.ResolveCacheDir(r.base.RepoRoot)
+	previous, err := readGlobalFileHashes(cacheDir)
+	if err != nil {
+		r.base.Logger.Debug("failed to read previous global file hashes", "error", err)
+	} else if previous != nil && !r.opts.runOpts.noPreamble {
+		if changed := diffGlobalFileHashes(previous, globalFileHashMap); len(changed) > 0 {
+			r.base.UI.Info(ui.Dim(fmt.Sprintf("• Global files changed since the previous run: %v", strings.Join(changed, ", "))))
+		}
+	}
+
+	if err := writeGlobalFileHashes(cacheDir, globalFileHashMap); err != nil {
+		r.base.Logger.Debug("failed to record global file hashes", "error", err)
+	}
+}
+
+// offlineConflict returns the flag that was passed, if any, that only works by reaching
+// the network
+func offlineConflict(runPayload *turbostate.RunPayload) string {
+	switch {
+	case runPayload.RequireRemoteCache:
+		return "--require-remote-cache"
+	case runPayload.RemoteOnly:
+		return "--remote-only"
+	case runPayload.CompletionWebhook != "":
+		return "--completion-webhook"
+	}
+	return ""
+}
+
 func (r *run) initAnalyticsClient(ctx gocontext.Context) analytics.Client {
 	apiClient := r.base.APIClient
+	if r.opts.runOpts.offline {
+		apiClient.SetOffline()
+	}
 	var analyticsSink analytics.Sink
-	if apiClient.IsLinked() {
+	if apiClient.IsLinked() && !r.opts.runOpts.offline {
 		analyticsSink = apiClient
 	} else {
 		r.opts.cacheOpts.SkipRemote = true
@@ -413,7 +716,9 @@ func (r *run) initCache(ctx gocontext.Context, rs *runSpec, analyticsClient anal
 	// Theoretically this is overkill, but bias towards not spamming the console
 	once := &sync.Once{}
 
-	return cache.New(rs.Opts.cacheOpts, r.base.RepoRoot, apiClient, analyticsClient, func(_cache cache.Cache, err error) {
+	cacheOpts := rs.Opts.cacheOpts
+	cacheOpts.Logger = r.base.Logger
+	return cache.New(cacheOpts, r.base.RepoRoot, apiClient, analyticsClient, func(_cache cache.Cache, err error) {
 		// Currently the HTTP Cache is the only one that can be disabled.
 		// With a cache system refactor, we might consider giving names to the caches so
 		// we can accurately report them here.
@@ -423,6 +728,18 @@ func (r *run) initCache(ctx gocontext.Context, rs *runSpec, analyticsClient anal
 	})
 }
 
+// countTasks returns the number of tasks in the engine's task graph, leaving out the
+// placeholder root node
+func countTasks(engine *core.Engine) int {
+	count := 0
+	for _, v := range engine.TaskGraph.Vertices() {
+		if taskID, ok := v.(string); ok && taskID != core.ROOT_NODE_NAME {
+			count++
+		}
+	}
+	return count
+}
+
 func buildTaskGraphEngine(
 	g *graph.CompleteGraph,
 	rs *runSpec,
@@ -453,6 +770,16 @@ func buildTaskGraphEngine(
 		return nil, fmt.Errorf("Invalid persistent task dependency:\n%v", err)
 	}
 
+	// Check that interactive tasks have the terminal to themselves
+	if err := validateInteractiveTasks(engine, g.TaskDefinitions, rs.Opts.runOpts.concurrency, rs.Opts.runOpts.parallel); err != nil {
+		return nil, err
+	}
+
+	// Check that persistent tasks don't take every slot of their concurrency group
+	if err := validateConcurrencyGroups(engine, g.TaskDefinitions, rs.Opts.runOpts.concurrencyGroups); err != nil {
+		return nil, err
+	}
+
 	return engine, nil
 }
 
@@ -463,3 +790,29 @@ const (
 	_dryRunJSONValue = "Json"
 	_dryRunTextValue = "Text"
 )
+
+// NOTE: This *must* be kept in sync with the OutputFormat Rust enum in
+// crates/turborepo-lib/src/cli.rs
+const _outputNDJSONValue = "Ndjson"
+
+// NOTE: This *must* be kept in sync with the LogOrder Rust enum in
+// crates/turborepo-lib/src/cli.rs
+const (
+	_logOrderGroupedValue = "Grouped"
+	_logOrderStreamValue  = "Stream"
+)
+
+// NOTE: This *must* be kept in sync with the ErrorFormat Rust enum in
+// crates/turborepo-lib/src/cli.rs
+const _errorFormatJSONValue = "Json"
+
+// NOTE: This *must* be kept in sync with the CacheSigning Rust enum in
+// crates/turborepo-lib/src/cli.rs
+const _cacheSigningRequiredValue = "Required"
+
+// _cacheSigningKeyEnvVar holds the key that cache artifacts are signed with
+const _cacheSigningKeyEnvVar = "TURBO_CACHE_SIGNING_KEY"
+
+// NOTE: This *must* be kept in sync with the EnvMode Rust enum in
+// crates/turborepo-lib/src/cli.rs
+const _envModeStrictValue = "Strict"
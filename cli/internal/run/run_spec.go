@@ -3,8 +3,12 @@
 package run
 
 import (
+	"time"
+
 	"github.com/vercel/turbo/cli/internal/cache"
 	"github.com/vercel/turbo/cli/internal/client"
+	"github.com/vercel/turbo/cli/internal/fs"
+	"github.com/vercel/turbo/cli/internal/process"
 	"github.com/vercel/turbo/cli/internal/runcache"
 	"github.com/vercel/turbo/cli/internal/scope"
 	"github.com/vercel/turbo/cli/internal/util"
@@ -20,6 +24,10 @@ type runSpec struct {
 	// FilteredPkgs is the list of packages that are relevant for this run.
 	FilteredPkgs util.Set
 
+	// RerunTasks are the tasks that turbo watch re-runs because their inputs changed, or nil to
+	// run every task. The other tasks of the run are hashed, but not run.
+	RerunTasks util.Set
+
 	// Opts contains various opts, gathered from CLI flags,
 	// but bucketed in smaller structs based on what they mean.
 	Opts *Opts
@@ -50,6 +58,7 @@ func getDefaultOptions() *Opts {
 	return &Opts{
 		runOpts: runOpts{
 			concurrency: 10,
+			killTimeout: process.DefaultKillTimeout,
 		},
 		clientOpts: client.Opts{
 			Timeout: client.ClientTimeout,
@@ -63,6 +72,12 @@ type runOpts struct {
 	concurrency int
 	// Whether to execute in parallel (defaults to false)
 	parallel bool
+	// Whether to run at most one task of each package at a time
+	serialWithinPackage bool
+	// How many tasks of each concurrency group can run at a time
+	concurrencyGroups map[string]int
+	// Whether task logs are stored gzipped
+	compressLogs bool
 
 	// The filename to write a perf profile.
 	profile string
@@ -80,9 +95,132 @@ type runOpts struct {
 	noDaemon      bool
 	singlePackage bool
 
+	// File that the packages with tasks that would miss the cache are written to,
+	// instead of running the tasks
+	affectedOutput string
+
 	// logPrefix controls whether we should print a prefix in task logs
 	logPrefix string
 
+	// The prefix of task logs, with tokens replaced by renderLogPrefix, in place of "package:task"
+	logPrefixTemplate string
+
 	// Whether turbo should create a run summary
 	summarize bool
+
+	// Whether each task should be executed twice to verify its outputs are reproducible
+	checkReproducible bool
+
+	// Whether task logs and cache artifacts should be staged in a per-run workspace
+	isolateOutputs bool
+
+	// Directory into which the live output of each task is written, if any
+	streamLogsTo string
+	// Name of the files written into streamLogsTo, see _defaultLogFileName
+	logFileName string
+
+	// Whether a JSON object is written to stdout for each task as soon as it finishes
+	outputNDJSON bool
+
+	// Whether the lines describing the run, printed before any task runs, are left out
+	noPreamble bool
+
+	// Whether the errors printed at the end of the run are JSON objects rather than text
+	errorsJSON bool
+
+	// Whether task output is grouped and failures are annotated with GitHub Actions
+	// workflow commands
+	githubAnnotations bool
+
+	// Whether GITHUB_ACTIONS turns on githubAnnotations. It doesn't when either
+	// --github-annotations or --log-order is given.
+	detectGithubAnnotations bool
+
+	// Whether the output of each task is printed at once when the task finishes
+	groupLogs bool
+
+	// How long tasks are given to exit after SIGTERM when the run is stopped
+	killTimeout time.Duration
+
+	// How long the whole run may take before it is stopped, or zero for no limit
+	runTimeout time.Duration
+
+	// The size in bytes after which the output of a task is truncated, 0 for no limit
+	maxLogBytesPerTask int64
+
+	// Directory into which the expanded inputs of each task are written, if any
+	dumpInputs string
+
+	// cacheKeySalt is folded into the global cache key, so that changing it invalidates
+	// every cache
+	cacheKeySalt string
+
+	// Whether the inputs of the global hash are printed as JSON instead of running tasks
+	summarizeGlobalHash bool
+
+	// The file to write the run summary to when the run ends, relative to the repository
+	// root unless it is absolute
+	summaryPath string
+
+	// The file listing the tasks that start ahead of other tasks ready to run, relative to
+	// the repository root unless it is absolute
+	taskOrderFile string
+
+	// The URL that the run summary is POSTed to when the run ends, if any
+	completionWebhook string
+
+	// The Unix socket that task start and finish events are written to, if any
+	eventsSocket string
+
+	// Whether log prefixes are colored in the order packages first log, rather than
+	// by a hash of the package name
+	sequentialPrefixColors bool
+
+	// Whether a run with no tasks in scope fails, rather than exiting successfully
+	errorOnEmpty bool
+
+	// Whether tasks are only given the env vars they declare, rather than every env var
+	strictEnv bool
+
+	// Whether a task whose script uses env vars that it doesn't declare is an error, rather than a warning
+	failOnUndeclaredEnv bool
+
+	// Tasks whose dependents bypass the cache
+	rerunDependentsOf []string
+
+	// Whether the run fails when the remote cache can't be read from and written to
+	requireRemoteCache bool
+
+	// Whether remote cache artifacts are downloaded before the tasks that restore them run
+	prefetch bool
+
+	// Whether remote caching is disabled and network requests fail, for air-gapped builds
+	offline bool
+
+	// Whether task commands are replaced with simulated executions, and how
+	// long each simulated execution takes
+	noOp         bool
+	noOpDuration time.Duration
+
+	// Labels for the kinds of failures that task exit codes represent, from turbo.json
+	exitCodeCategories map[int]string
+
+	// Commands run before and after the command of every task, from turbo.json
+	taskHooks *fs.TaskHooks
+
+	// Whether the lockfile is always parsed, rather than reused from the parse cache
+	noLockfileCache bool
+
+	// Whether workspaces are always discovered, rather than reused from the previous discovery
+	noWorkspaceCache bool
+
+	// Whether configuration that can lead to inconsistent caching is an error, rather than a warning
+	strict bool
+
+	// Whether the turbo.json of every workspace is only validated, without running any tasks
+	validateConfig bool
+
+	// Whether each task is run under a syscall tracer, to compare the files it accesses
+	// against its declared inputs and outputs
+	auditIO bool
 }
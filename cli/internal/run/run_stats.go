@@ -0,0 +1,46 @@
+package run
+
+import (
+	"github.com/vercel/turbo/cli/internal/runstats"
+	"github.com/vercel/turbo/cli/internal/runsummary"
+	"github.com/vercel/turbo/cli/internal/turbopath"
+)
+
+// _statsOutcomes maps the execution statuses of tasks to the outcomes recorded for `turbo stats`
+var _statsOutcomes = map[string]string{
+	"built":  runstats.OutcomeExecuted,
+	"cached": runstats.OutcomeCached,
+}
+
+// recordStats adds the durations and cache outcomes of the tasks of a run to the history
+// reported by `turbo stats`. Runs in which no task was executed or restored are not recorded.
+func recordStats(repoRoot turbopath.AbsoluteSystemPath, runSummary *runsummary.RunSummary) error {
+	run := statsRun(runSummary)
+	if len(run.Tasks) == 0 {
+		return nil
+	}
+	return runstats.Record(runstats.Path(repoRoot), run)
+}
+
+func statsRun(runSummary *runsummary.RunSummary) *runstats.Run {
+	run := &runstats.Run{
+		ID:        runSummary.ID.String(),
+		StartedAt: runSummary.StartedAt(),
+		Tasks:     []runstats.Task{},
+	}
+	for _, task := range runSummary.Tasks {
+		if task.Execution == nil {
+			continue
+		}
+		outcome, ok := _statsOutcomes[task.Execution.Status]
+		if !ok {
+			continue
+		}
+		run.Tasks = append(run.Tasks, runstats.Task{
+			TaskID:     task.TaskID,
+			Outcome:    outcome,
+			DurationMs: task.Execution.Duration.Milliseconds(),
+		})
+	}
+	return run
+}
@@ -0,0 +1,104 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/vercel/turbo/cli/internal/turbostate"
+	"gotest.tools/v3/assert"
+)
+
+func Test_optsFromArgs_serialWithinPackage(t *testing.T) {
+	opts, err := optsFromArgs(&turbostate.ParsedArgsFromRust{
+		Command: turbostate.Command{Run: &turbostate.RunPayload{SerialWithinPackage: true}},
+	})
+	assert.NilError(t, err, "optsFromArgs")
+	assert.Assert(t, opts.runOpts.serialWithinPackage)
+
+	_, err = optsFromArgs(&turbostate.ParsedArgsFromRust{
+		Command: turbostate.Command{Run: &turbostate.RunPayload{SerialWithinPackage: true, Parallel: true}},
+	})
+	assert.Error(t, err, "--serial-within-package can't be used with --parallel, which runs every task at once")
+}
+
+func Test_optsFromArgs_githubAnnotations(t *testing.T) {
+	enabled := true
+	disabled := false
+	testCases := []struct {
+		name       string
+		payload    turbostate.RunPayload
+		want       bool
+		wantDetect bool
+	}{
+		{name: "default", payload: turbostate.RunPayload{}, want: false, wantDetect: true},
+		{name: "enabled", payload: turbostate.RunPayload{GithubAnnotations: &enabled}, want: true, wantDetect: false},
+		{name: "disabled", payload: turbostate.RunPayload{GithubAnnotations: &disabled}, want: false, wantDetect: false},
+		{name: "log order", payload: turbostate.RunPayload{LogOrder: "Stream"}, want: false, wantDetect: false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			payload := tc.payload
+			opts, err := optsFromArgs(&turbostate.ParsedArgsFromRust{
+				Command: turbostate.Command{Run: &payload},
+			})
+			assert.NilError(t, err, "optsFromArgs")
+			assert.Equal(t, opts.runOpts.githubAnnotations, tc.want)
+			assert.Equal(t, opts.runOpts.detectGithubAnnotations, tc.wantDetect)
+		})
+	}
+}
+
+func Test_optsFromArgs_githubAnnotationsLogOrder(t *testing.T) {
+	enabled := true
+	opts, err := optsFromArgs(&turbostate.ParsedArgsFromRust{
+		Command: turbostate.Command{Run: &turbostate.RunPayload{GithubAnnotations: &enabled, LogOrder: "Grouped"}},
+	})
+	assert.NilError(t, err, "optsFromArgs")
+	assert.Assert(t, opts.runOpts.githubAnnotations)
+	assert.Assert(t, opts.runOpts.groupLogs)
+
+	_, err = optsFromArgs(&turbostate.ParsedArgsFromRust{
+		Command: turbostate.Command{Run: &turbostate.RunPayload{GithubAnnotations: &enabled, LogOrder: "Stream"}},
+	})
+	assert.Error(t, err, "--github-annotations can't be used with --log-order=stream, since annotations group the output of each task")
+}
+
+func Test_optsFromArgs_noPreamble(t *testing.T) {
+	testCases := []struct {
+		name    string
+		payload turbostate.RunPayload
+		want    bool
+	}{
+		{name: "default", payload: turbostate.RunPayload{}, want: false},
+		{name: "flag", payload: turbostate.RunPayload{NoPreamble: true}, want: true},
+		// The preamble isn't part of the stream of tasks
+		{name: "ndjson", payload: turbostate.RunPayload{Output: _outputNDJSONValue}, want: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			payload := tc.payload
+			opts, err := optsFromArgs(&turbostate.ParsedArgsFromRust{
+				Command: turbostate.Command{Run: &payload},
+			})
+			assert.NilError(t, err, "optsFromArgs")
+			assert.Equal(t, opts.runOpts.noPreamble, tc.want)
+		})
+	}
+}
+
+func Test_optsFromArgs_logFileName(t *testing.T) {
+	opts, err := optsFromArgs(&turbostate.ParsedArgsFromRust{
+		Command: turbostate.Command{Run: &turbostate.RunPayload{StreamLogsTo: "logs", LogFileName: "{package}-{task}.log"}},
+	})
+	assert.NilError(t, err, "optsFromArgs")
+	assert.Equal(t, opts.runOpts.logFileName, "{package}-{task}.log")
+
+	_, err = optsFromArgs(&turbostate.ParsedArgsFromRust{
+		Command: turbostate.Command{Run: &turbostate.RunPayload{LogFileName: "{package}-{task}.log"}},
+	})
+	assert.Error(t, err, "--log-file-name requires --stream-logs-to")
+
+	_, err = optsFromArgs(&turbostate.ParsedArgsFromRust{
+		Command: turbostate.Command{Run: &turbostate.RunPayload{StreamLogsTo: "logs", LogFileName: "{task}.log"}},
+	})
+	assert.Error(t, err, "--log-file-name must contain {hash}, or both {package} and {task}, so that each task writes its own file")
+}
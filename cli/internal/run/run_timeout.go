@@ -0,0 +1,62 @@
+package run
+
+import (
+	gocontext "context"
+	"fmt"
+	"time"
+
+	"github.com/vercel/turbo/cli/internal/process"
+)
+
+// runDeadline stops a run like an interrupted run once it runs past --run-timeout. Tasks that
+// are still running, or haven't started, are recorded as canceled. The manager is closed
+// before the context is canceled, so that stopped tasks aren't reported as running past
+// timeouts of their own.
+type runDeadline struct {
+	timer     *time.Timer
+	cancel    gocontext.CancelFunc
+	processes *process.Manager
+	// reason is why the processes are closed once the deadline passes
+	reason   string
+	timedOut bool
+}
+
+// startRunDeadline returns the context to execute the tasks of a run with, which is canceled
+// once timeout passes. A timeout of zero never passes.
+func startRunDeadline(ctx gocontext.Context, timeout time.Duration, processes *process.Manager) (gocontext.Context, *runDeadline) {
+	d := &runDeadline{
+		processes: processes,
+		reason:    fmt.Sprintf("the run did not finish within its timeout of %v", timeout),
+	}
+	if timeout <= 0 {
+		d.cancel = func() {}
+		return ctx, d
+	}
+	ctx, d.cancel = gocontext.WithCancel(ctx)
+	d.timer = time.AfterFunc(timeout, func() {
+		processes.CloseWithReason(d.reason)
+		d.cancel()
+	})
+	return ctx, d
+}
+
+// stop is called once the tasks of the run are done, and returns whether the run timed out
+func (d *runDeadline) stop() bool {
+	defer d.cancel()
+	if d.timer != nil && !d.timer.Stop() {
+		// Wait for the stopped tasks to exit. The run only timed out if it wasn't already
+		// being stopped for another reason.
+		d.processes.Close()
+		d.timedOut = d.processes.CancellationReason() == d.reason
+	}
+	return d.timedOut
+}
+
+// exitCode returns the exit code of the run given that of its tasks, which is
+// ExitCodeRunTimeout when the run timed out
+func (d *runDeadline) exitCode(tasksExitCode int) int {
+	if d.timedOut {
+		return process.ExitCodeRunTimeout
+	}
+	return tasksExitCode
+}
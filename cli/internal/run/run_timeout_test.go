@@ -0,0 +1,70 @@
+package run
+
+import (
+	gocontext "context"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/mitchellh/cli"
+	"github.com/vercel/turbo/cli/internal/fs"
+	"github.com/vercel/turbo/cli/internal/nodes"
+	"github.com/vercel/turbo/cli/internal/process"
+	"github.com/vercel/turbo/cli/internal/runsummary"
+	"gotest.tools/v3/assert"
+)
+
+func Test_runDeadline(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the command uses sleep")
+	}
+	ec := &execContext{processes: process.NewManager(hclog.NewNullLogger())}
+	execCtx, deadline := startRunDeadline(gocontext.Background(), 50*time.Millisecond, ec.processes)
+	packageTask := &nodes.PackageTask{TaskID: "web#test", TaskDefinition: &fs.TaskDefinition{}}
+	summary := runsummary.NewRunSummary(time.Now(), "", "", nil, nil)
+	tracer, taskExecutionSummary := summary.TrackTask(packageTask.TaskID, "")
+
+	// The task is stopped once the run's timeout passes, and recorded as canceled like exec does
+	err := ec.execWithRetries(execCtx, packageTask, func() *exec.Cmd { return exec.Command("sleep", "10") }, cli.NewMockUi(), taskExecutionSummary)
+	assert.ErrorIs(t, err, process.ErrClosing)
+	tracer(runsummary.TargetCanceled, ec.cancellationReason())
+	assert.ErrorIs(t, execCtx.Err(), gocontext.Canceled)
+
+	assert.Assert(t, deadline.stop(), "expected the run to time out")
+	assert.Equal(t, deadline.exitCode(1), process.ExitCodeRunTimeout)
+	assert.Equal(t, deadline.exitCode(0), 125)
+	assert.Equal(t, summary.ExecutionSummary.Canceled, 1)
+	assert.Equal(t, taskExecutionSummary.CancellationReason, "the run did not finish within its timeout of 50ms")
+}
+
+func Test_runDeadline_finishesInTime(t *testing.T) {
+	processes := process.NewManager(hclog.NewNullLogger())
+	execCtx, deadline := startRunDeadline(gocontext.Background(), time.Hour, processes)
+	assert.NilError(t, processes.Exec(exec.Command("go", "version")))
+
+	assert.Assert(t, !deadline.stop(), "expected the run not to time out")
+	assert.Assert(t, !processes.IsClosing())
+	assert.Equal(t, deadline.exitCode(1), 1)
+	// The context of the run is released once it is done
+	assert.ErrorIs(t, execCtx.Err(), gocontext.Canceled)
+}
+
+func Test_runDeadline_interrupted(t *testing.T) {
+	processes := process.NewManager(hclog.NewNullLogger())
+	_, deadline := startRunDeadline(gocontext.Background(), 10*time.Millisecond, processes)
+	// A run that is already stopping for another reason doesn't time out
+	processes.CloseWithReason("interrupted")
+	time.Sleep(50 * time.Millisecond)
+
+	assert.Assert(t, !deadline.stop(), "expected the run not to time out")
+	assert.Equal(t, deadline.exitCode(130), 130)
+}
+
+func Test_runDeadline_none(t *testing.T) {
+	ctx := gocontext.Background()
+	execCtx, deadline := startRunDeadline(ctx, 0, process.NewManager(hclog.NewNullLogger()))
+	assert.Equal(t, execCtx, ctx)
+	assert.Assert(t, !deadline.stop())
+}
@@ -0,0 +1,34 @@
+package run
+
+import (
+	"sort"
+
+	"github.com/pyr-sh/dag"
+	"github.com/vercel/turbo/cli/internal/core"
+	"github.com/vercel/turbo/cli/internal/util"
+)
+
+// skippedTasks returns the sorted IDs of the tasks that never ran because a task they depend
+// on failed, or didn't run itself. visited are the IDs of the tasks that ran, and failed are
+// the IDs of those that failed.
+func skippedTasks(engine *core.Engine, visited util.Set, failed util.Set) []string {
+	skipped := []string{}
+	for _, v := range engine.TaskGraph.Vertices() {
+		taskID, ok := v.(string)
+		if !ok || taskID == core.ROOT_NODE_NAME || visited.Includes(taskID) {
+			continue
+		}
+		for _, dep := range engine.TaskGraph.DownEdges(taskID).List() {
+			depID := dag.VertexName(dep)
+			if depID == core.ROOT_NODE_NAME {
+				continue
+			}
+			if failed.Includes(depID) || !visited.Includes(depID) {
+				skipped = append(skipped, taskID)
+				break
+			}
+		}
+	}
+	sort.Strings(skipped)
+	return skipped
+}
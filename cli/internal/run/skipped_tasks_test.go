@@ -0,0 +1,30 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/pyr-sh/dag"
+	"github.com/vercel/turbo/cli/internal/core"
+	"github.com/vercel/turbo/cli/internal/util"
+	"gotest.tools/v3/assert"
+)
+
+func Test_skippedTasks(t *testing.T) {
+	engine := core.NewEngine(nil, false)
+	for _, taskID := range []string{core.ROOT_NODE_NAME, "ui#build", "web#build", "web#test", "docs#build", "docs#test", "api#build"} {
+		engine.TaskGraph.Add(taskID)
+	}
+	// An edge goes from a task to the task it depends on
+	engine.TaskGraph.Connect(dag.BasicEdge("ui#build", core.ROOT_NODE_NAME))
+	engine.TaskGraph.Connect(dag.BasicEdge("api#build", core.ROOT_NODE_NAME))
+	engine.TaskGraph.Connect(dag.BasicEdge("docs#build", core.ROOT_NODE_NAME))
+	engine.TaskGraph.Connect(dag.BasicEdge("web#build", "ui#build"))
+	engine.TaskGraph.Connect(dag.BasicEdge("web#test", "web#build"))
+	engine.TaskGraph.Connect(dag.BasicEdge("docs#test", "docs#build"))
+
+	// ui#build failed, and api#build couldn't be hashed so it never ran
+	visited := util.SetFromStrings([]string{"ui#build", "docs#build", "docs#test"})
+	failed := util.SetFromStrings([]string{"ui#build"})
+
+	assert.DeepEqual(t, skippedTasks(engine, visited, failed), []string{"web#build", "web#test"})
+}
@@ -0,0 +1,102 @@
+package run
+
+import (
+	"io"
+	"strings"
+
+	"github.com/vercel/turbo/cli/internal/fs"
+	"github.com/vercel/turbo/cli/internal/nodes"
+	"github.com/vercel/turbo/cli/internal/runcache"
+	"github.com/vercel/turbo/cli/internal/turbopath"
+)
+
+// teeWriteCloser duplicates writes across several writers, and closes all of them
+type teeWriteCloser struct {
+	io.Writer
+	closers []io.Closer
+}
+
+func (t *teeWriteCloser) Close() error {
+	var closeErr error
+	for _, closer := range t.closers {
+		if err := closer.Close(); err != nil && closeErr == nil {
+			closeErr = err
+		}
+	}
+	return closeErr
+}
+
+// _defaultLogFileName is the name of the files written into --stream-logs-to when
+// --log-file-name isn't given
+const _defaultLogFileName = "{package}/{task}.log"
+
+// isPerTaskLogFileName returns whether every task gets its own file from the given
+// --log-file-name, so that tasks don't overwrite each other's logs
+func isPerTaskLogFileName(name string) bool {
+	if strings.Contains(name, "{hash}") {
+		return true
+	}
+	return strings.Contains(name, "{package}") && strings.Contains(name, "{task}")
+}
+
+// streamedLogFile returns where the output of a task is streamed to, or an empty path when
+// logs aren't streamed
+func (ec *execContext) streamedLogFile(packageTask *nodes.PackageTask) turbopath.AbsoluteSystemPath {
+	if ec.rs.Opts.runOpts.streamLogsTo == "" {
+		return ""
+	}
+	name := ec.rs.Opts.runOpts.logFileName
+	if name == "" {
+		name = _defaultLogFileName
+	}
+	name = strings.NewReplacer(
+		"{package}", packageTask.PackageName,
+		"{task}", packageTask.Task,
+		"{hash}", packageTask.Hash,
+	).Replace(name)
+	return fs.ResolveUnknownPath(ec.repoRoot, ec.rs.Opts.runOpts.streamLogsTo).UntypedJoin(name)
+}
+
+// copyRestoredLog copies the log file of a task that was restored from the cache to where
+// its output would have been streamed, so that the directory holds the logs of every task
+func (ec *execContext) copyRestoredLog(packageTask *nodes.PackageTask, restoredLogFile turbopath.AbsoluteSystemPath) error {
+	logFile := ec.streamedLogFile(packageTask)
+	if logFile == "" || !restoredLogFile.FileExists() {
+		return nil
+	}
+	if err := logFile.EnsureDir(); err != nil {
+		return err
+	}
+	// The streamed logs are plain text, even when the restored log is compressed
+	restored, err := runcache.OpenLogFile(restoredLogFile)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = restored.Close() }()
+	file, err := logFile.Create()
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, restored); err != nil {
+		_ = file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// streamLogs tees the output of a task into the file returned by streamedLogFile, in addition
+// to the given writer. The file is not buffered, so it can be followed while the task runs.
+func (ec *execContext) streamLogs(packageTask *nodes.PackageTask, writer io.WriteCloser) (io.WriteCloser, error) {
+	logFile := ec.streamedLogFile(packageTask)
+	if err := logFile.EnsureDir(); err != nil {
+		return nil, err
+	}
+	file, err := logFile.Create()
+	if err != nil {
+		return nil, err
+	}
+	return &teeWriteCloser{
+		Writer:  io.MultiWriter(writer, file),
+		closers: []io.Closer{writer, file},
+	}, nil
+}
@@ -0,0 +1,108 @@
+package run
+
+import (
+	"compress/gzip"
+	"testing"
+
+	"github.com/vercel/turbo/cli/internal/nodes"
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"gotest.tools/v3/assert"
+)
+
+type closeRecorder struct {
+	written []byte
+	closed  bool
+}
+
+func (c *closeRecorder) Write(p []byte) (int, error) {
+	c.written = append(c.written, p...)
+	return len(p), nil
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+func streamingExecContext(repoRoot turbopath.AbsoluteSystemPath, streamLogsTo string) *execContext {
+	return &execContext{
+		repoRoot: repoRoot,
+		rs:       &runSpec{Opts: &Opts{runOpts: runOpts{streamLogsTo: streamLogsTo}}},
+	}
+}
+
+func Test_streamedLogFile(t *testing.T) {
+	repoRoot := turbopath.AbsoluteSystemPath(t.TempDir())
+	packageTask := &nodes.PackageTask{PackageName: "web", Task: "build", Hash: "abc123"}
+
+	ec := streamingExecContext(repoRoot, "")
+	assert.Equal(t, ec.streamedLogFile(packageTask), turbopath.AbsoluteSystemPath(""))
+
+	ec = streamingExecContext(repoRoot, "logs")
+	assert.Equal(t, ec.streamedLogFile(packageTask), repoRoot.UntypedJoin("logs", "web", "build.log"))
+
+	ec.rs.Opts.runOpts.logFileName = "{package}-{task}-{hash}.log"
+	assert.Equal(t, ec.streamedLogFile(packageTask), repoRoot.UntypedJoin("logs", "web-build-abc123.log"))
+}
+
+func Test_isPerTaskLogFileName(t *testing.T) {
+	testCases := map[string]bool{
+		_defaultLogFileName:    true,
+		"{package}-{task}.log": true,
+		"{hash}.log":           true,
+		"{task}.log":           false,
+		"{package}.log":        false,
+		"build.log":            false,
+	}
+	for name, want := range testCases {
+		assert.Equal(t, isPerTaskLogFileName(name), want, name)
+	}
+}
+
+func Test_streamLogs(t *testing.T) {
+	repoRoot := turbopath.AbsoluteSystemPath(t.TempDir())
+	packageTask := &nodes.PackageTask{PackageName: "web", Task: "build"}
+	ec := streamingExecContext(repoRoot, "logs")
+
+	output := &closeRecorder{}
+	writer, err := ec.streamLogs(packageTask, output)
+	assert.NilError(t, err, "streamLogs")
+	_, err = writer.Write([]byte("building\n"))
+	assert.NilError(t, err, "Write")
+
+	// The file can be followed before the task finishes
+	logFile := repoRoot.UntypedJoin("logs", "web", "build.log")
+	contents, err := logFile.ReadFile()
+	assert.NilError(t, err, "ReadFile")
+	assert.Equal(t, string(contents), "building\n")
+	assert.Equal(t, string(output.written), "building\n")
+
+	assert.NilError(t, writer.Close(), "Close")
+	assert.Assert(t, output.closed, "expected the task output to be closed")
+}
+
+func Test_copyRestoredLog(t *testing.T) {
+	repoRoot := turbopath.AbsoluteSystemPath(t.TempDir())
+	packageTask := &nodes.PackageTask{PackageName: "web", Task: "build"}
+	ec := streamingExecContext(repoRoot, "logs")
+
+	restoredLog := repoRoot.UntypedJoin("apps", "web", ".turbo", "turbo-build.log.gz")
+	assert.NilError(t, restoredLog.EnsureDir(), "EnsureDir")
+	file, err := restoredLog.Create()
+	assert.NilError(t, err, "Create")
+	gzipWriter := gzip.NewWriter(file)
+	_, err = gzipWriter.Write([]byte("restored output\n"))
+	assert.NilError(t, err, "Write")
+	assert.NilError(t, gzipWriter.Close(), "Close")
+	assert.NilError(t, file.Close(), "Close")
+
+	assert.NilError(t, ec.copyRestoredLog(packageTask, restoredLog), "copyRestoredLog")
+	contents, err := repoRoot.UntypedJoin("logs", "web", "build.log").ReadFile()
+	assert.NilError(t, err, "ReadFile")
+	assert.Equal(t, string(contents), "restored output\n")
+
+	// A task without a log has nothing to copy
+	missing := &nodes.PackageTask{PackageName: "docs", Task: "build"}
+	assert.NilError(t, ec.copyRestoredLog(missing, repoRoot.UntypedJoin("missing.log")), "copyRestoredLog")
+	assert.Assert(t, !repoRoot.UntypedJoin("logs", "docs", "build.log").FileExists())
+}
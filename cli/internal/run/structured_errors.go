@@ -0,0 +1,107 @@
+package run
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/vercel/turbo/cli/internal/graph"
+	"github.com/vercel/turbo/cli/internal/nodes"
+	"github.com/vercel/turbo/cli/internal/process"
+)
+
+// The categories of the errors written with --errors=json
+const (
+	_errorCategoryHash    = "hash"
+	_errorCategoryCache   = "cache"
+	_errorCategoryCommand = "command"
+	_errorCategoryTimeout = "timeout"
+	_errorCategoryOther   = "other"
+)
+
+// taskError is an error returned by a task, along with the task that returned it
+type taskError struct {
+	packageTask *nodes.PackageTask
+	err         error
+}
+
+func (e *taskError) Error() string {
+	return e.err.Error()
+}
+
+func (e *taskError) Unwrap() error {
+	return e.err
+}
+
+// cacheError is an error restoring a task from the cache, or capturing its logs for the cache
+type cacheError struct {
+	err error
+}
+
+func (e *cacheError) Error() string {
+	return e.err.Error()
+}
+
+func (e *cacheError) Unwrap() error {
+	return e.err
+}
+
+// structuredError describes an error of the run for tools that categorize failures
+type structuredError struct {
+	TaskID  string `json:"taskId,omitempty"`
+	Package string `json:"package,omitempty"`
+	// ExitCode is the exit code of the failed command, or 1 for errors that aren't
+	// the exit of a command, as they are counted towards the exit code of the run
+	ExitCode int    `json:"exitCode"`
+	LogFile  string `json:"logFile,omitempty"`
+	Category string `json:"category"`
+	// FailureCategory is the category configured for the exit code in exitCodeCategories
+	FailureCategory string `json:"failureCategory,omitempty"`
+	Error           string `json:"error"`
+}
+
+func newStructuredError(err error) *structuredError {
+	structured := &structuredError{
+		ExitCode: 1,
+		Category: _errorCategoryOther,
+		Error:    err.Error(),
+	}
+	failedTask := &taskError{}
+	if errors.As(err, &failedTask) {
+		structured.TaskID = failedTask.packageTask.TaskID
+		structured.Package = failedTask.packageTask.PackageName
+		structured.LogFile = failedTask.packageTask.LogFile
+	}
+	// An error from restoring the cache can wrap the exit of a postCacheRestore command
+	childExit := &process.ChildExit{}
+	exited := errors.As(err, &childExit)
+	if exited {
+		structured.ExitCode = childExit.ExitCode
+		structured.FailureCategory = childExit.Category
+	}
+	hashErr := &graph.TaskHashError{}
+	switch {
+	case errors.As(err, &hashErr):
+		structured.TaskID = hashErr.TaskID
+		structured.Package = hashErr.PackageName
+		structured.Category = _errorCategoryHash
+	case errors.As(err, new(*cacheError)):
+		structured.Category = _errorCategoryCache
+	case exited && childExit.TimedOut:
+		structured.Category = _errorCategoryTimeout
+	case exited:
+		structured.Category = _errorCategoryCommand
+	}
+	return structured
+}
+
+// writeStructuredErrors writes each error of the run to w as a JSON object on its own line
+func writeStructuredErrors(w io.Writer, errs []error) error {
+	encoder := json.NewEncoder(w)
+	for _, err := range errs {
+		if err := encoder.Encode(newStructuredError(err)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package run
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/vercel/turbo/cli/internal/graph"
+	"github.com/vercel/turbo/cli/internal/nodes"
+	"github.com/vercel/turbo/cli/internal/process"
+	"gotest.tools/v3/assert"
+)
+
+func Test_writeStructuredErrors(t *testing.T) {
+	packageTask := &nodes.PackageTask{TaskID: "web#build", PackageName: "web", LogFile: "apps/web/.turbo/turbo-build.log"}
+	errs := []error{
+		&taskError{packageTask: packageTask, err: fmt.Errorf("running web#build failed: %w", &process.ChildExit{ExitCode: 2, Command: "next build", Category: "lint"})},
+		&taskError{packageTask: packageTask, err: &process.ChildExit{ExitCode: 143, Command: "next build", TimedOut: true}},
+		&taskError{packageTask: packageTask, err: &cacheError{err: errors.New("could not open the log file")}},
+		&graph.TaskHashError{TaskID: "docs#build", PackageName: "docs", Err: errors.New("invalid inputs")},
+		errors.New("cannot find package ui for task ui#build"),
+	}
+	output := &bytes.Buffer{}
+	assert.NilError(t, writeStructuredErrors(output, errs), "writeStructuredErrors")
+
+	assert.Equal(t, output.String(), `{"taskId":"web#build","package":"web","exitCode":2,"logFile":"apps/web/.turbo/turbo-build.log","category":"command","failureCategory":"lint","error":"running web#build failed: command next build exited (2, lint)"}
+{"taskId":"web#build","package":"web","exitCode":143,"logFile":"apps/web/.turbo/turbo-build.log","category":"timeout","error":"command next build timed out"}
+{"taskId":"web#build","package":"web","exitCode":1,"logFile":"apps/web/.turbo/turbo-build.log","category":"cache","error":"could not open the log file"}
+{"taskId":"docs#build","package":"docs","exitCode":1,"category":"hash","error":"Hashing error: invalid inputs"}
+{"exitCode":1,"category":"other","error":"cannot find package ui for task ui#build"}
+`)
+}
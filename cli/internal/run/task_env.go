@@ -0,0 +1,48 @@
+package run
+
+import (
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/vercel/turbo/cli/internal/env"
+)
+
+// _strictEnvDefaults are the env vars that tasks are given with --env-mode=strict even
+// though they don't declare them, since the package manager can't run without them
+var _strictEnvDefaults = []string{"PATH"}
+
+// _windowsStrictEnvDefaults replace _strictEnvDefaults on Windows, where processes can't
+// start without SystemRoot, and cmd.exe needs ComSpec and PATHEXT to find commands
+var _windowsStrictEnvDefaults = []string{"PATH", "PATHEXT", "SYSTEMROOT", "COMSPEC", "WINDIR", "TEMP", "TMP"}
+
+// strictEnvDefaults returns the names of the env vars in environ that tasks are given by
+// default with --env-mode=strict. Env var names are case-insensitive on Windows, where PATH
+// is usually spelled Path, so they are matched regardless of case there.
+func strictEnvDefaults(goos string, environ []string) []string {
+	if goos != "windows" {
+		return append([]string{}, _strictEnvDefaults...)
+	}
+	names := []string{}
+	for _, pair := range environ {
+		name, _, _ := strings.Cut(pair, "=")
+		for _, defaultName := range _windowsStrictEnvDefaults {
+			if strings.EqualFold(name, defaultName) {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	return names
+}
+
+// strictTaskEnv returns the environment of a task run with --env-mode=strict: the env vars
+// that are part of its hash or the global hash, the env vars it passes through, and
+// the defaults from strictEnvDefaults. Any other env var is left out.
+func strictTaskEnv(hashedEnv env.EnvironmentVariableMap, globalEnv env.EnvironmentVariableMap, passThroughEnv []string) env.EnvironmentVariablePairs {
+	names := strictEnvDefaults(runtime.GOOS, os.Environ())
+	names = append(names, hashedEnv.Names()...)
+	names = append(names, globalEnv.Names()...)
+	names = append(names, passThroughEnv...)
+	return env.GetEnvVars(names).ToHashable()
+}
@@ -0,0 +1,34 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/vercel/turbo/cli/internal/env"
+	"gotest.tools/v3/assert"
+)
+
+func Test_strictTaskEnv(t *testing.T) {
+	t.Setenv("PATH", "/usr/bin")
+	t.Setenv("API_URL", "https://example.com")
+	t.Setenv("CI", "true")
+	t.Setenv("DEPLOY_REGION", "us-east-1")
+	t.Setenv("DEPLOY_SECRET", "secret")
+	t.Setenv("HOME", "/home/user")
+
+	hashedEnv := env.EnvironmentVariableMap{"API_URL": "https://example.com", "UNSET": ""}
+	globalEnv := env.EnvironmentVariableMap{"CI": "true"}
+	taskEnv := strictTaskEnv(hashedEnv, globalEnv, []string{"DEPLOY_*", "!DEPLOY_SECRET"})
+
+	assert.DeepEqual(t, taskEnv, env.EnvironmentVariablePairs{
+		"API_URL=https://example.com",
+		"CI=true",
+		"DEPLOY_REGION=us-east-1",
+		"PATH=/usr/bin",
+	})
+}
+
+func Test_strictEnvDefaults(t *testing.T) {
+	environ := []string{"Path=C:\\Windows", "SystemRoot=C:\\Windows", "ComSpec=cmd.exe", "PATHEXT=.EXE", "USERNAME=user"}
+	assert.DeepEqual(t, strictEnvDefaults("windows", environ), []string{"Path", "SystemRoot", "ComSpec", "PATHEXT"})
+	assert.DeepEqual(t, strictEnvDefaults("linux", []string{"PATH=/usr/bin", "HOME=/home/user"}), []string{"PATH"})
+}
@@ -0,0 +1,50 @@
+package run
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/vercel/turbo/cli/internal/logstreamer"
+	"github.com/vercel/turbo/cli/internal/nodes"
+	"github.com/vercel/turbo/cli/internal/process"
+	"github.com/vercel/turbo/cli/internal/ui"
+)
+
+// runTaskHook runs one of the taskHooks of turbo.json for a task that is about to run, or
+// just ran. Every line the hook prints is shown after the task's prefix and the name of the
+// hook, so that it isn't mistaken for the output of the task itself. The hook's output isn't
+// part of the task's log file.
+func (ec *execContext) runTaskHook(name string, command string, packageTask *nodes.PackageTask, prettyPrefix string, progressLogger hclog.Logger) error {
+	progressLogger.Debug("running task hook", "hook", name, "command", command)
+	cmd := taskHookCommand(command, packageTask.Pkg.Dir.ToSystemPath().RestoreAnchor(ec.repoRoot).ToString(), packageTask)
+	hookPrefix := prettyPrefix + ui.Dim(fmt.Sprintf("[%v hook] ", name))
+	logger := log.New(ec.runCache.Stdout(), "", 0)
+	stdout := logstreamer.NewLogstreamer(logger, hookPrefix, false)
+	stderr := logstreamer.NewLogstreamer(logger, hookPrefix, false)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	err := ec.processes.Exec(cmd)
+	// Closing flushes the last line, if the hook didn't end it
+	_ = stdout.Close()
+	_ = stderr.Close()
+	if err != nil {
+		return fmt.Errorf("%v hook \"%v\" failed: %w", name, command, err)
+	}
+	return nil
+}
+
+// taskHookCommand builds the command that runs a task hook via the system shell in the
+// given package directory. The hook is told which task is running, and its hash, through
+// TURBO_TASK_ID and TURBO_HASH.
+func taskHookCommand(command string, pkgDir string, packageTask *nodes.PackageTask) *exec.Cmd {
+	cmd := process.ShellCommand(command)
+	cmd.Dir = pkgDir
+	cmd.Env = append(os.Environ(),
+		fmt.Sprintf("TURBO_HASH=%v", packageTask.Hash),
+		fmt.Sprintf("TURBO_TASK_ID=%v", packageTask.TaskID),
+	)
+	return cmd
+}
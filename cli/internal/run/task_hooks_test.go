@@ -0,0 +1,29 @@
+package run
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/vercel/turbo/cli/internal/nodes"
+	"gotest.tools/v3/assert"
+)
+
+func Test_taskHookCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the hook uses a POSIX shell command")
+	}
+	// pwd prints the path with symlinks resolved
+	pkgDir, err := filepath.EvalSymlinks(t.TempDir())
+	assert.NilError(t, err)
+	packageTask := &nodes.PackageTask{TaskID: "web#build", Hash: "abc123"}
+
+	output, err := taskHookCommand("echo $TURBO_TASK_ID $TURBO_HASH && pwd", pkgDir, packageTask).Output()
+	assert.NilError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	assert.DeepEqual(t, lines, []string{"web#build abc123", pkgDir})
+
+	err = taskHookCommand("exit 3", pkgDir, packageTask).Run()
+	assert.ErrorContains(t, err, "exit status 3")
+}
@@ -0,0 +1,30 @@
+package run
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vercel/turbo/cli/internal/util"
+)
+
+// parseTaskOrder parses the contents of a --task-order-file, one task ID per line, into the
+// rank of each task. Blank lines and lines starting with # are ignored, and a task listed more
+// than once keeps its first rank. In single-package mode, tasks can be listed without a package.
+func parseTaskOrder(contents string, singlePackage bool) (map[string]int, error) {
+	ranks := make(map[string]int)
+	for i, line := range strings.Split(contents, "\n") {
+		taskID := strings.TrimSpace(line)
+		if taskID == "" || strings.HasPrefix(taskID, "#") {
+			continue
+		}
+		if singlePackage {
+			taskID = util.RootTaskID(taskID)
+		} else if !util.IsPackageTask(taskID) {
+			return nil, fmt.Errorf("invalid task %q on line %v of --task-order-file, use <package>#<task>", taskID, i+1)
+		}
+		if _, ok := ranks[taskID]; !ok {
+			ranks[taskID] = len(ranks)
+		}
+	}
+	return ranks, nil
+}
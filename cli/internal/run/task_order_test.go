@@ -0,0 +1,20 @@
+package run
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func Test_parseTaskOrder(t *testing.T) {
+	ranks, err := parseTaskOrder("# heavy tasks first\nweb#e2e\n\n  docs#build  \nweb#e2e\nweb#build\n", false)
+	assert.NilError(t, err, "parseTaskOrder")
+	assert.DeepEqual(t, ranks, map[string]int{"web#e2e": 0, "docs#build": 1, "web#build": 2})
+
+	_, err = parseTaskOrder("web#e2e\nbuild\n", false)
+	assert.Error(t, err, "invalid task \"build\" on line 2 of --task-order-file, use <package>#<task>")
+
+	ranks, err = parseTaskOrder("test\nbuild\n//#lint\n", true)
+	assert.NilError(t, err, "parseTaskOrder")
+	assert.DeepEqual(t, ranks, map[string]int{"//#test": 0, "//#build": 1, "//#lint": 2})
+}
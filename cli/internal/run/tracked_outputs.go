@@ -0,0 +1,122 @@
+package run
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pyr-sh/dag"
+	"github.com/vercel/turbo/cli/internal/doublestar"
+	"github.com/vercel/turbo/cli/internal/fs"
+	"github.com/vercel/turbo/cli/internal/graph"
+	"github.com/vercel/turbo/cli/internal/hashing"
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"github.com/vercel/turbo/cli/internal/util"
+)
+
+// _maxTrackedOutputExamples is the number of tracked files listed for each task in a warning
+const _maxTrackedOutputExamples = 3
+
+// _emptyBlobHash is the git object hash of an empty file. Empty placeholders (e.g. a .keep
+// file that keeps an output directory in git) restore identically, so they aren't reported.
+const _emptyBlobHash = "e69de29bb2d1d6434b8b29ae775ad8c2e48c5391"
+
+// checkTrackedOutputs warns about tasks whose declared outputs match files that are committed
+// to git. Such outputs are restored from the cache on top of whatever is checked out, so caches
+// diverge depending on whether the files were regenerated. With --strict, this is an error.
+func (r *run) checkTrackedOutputs(g *graph.CompleteGraph, tasks []dag.Vertex) error {
+	trackedFilesByPackage := make(map[string]map[turbopath.AnchoredUnixPath]string)
+	problems := []string{}
+
+	for _, v := range tasks {
+		taskID, ok := v.(string)
+		if !ok || taskID == g.RootNode {
+			continue
+		}
+		taskDefinition, ok := g.TaskDefinitions[taskID]
+		if !ok || !taskDefinition.ShouldCache || len(taskDefinition.Outputs.Inclusions) == 0 {
+			continue
+		}
+		pkgName, _ := util.GetPackageTaskFromId(taskID)
+		pkg, ok := g.WorkspaceInfos.PackageJSONs[pkgName]
+		if !ok {
+			continue
+		}
+
+		trackedFiles, ok := trackedFilesByPackage[pkgName]
+		if !ok {
+			files, err := hashing.GetTrackedFiles(r.base.RepoRoot, pkg.Dir)
+			if err != nil {
+				// Not every repository is a git repository, there's nothing to check
+				r.base.Logger.Debug("failed to list tracked files", "package", pkgName, "error", err)
+			}
+			trackedFiles = files
+			trackedFilesByPackage[pkgName] = trackedFiles
+		}
+
+		matches, err := trackedOutputs(trackedFiles, taskDefinition.Outputs)
+		if err != nil {
+			return err
+		}
+		if len(matches) == 0 {
+			continue
+		}
+		examples := matches
+		if len(examples) > _maxTrackedOutputExamples {
+			examples = examples[:_maxTrackedOutputExamples]
+		}
+		problems = append(problems, fmt.Sprintf("%v: %v file(s) matching its outputs are tracked by git (%v)", taskID, len(matches), strings.Join(examples, ", ")))
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	if r.opts.runOpts.strict {
+		return fmt.Errorf("task outputs must be ignored by git:\n  %v", strings.Join(problems, "\n  "))
+	}
+	for _, problem := range problems {
+		r.base.UI.Warn(fmt.Sprintf("WARNING: %v. Outputs should be ignored by git so that the cache is consistent.", problem))
+	}
+	return nil
+}
+
+// trackedOutputs returns the sorted tracked, non-empty files, anchored at the package directory,
+// that match the given outputs
+func trackedOutputs(trackedFiles map[turbopath.AnchoredUnixPath]string, outputs fs.TaskOutputs) ([]string, error) {
+	matches := []string{}
+	for file, hash := range trackedFiles {
+		if hash == _emptyBlobHash {
+			continue
+		}
+		included, err := matchesAny(outputs.Inclusions, file.ToString())
+		if err != nil {
+			return nil, err
+		}
+		if !included {
+			continue
+		}
+		excluded, err := matchesAny(outputs.Exclusions, file.ToString())
+		if err != nil {
+			return nil, err
+		}
+		if !excluded {
+			matches = append(matches, file.ToString())
+		}
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+func matchesAny(patterns []string, file string) (bool, error) {
+	for _, pattern := range patterns {
+		matched, err := doublestar.Match(pattern, file)
+		if err != nil {
+			return false, fmt.Errorf("invalid output glob %v: %w", pattern, err)
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
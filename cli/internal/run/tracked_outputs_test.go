@@ -0,0 +1,47 @@
+package run
+
+import (
+	"testing"
+
+	"github.com/vercel/turbo/cli/internal/fs"
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"gotest.tools/v3/assert"
+)
+
+func Test_trackedOutputs(t *testing.T) {
+	trackedFiles := map[turbopath.AnchoredUnixPath]string{
+		"package.json":              "cb35e8d686a3a4ad777290c436ad4dc7d9bfe9e9",
+		"src/index.ts":              "3928aa9e4a1d8cced42f4c3e3f50f9353d0b3f9b",
+		"dist/index.js":             "07bc1f54a7ba4f2be08ac0df73c1d10a17a1e89c",
+		"dist/generated/types.d.ts": "1e3e4a1f86d405d7c1b3f17b4b1ebd3867d6f5a3",
+		"dist/README.md":            "8c3bf7ff1a1ab4d7e1d9500a1045fd1e8e6c290b",
+		"dist/.keep":                _emptyBlobHash,
+	}
+
+	testCases := []struct {
+		name    string
+		outputs fs.TaskOutputs
+		want    []string
+	}{
+		{
+			name:    "outputs that don't match tracked files",
+			outputs: fs.TaskOutputs{Inclusions: []string{"build/**"}},
+			want:    []string{},
+		},
+		{
+			name:    "outputs that match tracked files",
+			outputs: fs.TaskOutputs{Inclusions: []string{"dist/**"}},
+			want:    []string{"dist/README.md", "dist/generated/types.d.ts", "dist/index.js"},
+		},
+		{
+			name:    "excluded outputs",
+			outputs: fs.TaskOutputs{Inclusions: []string{"dist/**"}, Exclusions: []string{"dist/*.md"}},
+			want:    []string{"dist/generated/types.d.ts", "dist/index.js"},
+		},
+	}
+	for _, tc := range testCases {
+		got, err := trackedOutputs(trackedFiles, tc.outputs)
+		assert.NilError(t, err, tc.name)
+		assert.DeepEqual(t, got, tc.want)
+	}
+}
@@ -0,0 +1,49 @@
+package run
+
+import (
+	"fmt"
+	"io"
+)
+
+// truncatingWriter passes the output of a task through until it reaches a limit, then writes
+// a marker and discards the rest. Writes are never failed, so the task keeps running.
+// Output is written a line at a time, so the output is truncated at the end of a line.
+type truncatingWriter struct {
+	writer io.WriteCloser
+	// limit is the number of bytes written before the output is truncated
+	limit   int64
+	written int64
+	// marker is written in place of the first write that goes over the limit
+	marker    string
+	truncated bool
+}
+
+func newTruncatingWriter(writer io.WriteCloser, limit int64) *truncatingWriter {
+	return &truncatingWriter{
+		writer: writer,
+		limit:  limit,
+		marker: fmt.Sprintf("… output truncated after %v bytes …\n", limit),
+	}
+}
+
+// Write implements io.Writer
+func (w *truncatingWriter) Write(p []byte) (int, error) {
+	if w.truncated {
+		return len(p), nil
+	}
+	if w.written+int64(len(p)) > w.limit {
+		w.truncated = true
+		if _, err := io.WriteString(w.writer, w.marker); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	n, err := w.writer.Write(p)
+	w.written += int64(n)
+	return n, err
+}
+
+// Close implements io.Closer
+func (w *truncatingWriter) Close() error {
+	return w.writer.Close()
+}
@@ -0,0 +1,32 @@
+package run
+
+import (
+	"bytes"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+type bufferCloser struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (b *bufferCloser) Close() error {
+	b.closed = true
+	return nil
+}
+
+func Test_truncatingWriter(t *testing.T) {
+	output := &bufferCloser{}
+	writer := newTruncatingWriter(output, 12)
+	for _, line := range []string{"line 1\n", "line 2\n", "line 3\n", "line 4\n"} {
+		n, err := writer.Write([]byte(line))
+		assert.NilError(t, err, "Write")
+		assert.Equal(t, n, len(line), "writes past the limit are reported as written")
+	}
+	assert.NilError(t, writer.Close(), "Close")
+
+	assert.Equal(t, output.String(), "line 1\n… output truncated after 12 bytes …\n")
+	assert.Assert(t, output.closed, "the underlying writer is closed")
+}
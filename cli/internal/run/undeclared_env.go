@@ -0,0 +1,103 @@
+package run
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pyr-sh/dag"
+	"github.com/vercel/turbo/cli/internal/env"
+	"github.com/vercel/turbo/cli/internal/graph"
+	"github.com/vercel/turbo/cli/internal/util"
+)
+
+// _scriptEnvReference matches the env vars that a script reads: $VAR and ${VAR} in sh,
+// and %VAR% in cmd.exe
+var _scriptEnvReference = regexp.MustCompile(`\$\{?([A-Za-z_][A-Za-z0-9_]*)|%([A-Za-z_][A-Za-z0-9_]*)%`)
+
+// _scriptEnvAssignment matches the env vars that a script sets itself, like FOO=bar in
+// "FOO=bar next build" or "export FOO=bar && ..."
+var _scriptEnvAssignment = regexp.MustCompile(`(?:^|[\s;&|(])([A-Za-z_][A-Za-z0-9_]*)=`)
+
+// _scriptProvidedEnv are the env vars that every script is given without declaring them,
+// by turbo or by the package manager that runs the script
+var _scriptProvidedEnv = []string{"TURBO_HASH", "INIT_CWD", "npm_*"}
+
+// checkUndeclaredEnv warns about tasks whose scripts reference env vars that they don't
+// declare, in their "env" or "passThroughEnv", or globally. With --env-mode=strict those
+// env vars are unset when the task runs, and otherwise they change its outputs without
+// changing its hash. This is a heuristic, env vars read by the programs the script runs
+// aren't found. With --strict-env, this is an error.
+func (r *run) checkUndeclaredEnv(g *graph.CompleteGraph, tasks []dag.Vertex, globalEnv []string) error {
+	problems := []string{}
+	for _, v := range tasks {
+		taskID, ok := v.(string)
+		if !ok || taskID == g.RootNode {
+			continue
+		}
+		taskDefinition, ok := g.TaskDefinitions[taskID]
+		if !ok {
+			continue
+		}
+		pkgName, taskName := util.GetPackageTaskFromId(taskID)
+		pkg, ok := g.WorkspaceInfos.PackageJSONs[pkgName]
+		if !ok {
+			continue
+		}
+		script, ok := pkg.Scripts[taskName]
+		if !ok {
+			continue
+		}
+
+		declared := append([]string{}, _strictEnvDefaults...)
+		declared = append(declared, _scriptProvidedEnv...)
+		declared = append(declared, globalEnv...)
+		declared = append(declared, g.GlobalPassThroughEnv...)
+		declared = append(declared, taskDefinition.EnvVarDependencies...)
+		declared = append(declared, taskDefinition.PassThroughEnv...)
+		undeclared := []string{}
+		for _, name := range scriptEnvReferences(script) {
+			if !env.IsMatched(name, declared) {
+				undeclared = append(undeclared, name)
+			}
+		}
+		if len(undeclared) > 0 {
+			problems = append(problems, fmt.Sprintf("%v: its script uses %v, which it doesn't declare", taskID, strings.Join(undeclared, ", ")))
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	if r.opts.runOpts.failOnUndeclaredEnv {
+		return fmt.Errorf("tasks must declare the env vars they use in \"env\" or \"passThroughEnv\":\n  %v", strings.Join(problems, "\n  "))
+	}
+	for _, problem := range problems {
+		r.base.UI.Warn(fmt.Sprintf("WARNING: %v. Add them to \"env\" if they affect the task's outputs, or to \"passThroughEnv\" if they don't.", problem))
+	}
+	return nil
+}
+
+// scriptEnvReferences returns the sorted names of the env vars that the script references,
+// leaving out those that it assigns itself
+func scriptEnvReferences(script string) []string {
+	assigned := make(util.Set)
+	for _, match := range _scriptEnvAssignment.FindAllStringSubmatch(script, -1) {
+		assigned.Add(match[1])
+	}
+	references := make(util.Set)
+	for _, match := range _scriptEnvReference.FindAllStringSubmatch(script, -1) {
+		name := match[1]
+		if name == "" {
+			name = match[2]
+		}
+		if !assigned.Includes(name) {
+			references.Add(name)
+		}
+	}
+	names := references.UnsafeListOfStrings()
+	sort.Strings(names)
+	return names
+}
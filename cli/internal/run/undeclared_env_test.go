@@ -0,0 +1,41 @@
+package run
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func Test_scriptEnvReferences(t *testing.T) {
+	testCases := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{
+			name:   "no env vars",
+			script: "tsc --build",
+			want:   []string{},
+		},
+		{
+			name:   "sh references",
+			script: "next build --base ${BASE_PATH} && echo $API_URL $1",
+			want:   []string{"API_URL", "BASE_PATH"},
+		},
+		{
+			name:   "cmd.exe references",
+			script: "node build.js %API_URL% && echo $API_URL",
+			want:   []string{"API_URL"},
+		},
+		{
+			name:   "env vars that the script sets",
+			script: "NODE_ENV=production next build && export OUT=dist; cp -r $OUT $DEST",
+			want:   []string{"DEST"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.DeepEqual(t, scriptEnvReferences(tc.script), tc.want)
+		})
+	}
+}
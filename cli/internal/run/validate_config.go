@@ -0,0 +1,47 @@
+package run
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/vercel/turbo/cli/internal/core"
+	"github.com/vercel/turbo/cli/internal/graph"
+	"github.com/vercel/turbo/cli/internal/ui"
+	"github.com/vercel/turbo/cli/internal/util"
+)
+
+// validateConfig reads the turbo.json of every workspace that has one, which checks it
+// against the keys and types turbo.json supports, and reports every workspace whose
+// turbo.json is invalid. The root turbo.json has already been read by the time it is called.
+func (r *run) validateConfig(g *graph.CompleteGraph) error {
+	workspaces := make([]string, 0, len(g.WorkspaceInfos.PackageJSONs))
+	for name := range g.WorkspaceInfos.PackageJSONs {
+		if name != util.RootPkgName {
+			workspaces = append(workspaces, name)
+		}
+	}
+	sort.Strings(workspaces)
+
+	problems := []string{}
+	for _, name := range workspaces {
+		turboJSON, err := g.GetTurboConfigFromWorkspace(name, r.opts.runOpts.singlePackage)
+		if errors.Is(err, os.ErrNotExist) {
+			// A turbo.json is optional in workspaces
+			continue
+		}
+		if err == nil {
+			err = core.ValidateWorkspaceTurboJSON(turboJSON)
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%v: %v", name, err))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid turbo.json in %v workspace(s):\n%v", len(problems), strings.Join(problems, "\n"))
+	}
+	r.base.UI.Output(ui.Dim("• turbo.json is valid in the root and every workspace"))
+	return nil
+}
@@ -0,0 +1,417 @@
+package run
+
+import (
+	gocontext "context"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/pyr-sh/dag"
+	"github.com/vercel/turbo/cli/internal/cmdutil"
+	"github.com/vercel/turbo/cli/internal/context"
+	"github.com/vercel/turbo/cli/internal/core"
+	"github.com/vercel/turbo/cli/internal/filewatcher"
+	"github.com/vercel/turbo/cli/internal/fs"
+	"github.com/vercel/turbo/cli/internal/graph"
+	"github.com/vercel/turbo/cli/internal/hashing"
+	"github.com/vercel/turbo/cli/internal/nodes"
+	"github.com/vercel/turbo/cli/internal/signals"
+	"github.com/vercel/turbo/cli/internal/taskhash"
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"github.com/vercel/turbo/cli/internal/turbostate"
+	"github.com/vercel/turbo/cli/internal/ui"
+	"github.com/vercel/turbo/cli/internal/util"
+)
+
+// _watchDebounce is how long files have to stay unchanged before tasks are re-run, so that
+// a burst of changes, like a branch checkout or a formatter, re-runs the tasks once
+const _watchDebounce = 200 * time.Millisecond
+
+// _watchConfigFiles are the files of the repository root that configure every task.
+// A change to one of them re-runs every task.
+var _watchConfigFiles = []turbopath.AnchoredUnixPath{"package.json", "turbo.json"}
+
+// ExecuteWatch executes the watch command, which runs the tasks and then re-runs the tasks
+// whose inputs changed each time files are edited
+func ExecuteWatch(ctx gocontext.Context, helper *cmdutil.Helper, signalWatcher *signals.Watcher, args *turbostate.ParsedArgsFromRust) error {
+	// watch takes the same arguments as run, and the options are read from the run payload
+	args.Command.Run = args.Command.Watch
+	base, err := helper.GetCmdBase(args)
+	if err != nil {
+		return err
+	}
+	tasks := args.Command.Run.Tasks
+	if len(tasks) == 0 {
+		return errors.New("at least one task must be specified")
+	}
+	opts, err := optsFromArgs(args)
+	if err != nil {
+		return err
+	}
+	if err := validateWatchOpts(opts); err != nil {
+		return err
+	}
+	if opts.runOpts.outputNDJSON {
+		// Keep stdout for the stream of tasks
+		base.UI = ui.OutputToStderr(base.UI)
+		opts.runcacheOpts.Stdout = os.Stderr
+	}
+	opts.runOpts.passThroughArgs = args.Command.Run.PassThroughArgs
+	run := configureRun(base, opts, signalWatcher)
+	run.watched = newWatchState()
+
+	backend, err := filewatcher.GetPlatformSpecificBackend(base.Logger)
+	if err != nil {
+		return err
+	}
+	// Watching starts before the first run, so that edits made while it runs aren't missed
+	fileWatcher := filewatcher.New(base.Logger.Named("FileWatcher"), base.RepoRoot, backend)
+	changes := newFileChanges(base.RepoRoot)
+	fileWatcher.AddClient(changes)
+	if err := fileWatcher.Start(); err != nil {
+		return errors.Wrapf(err, "watching %v", base.RepoRoot)
+	}
+	defer func() { _ = fileWatcher.Close() }()
+
+	for {
+		// A failed run is reported, and the tasks are run again once files change
+		if err := run.run(ctx, tasks); errors.Is(err, errWatchPersistentTasks) {
+			return err
+		} else if err != nil {
+			base.LogError("run failed: %v", err)
+		}
+		base.UI.Output(ui.Dim("• Watching for changes..."))
+		for {
+			changed, ok := changes.wait(signalWatcher.Done())
+			if !ok {
+				return nil
+			}
+			if run.watched.changesGraph(changed) {
+				run.watched.forgetGraph()
+			}
+			affected, globalChange := run.watched.affectedTasks(base.RepoRoot, changed)
+			if globalChange != "" {
+				base.UI.Output(ui.Dim(fmt.Sprintf("• %v changed, re-running every task", globalChange)))
+				run.watched.forgetGraph()
+				run.watched.rerunTasks = nil
+				break
+			}
+			if affected.Len() > 0 {
+				base.UI.Output(ui.Dim(fmt.Sprintf("• Inputs of %v task(s) changed, re-running them", affected.Len())))
+				run.watched.rerunTasks = affected
+				break
+			}
+		}
+	}
+}
+
+// validateWatchOpts checks that the options of turbo watch apply to a run that is repeated
+func validateWatchOpts(opts *Opts) error {
+	if opts.runOpts.dryRun || opts.runOpts.graphDot || opts.runOpts.graphFile != "" || opts.runOpts.affectedOutput != "" || opts.runOpts.summarizeGlobalHash {
+		return errors.New("turbo watch runs tasks, it can't be used with --dry-run, --graph, --affected-output or --summarize-global-hash")
+	}
+	// A run that times out closes the process manager that every run of turbo watch shares
+	if opts.runOpts.runTimeout > 0 {
+		return errors.New("turbo watch keeps running until it is stopped, it can't be used with --run-timeout")
+	}
+	return nil
+}
+
+// watchState holds what turbo watch knows about the tasks of its runs so far, to map the
+// files that change back to the tasks that hash them
+type watchState struct {
+	// global are the files in the global hash, keyed by their path from the repository root,
+	// and their hashes. A change to one of them re-runs every task.
+	global map[turbopath.AnchoredUnixPath]string
+	// inputs maps each task of the runs so far to the hashes of its input files, keyed by
+	// their path from the repository root
+	inputs map[string]map[turbopath.AnchoredUnixPath]string
+	// inputSpecs maps each task of the runs so far to where its input files come from, to
+	// find the inputs that are added after it ran
+	inputSpecs map[string]*watchedInputSpec
+	// dependents maps each task of the runs so far to the tasks that depend on it, directly
+	// or transitively, whose hashes change along with it
+	dependents map[string][]string
+	// rerunTasks limits the tasks that the next run runs, or is nil for the next run to run
+	// every task in scope. The other tasks are still hashed, for the tasks that depend on them.
+	rerunTasks util.Set
+	// pkgDepGraph and globalHashable are those of the last run, which the next run reuses
+	// unless a file that they are built from changed. Both are nil before the first run.
+	pkgDepGraph    *context.Context
+	globalHashable *GlobalHashable
+}
+
+// errWatchPersistentTasks is returned for a run of turbo watch with persistent tasks
+var errWatchPersistentTasks = errors.New("turbo watch can't run persistent tasks, since they never exit and the tasks would never be re-run")
+
+// validateWatchedTasks checks that the tasks of a run of turbo watch finish, so that they can be
+// re-run when files change
+func validateWatchedTasks(engine *core.Engine, taskDefinitions map[string]*fs.TaskDefinition) error {
+	persistent := []string{}
+	for _, v := range engine.TaskGraph.Vertices() {
+		taskID := dag.VertexName(v)
+		if taskDefinition, ok := taskDefinitions[taskID]; ok && taskDefinition.Persistent {
+			persistent = append(persistent, taskID)
+		}
+	}
+	if len(persistent) == 0 {
+		return nil
+	}
+	sort.Strings(persistent)
+	return fmt.Errorf("%w: %v", errWatchPersistentTasks, strings.Join(persistent, ", "))
+}
+
+// rerunPackages returns the packages of the tasks that the next run runs, or nil for the next
+// run to run every package in scope
+func (w *watchState) rerunPackages() util.Set {
+	if w.rerunTasks == nil {
+		return nil
+	}
+	packages := make(util.Set)
+	for _, taskID := range w.rerunTasks.UnsafeListOfStrings() {
+		pkg, _ := util.GetPackageTaskFromId(taskID)
+		packages.Add(pkg)
+	}
+	return packages
+}
+
+// changesGraph is whether a changed file can change the package graph or the global hash of the
+// last run: the package.json of a workspace, which can add or remove a workspace or change its
+// dependencies, or the lockfile
+func (w *watchState) changesGraph(changed []turbopath.AnchoredUnixPath) bool {
+	lockfile := ""
+	if w.pkgDepGraph != nil && w.pkgDepGraph.PackageManager != nil {
+		lockfile = w.pkgDepGraph.PackageManager.Lockfile
+	}
+	for _, file := range changed {
+		if path.Base(file.ToString()) == "package.json" || (lockfile != "" && file.ToString() == lockfile) {
+			return true
+		}
+	}
+	return false
+}
+
+// forgetGraph makes the next run build the package graph and the global hash again
+func (w *watchState) forgetGraph() {
+	w.pkgDepGraph = nil
+	w.globalHashable = nil
+}
+
+// watchedInputSpec is the package and the input patterns of a task
+type watchedInputSpec struct {
+	pkg    *fs.PackageJSON
+	inputs []string
+}
+
+// key identifies the files of the spec, which tasks with the same package and inputs share
+func (s *watchedInputSpec) key() string {
+	return fmt.Sprintf("%v#%v", s.pkg.Dir.ToUnixPath(), strings.Join(s.inputs, "!"))
+}
+
+func newWatchState() *watchState {
+	return &watchState{
+		inputs:     make(map[string]map[turbopath.AnchoredUnixPath]string),
+		inputSpecs: make(map[string]*watchedInputSpec),
+		dependents: make(map[string][]string),
+	}
+}
+
+// record updates the inputs of the tasks of a run once their files are hashed. Tasks left out
+// of the run keep the inputs of the run that last ran them.
+func (w *watchState) record(g *graph.CompleteGraph, engine *core.Engine, tracker *taskhash.Tracker, globalFileHashMap map[turbopath.AnchoredUnixPath]string) error {
+	w.global = globalFileHashMap
+	for _, v := range engine.TaskGraph.Vertices() {
+		taskID, ok := v.(string)
+		if !ok || taskID == core.ROOT_NODE_NAME {
+			continue
+		}
+		pkgName, _ := util.GetPackageTaskFromId(taskID)
+		pkg, ok := g.WorkspaceInfos.PackageJSONs[pkgName]
+		if !ok {
+			continue
+		}
+		taskDefinition := g.TaskDefinitions[taskID]
+		expandedInputs := tracker.GetExpandedInputs(&nodes.PackageTask{
+			PackageName:    pkgName,
+			TaskDefinition: taskDefinition,
+		})
+		inputs := make(map[turbopath.AnchoredUnixPath]string, len(expandedInputs))
+		for file, hash := range expandedInputs {
+			inputs[pkg.Dir.ToUnixPath().Join(turbopath.RelativeUnixPath(file))] = hash
+		}
+		w.inputs[taskID] = inputs
+		if taskDefinition != nil {
+			w.inputSpecs[taskID] = &watchedInputSpec{pkg: pkg, inputs: taskDefinition.Inputs}
+		}
+		dependents, err := engine.GetTaskGraphDescendants(taskID)
+		if err != nil {
+			return err
+		}
+		w.dependents[taskID] = dependents
+	}
+	return nil
+}
+
+// affectedTasks returns the tasks to re-run for the changed files: the tasks that hash a
+// file whose contents changed, or that would hash a file added since they ran, and the tasks
+// that depend on them. When a file that configures or is hashed into every task changed,
+// that file is returned instead, and every task re-runs.
+func (w *watchState) affectedTasks(repoRoot turbopath.AbsoluteSystemPath, changed []turbopath.AnchoredUnixPath) (util.Set, turbopath.AnchoredUnixPath) {
+	affected := make(util.Set)
+	// currentInputs holds the inputs of each spec as they are now, found once per spec for
+	// the files that aren't among the inputs of the previous runs
+	currentInputs := make(map[string]map[turbopath.AnchoredUnixPath]string)
+	for _, file := range changed {
+		for _, configFile := range _watchConfigFiles {
+			if file == configFile {
+				return nil, file
+			}
+		}
+		if hash, ok := w.global[file]; ok && contentsChanged(repoRoot, file, hash) {
+			return nil, file
+		}
+		for taskID, inputs := range w.inputs {
+			if affected.Includes(taskID) {
+				continue
+			}
+			if hash, ok := inputs[file]; ok {
+				if !contentsChanged(repoRoot, file, hash) {
+					continue
+				}
+			} else if !w.isAddedInput(repoRoot, taskID, file, currentInputs) {
+				continue
+			}
+			affected.Add(taskID)
+			for _, dependent := range w.dependents[taskID] {
+				affected.Add(dependent)
+			}
+		}
+	}
+	return affected, ""
+}
+
+// isAddedInput is whether a file that the task didn't hash when it last ran is one of its
+// inputs now. The files of the task's package are only listed, with the same logic as its
+// hash, when its input patterns match the file.
+func (w *watchState) isAddedInput(repoRoot turbopath.AbsoluteSystemPath, taskID string, file turbopath.AnchoredUnixPath, currentInputs map[string]map[turbopath.AnchoredUnixPath]string) bool {
+	spec, ok := w.inputSpecs[taskID]
+	if !ok {
+		return false
+	}
+	if matched, err := hashing.MayMatchInputs(spec.pkg.Dir.ToUnixPath(), spec.inputs, file); err != nil || !matched {
+		return false
+	}
+	key := spec.key()
+	inputs, ok := currentInputs[key]
+	if !ok {
+		packageInputs := taskhash.GetPackageInputs(spec.pkg, spec.inputs, repoRoot)
+		inputs = make(map[turbopath.AnchoredUnixPath]string, len(packageInputs))
+		for input, hash := range packageInputs {
+			inputs[spec.pkg.Dir.ToUnixPath().Join(turbopath.RelativeUnixPath(input))] = hash
+		}
+		currentInputs[key] = inputs
+	}
+	_, ok = inputs[file]
+	return ok
+}
+
+// contentsChanged is whether the file no longer has the given hash, which skips the events of
+// files written with the same contents, like the inputs that a task regenerates
+func contentsChanged(repoRoot turbopath.AbsoluteSystemPath, file turbopath.AnchoredUnixPath, hash string) bool {
+	current, err := fs.GitLikeHashFile(file.ToSystemPath().RestoreAnchor(repoRoot).ToString())
+	// A file that was deleted, or can't be read, has changed
+	return err != nil || current != hash
+}
+
+// fileChanges collects the files that changed since they were last taken, as a
+// filewatcher.FileWatchClient
+type fileChanges struct {
+	repoRoot turbopath.AbsoluteSystemPath
+
+	mu    sync.Mutex
+	files map[turbopath.AnchoredUnixPath]struct{}
+	// changed is signaled when a file changes, without waiting for a receiver
+	changed chan struct{}
+	// closed is closed once file watching stops
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func newFileChanges(repoRoot turbopath.AbsoluteSystemPath) *fileChanges {
+	return &fileChanges{
+		repoRoot: repoRoot,
+		files:    make(map[turbopath.AnchoredUnixPath]struct{}),
+		changed:  make(chan struct{}, 1),
+		closed:   make(chan struct{}),
+	}
+}
+
+// OnFileWatchEvent implements filewatcher.FileWatchClient.OnFileWatchEvent
+func (c *fileChanges) OnFileWatchEvent(ev filewatcher.Event) {
+	file, err := ev.Path.RelativeTo(c.repoRoot)
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	c.files[file.ToUnixPath()] = struct{}{}
+	c.mu.Unlock()
+	select {
+	case c.changed <- struct{}{}:
+	default:
+	}
+}
+
+// OnFileWatchError implements filewatcher.FileWatchClient.OnFileWatchError
+func (c *fileChanges) OnFileWatchError(err error) {}
+
+// OnFileWatchClosed implements filewatcher.FileWatchClient.OnFileWatchClosed
+func (c *fileChanges) OnFileWatchClosed() {
+	c.closeOnce.Do(func() { close(c.closed) })
+}
+
+// wait blocks until files change and then stay unchanged for _watchDebounce, and returns the
+// sorted files that changed. Returns false if file watching stops, or done is closed, first.
+func (c *fileChanges) wait(done <-chan struct{}) ([]turbopath.AnchoredUnixPath, bool) {
+	select {
+	case <-c.changed:
+	case <-c.closed:
+		return nil, false
+	case <-done:
+		return nil, false
+	}
+	timer := time.NewTimer(_watchDebounce)
+	defer timer.Stop()
+	for {
+		select {
+		case <-c.changed:
+			if !timer.Stop() {
+				<-timer.C
+			}
+			timer.Reset(_watchDebounce)
+		case <-timer.C:
+			return c.take(), true
+		case <-c.closed:
+			return nil, false
+		case <-done:
+			return nil, false
+		}
+	}
+}
+
+// take returns the sorted files that changed, and forgets them
+func (c *fileChanges) take() []turbopath.AnchoredUnixPath {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	files := make([]turbopath.AnchoredUnixPath, 0, len(c.files))
+	for file := range c.files {
+		files = append(files, file)
+	}
+	c.files = make(map[turbopath.AnchoredUnixPath]struct{})
+	sort.Slice(files, func(i, j int) bool { return files[i] < files[j] })
+	return files
+}
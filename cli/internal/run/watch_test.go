@@ -0,0 +1,160 @@
+package run
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/pyr-sh/dag"
+	"github.com/vercel/turbo/cli/internal/context"
+	"github.com/vercel/turbo/cli/internal/core"
+	"github.com/vercel/turbo/cli/internal/filewatcher"
+	"github.com/vercel/turbo/cli/internal/fs"
+	"github.com/vercel/turbo/cli/internal/packagemanager"
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"github.com/vercel/turbo/cli/internal/util"
+	"gotest.tools/v3/assert"
+)
+
+func Test_watchStateAffectedTasks(t *testing.T) {
+	repoRoot := turbopath.AbsoluteSystemPath(t.TempDir())
+	writeFile := func(file turbopath.AnchoredUnixPath, contents string) string {
+		t.Helper()
+		path := file.ToSystemPath().RestoreAnchor(repoRoot)
+		assert.NilError(t, path.EnsureDir(), "EnsureDir")
+		assert.NilError(t, path.WriteFile([]byte(contents), 0644), "WriteFile")
+		hash, err := fs.GitLikeHashFile(path.ToString())
+		assert.NilError(t, err, "GitLikeHashFile")
+		return hash
+	}
+
+	w := newWatchState()
+	w.global = map[turbopath.AnchoredUnixPath]string{
+		"tsconfig.json": writeFile("tsconfig.json", "{}"),
+	}
+	w.inputs["ui#build"] = map[turbopath.AnchoredUnixPath]string{
+		"packages/ui/index.ts": writeFile("packages/ui/index.ts", "export const ui = 1"),
+	}
+	w.inputs["web#build"] = map[turbopath.AnchoredUnixPath]string{
+		"apps/web/index.ts": writeFile("apps/web/index.ts", "import { ui } from 'ui'"),
+	}
+	w.inputs["docs#build"] = map[turbopath.AnchoredUnixPath]string{
+		"apps/docs/index.ts": writeFile("apps/docs/index.ts", "export {}"),
+	}
+	w.dependents["ui#build"] = []string{"web#build"}
+
+	// Writing the same contents, or changing files that no task hashes, affects nothing
+	writeFile("packages/ui/index.ts", "export const ui = 1")
+	writeFile("packages/ui/dist/index.js", "exports.ui = 1")
+	affected, globalChange := w.affectedTasks(repoRoot, []turbopath.AnchoredUnixPath{"packages/ui/index.ts", "packages/ui/dist/index.js"})
+	assert.Equal(t, globalChange, turbopath.AnchoredUnixPath(""))
+	assert.Equal(t, affected.Len(), 0)
+
+	// A changed input affects its task and the tasks that depend on it
+	writeFile("packages/ui/index.ts", "export const ui = 2")
+	affected, globalChange = w.affectedTasks(repoRoot, []turbopath.AnchoredUnixPath{"packages/ui/index.ts"})
+	assert.Equal(t, globalChange, turbopath.AnchoredUnixPath(""))
+	affectedTasks := affected.UnsafeListOfStrings()
+	sort.Strings(affectedTasks)
+	assert.DeepEqual(t, affectedTasks, []string{"ui#build", "web#build"})
+
+	// A deleted input has changed too
+	assert.NilError(t, repoRoot.UntypedJoin("apps", "docs", "index.ts").Remove(), "Remove")
+	affected, _ = w.affectedTasks(repoRoot, []turbopath.AnchoredUnixPath{"apps/docs/index.ts"})
+	assert.DeepEqual(t, affected.UnsafeListOfStrings(), []string{"docs#build"})
+
+	// Global files and the root configuration re-run every task
+	writeFile("tsconfig.json", `{"strict": true}`)
+	_, globalChange = w.affectedTasks(repoRoot, []turbopath.AnchoredUnixPath{"apps/web/index.ts", "tsconfig.json"})
+	assert.Equal(t, globalChange, turbopath.AnchoredUnixPath("tsconfig.json"))
+	_, globalChange = w.affectedTasks(repoRoot, []turbopath.AnchoredUnixPath{"turbo.json"})
+	assert.Equal(t, globalChange, turbopath.AnchoredUnixPath("turbo.json"))
+
+	// A file added since the last run affects the tasks whose inputs it is now, with their
+	// default inputs leaving out the files that git ignores
+	w.inputSpecs["ui#build"] = &watchedInputSpec{pkg: &fs.PackageJSON{Name: "ui", Dir: turbopath.AnchoredUnixPath("packages/ui").ToSystemPath()}}
+	w.inputSpecs["docs#build"] = &watchedInputSpec{pkg: &fs.PackageJSON{Name: "docs", Dir: turbopath.AnchoredUnixPath("apps/docs").ToSystemPath()}, inputs: []string{"src/**"}}
+	writeFile("packages/ui/.gitignore", "dist\n")
+	writeFile("packages/ui/dist/button.js", "exports.button = 1")
+	writeFile("apps/docs/README.md", "# docs")
+	affected, _ = w.affectedTasks(repoRoot, []turbopath.AnchoredUnixPath{"packages/ui/dist/button.js", "apps/docs/README.md"})
+	assert.Equal(t, affected.Len(), 0)
+	writeFile("packages/ui/src/button.ts", "export const button = 1")
+	affected, _ = w.affectedTasks(repoRoot, []turbopath.AnchoredUnixPath{"packages/ui/src/button.ts"})
+	affectedTasks = affected.UnsafeListOfStrings()
+	sort.Strings(affectedTasks)
+	assert.DeepEqual(t, affectedTasks, []string{"ui#build", "web#build"})
+	writeFile("apps/docs/src/page.ts", "export {}")
+	affected, _ = w.affectedTasks(repoRoot, []turbopath.AnchoredUnixPath{"apps/docs/src/page.ts"})
+	assert.DeepEqual(t, affected.UnsafeListOfStrings(), []string{"docs#build"})
+}
+
+func Test_validateWatchOpts(t *testing.T) {
+	assert.NilError(t, validateWatchOpts(&Opts{}))
+	assert.ErrorContains(t, validateWatchOpts(&Opts{runOpts: runOpts{dryRun: true}}), "can't be used with --dry-run")
+	// Every run of turbo watch shares the process manager that a timed out run closes
+	assert.ErrorContains(t, validateWatchOpts(&Opts{runOpts: runOpts{runTimeout: time.Minute}}), "can't be used with --run-timeout")
+}
+
+func Test_validateWatchedTasks(t *testing.T) {
+	engine := core.NewEngine(nil, false)
+	for _, taskID := range []string{core.ROOT_NODE_NAME, "web#dev", "web#build", "ui#build"} {
+		engine.TaskGraph.Add(taskID)
+	}
+	engine.TaskGraph.Connect(dag.BasicEdge("ui#build", core.ROOT_NODE_NAME))
+	engine.TaskGraph.Connect(dag.BasicEdge("web#build", "ui#build"))
+	engine.TaskGraph.Connect(dag.BasicEdge("web#dev", "ui#build"))
+	taskDefinitions := map[string]*fs.TaskDefinition{
+		"web#build": {},
+		"ui#build":  {},
+	}
+	assert.NilError(t, validateWatchedTasks(engine, taskDefinitions))
+
+	taskDefinitions["web#dev"] = &fs.TaskDefinition{Persistent: true}
+	err := validateWatchedTasks(engine, taskDefinitions)
+	assert.Assert(t, errors.Is(err, errWatchPersistentTasks), err)
+	assert.ErrorContains(t, err, ": web#dev")
+}
+
+func Test_watchStateRerunPackages(t *testing.T) {
+	w := newWatchState()
+	assert.Assert(t, w.rerunPackages() == nil)
+
+	w.rerunTasks = util.SetFromStrings([]string{"web#build", "web#test", "ui#build"})
+	packages := w.rerunPackages().UnsafeListOfStrings()
+	sort.Strings(packages)
+	assert.DeepEqual(t, packages, []string{"ui", "web"})
+}
+
+func Test_watchStateChangesGraph(t *testing.T) {
+	w := newWatchState()
+	w.pkgDepGraph = &context.Context{PackageManager: &packagemanager.PackageManager{Lockfile: "pnpm-lock.yaml"}}
+	w.globalHashable = &GlobalHashable{}
+
+	assert.Assert(t, !w.changesGraph([]turbopath.AnchoredUnixPath{"packages/ui/index.ts", "packages/ui/package.json.bak"}))
+	assert.Assert(t, w.changesGraph([]turbopath.AnchoredUnixPath{"packages/ui/index.ts", "packages/ui/package.json"}))
+	assert.Assert(t, w.changesGraph([]turbopath.AnchoredUnixPath{"pnpm-lock.yaml"}))
+
+	w.forgetGraph()
+	assert.Assert(t, w.pkgDepGraph == nil)
+	assert.Assert(t, w.globalHashable == nil)
+}
+
+func Test_fileChangesWait(t *testing.T) {
+	repoRoot := turbopath.AbsoluteSystemPath(t.TempDir())
+	changes := newFileChanges(repoRoot)
+	for _, file := range []string{"b.ts", "a.ts", "b.ts"} {
+		changes.OnFileWatchEvent(filewatcher.Event{
+			Path:      repoRoot.UntypedJoin(file),
+			EventType: filewatcher.FileModified,
+		})
+	}
+	changed, ok := changes.wait(nil)
+	assert.Assert(t, ok)
+	assert.DeepEqual(t, changed, []turbopath.AnchoredUnixPath{"a.ts", "b.ts"})
+
+	changes.OnFileWatchClosed()
+	_, ok = changes.wait(nil)
+	assert.Assert(t, !ok)
+}
@@ -0,0 +1,113 @@
+package runcache
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/mitchellh/cli"
+	"github.com/vercel/turbo/cli/internal/fs"
+	"github.com/vercel/turbo/cli/internal/process"
+	"github.com/vercel/turbo/cli/internal/turbopath"
+)
+
+// outputTransformFunc normalizes the given output files in place
+type outputTransformFunc = func(repoRoot turbopath.AbsoluteSystemPath, files []turbopath.AbsoluteSystemPath) error
+
+// _builtinOutputTransforms are the normalizers that can be selected with
+// "outputTransform": "builtin:<name>"
+var _builtinOutputTransforms = map[string]outputTransformFunc{
+	fs.OutputTransformRepoRoot: relativizeRepoRoot,
+}
+
+// TransformOutputs applies the task's outputTransform to the files it produced, rewriting
+// them on disk, so that the files left in the repository match the ones that are cached.
+// The task's log file is never transformed.
+func (tc TaskCache) TransformOutputs(logger hclog.Logger, terminal cli.Ui) error {
+	transform := tc.pt.TaskDefinition.OutputTransform
+	if transform == "" {
+		return nil
+	}
+	files, err := tc.producedOutputs(logger, terminal)
+	if err != nil {
+		return err
+	}
+	logger.Debug("transforming outputs", "transform", transform, "files", len(files))
+
+	if strings.HasPrefix(transform, fs.OutputTransformBuiltinPrefix) {
+		name := strings.TrimPrefix(transform, fs.OutputTransformBuiltinPrefix)
+		// The name was validated when turbo.json was read
+		builtin, ok := _builtinOutputTransforms[name]
+		if !ok {
+			return fmt.Errorf("unknown outputTransform %q", transform)
+		}
+		return builtin(tc.rc.repoRoot, files)
+	}
+	return runOutputTransform(transform, tc.pt.Pkg.Dir.RestoreAnchor(tc.rc.repoRoot), tc.hash, files)
+}
+
+// runOutputTransform runs an outputTransform command via the system shell in the package
+// directory. The paths of the files to transform, relative to the package directory, are
+// written to its stdin, one per line.
+func runOutputTransform(command string, pkgDir turbopath.AbsoluteSystemPath, hash string, files []turbopath.AbsoluteSystemPath) error {
+	stdin := &bytes.Buffer{}
+	for _, file := range files {
+		relativePath, err := filepath.Rel(pkgDir.ToString(), file.ToString())
+		if err != nil {
+			return err
+		}
+		stdin.WriteString(filepath.ToSlash(relativePath))
+		stdin.WriteString("\n")
+	}
+
+	cmd := process.ShellCommand(command)
+	cmd.Dir = pkgDir.ToString()
+	cmd.Env = append(os.Environ(), fmt.Sprintf("TURBO_HASH=%v", hash))
+	cmd.Stdin = stdin
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("outputTransform command \"%v\" failed in %v: %w: %v", command, pkgDir, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// relativizeRepoRoot replaces the absolute path of the repository root in text files with
+// the path to the root relative to each file, so that outputs don't depend on where the
+// repository is checked out. Binary files are left untouched.
+func relativizeRepoRoot(repoRoot turbopath.AbsoluteSystemPath, files []turbopath.AbsoluteSystemPath) error {
+	// Only match the root itself, not a sibling directory that shares its prefix
+	rootPattern := regexp.MustCompile(regexp.QuoteMeta(repoRoot.ToString()) + `([^\w.-]|$)`)
+	for _, file := range files {
+		info, err := file.Lstat()
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		contents, err := file.ReadFile()
+		if err != nil {
+			return err
+		}
+		if bytes.IndexByte(contents, 0) != -1 {
+			continue
+		}
+		relativeRoot, err := filepath.Rel(file.Dir().ToString(), repoRoot.ToString())
+		if err != nil {
+			return err
+		}
+		transformed := rootPattern.ReplaceAll(contents, []byte(filepath.ToSlash(relativeRoot)+"${1}"))
+		if bytes.Equal(transformed, contents) {
+			continue
+		}
+		if err := file.WriteFile(transformed, info.Mode()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
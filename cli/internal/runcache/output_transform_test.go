@@ -0,0 +1,50 @@
+package runcache
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"gotest.tools/v3/assert"
+)
+
+func Test_relativizeRepoRoot(t *testing.T) {
+	repoRoot := turbopath.AbsoluteSystemPath(t.TempDir())
+	sourceMap := repoRoot.UntypedJoin("packages", "ui", "dist", "index.js.map")
+	binary := repoRoot.UntypedJoin("packages", "ui", "dist", "image.png")
+	for _, file := range []turbopath.AbsoluteSystemPath{sourceMap, binary} {
+		assert.NilError(t, file.EnsureDir(), "EnsureDir")
+	}
+	root := repoRoot.ToString()
+	assert.NilError(t, sourceMap.WriteFile([]byte(`{"sources":["`+root+`/packages/ui/src/index.ts"],"root":"`+root+`","sibling":"`+root+`-fork/a.ts"}`), 0644), "WriteFile")
+	binaryContents := append([]byte(root), 0)
+	assert.NilError(t, binary.WriteFile(binaryContents, 0644), "WriteFile")
+
+	assert.NilError(t, relativizeRepoRoot(repoRoot, []turbopath.AbsoluteSystemPath{sourceMap, binary}), "relativizeRepoRoot")
+
+	contents, err := sourceMap.ReadFile()
+	assert.NilError(t, err, "ReadFile")
+	assert.Equal(t, string(contents), `{"sources":["../../../packages/ui/src/index.ts"],"root":"../../..","sibling":"`+root+`-fork/a.ts"}`)
+	contents, err = binary.ReadFile()
+	assert.NilError(t, err, "ReadFile")
+	assert.DeepEqual(t, contents, binaryContents)
+}
+
+func Test_runOutputTransform(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	pkgDir := turbopath.AbsoluteSystemPath(t.TempDir())
+	output := pkgDir.UntypedJoin("dist", "out.txt")
+	assert.NilError(t, output.EnsureDir(), "EnsureDir")
+	assert.NilError(t, output.WriteFile([]byte("built at 12:00\n"), 0644), "WriteFile")
+
+	command := `while read -r file; do echo "$TURBO_HASH" > "$file"; done`
+	assert.NilError(t, runOutputTransform(command, pkgDir, "abc123", []turbopath.AbsoluteSystemPath{output}), "runOutputTransform")
+	contents, err := output.ReadFile()
+	assert.NilError(t, err, "ReadFile")
+	assert.Equal(t, string(contents), "abc123\n")
+
+	err = runOutputTransform("echo nope >&2; exit 3", pkgDir, "abc123", nil)
+	assert.ErrorContains(t, err, "nope")
+}
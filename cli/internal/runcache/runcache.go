@@ -2,12 +2,15 @@ package runcache
 
 import (
 	"bufio"
+	"compress/gzip"
 	"context"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/fatih/color"
 	"github.com/hashicorp/go-hclog"
@@ -16,6 +19,7 @@ import (
 	"github.com/vercel/turbo/cli/internal/colorcache"
 	"github.com/vercel/turbo/cli/internal/fs"
 	"github.com/vercel/turbo/cli/internal/globby"
+	"github.com/vercel/turbo/cli/internal/hashing"
 	"github.com/vercel/turbo/cli/internal/logstreamer"
 	"github.com/vercel/turbo/cli/internal/nodes"
 	"github.com/vercel/turbo/cli/internal/turbopath"
@@ -23,6 +27,10 @@ import (
 	"github.com/vercel/turbo/cli/internal/util"
 )
 
+// _maxMissingOutputExamples is the number of missing outputs listed when the outputs of a task
+// are only partially restored. Every missing output is logged.
+const _maxMissingOutputExamples = 3
+
 // LogReplayer is a function that is responsible for replaying the contents of a given log file
 type LogReplayer = func(logger hclog.Logger, output *cli.PrefixedUi, logFile turbopath.AbsoluteSystemPath)
 
@@ -30,9 +38,21 @@ type LogReplayer = func(logger hclog.Logger, output *cli.PrefixedUi, logFile tur
 type Opts struct {
 	SkipReads              bool
 	SkipWrites             bool
+	DeferWrites            bool
 	TaskOutputModeOverride *util.TaskOutputMode
 	LogReplayer            LogReplayer
 	OutputWatcher          OutputWatcher
+	// IsolationDir, when set, is a per-run workspace in which task logs are captured
+	// and cache artifacts are staged before being moved into the repository
+	IsolationDir turbopath.AbsoluteSystemPath
+	// StagingDir, when set, is a per-run directory into which outputs are copied while
+	// writes are deferred. Runs in isolation stage outputs in IsolationDir instead.
+	StagingDir turbopath.AbsoluteSystemPath
+	// Stdout, when set, receives the output of tasks instead of os.Stdout
+	Stdout io.Writer
+	// VerifyOutputs checks restored outputs against the cache manifest, treating a
+	// mismatch as a cache miss
+	VerifyOutputs bool
 }
 
 // SetTaskOutputMode parses the task output mode from string and then sets it in opts
@@ -66,10 +86,29 @@ type RunCache struct {
 	cache                  cache.Cache
 	readsDisabled          bool
 	writesDisabled         bool
+	writesDeferred         bool
 	repoRoot               turbopath.AbsoluteSystemPath
 	logReplayer            LogReplayer
 	outputWatcher          OutputWatcher
 	colorCache             *colorcache.ColorCache
+	isolationDir           turbopath.AbsoluteSystemPath
+	stagingDir             turbopath.AbsoluteSystemPath
+	stdout                 io.Writer
+	verifyOutputs          bool
+
+	// deferredWrites holds the cache writes staged while writes are deferred
+	deferredWrites   []deferredWrite
+	deferredWritesMu sync.Mutex
+}
+
+// deferredWrite is a cache write that has been staged until the run completes
+type deferredWrite struct {
+	taskID   string
+	anchor   turbopath.AbsoluteSystemPath
+	hash     string
+	duration int
+	files    []turbopath.AnchoredSystemPath
+	globs    fs.TaskOutputs
 }
 
 // New returns a new instance of RunCache, wrapping the given cache
@@ -79,10 +118,15 @@ func New(cache cache.Cache, repoRoot turbopath.AbsoluteSystemPath, opts Opts, co
 		cache:                  cache,
 		readsDisabled:          opts.SkipReads,
 		writesDisabled:         opts.SkipWrites,
+		writesDeferred:         opts.DeferWrites,
 		repoRoot:               repoRoot,
 		logReplayer:            opts.LogReplayer,
 		outputWatcher:          opts.OutputWatcher,
 		colorCache:             colorCache,
+		isolationDir:           opts.IsolationDir,
+		stagingDir:             opts.StagingDir,
+		stdout:                 opts.Stdout,
+		verifyOutputs:          opts.VerifyOutputs,
 	}
 
 	if rc.logReplayer == nil {
@@ -91,6 +135,9 @@ func New(cache cache.Cache, repoRoot turbopath.AbsoluteSystemPath, opts Opts, co
 	if rc.outputWatcher == nil {
 		rc.outputWatcher = &NoOpOutputWatcher{}
 	}
+	if rc.stdout == nil {
+		rc.stdout = os.Stdout
+	}
 	return rc
 }
 
@@ -99,42 +146,88 @@ func New(cache cache.Cache, repoRoot turbopath.AbsoluteSystemPath, opts Opts, co
 type TaskCache struct {
 	rc                *RunCache
 	repoRelativeGlobs fs.TaskOutputs
-	hash              string
-	pt                *nodes.PackageTask
-	taskOutputMode    util.TaskOutputMode
-	cachingDisabled   bool
-	LogFileName       turbopath.AbsoluteSystemPath
+	// restoreOnlyGlobs are the repo-relative globs, also included in repoRelativeGlobs,
+	// of outputs that are restored from the cache but never saved to it
+	restoreOnlyGlobs []string
+	hash             string
+	pt               *nodes.PackageTask
+	taskOutputMode   util.TaskOutputMode
+	cachingDisabled  bool
+	LogFileName      turbopath.AbsoluteSystemPath
+	// logOutputs are files, outside of the Task's output globs, that the Task reported
+	// writing in its logs
+	logOutputs []turbopath.AnchoredSystemPath
+}
+
+// ReportBypass reports that the task executes without checking the cache, for the given reason
+func (tc TaskCache) ReportBypass(prefixedUI *cli.PrefixedUi, reason string) {
+	if tc.taskOutputMode != util.NoTaskOutput && tc.taskOutputMode != util.ErrorTaskOutput {
+		prefixedUI.Output(fmt.Sprintf("cache bypass, %v, force executing %s", reason, ui.Dim(tc.hash)))
+	}
 }
 
 // RestoreOutputs attempts to restore output for the corresponding task from the cache.
-// Returns true if successful.
-func (tc TaskCache) RestoreOutputs(ctx context.Context, prefixedUI *cli.PrefixedUi, progressLogger hclog.Logger) (bool, error) {
+// Returns true if successful, along with the restored output files.
+func (tc TaskCache) RestoreOutputs(ctx context.Context, prefixedUI *cli.PrefixedUi, progressLogger hclog.Logger) (bool, []turbopath.AnchoredUnixPath, error) {
 	if tc.cachingDisabled || tc.rc.readsDisabled {
 		if tc.taskOutputMode != util.NoTaskOutput && tc.taskOutputMode != util.ErrorTaskOutput {
 			prefixedUI.Output(fmt.Sprintf("cache bypass, force executing %s", ui.Dim(tc.hash)))
 		}
-		return false, nil
+		return false, nil, nil
+	}
+	var changedOutputGlobs []string
+	var err error
+	if tc.pt.TaskDefinition.OutputsFromLog != "" {
+		// The files a task reports in its logs are only known once it has run, so the output
+		// watcher can't tell whether they have changed
+		changedOutputGlobs = tc.repoRelativeGlobs.Inclusions
+	} else {
+		changedOutputGlobs, err = tc.rc.outputWatcher.GetChangedOutputs(ctx, tc.hash, tc.repoRelativeGlobs.Inclusions)
 	}
-	changedOutputGlobs, err := tc.rc.outputWatcher.GetChangedOutputs(ctx, tc.hash, tc.repoRelativeGlobs.Inclusions)
 	if err != nil {
 		progressLogger.Warn(fmt.Sprintf("Failed to check if we can skip restoring outputs for %v: %v. Proceeding to check cache", tc.pt.TaskID, err))
 		prefixedUI.Warn(ui.Dim(fmt.Sprintf("Failed to check if we can skip restoring outputs for %v: %v. Proceeding to check cache", tc.pt.TaskID, err)))
 		changedOutputGlobs = tc.repoRelativeGlobs.Inclusions
 	}
 
+	var restored []turbopath.AnchoredSystemPath
 	hasChangedOutputs := len(changedOutputGlobs) > 0
 	if hasChangedOutputs {
 		// Note that we currently don't use the output globs when restoring, but we could in the
 		// future to avoid doing unnecessary file I/O. We also need to pass along the exclusion
 		// globs as well.
-		hit, _, _, err := tc.rc.cache.Fetch(tc.rc.repoRoot, tc.hash, nil)
+		hit, restoredFiles, err := tc.fetch()
 		if err != nil {
-			return false, err
+			return false, nil, err
 		} else if !hit {
 			if tc.taskOutputMode != util.NoTaskOutput && tc.taskOutputMode != util.ErrorTaskOutput {
 				prefixedUI.Output(fmt.Sprintf("cache miss, executing %s", ui.Dim(tc.hash)))
 			}
-			return false, nil
+			return false, nil, nil
+		}
+		restored = restoredFiles
+		if missing := tc.missingOutputs(progressLogger); len(missing) > 0 {
+			examples := missing
+			if len(examples) > _maxMissingOutputExamples {
+				examples = examples[:_maxMissingOutputExamples]
+			}
+			progressLogger.Warn("partial cache restore", "task", tc.pt.TaskID, "missing", missing)
+			prefixedUI.Warn(fmt.Sprintf("cache artifact is missing %v of its outputs (%v), executing %s", len(missing), strings.Join(examples, ", "), ui.Dim(tc.hash)))
+			tc.removeRestored(progressLogger, restored)
+			return false, nil, nil
+		}
+		if tc.rc.verifyOutputs {
+			if problem, err := tc.verifyOutputs(); err != nil {
+				return false, nil, err
+			} else if problem != "" {
+				prefixedUI.Warn(fmt.Sprintf("restored outputs don't match the cache manifest (%v), executing %s", problem, ui.Dim(tc.hash)))
+				tc.removeRestored(progressLogger, restored)
+				return false, nil, nil
+			}
+		}
+
+		if err := tc.convertRestoredLog(); err != nil {
+			prefixedUI.Warn(fmt.Sprintf("could not convert the restored log file: %v", err))
 		}
 
 		if err := tc.rc.outputWatcher.NotifyOutputsWritten(ctx, tc.hash, tc.repoRelativeGlobs); err != nil {
@@ -143,6 +236,10 @@ func (tc TaskCache) RestoreOutputs(ctx context.Context, prefixedUI *cli.Prefixed
 		}
 	} else {
 		prefixedUI.Warn(fmt.Sprintf("Skipping cache check for %v, outputs have not changed since previous run.", tc.pt.TaskID))
+		// The outputs on disk are the ones that would have been restored
+		if restored, err = tc.expandOutputs(progressLogger, prefixedUI); err != nil {
+			progressLogger.Warn(fmt.Sprintf("Failed to list the outputs of %v: %v", tc.pt.TaskID, err))
+		}
 	}
 
 	switch tc.taskOutputMode {
@@ -161,7 +258,110 @@ func (tc TaskCache) RestoreOutputs(ctx context.Context, prefixedUI *cli.Prefixed
 		// NoLogs, do not output anything
 	}
 
-	return true, nil
+	return true, tc.outputFiles(restored), nil
+}
+
+// fetch restores the task's outputs from the cache. When running in isolation, the outputs
+// are first restored into the run's workspace and then moved into the repository, so that
+// a concurrent run never observes a partially restored file.
+func (tc TaskCache) fetch() (bool, []turbopath.AnchoredSystemPath, error) {
+	if tc.rc.isolationDir == "" {
+		hit, restoredFiles, _, err := tc.rc.cache.Fetch(tc.rc.repoRoot, tc.hash, nil)
+		return hit, restoredFiles, err
+	}
+
+	restoreDir := tc.workspace("restore")
+	defer func() { _ = restoreDir.RemoveAll() }()
+	hit, restoredFiles, _, err := tc.rc.cache.Fetch(restoreDir, tc.hash, nil)
+	if err != nil || !hit {
+		return hit, nil, err
+	}
+	for _, file := range restoredFiles {
+		from := file.RestoreAnchor(restoreDir)
+		to := file.RestoreAnchor(tc.rc.repoRoot)
+		info, err := from.Lstat()
+		if err != nil {
+			return false, nil, err
+		}
+		if info.IsDir() {
+			if err := to.MkdirAll(info.Mode()); err != nil {
+				return false, nil, err
+			}
+			continue
+		}
+		if err := to.EnsureDir(); err != nil {
+			return false, nil, err
+		}
+		if err := from.Rename(to); err != nil {
+			return false, nil, err
+		}
+	}
+	return true, restoredFiles, nil
+}
+
+// missingOutputs returns the outputs listed in the manifest of the cached artifact that weren't
+// restored. Artifacts without a manifest can't be checked.
+func (tc TaskCache) missingOutputs(progressLogger hclog.Logger) []string {
+	reader, ok := tc.rc.cache.(cache.ManifestReader)
+	if !ok {
+		return nil
+	}
+	manifest, err := reader.ReadManifest(tc.hash)
+	if err == nil && manifest == nil {
+		return nil
+	}
+	var missing []string
+	if err == nil {
+		missing, err = manifest.Missing(tc.rc.repoRoot)
+	}
+	if err != nil {
+		// The outputs were restored, a manifest that can't be read only means they can't be checked
+		progressLogger.Debug("failed to check the restored outputs against the cache manifest", "task", tc.pt.TaskID, "error", err)
+		return nil
+	}
+	return missing
+}
+
+// removeRestored removes the files of a partial or rejected restore, so that the task
+// executes with none of the outputs of the artifact rather than some of them
+func (tc TaskCache) removeRestored(progressLogger hclog.Logger, restored []turbopath.AnchoredSystemPath) {
+	for _, file := range restored {
+		path := file.RestoreAnchor(tc.rc.repoRoot)
+		if info, err := path.Lstat(); err != nil || info.IsDir() {
+			// Directories are left in place, the task may write into them again
+			continue
+		}
+		if err := path.Remove(); err != nil {
+			progressLogger.Debug("failed to remove a partially restored output", "path", path, "error", err)
+		}
+	}
+}
+
+// verifyOutputs compares the restored outputs with the manifest of the cached artifact, and
+// describes the first difference. Artifacts without a manifest can't be verified.
+func (tc TaskCache) verifyOutputs() (string, error) {
+	reader, ok := tc.rc.cache.(cache.ManifestReader)
+	if !ok {
+		return "", nil
+	}
+	manifest, err := reader.ReadManifest(tc.hash)
+	if err != nil || manifest == nil {
+		return "", err
+	}
+	return manifest.Verify(tc.rc.repoRoot)
+}
+
+// workspace returns a directory for this task within the run's isolation workspace
+func (tc TaskCache) workspace(kind string) turbopath.AbsoluteSystemPath {
+	return tc.rc.isolationDir.UntypedJoin(kind, tc.hash)
+}
+
+// capturedLogFile is where the output of the task is written while it executes
+func (tc TaskCache) capturedLogFile() turbopath.AbsoluteSystemPath {
+	if tc.rc.isolationDir == "" {
+		return tc.LogFileName
+	}
+	return tc.workspace("logs").UntypedJoin(tc.pt.LogFile)
 }
 
 // ReplayLogFile writes out the stored logfile to the terminal
@@ -175,7 +375,9 @@ func (tc TaskCache) ReplayLogFile(prefixedUI *cli.PrefixedUi, progressLogger hcl
 // This is called if the task exited with an non-zero error code.
 func (tc TaskCache) OnError(terminal *cli.PrefixedUi, logger hclog.Logger) {
 	if tc.taskOutputMode == util.ErrorTaskOutput {
-		tc.ReplayLogFile(terminal, logger)
+		if logFile := tc.capturedLogFile(); logFile.FileExists() {
+			tc.rc.logReplayer(logger, terminal, logFile)
+		}
 	}
 }
 
@@ -190,30 +392,57 @@ type fileWriterCloser struct {
 	io.Writer
 	file  *os.File
 	bufio *bufio.Writer
+	// gzip is set when the log file is compressed
+	gzip *gzip.Writer
+	// copyTo, when set, is where the log file is copied once it is complete
+	copyTo turbopath.AbsoluteSystemPath
 }
 
 func (fwc *fileWriterCloser) Close() error {
+	if fwc.gzip != nil {
+		if err := fwc.gzip.Close(); err != nil {
+			return err
+		}
+	}
 	if err := fwc.bufio.Flush(); err != nil {
 		return err
 	}
-	return fwc.file.Close()
+	if err := fwc.file.Close(); err != nil {
+		return err
+	}
+	if fwc.copyTo != "" {
+		return fs.CopyFile(&fs.LstatCachedFile{Path: turbopath.AbsoluteSystemPath(fwc.file.Name())}, fwc.copyTo.ToString())
+	}
+	return nil
+}
+
+// Stdout returns where the output of tasks is shown
+func (rc *RunCache) Stdout() io.Writer {
+	return rc.stdout
 }
 
 // OutputWriter creates a sink suitable for handling the output of the command associated
 // with this task.
 func (tc TaskCache) OutputWriter(prefix string) (io.WriteCloser, error) {
-	// an os.Stdout wrapper that will add prefixes before printing to stdout
-	stdoutWriter := logstreamer.NewPrettyStdoutWriter(prefix)
+	return tc.OutputWriterTo(tc.rc.stdout, prefix)
+}
+
+// OutputWriterTo is like OutputWriter, but shows the output of the task by writing it to
+// terminal rather than to the run's stdout.
+func (tc TaskCache) OutputWriterTo(terminal io.Writer, prefix string) (io.WriteCloser, error) {
+	// a terminal wrapper that will add prefixes before printing
+	stdoutWriter := logstreamer.NewPrettyWriter(terminal, prefix)
 
 	if tc.cachingDisabled || tc.rc.writesDisabled {
 		return nopWriteCloser{stdoutWriter}, nil
 	}
 	// Setup log file
-	if err := tc.LogFileName.EnsureDir(); err != nil {
+	logFile := tc.capturedLogFile()
+	if err := logFile.EnsureDir(); err != nil {
 		return nil, err
 	}
 
-	output, err := tc.LogFileName.Create()
+	output, err := logFile.Create()
 	if err != nil {
 		return nil, err
 	}
@@ -223,11 +452,21 @@ func (tc TaskCache) OutputWriter(prefix string) (io.WriteCloser, error) {
 		file:  output,
 		bufio: bufWriter,
 	}
+	if tc.rc.isolationDir != "" {
+		// The isolation workspace is removed when the run ends, the log of a task that
+		// fails or isn't saved to the cache must still be found in the repository
+		fwc.copyTo = tc.LogFileName
+	}
+	var logWriter io.Writer = bufWriter
+	if tc.pt.CompressedLog {
+		fwc.gzip = gzip.NewWriter(bufWriter)
+		logWriter = fwc.gzip
+	}
 	if tc.taskOutputMode == util.NoTaskOutput || tc.taskOutputMode == util.HashTaskOutput || tc.taskOutputMode == util.ErrorTaskOutput {
 		// only write to log file, not to stdout
-		fwc.Writer = bufWriter
+		fwc.Writer = logWriter
 	} else {
-		fwc.Writer = io.MultiWriter(stdoutWriter, bufWriter)
+		fwc.Writer = io.MultiWriter(stdoutWriter, logWriter)
 	}
 
 	return fwc, nil
@@ -235,18 +474,179 @@ func (tc TaskCache) OutputWriter(prefix string) (io.WriteCloser, error) {
 
 var _emptyIgnore []string
 
-// SaveOutputs is responsible for saving the outputs of task to the cache, after the task has completed
-func (tc TaskCache) SaveOutputs(ctx context.Context, logger hclog.Logger, terminal cli.Ui, duration int) error {
+// SaveOutputs is responsible for saving the outputs of task to the cache, after the task has completed.
+// Returns the output files that are cached.
+func (tc TaskCache) SaveOutputs(ctx context.Context, logger hclog.Logger, terminal cli.Ui, duration int) ([]turbopath.AnchoredUnixPath, error) {
 	if tc.cachingDisabled || tc.rc.writesDisabled {
-		return nil
+		return nil, nil
+	}
+
+	if err := tc.TransformOutputs(logger, terminal); err != nil {
+		return nil, err
 	}
 
 	logger.Debug("caching output", "outputs", tc.repoRelativeGlobs)
 
-	filesToBeCached, err := globby.GlobAll(tc.rc.repoRoot.ToStringDuringMigration(), tc.repoRelativeGlobs.Inclusions, tc.repoRelativeGlobs.Exclusions)
+	anchor := tc.rc.repoRoot
+	var relativePaths []turbopath.AnchoredSystemPath
+	var err error
+	if tc.rc.isolationDir != "" || tc.rc.writesDeferred {
+		// A deferred write is committed once the run completes, by which time the outputs
+		// in the repository may have been modified again
+		anchor, relativePaths, err = tc.snapshotOutputs(logger, terminal)
+	} else {
+		relativePaths, err = tc.outputsToCache(logger, terminal)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	write := deferredWrite{
+		taskID:   tc.pt.TaskID,
+		anchor:   anchor,
+		hash:     tc.hash,
+		duration: duration,
+		files:    relativePaths,
+		globs:    tc.watchedOutputs(),
+	}
+	if tc.rc.writesDeferred {
+		logger.Debug("deferring cache write until the run completes")
+		tc.rc.deferredWritesMu.Lock()
+		tc.rc.deferredWrites = append(tc.rc.deferredWrites, write)
+		tc.rc.deferredWritesMu.Unlock()
+		return tc.outputFiles(relativePaths), nil
+	}
+	if err := tc.rc.put(ctx, logger, terminal, write); err != nil {
+		return nil, err
+	}
+	return tc.outputFiles(relativePaths), nil
+}
+
+// snapshotOutputs copies the task's outputs into the run's workspace, so that the cache
+// artifact is built from the files this run produced even if they are modified before it is
+// written. In isolation, the captured log file is added to the snapshot. Returns the snapshot
+// directory and the files within it.
+func (tc TaskCache) snapshotOutputs(logger hclog.Logger, terminal cli.Ui) (turbopath.AbsoluteSystemPath, []turbopath.AnchoredSystemPath, error) {
+	snapshotDir := tc.snapshotDir()
+	relativePaths, err := tc.outputsToCache(logger, terminal)
 	if err != nil {
+		return "", nil, err
+	}
+	isolated := tc.rc.isolationDir != ""
+	logFile := fs.UnsafeToAnchoredSystemPath(tc.pt.LogFile)
+	snapshotPaths := make([]turbopath.AnchoredSystemPath, 0, len(relativePaths)+1)
+	for _, relativePath := range relativePaths {
+		if relativePath == "" {
+			continue
+		}
+		// In isolation, the log file in the repository belongs to a previous execution, the
+		// captured one is added below
+		if isolated && relativePath == logFile {
+			continue
+		}
+		from := relativePath.RestoreAnchor(tc.rc.repoRoot)
+		to := relativePath.RestoreAnchor(snapshotDir)
+		info, err := from.Lstat()
+		if err != nil {
+			return "", nil, err
+		}
+		if info.IsDir() {
+			if err := to.MkdirAll(info.Mode()); err != nil {
+				return "", nil, err
+			}
+		} else if err := fs.CopyFile(&fs.LstatCachedFile{Path: from}, to.ToString()); err != nil {
+			return "", nil, err
+		}
+		snapshotPaths = append(snapshotPaths, relativePath)
+	}
+	if !isolated {
+		return snapshotDir, snapshotPaths, nil
+	}
+
+	capturedLogFile := tc.capturedLogFile()
+	if err := fs.CopyFile(&fs.LstatCachedFile{Path: capturedLogFile}, logFile.RestoreAnchor(snapshotDir).ToString()); err != nil {
+		return "", nil, err
+	}
+	snapshotPaths = append(snapshotPaths, logFile)
+	return snapshotDir, snapshotPaths, nil
+}
+
+// snapshotDir is where snapshotOutputs copies the task's outputs
+func (tc TaskCache) snapshotDir() turbopath.AbsoluteSystemPath {
+	if tc.rc.isolationDir != "" {
+		return tc.workspace("outputs")
+	}
+	return tc.rc.stagingDir.UntypedJoin(tc.hash)
+}
+
+// RemoveIsolationDir deletes the run's isolation workspace and the outputs staged for deferred
+// writes, if there are any. It must only be called after all cache writes have completed.
+func (rc *RunCache) RemoveIsolationDir() error {
+	for _, dir := range []turbopath.AbsoluteSystemPath{rc.isolationDir, rc.stagingDir} {
+		if dir == "" {
+			continue
+		}
+		if err := dir.RemoveAll(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// put writes the given outputs to the cache and records them with the output watcher
+func (rc *RunCache) put(ctx context.Context, logger hclog.Logger, terminal cli.Ui, write deferredWrite) error {
+	if err := rc.cache.Put(write.anchor, write.hash, write.duration, write.files); err != nil {
 		return err
 	}
+	err := rc.outputWatcher.NotifyOutputsWritten(ctx, write.hash, write.globs)
+	if err != nil {
+		// Don't fail the cache write because we also failed to record it, we will just do
+		// extra I/O in the future restoring files that haven't changed from cache
+		logger.Warn(fmt.Sprintf("Failed to mark outputs as cached for %v: %v", write.taskID, err))
+		terminal.Warn(ui.Dim(fmt.Sprintf("Failed to mark outputs as cached for %v: %v", write.taskID, err)))
+	}
+	return nil
+}
+
+// CommitDeferredWrites writes every output staged while writes were deferred to the cache.
+// It is meant to be called once the whole run has succeeded. Outputs are read from disk
+// at this point, so tasks must not modify the outputs of other tasks.
+func (rc *RunCache) CommitDeferredWrites(ctx context.Context, logger hclog.Logger, terminal cli.Ui) error {
+	rc.deferredWritesMu.Lock()
+	writes := rc.deferredWrites
+	rc.deferredWrites = nil
+	rc.deferredWritesMu.Unlock()
+
+	var errs []string
+	for _, write := range writes {
+		if err := rc.put(ctx, logger, terminal, write); err != nil {
+			errs = append(errs, fmt.Sprintf("%v: %v", write.taskID, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("error caching output: %v", strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// DiscardDeferredWrites drops every output staged while writes were deferred, without
+// writing it to the cache. Returns the number of writes that were discarded.
+func (rc *RunCache) DiscardDeferredWrites() int {
+	rc.deferredWritesMu.Lock()
+	defer rc.deferredWritesMu.Unlock()
+	discarded := len(rc.deferredWrites)
+	rc.deferredWrites = nil
+	return discarded
+}
+
+// expandOutputs resolves the task's output globs to the list of files currently on disk,
+// relative to the repo root. Restore-only outputs are left out, since they are never cached.
+func (tc TaskCache) expandOutputs(logger hclog.Logger, terminal cli.Ui) ([]turbopath.AnchoredSystemPath, error) {
+	exclusions := append(append([]string{}, tc.repoRelativeGlobs.Exclusions...), tc.restoreOnlyGlobs...)
+	filesToBeCached, err := globby.GlobAll(tc.rc.repoRoot.ToStringDuringMigration(), tc.repoRelativeGlobs.Inclusions, exclusions)
+	if err != nil {
+		return nil, err
+	}
 
 	relativePaths := make([]turbopath.AnchoredSystemPath, len(filesToBeCached))
 
@@ -259,16 +659,155 @@ func (tc TaskCache) SaveOutputs(ctx context.Context, logger hclog.Logger, termin
 		}
 		relativePaths[index] = fs.UnsafeToAnchoredSystemPath(relativePath)
 	}
+	return relativePaths, nil
+}
 
-	if err = tc.rc.cache.Put(tc.rc.repoRoot, tc.hash, duration, relativePaths); err != nil {
-		return err
+// ExcludedOutputs returns the files that are matched by the task's output globs but left out of
+// the cache by its exclusions, sorted. It is nil when the task has no exclusions or isn't cached.
+func (tc TaskCache) ExcludedOutputs() ([]turbopath.AnchoredUnixPath, error) {
+	if tc.cachingDisabled || tc.rc.writesDisabled || len(tc.repoRelativeGlobs.Exclusions) == 0 {
+		return nil, nil
 	}
-	err = tc.rc.outputWatcher.NotifyOutputsWritten(ctx, tc.hash, tc.repoRelativeGlobs)
+	repoRoot := tc.rc.repoRoot.ToStringDuringMigration()
+	matched, err := globby.GlobFiles(repoRoot, tc.repoRelativeGlobs.Inclusions, tc.restoreOnlyGlobs)
 	if err != nil {
-		// Don't fail the cache write because we also failed to record it, we will just do
-		// extra I/O in the future restoring files that haven't changed from cache
-		logger.Warn(fmt.Sprintf("Failed to mark outputs as cached for %v: %v", tc.pt.TaskID, err))
-		terminal.Warn(ui.Dim(fmt.Sprintf("Failed to mark outputs as cached for %v: %v", tc.pt.TaskID, err)))
+		return nil, err
+	}
+	exclusions := append(append([]string{}, tc.repoRelativeGlobs.Exclusions...), tc.restoreOnlyGlobs...)
+	kept, err := globby.GlobFiles(repoRoot, tc.repoRelativeGlobs.Inclusions, exclusions)
+	if err != nil {
+		return nil, err
+	}
+	keptSet := util.SetFromStrings(kept)
+	excluded := []turbopath.AnchoredUnixPath{}
+	for _, file := range matched {
+		if keptSet.Includes(file) {
+			continue
+		}
+		relativePath, err := tc.rc.repoRoot.RelativePathString(file)
+		if err != nil {
+			return nil, err
+		}
+		excluded = append(excluded, fs.UnsafeToAnchoredSystemPath(relativePath).ToUnixPath())
+	}
+	sort.Slice(excluded, func(i, j int) bool { return excluded[i] < excluded[j] })
+	return excluded, nil
+}
+
+// outputFiles returns the files among the given repo-relative outputs, sorted. Directories
+// are left out, they are part of artifacts but only hold the output files.
+func (tc TaskCache) outputFiles(outputs []turbopath.AnchoredSystemPath) []turbopath.AnchoredUnixPath {
+	files := make([]turbopath.AnchoredUnixPath, 0, len(outputs))
+	for _, output := range outputs {
+		if output == "" || output.RestoreAnchor(tc.rc.repoRoot).DirExists() {
+			continue
+		}
+		files = append(files, output.ToUnixPath())
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i] < files[j] })
+	return files
+}
+
+// AddLogOutputs adds repo-relative files to the outputs that SaveOutputs caches, for
+// outputs that the task reports in its logs rather than writing to a predictable location
+func (tc *TaskCache) AddLogOutputs(files []turbopath.AnchoredSystemPath) {
+	tc.logOutputs = append(tc.logOutputs, files...)
+}
+
+// watchedOutputs returns the task's output globs, along with the files added with
+// AddLogOutputs, so that the output watcher tracks every file that is cached
+func (tc TaskCache) watchedOutputs() fs.TaskOutputs {
+	if len(tc.logOutputs) == 0 {
+		return tc.repoRelativeGlobs
+	}
+	inclusions := append([]string{}, tc.repoRelativeGlobs.Inclusions...)
+	for _, file := range tc.logOutputs {
+		inclusions = append(inclusions, file.ToString())
+	}
+	return fs.TaskOutputs{Inclusions: inclusions, Exclusions: tc.repoRelativeGlobs.Exclusions}
+}
+
+// outputsToCache returns the files matched by the task's output globs, followed by the files
+// added with AddLogOutputs that the globs didn't match
+func (tc TaskCache) outputsToCache(logger hclog.Logger, terminal cli.Ui) ([]turbopath.AnchoredSystemPath, error) {
+	relativePaths, err := tc.expandOutputs(logger, terminal)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[turbopath.AnchoredSystemPath]bool, len(relativePaths))
+	for _, relativePath := range relativePaths {
+		seen[relativePath] = true
+	}
+	for _, file := range tc.logOutputs {
+		if !seen[file] {
+			seen[file] = true
+			relativePaths = append(relativePaths, file)
+		}
+	}
+	return relativePaths, nil
+}
+
+// producedOutputs returns the absolute paths of the files matched by the task's output globs,
+// leaving out the task's log file, which is expected to differ between executions.
+func (tc TaskCache) producedOutputs(logger hclog.Logger, terminal cli.Ui) ([]turbopath.AbsoluteSystemPath, error) {
+	relativePaths, err := tc.expandOutputs(logger, terminal)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]turbopath.AbsoluteSystemPath, 0, len(relativePaths))
+	for _, relativePath := range relativePaths {
+		if relativePath == "" {
+			continue
+		}
+		file := relativePath.RestoreAnchor(tc.rc.repoRoot)
+		if file == tc.LogFileName || !file.FileExists() {
+			continue
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// OutputHashes returns the hash of every file the task produced, keyed by its repo-relative path.
+// It is used to compare the outputs of two executions of the same task.
+func (tc TaskCache) OutputHashes(logger hclog.Logger, terminal cli.Ui) (map[turbopath.AnchoredUnixPath]string, error) {
+	files, err := tc.producedOutputs(logger, terminal)
+	if err != nil {
+		return nil, err
+	}
+	return hashing.GetHashableDeps(tc.rc.repoRoot, files)
+}
+
+// DiffOutputHashes compares the output hashes of two executions of a task, as returned by
+// OutputHashes, and returns the sorted paths of the files that are missing, new, or have
+// different contents in the second one.
+func DiffOutputHashes(first map[turbopath.AnchoredUnixPath]string, second map[turbopath.AnchoredUnixPath]string) []string {
+	mismatched := []string{}
+	for file, hash := range first {
+		if secondHash, ok := second[file]; !ok || secondHash != hash {
+			mismatched = append(mismatched, file.ToString())
+		}
+	}
+	for file := range second {
+		if _, ok := first[file]; !ok {
+			mismatched = append(mismatched, file.ToString())
+		}
+	}
+	sort.Strings(mismatched)
+	return mismatched
+}
+
+// RemoveOutputs deletes the files the task produced so that it can be executed again from
+// a clean slate.
+func (tc TaskCache) RemoveOutputs(logger hclog.Logger, terminal cli.Ui) error {
+	files, err := tc.producedOutputs(logger, terminal)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := file.Remove(); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -289,6 +828,10 @@ func (rc *RunCache) TaskCache(pt *nodes.PackageTask, hash string) TaskCache {
 	for index, output := range hashableOutputs.Exclusions {
 		repoRelativeGlobs.Exclusions[index] = filepath.Join(pt.Pkg.Dir.ToStringDuringMigration(), output)
 	}
+	restoreOnlyGlobs := make([]string, len(pt.TaskDefinition.RestoreOnlyOutputs))
+	for index, output := range pt.TaskDefinition.RestoreOnlyOutputs {
+		restoreOnlyGlobs[index] = filepath.Join(pt.Pkg.Dir.ToStringDuringMigration(), output)
+	}
 
 	taskOutputMode := pt.TaskDefinition.OutputMode
 	if rc.taskOutputModeOverride != nil {
@@ -298,6 +841,7 @@ func (rc *RunCache) TaskCache(pt *nodes.PackageTask, hash string) TaskCache {
 	return TaskCache{
 		rc:                rc,
 		repoRelativeGlobs: repoRelativeGlobs,
+		restoreOnlyGlobs:  restoreOnlyGlobs,
 		hash:              hash,
 		pt:                pt,
 		taskOutputMode:    taskOutputMode,
@@ -306,13 +850,89 @@ func (rc *RunCache) TaskCache(pt *nodes.PackageTask, hash string) TaskCache {
 	}
 }
 
+// gzipReadCloser closes both the gzip stream and the file it reads from
+type gzipReadCloser struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (grc *gzipReadCloser) Close() error {
+	if err := grc.Reader.Close(); err != nil {
+		_ = grc.file.Close()
+		return err
+	}
+	return grc.file.Close()
+}
+
+// convertRestoredLog rewrites a restored log file that was saved with the other --compress-logs
+// setting to the name and format the task's log file has in this run. The setting isn't part
+// of the task's hash, so an artifact may hold the log in either format.
+func (tc TaskCache) convertRestoredLog() error {
+	if tc.LogFileName.FileExists() {
+		return nil
+	}
+	otherLogFileName := turbopath.AbsoluteSystemPath(strings.TrimSuffix(tc.LogFileName.ToString(), ".gz"))
+	if !tc.pt.CompressedLog {
+		otherLogFileName = turbopath.AbsoluteSystemPath(tc.LogFileName.ToString() + ".gz")
+	}
+	if !otherLogFileName.FileExists() {
+		return nil
+	}
+	restored, err := OpenLogFile(otherLogFileName)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = restored.Close() }()
+	output, err := tc.LogFileName.Create()
+	if err != nil {
+		return err
+	}
+	var writer io.WriteCloser = output
+	if tc.pt.CompressedLog {
+		writer = gzip.NewWriter(output)
+	}
+	if _, err := io.Copy(writer, restored); err != nil {
+		_ = output.Close()
+		return err
+	}
+	if tc.pt.CompressedLog {
+		if err := writer.Close(); err != nil {
+			_ = output.Close()
+			return err
+		}
+	}
+	if err := output.Close(); err != nil {
+		return err
+	}
+	return otherLogFileName.Remove()
+}
+
+// OpenLogFile opens a task's log file for reading. Log files stored gzipped, with a .gz
+// extension, are decompressed as they are read.
+func OpenLogFile(logFileName turbopath.AbsoluteSystemPath) (io.ReadCloser, error) {
+	f, err := logFileName.Open()
+	if err != nil {
+		return nil, err
+	}
+	if filepath.Ext(logFileName.ToString()) != ".gz" {
+		return f, nil
+	}
+	gzipReader, err := gzip.NewReader(f)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return &gzipReadCloser{Reader: gzipReader, file: f}, nil
+}
+
 // defaultLogReplayer will try to replay logs back to the given Ui instance
 func defaultLogReplayer(logger hclog.Logger, output *cli.PrefixedUi, logFileName turbopath.AbsoluteSystemPath) {
 	logger.Debug("start replaying logs")
-	f, err := logFileName.Open()
+	f, err := OpenLogFile(logFileName)
 	if err != nil {
 		output.Warn(fmt.Sprintf("error reading logs: %v", err))
 		logger.Error(fmt.Sprintf("error reading logs: %v", err.Error()))
+		return
 	}
 	defer func() { _ = f.Close() }()
 	scan := bufio.NewScanner(f)
@@ -0,0 +1,558 @@
+package runcache
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/mitchellh/cli"
+	"github.com/vercel/turbo/cli/internal/analytics"
+	"github.com/vercel/turbo/cli/internal/cache"
+	"github.com/vercel/turbo/cli/internal/cacheitem"
+	"github.com/vercel/turbo/cli/internal/fs"
+	"github.com/vercel/turbo/cli/internal/nodes"
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"github.com/vercel/turbo/cli/internal/util"
+	"gotest.tools/v3/assert"
+)
+
+func Test_outputFiles(t *testing.T) {
+	repoRoot := turbopath.AbsoluteSystemPath(t.TempDir())
+	for _, file := range []string{"apps/web/dist/b.js", "apps/web/dist/a.js"} {
+		path := turbopath.AnchoredUnixPath(file).ToSystemPath().RestoreAnchor(repoRoot)
+		assert.NilError(t, path.EnsureDir(), "EnsureDir")
+		assert.NilError(t, path.WriteFile([]byte("output"), 0644), "WriteFile")
+	}
+	tc := TaskCache{rc: &RunCache{repoRoot: repoRoot}}
+
+	files := tc.outputFiles([]turbopath.AnchoredSystemPath{
+		"",
+		turbopath.AnchoredUnixPath("apps/web/dist").ToSystemPath(),
+		turbopath.AnchoredUnixPath("apps/web/dist/b.js").ToSystemPath(),
+		turbopath.AnchoredUnixPath("apps/web/dist/a.js").ToSystemPath(),
+	})
+	assert.DeepEqual(t, files, []turbopath.AnchoredUnixPath{"apps/web/dist/a.js", "apps/web/dist/b.js"})
+}
+
+func TestTaskCache_CachingDisabled(t *testing.T) {
+	repoRoot := turbopath.AbsoluteSystemPath(t.TempDir())
+	stdout := &bytes.Buffer{}
+	rc := New(nil, repoRoot, Opts{Stdout: stdout}, nil)
+	tc := rc.TaskCache(&nodes.PackageTask{
+		TaskID:         "web#dev",
+		Pkg:            &fs.PackageJSON{Dir: "apps/web"},
+		TaskDefinition: &fs.TaskDefinition{ShouldCache: false, Outputs: fs.TaskOutputs{Inclusions: []string{"dist/**"}}},
+		LogFile:        "apps/web/.turbo/turbo-dev.log",
+	}, "hash")
+	logger := hclog.NewNullLogger()
+	terminal := cli.NewMockUi()
+
+	hit, restored, err := tc.RestoreOutputs(context.Background(), &cli.PrefixedUi{Ui: terminal}, logger)
+	assert.NilError(t, err, "RestoreOutputs")
+	assert.Assert(t, !hit)
+	assert.Equal(t, len(restored), 0)
+
+	// The output goes straight to the terminal, without a log file
+	writer, err := tc.OutputWriter("web:dev: ")
+	assert.NilError(t, err, "OutputWriter")
+	_, err = io.WriteString(writer, "ready\n")
+	assert.NilError(t, err, "Write")
+	assert.NilError(t, writer.Close(), "Close")
+	assert.Equal(t, stdout.String(), "web:dev: ready\n")
+
+	saved, err := tc.SaveOutputs(context.Background(), logger, terminal, 0)
+	assert.NilError(t, err, "SaveOutputs")
+	assert.Equal(t, len(saved), 0)
+
+	// Nothing was written to the repository, not even a log file or a cache manifest
+	entries, err := os.ReadDir(repoRoot.ToString())
+	assert.NilError(t, err, "ReadDir")
+	assert.Equal(t, len(entries), 0)
+}
+
+// partialCache restores only some of the files its manifest lists
+type partialCache struct {
+	manifest *cacheitem.Manifest
+	restores []turbopath.AnchoredUnixPath
+}
+
+func (c *partialCache) Fetch(anchor turbopath.AbsoluteSystemPath, hash string, files []string) (bool, []turbopath.AnchoredSystemPath, int, error) {
+	restored := []turbopath.AnchoredSystemPath{}
+	for _, file := range c.restores {
+		path := file.ToSystemPath().RestoreAnchor(anchor)
+		if err := path.EnsureDir(); err != nil {
+			return false, nil, 0, err
+		}
+		if err := path.WriteFile([]byte("output"), 0644); err != nil {
+			return false, nil, 0, err
+		}
+		restored = append(restored, file.ToSystemPath())
+	}
+	return true, restored, 0, nil
+}
+
+func (c *partialCache) Exists(hash string) cache.ItemStatus { return cache.ItemStatus{Local: true} }
+
+func (c *partialCache) Put(anchor turbopath.AbsoluteSystemPath, hash string, duration int, files []turbopath.AnchoredSystemPath) error {
+	return nil
+}
+
+func (c *partialCache) Clean(anchor turbopath.AbsoluteSystemPath) {}
+
+func (c *partialCache) CleanAll() {}
+
+func (c *partialCache) Shutdown() {}
+
+func (c *partialCache) ReadManifest(hash string) (*cacheitem.Manifest, error) {
+	return c.manifest, nil
+}
+
+func TestTaskCache_PartialRestore(t *testing.T) {
+	manifest := &cacheitem.Manifest{Files: []cacheitem.ManifestEntry{
+		{Path: "apps/web/dist/a.js", Type: cacheitem.ManifestTypeFile},
+		{Path: "apps/web/dist/b.js", Type: cacheitem.ManifestTypeFile},
+	}}
+	packageTask := &nodes.PackageTask{
+		TaskID:         "web#build",
+		Pkg:            &fs.PackageJSON{Dir: "apps/web"},
+		TaskDefinition: &fs.TaskDefinition{ShouldCache: true, Outputs: fs.TaskOutputs{Inclusions: []string{"dist/**"}}},
+		LogFile:        "apps/web/.turbo/turbo-build.log",
+	}
+	logger := hclog.NewNullLogger()
+
+	repoRoot := turbopath.AbsoluteSystemPath(t.TempDir())
+	partial := &partialCache{manifest: manifest, restores: []turbopath.AnchoredUnixPath{"apps/web/dist/a.js"}}
+	terminal := cli.NewMockUi()
+	hit, _, err := New(partial, repoRoot, Opts{}, nil).TaskCache(packageTask, "hash").RestoreOutputs(context.Background(), &cli.PrefixedUi{Ui: terminal}, logger)
+	assert.NilError(t, err, "RestoreOutputs")
+	assert.Assert(t, !hit, "a partial restore is a miss")
+	assert.Assert(t, strings.Contains(terminal.ErrorWriter.String(), "cache artifact is missing 1 of its outputs (apps/web/dist/b.js)"), terminal.ErrorWriter.String())
+	assert.Assert(t, !repoRoot.UntypedJoin("apps", "web", "dist", "a.js").FileExists(), "the partially restored outputs are removed")
+
+	repoRoot = turbopath.AbsoluteSystemPath(t.TempDir())
+	complete := &partialCache{manifest: manifest, restores: []turbopath.AnchoredUnixPath{"apps/web/dist/a.js", "apps/web/dist/b.js"}}
+	hit, restored, err := New(complete, repoRoot, Opts{}, nil).TaskCache(packageTask, "hash").RestoreOutputs(context.Background(), &cli.PrefixedUi{Ui: cli.NewMockUi()}, logger)
+	assert.NilError(t, err, "RestoreOutputs")
+	assert.Assert(t, hit, "a complete restore is a hit")
+	assert.DeepEqual(t, restored, []turbopath.AnchoredUnixPath{"apps/web/dist/a.js", "apps/web/dist/b.js"})
+}
+
+func TestTaskCache_VerifyOutputsMismatch(t *testing.T) {
+	manifest := &cacheitem.Manifest{Files: []cacheitem.ManifestEntry{
+		{Path: "apps/web/dist/a.js", Type: cacheitem.ManifestTypeFile, Hash: "not-the-hash"},
+	}}
+	packageTask := &nodes.PackageTask{
+		TaskID:         "web#build",
+		Pkg:            &fs.PackageJSON{Dir: "apps/web"},
+		TaskDefinition: &fs.TaskDefinition{ShouldCache: true, Outputs: fs.TaskOutputs{Inclusions: []string{"dist/**"}}},
+		LogFile:        "apps/web/.turbo/turbo-build.log",
+	}
+
+	repoRoot := turbopath.AbsoluteSystemPath(t.TempDir())
+	corrupt := &partialCache{manifest: manifest, restores: []turbopath.AnchoredUnixPath{"apps/web/dist/a.js"}}
+	terminal := cli.NewMockUi()
+	hit, _, err := New(corrupt, repoRoot, Opts{VerifyOutputs: true}, nil).TaskCache(packageTask, "hash").RestoreOutputs(context.Background(), &cli.PrefixedUi{Ui: terminal}, hclog.NewNullLogger())
+	assert.NilError(t, err, "RestoreOutputs")
+	assert.Assert(t, !hit, "outputs that don't match the manifest are a miss")
+	assert.Assert(t, strings.Contains(terminal.ErrorWriter.String(), "apps/web/dist/a.js has changed"), terminal.ErrorWriter.String())
+	assert.Assert(t, !repoRoot.UntypedJoin("apps", "web", "dist", "a.js").FileExists(), "the rejected outputs are removed")
+}
+
+func TestTaskCache_OutputHashes(t *testing.T) {
+	repoRoot := turbopath.AbsoluteSystemPath(t.TempDir())
+	writeFile := func(file string, contents string) {
+		path := turbopath.AnchoredUnixPath(file).ToSystemPath().RestoreAnchor(repoRoot)
+		assert.NilError(t, path.EnsureDir(), "EnsureDir")
+		assert.NilError(t, path.WriteFile([]byte(contents), 0644), "WriteFile")
+	}
+	packageTask := &nodes.PackageTask{
+		TaskID:         "web#build",
+		Pkg:            &fs.PackageJSON{Dir: "apps/web"},
+		TaskDefinition: &fs.TaskDefinition{ShouldCache: true, Outputs: fs.TaskOutputs{Inclusions: []string{".turbo/turbo-build.log", "dist/**"}}},
+		LogFile:        "apps/web/.turbo/turbo-build.log",
+	}
+	tc := New(nil, repoRoot, Opts{}, nil).TaskCache(packageTask, "hash")
+	logger := hclog.NewNullLogger()
+	terminal := cli.NewMockUi()
+
+	writeFile("apps/web/.turbo/turbo-build.log", "first execution")
+	writeFile("apps/web/dist/a.js", "a")
+	writeFile("apps/web/dist/b.js", "b")
+	first, err := tc.OutputHashes(logger, terminal)
+	assert.NilError(t, err, "OutputHashes")
+	assert.Equal(t, len(first), 2, "the log file isn't compared")
+
+	assert.NilError(t, tc.RemoveOutputs(logger, terminal), "RemoveOutputs")
+	assert.Assert(t, !repoRoot.UntypedJoin("apps", "web", "dist", "a.js").FileExists(), "outputs are removed")
+	assert.Assert(t, repoRoot.UntypedJoin("apps", "web", ".turbo", "turbo-build.log").FileExists(), "the log file is kept")
+
+	writeFile("apps/web/.turbo/turbo-build.log", "second execution")
+	writeFile("apps/web/dist/a.js", "a")
+	writeFile("apps/web/dist/b.js", "b, but different")
+	writeFile("apps/web/dist/c.js", "c")
+	second, err := tc.OutputHashes(logger, terminal)
+	assert.NilError(t, err, "OutputHashes")
+	assert.DeepEqual(t, DiffOutputHashes(first, second), []string{"apps/web/dist/b.js", "apps/web/dist/c.js"})
+	assert.DeepEqual(t, DiffOutputHashes(second, first), []string{"apps/web/dist/b.js", "apps/web/dist/c.js"})
+	assert.DeepEqual(t, DiffOutputHashes(first, first), []string{})
+}
+
+// recordingCache records the contents of the files of every artifact it is given, read from
+// the anchor at the time of the Put
+type recordingCache struct {
+	partialCache
+	puts map[string]map[turbopath.AnchoredUnixPath]string
+}
+
+func (c *recordingCache) Put(anchor turbopath.AbsoluteSystemPath, hash string, duration int, files []turbopath.AnchoredSystemPath) error {
+	contents := make(map[turbopath.AnchoredUnixPath]string)
+	for _, file := range files {
+		path := file.RestoreAnchor(anchor)
+		if path.DirExists() {
+			continue
+		}
+		data, err := path.ReadFile()
+		if err != nil {
+			return err
+		}
+		contents[file.ToUnixPath()] = string(data)
+	}
+	if c.puts == nil {
+		c.puts = make(map[string]map[turbopath.AnchoredUnixPath]string)
+	}
+	c.puts[hash] = contents
+	return nil
+}
+
+func TestTaskCache_DeferredWrites(t *testing.T) {
+	repoRoot := turbopath.AbsoluteSystemPath(t.TempDir())
+	stagingDir := repoRoot.UntypedJoin(".turbo", "deferred", "run")
+	output := repoRoot.UntypedJoin("apps", "web", "dist", "a.js")
+	packageTask := &nodes.PackageTask{
+		TaskID:         "web#build",
+		Pkg:            &fs.PackageJSON{Dir: "apps/web"},
+		TaskDefinition: &fs.TaskDefinition{ShouldCache: true, Outputs: fs.TaskOutputs{Inclusions: []string{"dist/**"}}},
+		LogFile:        "apps/web/.turbo/turbo-build.log",
+	}
+	logger := hclog.NewNullLogger()
+	terminal := cli.NewMockUi()
+
+	recording := &recordingCache{}
+	rc := New(recording, repoRoot, Opts{DeferWrites: true, StagingDir: stagingDir}, nil)
+	assert.NilError(t, output.EnsureDir(), "EnsureDir")
+	assert.NilError(t, output.WriteFile([]byte("built"), 0644), "WriteFile")
+	saved, err := rc.TaskCache(packageTask, "hash").SaveOutputs(context.Background(), logger, terminal, 0)
+	assert.NilError(t, err, "SaveOutputs")
+	assert.DeepEqual(t, saved, []turbopath.AnchoredUnixPath{"apps/web/dist/a.js"})
+	assert.Equal(t, len(recording.puts), 0, "nothing is cached before the run completes")
+
+	// Outputs modified later in the run don't change the staged artifact
+	assert.NilError(t, output.WriteFile([]byte("modified"), 0644), "WriteFile")
+	assert.NilError(t, rc.CommitDeferredWrites(context.Background(), logger, terminal), "CommitDeferredWrites")
+	assert.DeepEqual(t, recording.puts["hash"], map[turbopath.AnchoredUnixPath]string{"apps/web/dist/a.js": "built"})
+	assert.NilError(t, rc.RemoveIsolationDir(), "RemoveIsolationDir")
+	assert.Assert(t, !stagingDir.DirExists(), "staged outputs are removed")
+
+	// Discarded writes are never cached
+	recording = &recordingCache{}
+	rc = New(recording, repoRoot, Opts{DeferWrites: true, StagingDir: stagingDir}, nil)
+	_, err = rc.TaskCache(packageTask, "hash").SaveOutputs(context.Background(), logger, terminal, 0)
+	assert.NilError(t, err, "SaveOutputs")
+	assert.Equal(t, rc.DiscardDeferredWrites(), 1)
+	assert.NilError(t, rc.RemoveIsolationDir(), "RemoveIsolationDir")
+	assert.Equal(t, len(recording.puts), 0)
+	assert.Assert(t, !stagingDir.DirExists(), "staged outputs are removed")
+}
+
+// remoteCacheClient is a remote cache that keeps artifacts in memory
+type remoteCacheClient struct {
+	artifacts map[string][]byte
+}
+
+func (c *remoteCacheClient) PutArtifact(hash string, body []byte, duration int, tag string) error {
+	c.artifacts[hash] = body
+	return nil
+}
+
+func (c *remoteCacheClient) FetchArtifact(hash string) (*http.Response, error) {
+	artifact, ok := c.artifacts[hash]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(&bytes.Buffer{})}, nil
+	}
+	return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(bytes.NewReader(artifact))}, nil
+}
+
+func (c *remoteCacheClient) ArtifactExists(hash string) (*http.Response, error) {
+	return nil, nil
+}
+
+func (c *remoteCacheClient) GetTeamID() string {
+	return ""
+}
+
+func (c *remoteCacheClient) GetCachingStatus() (util.CachingStatus, error) {
+	return util.CachingStatusEnabled, nil
+}
+
+type nullRecorder struct{}
+
+func (nullRecorder) LogEvent(analytics.EventPayload) {}
+
+func TestTaskCache_IsolatedRemoteCache(t *testing.T) {
+	packageTask := &nodes.PackageTask{
+		TaskID:         "web#build",
+		Pkg:            &fs.PackageJSON{Dir: "apps/web"},
+		TaskDefinition: &fs.TaskDefinition{ShouldCache: true, Outputs: fs.TaskOutputs{Inclusions: []string{".turbo/turbo-build.log", "dist/**"}}},
+		LogFile:        "apps/web/.turbo/turbo-build.log",
+	}
+	logger := hclog.NewNullLogger()
+	terminal := cli.NewMockUi()
+	client := &remoteCacheClient{artifacts: make(map[string][]byte)}
+	newIsolatedRunCache := func(repoRoot turbopath.AbsoluteSystemPath) (*RunCache, cache.Cache) {
+		remote, err := cache.New(cache.Opts{SkipFilesystem: true}, repoRoot, client, nullRecorder{}, func(cache.Cache, error) {})
+		assert.NilError(t, err, "cache.New")
+		isolationDir := repoRoot.UntypedJoin(".turbo", "isolated", "run")
+		return New(remote, repoRoot, Opts{IsolationDir: isolationDir, Stdout: &bytes.Buffer{}}, nil), remote
+	}
+
+	// The artifact is uploaded from the snapshot of the outputs, along with the captured log
+	repoRoot := turbopath.AbsoluteSystemPath(t.TempDir())
+	rc, remote := newIsolatedRunCache(repoRoot)
+	tc := rc.TaskCache(packageTask, "hash")
+	writer, err := tc.OutputWriter("web:build: ")
+	assert.NilError(t, err, "OutputWriter")
+	_, err = io.WriteString(writer, "building\n")
+	assert.NilError(t, err, "Write")
+	assert.NilError(t, writer.Close(), "Close")
+	output := repoRoot.UntypedJoin("apps", "web", "dist", "a.js")
+	assert.NilError(t, output.EnsureDir(), "EnsureDir")
+	assert.NilError(t, output.WriteFile([]byte("built"), 0644), "WriteFile")
+	_, err = tc.SaveOutputs(context.Background(), logger, terminal, 0)
+	assert.NilError(t, err, "SaveOutputs")
+	assert.NilError(t, output.Remove(), "Remove")
+	remote.Shutdown()
+	assert.NilError(t, rc.RemoveIsolationDir(), "RemoveIsolationDir")
+	_, ok := client.artifacts["hash"]
+	assert.Assert(t, ok, "the artifact is uploaded")
+
+	// A hit restores the artifact into the repository through the isolation workspace
+	repoRoot = turbopath.AbsoluteSystemPath(t.TempDir())
+	rc, _ = newIsolatedRunCache(repoRoot)
+	hit, restored, err := rc.TaskCache(packageTask, "hash").RestoreOutputs(context.Background(), &cli.PrefixedUi{Ui: terminal}, logger)
+	assert.NilError(t, err, "RestoreOutputs")
+	assert.Assert(t, hit, "the artifact is restored")
+	assert.DeepEqual(t, restored, []turbopath.AnchoredUnixPath{"apps/web/.turbo/turbo-build.log", "apps/web/dist/a.js"})
+	contents, err := repoRoot.UntypedJoin("apps", "web", "dist", "a.js").ReadFile()
+	assert.NilError(t, err, "ReadFile")
+	assert.Equal(t, string(contents), "built")
+	contents, err = repoRoot.UntypedJoin("apps", "web", ".turbo", "turbo-build.log").ReadFile()
+	assert.NilError(t, err, "ReadFile")
+	assert.Equal(t, string(contents), "building\n")
+}
+
+func TestTaskCache_IsolatedLogOfFailedTask(t *testing.T) {
+	repoRoot := turbopath.AbsoluteSystemPath(t.TempDir())
+	rc := New(nil, repoRoot, Opts{IsolationDir: repoRoot.UntypedJoin(".turbo", "isolated", "run"), Stdout: &bytes.Buffer{}}, nil)
+	tc := rc.TaskCache(&nodes.PackageTask{
+		TaskID:         "web#build",
+		Pkg:            &fs.PackageJSON{Dir: "apps/web"},
+		TaskDefinition: &fs.TaskDefinition{ShouldCache: true},
+		LogFile:        "apps/web/.turbo/turbo-build.log",
+	}, "hash")
+
+	writer, err := tc.OutputWriter("web:build: ")
+	assert.NilError(t, err, "OutputWriter")
+	_, err = io.WriteString(writer, "error\n")
+	assert.NilError(t, err, "Write")
+	assert.NilError(t, writer.Close(), "Close")
+	assert.NilError(t, rc.RemoveIsolationDir(), "RemoveIsolationDir")
+
+	contents, err := tc.LogFileName.ReadFile()
+	assert.NilError(t, err, "the log is kept in the repository")
+	assert.Equal(t, string(contents), "error\n")
+}
+
+func TestTaskCache_RestoreOnlyOutputs(t *testing.T) {
+	packageTask := &nodes.PackageTask{
+		TaskID: "web#build",
+		Pkg:    &fs.PackageJSON{Dir: "apps/web"},
+		TaskDefinition: &fs.TaskDefinition{
+			ShouldCache:        true,
+			Outputs:            fs.TaskOutputs{Inclusions: []string{"dist/**"}},
+			RestoreOnlyOutputs: []string{"generated/**"},
+		},
+		LogFile: "apps/web/.turbo/turbo-build.log",
+	}
+	logger := hclog.NewNullLogger()
+	terminal := cli.NewMockUi()
+
+	// Restore-only outputs are left out of the artifact
+	repoRoot := turbopath.AbsoluteSystemPath(t.TempDir())
+	for _, file := range []string{"apps/web/dist/a.js", "apps/web/generated/schema.ts"} {
+		path := turbopath.AnchoredUnixPath(file).ToSystemPath().RestoreAnchor(repoRoot)
+		assert.NilError(t, path.EnsureDir(), "EnsureDir")
+		assert.NilError(t, path.WriteFile([]byte("built"), 0644), "WriteFile")
+	}
+	recording := &recordingCache{}
+	saved, err := New(recording, repoRoot, Opts{}, nil).TaskCache(packageTask, "hash").SaveOutputs(context.Background(), logger, terminal, 0)
+	assert.NilError(t, err, "SaveOutputs")
+	assert.DeepEqual(t, saved, []turbopath.AnchoredUnixPath{"apps/web/dist/a.js"})
+	assert.DeepEqual(t, recording.puts["hash"], map[turbopath.AnchoredUnixPath]string{"apps/web/dist/a.js": "built"})
+
+	// But restored when an artifact has them
+	repoRoot = turbopath.AbsoluteSystemPath(t.TempDir())
+	withGenerated := &partialCache{
+		manifest: &cacheitem.Manifest{Files: []cacheitem.ManifestEntry{
+			{Path: "apps/web/dist/a.js", Type: cacheitem.ManifestTypeFile},
+			{Path: "apps/web/generated/schema.ts", Type: cacheitem.ManifestTypeFile},
+		}},
+		restores: []turbopath.AnchoredUnixPath{"apps/web/dist/a.js", "apps/web/generated/schema.ts"},
+	}
+	hit, _, err := New(withGenerated, repoRoot, Opts{}, nil).TaskCache(packageTask, "hash").RestoreOutputs(context.Background(), &cli.PrefixedUi{Ui: terminal}, logger)
+	assert.NilError(t, err, "RestoreOutputs")
+	assert.Assert(t, hit)
+	assert.Assert(t, repoRoot.UntypedJoin("apps", "web", "generated", "schema.ts").FileExists())
+}
+
+// unchangedOutputWatcher reports that no outputs have changed, and records the outputs it
+// is told were written
+type unchangedOutputWatcher struct {
+	written []fs.TaskOutputs
+}
+
+func (w *unchangedOutputWatcher) GetChangedOutputs(ctx context.Context, hash string, repoRelativeOutputGlobs []string) ([]string, error) {
+	return []string{}, nil
+}
+
+func (w *unchangedOutputWatcher) NotifyOutputsWritten(ctx context.Context, hash string, repoRelativeOutputGlobs fs.TaskOutputs) error {
+	w.written = append(w.written, repoRelativeOutputGlobs)
+	return nil
+}
+
+func TestTaskCache_OutputsFromLogWatched(t *testing.T) {
+	repoRoot := turbopath.AbsoluteSystemPath(t.TempDir())
+	packageTask := &nodes.PackageTask{
+		TaskID: "web#build",
+		Pkg:    &fs.PackageJSON{Dir: "apps/web"},
+		TaskDefinition: &fs.TaskDefinition{
+			ShouldCache:    true,
+			Outputs:        fs.TaskOutputs{Inclusions: []string{"dist/**"}},
+			OutputsFromLog: "^wrote (.+)$",
+		},
+		LogFile: "apps/web/.turbo/turbo-build.log",
+	}
+	logger := hclog.NewNullLogger()
+	terminal := cli.NewMockUi()
+	report := turbopath.AnchoredUnixPath("apps/web/out/report.json").ToSystemPath()
+	assert.NilError(t, report.RestoreAnchor(repoRoot).EnsureDir(), "EnsureDir")
+	assert.NilError(t, report.RestoreAnchor(repoRoot).WriteFile([]byte("{}"), 0644), "WriteFile")
+
+	watcher := &unchangedOutputWatcher{}
+	tc := New(&recordingCache{}, repoRoot, Opts{OutputWatcher: watcher}, nil).TaskCache(packageTask, "hash")
+	tc.AddLogOutputs([]turbopath.AnchoredSystemPath{report})
+	_, err := tc.SaveOutputs(context.Background(), logger, terminal, 0)
+	assert.NilError(t, err, "SaveOutputs")
+	assert.Equal(t, len(watcher.written), 1)
+	inclusions := watcher.written[0].Inclusions
+	assert.Equal(t, inclusions[len(inclusions)-1], report.ToString())
+
+	// The watcher can't know which files the logs name, so the cache is always checked
+	complete := &partialCache{restores: []turbopath.AnchoredUnixPath{"apps/web/out/report.json"}}
+	hit, restored, err := New(complete, repoRoot, Opts{OutputWatcher: watcher}, nil).TaskCache(packageTask, "hash").RestoreOutputs(context.Background(), &cli.PrefixedUi{Ui: terminal}, logger)
+	assert.NilError(t, err, "RestoreOutputs")
+	assert.Assert(t, hit)
+	assert.DeepEqual(t, restored, []turbopath.AnchoredUnixPath{"apps/web/out/report.json"})
+	assert.Assert(t, !strings.Contains(terminal.ErrorWriter.String(), "outputs have not changed"), terminal.ErrorWriter.String())
+}
+
+func TestTaskCache_CompressedLogReplay(t *testing.T) {
+	logger := hclog.NewNullLogger()
+	replays := make([]string, 0, 2)
+	for _, compressed := range []bool{false, true} {
+		repoRoot := turbopath.AbsoluteSystemPath(t.TempDir())
+		packageTask := &nodes.PackageTask{
+			TaskID:         "web#build",
+			Task:           "build",
+			Pkg:            &fs.PackageJSON{Dir: "apps/web"},
+			TaskDefinition: &fs.TaskDefinition{ShouldCache: true},
+			CompressedLog:  compressed,
+		}
+		packageTask.LogFile = "apps/web/.turbo/" + packageTask.LogFileName()
+		tc := New(nil, repoRoot, Opts{Stdout: &bytes.Buffer{}}, nil).TaskCache(packageTask, "hash")
+
+		writer, err := tc.OutputWriter("web:build: ")
+		assert.NilError(t, err, "OutputWriter")
+		_, err = io.WriteString(writer, "compiling\n\n\ttrailing whitespace  \ndone")
+		assert.NilError(t, err, "Write")
+		assert.NilError(t, writer.Close(), "Close")
+
+		terminal := cli.NewMockUi()
+		tc.ReplayLogFile(&cli.PrefixedUi{Ui: terminal, OutputPrefix: "web:build: "}, logger)
+		replays = append(replays, terminal.OutputWriter.String())
+	}
+	assert.Equal(t, replays[1], replays[0])
+	assert.Equal(t, replays[0], "web:build: compiling\nweb:build: \nweb:build: \ttrailing whitespace  \nweb:build: done\n")
+}
+
+func TestTaskCache_RestoreLogOfOtherCompression(t *testing.T) {
+	repoRoot := turbopath.AbsoluteSystemPath(t.TempDir())
+	packageTask := &nodes.PackageTask{
+		TaskID:         "web#build",
+		Task:           "build",
+		Pkg:            &fs.PackageJSON{Dir: "apps/web"},
+		TaskDefinition: &fs.TaskDefinition{ShouldCache: true},
+		LogFile:        "apps/web/.turbo/turbo-build.log.gz",
+		CompressedLog:  true,
+	}
+	// The artifact was saved without --compress-logs
+	saved := &partialCache{restores: []turbopath.AnchoredUnixPath{"apps/web/.turbo/turbo-build.log"}}
+	tc := New(saved, repoRoot, Opts{}, nil).TaskCache(packageTask, "hash")
+	hit, _, err := tc.RestoreOutputs(context.Background(), &cli.PrefixedUi{Ui: cli.NewMockUi()}, hclog.NewNullLogger())
+	assert.NilError(t, err, "RestoreOutputs")
+	assert.Assert(t, hit)
+
+	assert.Assert(t, !repoRoot.UntypedJoin("apps", "web", ".turbo", "turbo-build.log").FileExists())
+	restored, err := OpenLogFile(tc.LogFileName)
+	assert.NilError(t, err, "OpenLogFile")
+	defer func() { _ = restored.Close() }()
+	contents, err := io.ReadAll(restored)
+	assert.NilError(t, err, "ReadAll")
+	assert.Equal(t, string(contents), "output")
+}
+
+func TestTaskCache_ExcludedOutputs(t *testing.T) {
+	repoRoot := turbopath.AbsoluteSystemPath(t.TempDir())
+	for _, file := range []string{"dist/index.js", "dist/cache/a/b/stale.js", "dist/cache/manifest.json", "dist/cachefile.js"} {
+		path := repoRoot.UntypedJoin("apps", "web", file)
+		assert.NilError(t, path.EnsureDir(), "EnsureDir")
+		assert.NilError(t, path.WriteFile([]byte("output"), 0644), "WriteFile")
+	}
+	packageTask := &nodes.PackageTask{
+		TaskID: "web#build",
+		Pkg:    &fs.PackageJSON{Dir: "apps/web"},
+		TaskDefinition: &fs.TaskDefinition{ShouldCache: true, Outputs: fs.TaskOutputs{
+			Inclusions: []string{"dist/**"},
+			Exclusions: []string{"dist/cache/**"},
+		}},
+		LogFile: "apps/web/.turbo/turbo-build.log",
+	}
+	tc := New(&recordingCache{}, repoRoot, Opts{}, nil).TaskCache(packageTask, "hash")
+
+	saved, err := tc.SaveOutputs(context.Background(), hclog.NewNullLogger(), cli.NewMockUi(), 0)
+	assert.NilError(t, err, "SaveOutputs")
+	assert.DeepEqual(t, saved, []turbopath.AnchoredUnixPath{"apps/web/dist/cachefile.js", "apps/web/dist/index.js"})
+	excluded, err := tc.ExcludedOutputs()
+	assert.NilError(t, err, "ExcludedOutputs")
+	assert.DeepEqual(t, excluded, []turbopath.AnchoredUnixPath{"apps/web/dist/cache/a/b/stale.js", "apps/web/dist/cache/manifest.json"})
+
+	// Without exclusions, nothing is reported
+	packageTask.TaskDefinition.Outputs.Exclusions = nil
+	excluded, err = New(&recordingCache{}, repoRoot, Opts{}, nil).TaskCache(packageTask, "hash").ExcludedOutputs()
+	assert.NilError(t, err, "ExcludedOutputs")
+	assert.Assert(t, excluded == nil)
+}
@@ -0,0 +1,213 @@
+// Package runstats keeps a history of the durations and cache outcomes of the tasks of
+// recent runs, and summarizes it for `turbo stats`
+package runstats
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vercel/turbo/cli/internal/turbopath"
+)
+
+// Version is the version of the stats file format written by this version of turbo.
+// Stats files with a different version are discarded rather than migrated.
+const Version = 1
+
+// DefaultMaxRuns is the number of runs kept in the history unless it is changed with
+// `turbo stats --max-runs`
+const DefaultMaxRuns = 100
+
+// Outcomes of a task that are recorded. Failed and canceled tasks are not recorded, their
+// durations don't say how long the task takes.
+const (
+	OutcomeExecuted = "executed"
+	OutcomeCached   = "cached"
+)
+
+// History is the content of the stats file
+type History struct {
+	Version int `json:"version"`
+	// MaxRuns is the number of most recent runs that are kept
+	MaxRuns int `json:"maxRuns"`
+	// Runs are ordered from oldest to newest
+	Runs []*Run `json:"runs"`
+}
+
+// Run holds the recorded tasks of a single `turbo run`
+type Run struct {
+	ID        string    `json:"id"`
+	StartedAt time.Time `json:"startedAt"`
+	Tasks     []Task    `json:"tasks"`
+}
+
+// Task is the outcome of a single task of a run
+type Task struct {
+	TaskID  string `json:"taskId"`
+	Outcome string `json:"outcome"`
+	// DurationMs is how long the task took, including restoring it from the cache
+	DurationMs int64 `json:"durationMs"`
+}
+
+// TaskReport summarizes the history of a single task
+type TaskReport struct {
+	TaskID string
+	// Executed is the number of runs in which the task was executed
+	Executed int
+	// Cached is the number of runs in which the task was restored from the cache
+	Cached int
+	// P50 and P95 are percentiles of the durations of the task's executions. Cache hits
+	// are excluded, they are fast regardless of the task. Both are zero if the task was
+	// never executed.
+	P50 time.Duration
+	P95 time.Duration
+}
+
+// HitRate returns the fraction of the recorded runs of the task that were cache hits
+func (tr *TaskReport) HitRate() float64 {
+	return float64(tr.Cached) / float64(tr.Executed+tr.Cached)
+}
+
+// Path returns the location of the stats file of the repository
+func Path(repoRoot turbopath.AbsoluteSystemPath) turbopath.AbsoluteSystemPath {
+	return repoRoot.UntypedJoin(".turbo", "stats.json")
+}
+
+// Load reads the stats file at path. A missing file, or one written in an unsupported
+// version, is an empty history.
+func Load(path turbopath.AbsoluteSystemPath) (*History, error) {
+	history := &History{Version: Version, MaxRuns: DefaultMaxRuns}
+	contents, err := path.ReadFile()
+	if os.IsNotExist(err) {
+		return history, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var stored History
+	if err := json.Unmarshal(contents, &stored); err != nil {
+		return nil, fmt.Errorf("malformed stats file %v: %w", path, err)
+	}
+	if stored.Version != Version {
+		return history, nil
+	}
+	if stored.MaxRuns > 0 {
+		history.MaxRuns = stored.MaxRuns
+	}
+	history.Runs = stored.Runs
+	return history, nil
+}
+
+// Save writes the history to the stats file at path
+func (h *History) Save(path turbopath.AbsoluteSystemPath) error {
+	contents, err := json.Marshal(h)
+	if err != nil {
+		return err
+	}
+	if err := path.EnsureDir(); err != nil {
+		return err
+	}
+	// Write to a temporary file first, so that concurrent runs never see a partial file
+	tempFile, err := os.CreateTemp(path.Dir().ToString(), ".stats-*.json")
+	if err != nil {
+		return err
+	}
+	tempPath := turbopath.AbsoluteSystemPathFromUpstream(tempFile.Name())
+	_, err = tempFile.Write(contents)
+	if err == nil {
+		err = tempFile.Chmod(0644)
+	}
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = tempPath.Remove()
+		return err
+	}
+	return tempPath.Rename(path)
+}
+
+// recordMu serializes the updates of the stats file made by this process, so that runs
+// recorded at the same time, such as by `turbo watch`, don't drop each other
+var recordMu sync.Mutex
+
+// Record adds a run to the history in the stats file at path. It is safe to call concurrently.
+func Record(path turbopath.AbsoluteSystemPath, run *Run) error {
+	recordMu.Lock()
+	defer recordMu.Unlock()
+	history, err := Load(path)
+	if err != nil {
+		return err
+	}
+	history.Add(run)
+	return history.Save(path)
+}
+
+// Add appends a run to the history, dropping the oldest runs beyond MaxRuns
+func (h *History) Add(run *Run) {
+	h.Runs = append(h.Runs, run)
+	h.Trim()
+}
+
+// Trim drops the oldest runs beyond MaxRuns
+func (h *History) Trim() {
+	if excess := len(h.Runs) - h.MaxRuns; excess > 0 {
+		h.Runs = h.Runs[excess:]
+	}
+}
+
+// Report summarizes each recorded task, slowest first. Tasks are ordered by the 95th
+// percentile of their durations, then by ID.
+func (h *History) Report() []*TaskReport {
+	durations := make(map[string][]time.Duration)
+	reports := make(map[string]*TaskReport)
+	for _, run := range h.Runs {
+		for _, task := range run.Tasks {
+			report, ok := reports[task.TaskID]
+			if !ok {
+				report = &TaskReport{TaskID: task.TaskID}
+				reports[task.TaskID] = report
+			}
+			switch task.Outcome {
+			case OutcomeExecuted:
+				report.Executed++
+				durations[task.TaskID] = append(durations[task.TaskID], time.Duration(task.DurationMs)*time.Millisecond)
+			case OutcomeCached:
+				report.Cached++
+			}
+		}
+	}
+
+	sorted := make([]*TaskReport, 0, len(reports))
+	for taskID, report := range reports {
+		if report.Executed+report.Cached == 0 {
+			continue
+		}
+		taskDurations := durations[taskID]
+		sort.Slice(taskDurations, func(i, j int) bool { return taskDurations[i] < taskDurations[j] })
+		report.P50 = percentile(taskDurations, 50)
+		report.P95 = percentile(taskDurations, 95)
+		sorted = append(sorted, report)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].P95 != sorted[j].P95 {
+			return sorted[i].P95 > sorted[j].P95
+		}
+		return sorted[i].TaskID < sorted[j].TaskID
+	})
+	return sorted
+}
+
+// percentile returns the nearest-rank percentile p of the sorted durations
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
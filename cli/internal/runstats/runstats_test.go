@@ -0,0 +1,105 @@
+package runstats
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"gotest.tools/v3/assert"
+)
+
+func Test_History(t *testing.T) {
+	path := turbopath.AbsoluteSystemPathFromUpstream(t.TempDir()).UntypedJoin(".turbo", "stats.json")
+
+	history, err := Load(path)
+	assert.NilError(t, err, "Load missing")
+	assert.Equal(t, history.MaxRuns, DefaultMaxRuns)
+	assert.Equal(t, len(history.Runs), 0)
+
+	history.MaxRuns = 3
+	for i := 0; i < 5; i++ {
+		history.Add(&Run{ID: fmt.Sprintf("run-%v", i)})
+	}
+	assert.NilError(t, history.Save(path), "Save")
+
+	assert.NilError(t, Record(path, &Run{ID: "run-5"}), "Record")
+	loaded, err := Load(path)
+	assert.NilError(t, err, "Load")
+	assert.Equal(t, loaded.MaxRuns, 3)
+	ids := []string{}
+	for _, run := range loaded.Runs {
+		ids = append(ids, run.ID)
+	}
+	assert.DeepEqual(t, ids, []string{"run-3", "run-4", "run-5"})
+
+	// A stats file written in another version is discarded
+	assert.NilError(t, path.WriteFile([]byte(`{"version": 2, "maxRuns": 3, "runs": [{"id": "future"}]}`), 0644))
+	loaded, err = Load(path)
+	assert.NilError(t, err, "Load other version")
+	assert.Equal(t, len(loaded.Runs), 0)
+	assert.Equal(t, loaded.MaxRuns, DefaultMaxRuns)
+}
+
+func Test_Report(t *testing.T) {
+	history := &History{MaxRuns: DefaultMaxRuns}
+	for i := 1; i <= 20; i++ {
+		run := &Run{Tasks: []Task{
+			// web#build takes i seconds, and is a cache hit every fourth run
+			{TaskID: "web#build", Outcome: OutcomeExecuted, DurationMs: int64(i * 1000)},
+			{TaskID: "ui#lint", Outcome: OutcomeCached, DurationMs: 20},
+		}}
+		if i%4 == 0 {
+			run.Tasks[0] = Task{TaskID: "web#build", Outcome: OutcomeCached, DurationMs: 100}
+		}
+		if i <= 2 {
+			run.Tasks = append(run.Tasks, Task{TaskID: "docs#build", Outcome: OutcomeExecuted, DurationMs: 500})
+		}
+		history.Add(run)
+	}
+
+	reports := history.Report()
+	assert.Equal(t, len(reports), 3)
+
+	build := reports[0]
+	assert.Equal(t, build.TaskID, "web#build")
+	assert.Equal(t, build.Executed, 15)
+	assert.Equal(t, build.Cached, 5)
+	assert.Equal(t, build.HitRate(), 0.25)
+	// The executed durations are 1-19s, skipping multiples of 4
+	assert.Equal(t, build.P50, 10*time.Second)
+	assert.Equal(t, build.P95, 19*time.Second)
+
+	assert.Equal(t, reports[1].TaskID, "docs#build")
+	assert.Equal(t, reports[1].P50, 500*time.Millisecond)
+
+	lint := reports[2]
+	assert.Equal(t, lint.TaskID, "ui#lint")
+	assert.Equal(t, lint.Executed, 0)
+	assert.Equal(t, lint.HitRate(), 1.0)
+	assert.Equal(t, lint.P95, time.Duration(0))
+}
+
+func Test_RecordConcurrently(t *testing.T) {
+	path := turbopath.AbsoluteSystemPathFromUpstream(t.TempDir()).UntypedJoin(".turbo", "stats.json")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		run := &Run{ID: fmt.Sprintf("run-%v", i), Tasks: []Task{{TaskID: "web#build", Outcome: OutcomeExecuted, DurationMs: 10}}}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Check(t, Record(path, run), "Record")
+		}()
+	}
+	wg.Wait()
+
+	history, err := Load(path)
+	assert.NilError(t, err, "Load")
+	assert.Equal(t, len(history.Runs), 10, "every run is recorded")
+	entries, err := os.ReadDir(path.Dir().ToString())
+	assert.NilError(t, err, "ReadDir")
+	assert.Equal(t, len(entries), 1, "no temporary files are left behind")
+}
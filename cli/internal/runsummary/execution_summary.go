@@ -1,13 +1,16 @@
 package runsummary
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/vercel/turbo/cli/internal/chrometracing"
 	"github.com/vercel/turbo/cli/internal/fs"
+	"github.com/vercel/turbo/cli/internal/process"
 
 	"github.com/mitchellh/cli"
 )
@@ -25,6 +28,8 @@ type executionEvent struct {
 	Status executionEventName
 	// Error, only populated for failure statuses
 	Err error
+	// Why the run was canceled, only populated for the canceled status
+	CancellationReason string
 }
 
 // executionEventName represents the status of a target when we log a build result.
@@ -37,6 +42,10 @@ const (
 	TargetBuilt
 	TargetCached
 	TargetBuildFailed
+	TargetCanceled
+	TargetBuildTimeout
+	// TargetNoop is a task whose package has no script for it, so there was nothing to run
+	TargetNoop
 )
 
 func (en executionEventName) toString() string {
@@ -51,6 +60,12 @@ func (en executionEventName) toString() string {
 		return "cached"
 	case TargetBuildFailed:
 		return "buildFailed"
+	case TargetCanceled:
+		return "canceled"
+	case TargetBuildTimeout:
+		return "buildTimeout"
+	case TargetNoop:
+		return "noCommand"
 	}
 
 	return ""
@@ -71,21 +86,50 @@ type TaskExecutionSummary struct {
 
 	// Error, only populated for failure statuses
 	Err error `json:"error"`
+
+	// The category configured in turbo.json for the exit code of a failed task, if any
+	FailureCategory string `json:"failureCategory,omitempty"`
+
+	// Why the run was canceled while the task was in flight, only populated for canceled tasks
+	CancellationReason string `json:"cancellationReason,omitempty"`
+
+	// Whether the task's outputs were newly uploaded to the remote cache during this run
+	UploadedToRemoteCache bool `json:"uploadedToRemoteCache,omitempty"`
+
+	// Whether the task bypassed the cache because it depends on a task given to --rerun-dependents-of
+	ForcedRerun bool `json:"forcedRerun,omitempty"`
+
+	// How many times the task's command was run, only populated for tasks that allow retries
+	Attempts int `json:"attempts,omitempty"`
 }
 
 // executionSummary is the state of the entire `turbo run`. Individual task state in `Tasks` field
 type executionSummary struct {
 	// mu guards reads/writes to the `state` field
-	mu        sync.Mutex                       `json:"-"`
-	state     map[string]*TaskExecutionSummary `json:"-"` // key is a taskID
-	Success   int                              `json:"success"`
-	Failure   int                              `json:"failed"`
-	Cached    int                              `json:"cached"`
-	Attempted int                              `json:"attempted"`
+	mu      sync.Mutex                       `json:"-"`
+	state   map[string]*TaskExecutionSummary `json:"-"` // key is a taskID
+	Success int                              `json:"success"`
+	Failure int                              `json:"failed"`
+	Cached  int                              `json:"cached"`
+	// Canceled tasks were interrupted by the run shutting down. They aren't counted as attempted.
+	Canceled int `json:"canceled"`
+	// TimedOut tasks are also counted as failed
+	TimedOut int `json:"timedOut,omitempty"`
+	// NoCommand tasks had no script to run. They aren't counted as attempted.
+	NoCommand int `json:"noCommand,omitempty"`
+	Attempted int `json:"attempted"`
+	// Skipped are the IDs of the tasks that didn't run because a task they depend on failed.
+	// They are only recorded when running with --continue.
+	Skipped []string `json:"skipped,omitempty"`
+	// ExitCode is the exit code of `turbo run`, recorded when the run is closed
+	ExitCode int `json:"exitCode"`
 
 	startedAt time.Time
 
 	profileFilename string
+
+	// events writes each task's start and finish, with --events-socket
+	events *taskEventWriter
 }
 
 // newExecutionSummary creates a executionSummary instance to track events in a `turbo run`.`
@@ -98,6 +142,7 @@ func newExecutionSummary(start time.Time, tracingProfile string) *executionSumma
 		Success:         0,
 		Failure:         0,
 		Cached:          0,
+		Canceled:        0,
 		Attempted:       0,
 		state:           make(map[string]*TaskExecutionSummary),
 		startedAt:       start,
@@ -107,20 +152,22 @@ func newExecutionSummary(start time.Time, tracingProfile string) *executionSumma
 
 // Run starts the Execution of a single task. It returns a function that can
 // be used to update the state of a given taskID with the executionEventName enum
-func (es *executionSummary) run(label string) (func(outcome executionEventName, err error), *TaskExecutionSummary) {
+func (es *executionSummary) run(label string, hash string) (func(outcome executionEventName, err error), *TaskExecutionSummary) {
 	start := time.Now()
-	taskExecutionSummary := es.add(&executionEvent{
+	startEvent := &executionEvent{
 		Time:   start,
 		Label:  label,
 		Status: targetBuilding,
-	})
+	}
+	taskExecutionSummary := es.add(startEvent)
+	es.events.write(startEvent, hash)
 
-	tracer := chrometracing.Event(label)
+	tracer := chrometracing.Complete(label)
 
 	// This function can be called with an enum and an optional error to update
 	// the state of a given taskID.
 	tracerFn := func(outcome executionEventName, err error) {
-		defer tracer.Done()
+		defer tracer.Done(traceArgs(hash, outcome))
 		now := time.Now()
 		result := &executionEvent{
 			Time:     now,
@@ -128,47 +175,115 @@ func (es *executionSummary) run(label string) (func(outcome executionEventName,
 			Label:    label,
 			Status:   outcome,
 		}
-		if err != nil {
+		if outcome == TargetCanceled {
+			// The error explains why the run was canceled, it isn't a failure of this task
+			if err != nil {
+				result.CancellationReason = err.Error()
+			}
+		} else if err != nil {
 			result.Err = fmt.Errorf("running %v failed: %w", label, err)
 		}
 		// Ignore the return value here
 		es.add(result)
+		es.events.write(result, hash)
 	}
 
 	return tracerFn, taskExecutionSummary
 }
 
+// traceArgs describes the outcome of a task in its --profile trace event
+func traceArgs(hash string, outcome executionEventName) map[string]string {
+	args := map[string]string{"status": outcome.toString()}
+	if hash != "" {
+		args["hash"] = hash
+	}
+	// Tasks without a command never look up the cache
+	if outcome == TargetCached {
+		args["cache"] = "HIT"
+	} else if outcome != TargetNoop {
+		args["cache"] = "MISS"
+	}
+	return args
+}
+
 func (es *executionSummary) add(event *executionEvent) *TaskExecutionSummary {
+	failureCategory := ""
+	childExit := &process.ChildExit{}
+	if errors.As(event.Err, &childExit) {
+		failureCategory = childExit.Category
+	}
+
 	es.mu.Lock()
 	defer es.mu.Unlock()
 	if s, ok := es.state[event.Label]; ok {
 		s.Status = event.Status.toString()
 		s.Err = event.Err
 		s.Duration = event.Duration
+		s.FailureCategory = failureCategory
+		s.CancellationReason = event.CancellationReason
 	} else {
 		es.state[event.Label] = &TaskExecutionSummary{
-			StartAt:  event.Time,
-			Label:    event.Label,
-			Status:   event.Status.toString(),
-			Err:      event.Err,
-			Duration: event.Duration,
+			StartAt:            event.Time,
+			Label:              event.Label,
+			Status:             event.Status.toString(),
+			Err:                event.Err,
+			Duration:           event.Duration,
+			FailureCategory:    failureCategory,
+			CancellationReason: event.CancellationReason,
 		}
 	}
 	switch {
 	case event.Status == TargetBuildFailed:
 		es.Failure++
 		es.Attempted++
+	case event.Status == TargetBuildTimeout:
+		es.Failure++
+		es.TimedOut++
+		es.Attempted++
 	case event.Status == TargetCached:
 		es.Cached++
 		es.Attempted++
 	case event.Status == TargetBuilt:
 		es.Success++
 		es.Attempted++
+	case event.Status == TargetCanceled:
+		es.Canceled++
+	case event.Status == TargetNoop:
+		es.NoCommand++
 	}
 
 	return es.state[event.Label]
 }
 
+// failureCategoryCounts returns how many failed tasks fall into each configured failure category
+func (es *executionSummary) failureCategoryCounts() map[string]int {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	counts := make(map[string]int)
+	for _, s := range es.state {
+		if s.FailureCategory != "" {
+			counts[s.FailureCategory]++
+		}
+	}
+	return counts
+}
+
+// cancellationReasons returns the sorted, distinct reasons that in-flight tasks were canceled
+func (es *executionSummary) cancellationReasons() []string {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	seen := make(map[string]bool)
+	reasons := []string{}
+	for _, s := range es.state {
+		if s.CancellationReason != "" && !seen[s.CancellationReason] {
+			seen[s.CancellationReason] = true
+			reasons = append(reasons, s.CancellationReason)
+		}
+	}
+	sort.Strings(reasons)
+	return reasons
+}
+
 // writeChromeTracing writes to a profile name if the `--profile` flag was passed to turbo run
 func writeChrometracing(filename string, terminal cli.Ui) error {
 	outputPath := chrometracing.Path()
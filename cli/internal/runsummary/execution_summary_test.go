@@ -0,0 +1,89 @@
+package runsummary
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"gotest.tools/v3/assert"
+)
+
+func TestExecutionSummary_counts(t *testing.T) {
+	es := newExecutionSummary(time.Now(), "")
+	outcomes := map[string]executionEventName{
+		"web#build":  TargetBuilt,
+		"docs#build": TargetCached,
+		"api#build":  TargetBuildFailed,
+		"ui#build":   TargetBuildTimeout,
+		"e2e#test":   TargetCanceled,
+		"cli#test":   TargetCanceled,
+		"lint#lint":  TargetNoop,
+	}
+	for taskID, outcome := range outcomes {
+		tracer, _ := es.run(taskID, "")
+		tracer(outcome, nil)
+	}
+
+	assert.Equal(t, es.Success, 1)
+	assert.Equal(t, es.Cached, 1)
+	assert.Equal(t, es.Failure, 2)
+	assert.Equal(t, es.TimedOut, 1)
+	assert.Equal(t, es.Canceled, 2)
+	assert.Equal(t, es.NoCommand, 1)
+	// Canceled tasks and tasks without a command weren't attempted
+	assert.Equal(t, es.Attempted, 4)
+}
+
+func TestExecutionSummary_cancellationReasons(t *testing.T) {
+	summary := NewRunSummary(time.Now(), "", "", nil, nil)
+	built, _ := summary.TrackTask("web#build", "")
+	built(TargetBuilt, nil)
+	for _, taskID := range []string{"api#build", "docs#build"} {
+		tracer, execution := summary.TrackTask(taskID, "")
+		tracer(TargetCanceled, errors.New("api#test failed"))
+		assert.Equal(t, execution.Status, "canceled")
+		assert.Equal(t, execution.CancellationReason, "api#test failed")
+		assert.NilError(t, execution.Err)
+	}
+	interrupted, _ := summary.TrackTask("ui#build", "")
+	interrupted(TargetCanceled, errors.New("interrupted"))
+
+	assert.DeepEqual(t, summary.ExecutionSummary.cancellationReasons(), []string{"api#test failed", "interrupted"})
+
+	terminal := cli.NewMockUi()
+	summary.Close(1, terminal)
+	output := terminal.OutputWriter.String()
+	assert.Assert(t, strings.Contains(output, "1 successful"), output)
+	assert.Assert(t, strings.Contains(output, "1 total"), output)
+	assert.Assert(t, strings.Contains(output, "Canceled:"), output)
+	assert.Assert(t, strings.Contains(output, "(api#test failed, interrupted)"), output)
+}
+
+func TestExecutionSummary_noCommand(t *testing.T) {
+	summary := NewRunSummary(time.Now(), "", "", nil, nil)
+	tracer, execution := summary.TrackTask("lint#lint", "")
+	tracer(TargetNoop, nil)
+	assert.Equal(t, execution.Status, "noCommand")
+	assert.NilError(t, execution.Err)
+
+	encoded, err := json.Marshal(summary.ExecutionSummary)
+	assert.NilError(t, err, "Marshal")
+	assert.Assert(t, strings.Contains(string(encoded), `"noCommand":1`), string(encoded))
+	assert.Assert(t, strings.Contains(string(encoded), `"attempted":0`), string(encoded))
+
+	// Without any task that was attempted, the run reports that nothing was executed
+	terminal := cli.NewMockUi()
+	summary.Close(0, terminal)
+	assert.Assert(t, strings.Contains(terminal.ErrorWriter.String(), "No tasks were executed"), terminal.ErrorWriter.String())
+}
+
+func TestExecutionSummary_traceArgs(t *testing.T) {
+	assert.DeepEqual(t, traceArgs("abc123", TargetCached), map[string]string{"status": "cached", "hash": "abc123", "cache": "HIT"})
+	assert.DeepEqual(t, traceArgs("abc123", TargetBuilt), map[string]string{"status": "built", "hash": "abc123", "cache": "MISS"})
+	assert.DeepEqual(t, traceArgs("abc123", TargetBuildFailed), map[string]string{"status": "buildFailed", "hash": "abc123", "cache": "MISS"})
+	// Tasks without a command never look up the cache
+	assert.DeepEqual(t, traceArgs("", TargetNoop), map[string]string{"status": "noCommand"})
+}
@@ -1,7 +1,10 @@
 package runsummary
 
 import (
+	"fmt"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/fatih/color"
@@ -12,7 +15,9 @@ import (
 
 func (summary *RunSummary) printExecutionSummary(ui cli.Ui) {
 	maybeFullTurbo := ""
-	if summary.ExecutionSummary.Cached == summary.ExecutionSummary.Attempted && summary.ExecutionSummary.Attempted > 0 {
+	if summary.Simulated {
+		maybeFullTurbo = util.Sprintf("${BOLD_YELLOW}(simulated, no commands were executed)${RESET}")
+	} else if summary.ExecutionSummary.Cached == summary.ExecutionSummary.Attempted && summary.ExecutionSummary.Attempted > 0 {
 		terminalProgram := os.Getenv("TERM_PROGRAM")
 		// On the macOS Terminal, the rainbow colors show up as a magenta background
 		// with a gray background on a single letter. Instead, we print in bold magenta
@@ -30,7 +35,41 @@ func (summary *RunSummary) printExecutionSummary(ui cli.Ui) {
 	}
 	ui.Output("") // Clear the line
 	ui.Output(util.Sprintf("${BOLD} Tasks:${BOLD_GREEN}    %v successful${RESET}${GRAY}, %v total${RESET}", summary.ExecutionSummary.Cached+summary.ExecutionSummary.Success, summary.ExecutionSummary.Attempted))
-	ui.Output(util.Sprintf("${BOLD}Cached:    %v cached${RESET}${GRAY}, %v total${RESET}", summary.ExecutionSummary.Cached, summary.ExecutionSummary.Attempted))
+	if categoryCounts := summary.ExecutionSummary.failureCategoryCounts(); len(categoryCounts) > 0 {
+		categories := make([]string, 0, len(categoryCounts))
+		for category := range categoryCounts {
+			categories = append(categories, category)
+		}
+		sort.Strings(categories)
+		for i, category := range categories {
+			categories[i] = fmt.Sprintf("%v %v", categoryCounts[category], category)
+		}
+		ui.Output(util.Sprintf("${BOLD}Failed:    ${BOLD_RED}%v${RESET}", strings.Join(categories, ", ")))
+	}
+	if summary.ExecutionSummary.TimedOut > 0 {
+		ui.Output(util.Sprintf("${BOLD}Timed out: ${BOLD_RED}%v${RESET}", summary.ExecutionSummary.TimedOut))
+	}
+	if summary.ExecutionSummary.Canceled > 0 {
+		maybeReasons := ""
+		if reasons := summary.ExecutionSummary.cancellationReasons(); len(reasons) > 0 {
+			maybeReasons = fmt.Sprintf(" (%v)", strings.Join(reasons, ", "))
+		}
+		ui.Output(util.Sprintf("${BOLD}Canceled:  ${BOLD_YELLOW}%v${RESET}${GRAY}%v${RESET}", summary.ExecutionSummary.Canceled, maybeReasons))
+	}
+	if skipped := summary.ExecutionSummary.Skipped; len(skipped) > 0 {
+		ui.Output(util.Sprintf("${BOLD}Skipped:   ${BOLD_YELLOW}%v${RESET}${GRAY} because a dependency failed (%v)${RESET}", len(skipped), strings.Join(skipped, ", ")))
+	}
+	maybeHitRate := ""
+	if summary.ExecutionSummary.Attempted > 0 {
+		maybeHitRate = fmt.Sprintf(" (%.0f%% cache hit)", 100*float64(summary.ExecutionSummary.Cached)/float64(summary.ExecutionSummary.Attempted))
+	}
+	ui.Output(util.Sprintf("${BOLD}Cached:    %v cached${RESET}${GRAY}, %v built, %v failed, %v total%v${RESET}", summary.ExecutionSummary.Cached, summary.ExecutionSummary.Success, summary.ExecutionSummary.Failure, summary.ExecutionSummary.Attempted, maybeHitRate))
+	if forced := summary.forcedRerunTaskIDs(); len(forced) > 0 {
+		ui.Output(util.Sprintf("${BOLD}Forced:    %v rerun${RESET}${GRAY} (%v)${RESET}", len(forced), strings.Join(forced, ", ")))
+	}
+	if uploaded := summary.uploadedTaskIDs(); len(uploaded) > 0 {
+		ui.Output(util.Sprintf("${BOLD}Uploaded:  %v to remote cache${RESET}${GRAY} (%v)${RESET}", len(uploaded), strings.Join(uploaded, ", ")))
+	}
 	ui.Output(util.Sprintf("${BOLD}  Time:    %v${RESET} %v${RESET}", time.Since(summary.ExecutionSummary.startedAt).Truncate(time.Millisecond), maybeFullTurbo))
 	ui.Output("")
 }
@@ -0,0 +1,93 @@
+package runsummary
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The final statuses of the tasks in the --output=ndjson stream
+const (
+	streamStatusBuilt    = "built"
+	streamStatusCached   = "cached"
+	streamStatusFailed   = "failed"
+	streamStatusCanceled = "canceled"
+	// streamStatusSkipped is the status of tasks without a command in their package
+	streamStatusSkipped = "skipped"
+)
+
+// taskStream writes a line of JSON for each task as soon as it finishes
+type taskStream struct {
+	mu            sync.Mutex
+	w             io.Writer
+	singlePackage bool
+}
+
+// taskEvent is a single line of the --output=ndjson stream
+type taskEvent struct {
+	Time   time.Time `json:"time"`
+	Status string    `json:"status"`
+	// Error, only populated for failed tasks
+	Error string `json:"error,omitempty"`
+	// Task is a TaskSummary, or a singlePackageTaskSummary in single package repos
+	Task interface{} `json:"task"`
+}
+
+// StreamTasks makes the run summary write a JSON object for each task to w, one per line,
+// as soon as the task finishes. It must be called before any task runs.
+func (summary *RunSummary) StreamTasks(w io.Writer, singlePackage bool) {
+	summary.stream = &taskStream{w: w, singlePackage: singlePackage}
+}
+
+// TaskFinished writes a finished task to the stream set up with StreamTasks, if there is
+// one. It is safe to call concurrently.
+func (summary *RunSummary) TaskFinished(task *TaskSummary, execution *TaskExecutionSummary) error {
+	if summary.stream == nil {
+		return nil
+	}
+
+	// Copy the task, its execution is only attached once it succeeded
+	streamed := *task
+	streamed.Execution = execution
+	streamed.EnvVars.Global = summary.GlobalHashSummary.EnvVars
+	event := &taskEvent{
+		Time:   time.Now(),
+		Status: streamStatus(execution),
+		Task:   &streamed,
+	}
+	if execution.Err != nil {
+		event.Error = execution.Err.Error()
+	}
+	if summary.stream.singlePackage {
+		event.Task = streamed.toSinglePackageTask()
+	}
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "failed to render JSON")
+	}
+	line = append(line, '\n')
+
+	summary.stream.mu.Lock()
+	defer summary.stream.mu.Unlock()
+	_, err = summary.stream.w.Write(line)
+	return err
+}
+
+func streamStatus(execution *TaskExecutionSummary) string {
+	switch execution.Status {
+	case TargetBuilt.toString():
+		return streamStatusBuilt
+	case TargetCached.toString():
+		return streamStatusCached
+	case TargetBuildFailed.toString(), TargetBuildTimeout.toString():
+		return streamStatusFailed
+	case TargetCanceled.toString():
+		return streamStatusCanceled
+	default:
+		return streamStatusSkipped
+	}
+}
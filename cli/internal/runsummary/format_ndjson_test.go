@@ -0,0 +1,89 @@
+package runsummary
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+// streamedEvent is the part of a line of the --output=ndjson stream that the tests check
+type streamedEvent struct {
+	Status string                 `json:"status"`
+	Error  string                 `json:"error"`
+	Task   map[string]interface{} `json:"task"`
+}
+
+func readStream(t *testing.T, stream *bytes.Buffer) []streamedEvent {
+	t.Helper()
+	events := []streamedEvent{}
+	for _, line := range strings.Split(strings.TrimSuffix(stream.String(), "\n"), "\n") {
+		event := streamedEvent{}
+		assert.NilError(t, json.Unmarshal([]byte(line), &event), "line %q", line)
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestRunSummary_TaskFinished(t *testing.T) {
+	summary := NewRunSummary(time.Now(), "", "", nil, &GlobalHashSummary{})
+	stream := &bytes.Buffer{}
+	summary.StreamTasks(stream, false)
+
+	outcomes := []struct {
+		taskID  string
+		outcome executionEventName
+		err     error
+	}{
+		{"web#build", TargetBuilt, nil},
+		{"docs#build", TargetCached, nil},
+		{"api#build", TargetBuildFailed, errors.New("exit status 1")},
+		{"ui#build", TargetCanceled, errors.New("api#build failed")},
+		{"lint#build", TargetNoop, nil},
+	}
+	for _, o := range outcomes {
+		tracer, execution := summary.TrackTask(o.taskID, "hash")
+		tracer(o.outcome, o.err)
+		assert.NilError(t, summary.TaskFinished(&TaskSummary{TaskID: o.taskID, Task: "build"}, execution), "TaskFinished")
+	}
+
+	events := readStream(t, stream)
+	statuses := []string{}
+	for _, event := range events {
+		statuses = append(statuses, event.Status)
+	}
+	assert.DeepEqual(t, statuses, []string{"built", "cached", "failed", "canceled", "skipped"})
+	assert.Equal(t, events[0].Task["taskId"], "web#build")
+	assert.Equal(t, events[2].Error, "running api#build failed: exit status 1")
+	// A canceled task didn't fail, the reason is part of its execution
+	assert.Equal(t, events[3].Error, "")
+	execution := events[3].Task["execution"].(map[string]interface{})
+	assert.Equal(t, execution["cancellationReason"], "api#build failed")
+}
+
+func TestRunSummary_TaskFinishedSinglePackage(t *testing.T) {
+	summary := NewRunSummary(time.Now(), "", "", nil, &GlobalHashSummary{})
+	stream := &bytes.Buffer{}
+	summary.StreamTasks(stream, true)
+
+	tracer, execution := summary.TrackTask("//#build", "hash")
+	tracer(TargetBuilt, nil)
+	assert.NilError(t, summary.TaskFinished(&TaskSummary{TaskID: "//#build", Task: "build"}, execution), "TaskFinished")
+
+	events := readStream(t, stream)
+	assert.Equal(t, len(events), 1)
+	assert.Equal(t, events[0].Task["task"], "build")
+	_, hasTaskID := events[0].Task["taskId"]
+	assert.Assert(t, !hasTaskID, "single package tasks are identified by their name")
+}
+
+func TestRunSummary_TaskFinishedWithoutStream(t *testing.T) {
+	summary := NewRunSummary(time.Now(), "", "", nil, &GlobalHashSummary{})
+	tracer, execution := summary.TrackTask("web#build", "hash")
+	tracer(TargetBuilt, nil)
+	assert.NilError(t, summary.TaskFinished(&TaskSummary{TaskID: "web#build"}, execution), "TaskFinished")
+}
@@ -97,6 +97,9 @@ func (summary RunSummary) FormatAndPrintText(ui cli.Ui, workspaceInfos workspace
 		fmt.Fprintln(w, util.Sprintf("  ${GREY}Configured Environment Variables\t=\t%s\t${RESET}", strings.Join(task.EnvVars.Configured, ", ")))
 		fmt.Fprintln(w, util.Sprintf("  ${GREY}Inferred Environment Variables\t=\t%s\t${RESET}", strings.Join(task.EnvVars.Inferred, ", ")))
 		fmt.Fprintln(w, util.Sprintf("  ${GREY}Global Environment Variables\t=\t%s\t${RESET}", strings.Join(task.EnvVars.Global, ", ")))
+		if len(task.EnvVars.PassThrough) > 0 {
+			fmt.Fprintln(w, util.Sprintf("  ${GREY}Passed Through Environment Variables\t=\t%s\t${RESET}", strings.Join(task.EnvVars.PassThrough, ", ")))
+		}
 
 		bytes, err := json.Marshal(task.ResolvedTaskDefinition)
 		// If there's an error, we can silently ignore it, we don't need to block the entire print.
@@ -4,6 +4,8 @@ package runsummary
 import (
 	"fmt"
 	"path/filepath"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/mitchellh/cli"
@@ -30,6 +32,17 @@ type RunSummary struct {
 	Packages          []string           `json:"packages"`
 	ExecutionSummary  *executionSummary  `json:"executionSummary"`
 	Tasks             []*TaskSummary     `json:"tasks"`
+	Simulated         bool               `json:"simulated,omitempty"` // set when running with --no-op
+
+	uploads *remoteUploads
+	stream  *taskStream
+}
+
+// remoteUploads tracks the artifacts written to the remote cache over the course of a run
+type remoteUploads struct {
+	mu       sync.Mutex
+	uploaded map[string]bool // hashes that were uploaded
+	existing map[string]bool // hashes that were already in the remote cache before the task ran
 }
 
 // NewRunSummary returns a RunSummary instance
@@ -43,21 +56,90 @@ func NewRunSummary(startAt time.Time, profile string, turboVersion string, packa
 		Packages:          packages,
 		Tasks:             []*TaskSummary{},
 		GlobalHashSummary: globalHashSummary,
+		uploads: &remoteUploads{
+			uploaded: make(map[string]bool),
+			existing: make(map[string]bool),
+		},
 	}
 }
 
-// Close wraps up the RunSummary at the end of a `turbo run`.
-func (summary *RunSummary) Close(terminal cli.Ui) {
+// RecordUpload records that the artifact for the given hash was uploaded to the remote cache.
+// It is safe to call concurrently.
+func (summary *RunSummary) RecordUpload(hash string) {
+	summary.uploads.mu.Lock()
+	defer summary.uploads.mu.Unlock()
+	summary.uploads.uploaded[hash] = true
+}
+
+// RecordExistingUpload records that the artifact for the given hash was already in the remote
+// cache before its task ran, so that uploading it again isn't reported as new.
+func (summary *RunSummary) RecordExistingUpload(hash string) {
+	summary.uploads.mu.Lock()
+	defer summary.uploads.mu.Unlock()
+	summary.uploads.existing[hash] = true
+}
+
+// markUploadedTasks flags the tasks whose artifacts were newly uploaded to the remote cache.
+// Uploads must have completed before it is called.
+func (summary *RunSummary) markUploadedTasks() {
+	summary.uploads.mu.Lock()
+	defer summary.uploads.mu.Unlock()
+	for _, task := range summary.Tasks {
+		if task.Execution != nil && summary.uploads.uploaded[task.Hash] && !summary.uploads.existing[task.Hash] {
+			task.Execution.UploadedToRemoteCache = true
+		}
+	}
+}
+
+// uploadedTaskIDs returns the sorted IDs of the tasks flagged by markUploadedTasks
+func (summary *RunSummary) uploadedTaskIDs() []string {
+	taskIDs := []string{}
+	for _, task := range summary.Tasks {
+		if task.Execution != nil && task.Execution.UploadedToRemoteCache {
+			taskIDs = append(taskIDs, task.TaskID)
+		}
+	}
+	sort.Strings(taskIDs)
+	return taskIDs
+}
+
+// forcedRerunTaskIDs returns the sorted IDs of the tasks that bypassed the cache because of
+// --rerun-dependents-of
+func (summary *RunSummary) forcedRerunTaskIDs() []string {
+	taskIDs := []string{}
+	for _, task := range summary.Tasks {
+		if task.Execution != nil && task.Execution.ForcedRerun {
+			taskIDs = append(taskIDs, task.TaskID)
+		}
+	}
+	sort.Strings(taskIDs)
+	return taskIDs
+}
+
+// Close wraps up the RunSummary at the end of a `turbo run` that exits with the given code.
+func (summary *RunSummary) Close(exitCode int, terminal cli.Ui) {
+	summary.ExecutionSummary.ExitCode = exitCode
 	if err := writeChrometracing(summary.ExecutionSummary.profileFilename, terminal); err != nil {
 		terminal.Error(fmt.Sprintf("Error writing tracing data: %v", err))
 	}
 
+	summary.markUploadedTasks()
 	summary.printExecutionSummary(terminal)
 }
 
+// RecordSkippedTasks records the tasks that didn't run because a task they depend on failed
+func (summary *RunSummary) RecordSkippedTasks(taskIDs []string) {
+	summary.ExecutionSummary.Skipped = taskIDs
+}
+
+// StartedAt returns when the run started
+func (summary *RunSummary) StartedAt() time.Time {
+	return summary.ExecutionSummary.startedAt
+}
+
 // TrackTask makes it possible for the consumer to send information about the execution of a task.
-func (summary *RunSummary) TrackTask(taskID string) (func(outcome executionEventName, err error), *TaskExecutionSummary) {
-	return summary.ExecutionSummary.run(taskID)
+func (summary *RunSummary) TrackTask(taskID string, hash string) (func(outcome executionEventName, err error), *TaskExecutionSummary) {
+	return summary.ExecutionSummary.run(taskID, hash)
 }
 
 func (summary *RunSummary) normalize() {
@@ -68,11 +150,6 @@ func (summary *RunSummary) normalize() {
 
 // Save saves the run summary to a file
 func (summary *RunSummary) Save(dir turbopath.AbsoluteSystemPath, singlePackage bool) error {
-	json, err := summary.FormatJSON(singlePackage)
-	if err != nil {
-		return err
-	}
-
 	// summaryPath will always be relative to the dir passsed in.
 	// We don't do a lot of validation, so `../../` paths are allowed
 	summaryPath := dir.UntypedJoin(
@@ -80,11 +157,18 @@ func (summary *RunSummary) Save(dir turbopath.AbsoluteSystemPath, singlePackage
 		fmt.Sprintf("%s.json", summary.ID),
 	)
 
-	if err := summaryPath.EnsureDir(); err != nil {
+	return summary.SaveTo(summaryPath, singlePackage)
+}
+
+// SaveTo writes the run summary to the given file, creating its directory if needed. The file
+// is replaced atomically, so a reader never sees a partially written summary.
+func (summary *RunSummary) SaveTo(summaryPath turbopath.AbsoluteSystemPath, singlePackage bool) error {
+	json, err := summary.FormatJSON(singlePackage)
+	if err != nil {
 		return err
 	}
 
-	return summaryPath.WriteFile(json, 0644)
+	return fs.WriteFileAtomic(summaryPath.ToString(), json, 0644)
 }
 
 // TaskSummary contains information about the task that was about to run
@@ -99,16 +183,47 @@ type TaskSummary struct {
 	CacheState             cache.ItemStatus                      `json:"cacheState"`
 	Command                string                                `json:"command"`
 	Outputs                []string                              `json:"outputs"`
-	ExcludedOutputs        []string                              `json:"excludedOutputs"`
+	ExcludedOutputs        []string                              `json:"excludedOutputs"` // the exclusion globs, or the files they left out of the cache once the task has run
 	LogFile                string                                `json:"logFile"`
 	Dir                    string                                `json:"directory"`
 	Dependencies           []string                              `json:"dependencies"`
 	Dependents             []string                              `json:"dependents"`
 	ResolvedTaskDefinition *fs.TaskDefinition                    `json:"resolvedTaskDefinition"`
 	ExpandedInputs         map[turbopath.AnchoredUnixPath]string `json:"expandedInputs"`
+	ExpandedOutputs        []turbopath.AnchoredUnixPath          `json:"expandedOutputs,omitempty"`
 	Framework              string                                `json:"framework"`
+	DependencyHashes       map[string]string                     `json:"dependencyHashes"`
 	EnvVars                TaskEnvVarSummary                     `json:"environmentVariables"`
 	Execution              *TaskExecutionSummary                 `json:"execution,omitempty"` // omit when it's not set
+	Timings                *TaskTimings                          `json:"timings,omitempty"`
+	Resources              *TaskResources                        `json:"resources,omitempty"`
+}
+
+// TaskTimings breaks down how long each phase of a task took. Phases the task didn't
+// reach, such as execution after a cache hit, are zero. Timings are only recorded when
+// tasks are run, not for dry runs.
+type TaskTimings struct {
+	// Hashing is how long calculating the task's hash took
+	Hashing time.Duration `json:"hashing"`
+	// CacheRestore is how long checking the cache and restoring outputs from it took
+	CacheRestore time.Duration `json:"cacheRestore"`
+	// Execution is how long running the task's command took, including retries
+	Execution time.Duration `json:"execution"`
+	// CacheSave is how long saving the task's outputs to the cache took
+	CacheSave time.Duration `json:"cacheSave"`
+}
+
+// TaskResources is what the process of a task's command used, as reported by the OS once it
+// exited. For tasks with retries, it is what the last attempt used. It is only recorded on
+// platforms that report rusage for child processes.
+type TaskResources struct {
+	// PeakRSS is the largest resident set size, in bytes, of the process or the largest of
+	// the children it waited for
+	PeakRSS int64 `json:"peakRSS"`
+	// UserCPU is the CPU time spent in user code by the process and the children it waited for
+	UserCPU time.Duration `json:"userCPU"`
+	// SystemCPU is the CPU time spent in the kernel on behalf of the process and its children
+	SystemCPU time.Duration `json:"systemCPU"`
 }
 
 // TaskEnvVarSummary contains the environment variables that impacted a task's hash
@@ -116,6 +231,8 @@ type TaskEnvVarSummary struct {
 	Configured []string `json:"configured"`
 	Inferred   []string `json:"inferred"`
 	Global     []string `json:"global"`
+	// PassThrough are the names of the env vars that the task uses but that are not hashed
+	PassThrough []string `json:"passThrough,omitempty"`
 }
 
 // toSinglePackageTask converts a TaskSummary into a singlePackageTaskSummary
@@ -128,6 +245,10 @@ func (ht *TaskSummary) toSinglePackageTask() singlePackageTaskSummary {
 	for i, dependent := range ht.Dependents {
 		dependents[i] = util.StripPackageName(dependent)
 	}
+	dependencyHashes := make(map[string]string, len(ht.DependencyHashes))
+	for dependency, hash := range ht.DependencyHashes {
+		dependencyHashes[util.StripPackageName(dependency)] = hash
+	}
 
 	return singlePackageTaskSummary{
 		Task:                   util.RootTaskTaskName(ht.TaskID),
@@ -140,8 +261,12 @@ func (ht *TaskSummary) toSinglePackageTask() singlePackageTaskSummary {
 		Dependents:             dependents,
 		ResolvedTaskDefinition: ht.ResolvedTaskDefinition,
 		Framework:              ht.Framework,
+		DependencyHashes:       dependencyHashes,
 		ExpandedInputs:         ht.ExpandedInputs,
+		ExpandedOutputs:        ht.ExpandedOutputs,
 		EnvVars:                ht.EnvVars,
 		Execution:              ht.Execution,
+		Timings:                ht.Timings,
+		Resources:              ht.Resources,
 	}
 }
@@ -0,0 +1,104 @@
+package runsummary
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"gotest.tools/v3/assert"
+)
+
+// trackedTask adds a task to the summary that finished with the given outcome
+func trackedTask(summary *RunSummary, taskID string, hash string, outcome executionEventName) *TaskSummary {
+	tracer, execution := summary.TrackTask(taskID, hash)
+	tracer(outcome, nil)
+	task := &TaskSummary{TaskID: taskID, Hash: hash, Execution: execution}
+	summary.Tasks = append(summary.Tasks, task)
+	return task
+}
+
+func TestRunSummary_uploadedTasks(t *testing.T) {
+	summary := NewRunSummary(time.Now(), "", "", nil, nil)
+	uploaded := trackedTask(summary, "web#build", "web-hash", TargetBuilt)
+	replaced := trackedTask(summary, "docs#build", "docs-hash", TargetBuilt)
+	cached := trackedTask(summary, "ui#build", "ui-hash", TargetCached)
+	alsoUploaded := trackedTask(summary, "api#build", "api-hash", TargetBuilt)
+
+	summary.RecordUpload("web-hash")
+	// With --force, the artifact of docs#build was already in the remote cache
+	summary.RecordExistingUpload("docs-hash")
+	summary.RecordUpload("docs-hash")
+	summary.RecordUpload("api-hash")
+
+	terminal := cli.NewMockUi()
+	summary.Close(0, terminal)
+
+	assert.Assert(t, uploaded.Execution.UploadedToRemoteCache)
+	assert.Assert(t, alsoUploaded.Execution.UploadedToRemoteCache)
+	assert.Assert(t, !replaced.Execution.UploadedToRemoteCache)
+	assert.Assert(t, !cached.Execution.UploadedToRemoteCache)
+	assert.DeepEqual(t, summary.uploadedTaskIDs(), []string{"api#build", "web#build"})
+	output := terminal.OutputWriter.String()
+	assert.Assert(t, strings.Contains(output, "Uploaded:  2 to remote cache"), output)
+	assert.Assert(t, strings.Contains(output, "(api#build, web#build)"), output)
+}
+
+func TestRunSummary_noUploads(t *testing.T) {
+	summary := NewRunSummary(time.Now(), "", "", nil, nil)
+	trackedTask(summary, "web#build", "web-hash", TargetBuilt)
+
+	terminal := cli.NewMockUi()
+	summary.Close(0, terminal)
+
+	assert.DeepEqual(t, summary.uploadedTaskIDs(), []string{})
+	assert.Assert(t, !strings.Contains(terminal.OutputWriter.String(), "Uploaded:"), terminal.OutputWriter.String())
+}
+
+func TestRunSummary_cachedLine(t *testing.T) {
+	summary := NewRunSummary(time.Now(), "", "", nil, nil)
+	trackedTask(summary, "web#build", "web-hash", TargetCached)
+	trackedTask(summary, "docs#build", "docs-hash", TargetCached)
+	trackedTask(summary, "ui#build", "ui-hash", TargetBuilt)
+	trackedTask(summary, "api#build", "api-hash", TargetBuildFailed)
+
+	terminal := cli.NewMockUi()
+	summary.Close(1, terminal)
+
+	output := terminal.OutputWriter.String()
+	assert.Assert(t, strings.Contains(output, "Cached:    2 cached"), output)
+	assert.Assert(t, strings.Contains(output, ", 1 built, 1 failed, 4 total (50% cache hit)"), output)
+}
+
+func TestTaskSummary_timings(t *testing.T) {
+	task := &TaskSummary{
+		TaskID: "web#build",
+		Timings: &TaskTimings{
+			Hashing:      2 * time.Millisecond,
+			CacheRestore: 3 * time.Millisecond,
+			Execution:    time.Second,
+		},
+	}
+
+	for _, summary := range []interface{}{task, task.toSinglePackageTask()} {
+		encoded, err := json.Marshal(summary)
+		assert.NilError(t, err, "Marshal")
+		var decoded struct {
+			Timings map[string]int64 `json:"timings"`
+		}
+		assert.NilError(t, json.Unmarshal(encoded, &decoded), "Unmarshal")
+		// Phases the task didn't reach are still reported, as zero
+		assert.DeepEqual(t, decoded.Timings, map[string]int64{
+			"hashing":      int64(2 * time.Millisecond),
+			"cacheRestore": int64(3 * time.Millisecond),
+			"execution":    int64(time.Second),
+			"cacheSave":    0,
+		})
+	}
+
+	// Dry runs don't record timings
+	encoded, err := json.Marshal(&TaskSummary{TaskID: "web#build"})
+	assert.NilError(t, err, "Marshal")
+	assert.Assert(t, !strings.Contains(string(encoded), "timings"), string(encoded))
+}
@@ -28,7 +28,11 @@ type singlePackageTaskSummary struct {
 	Dependents             []string                              `json:"dependents"`
 	ResolvedTaskDefinition *fs.TaskDefinition                    `json:"resolvedTaskDefinition"`
 	ExpandedInputs         map[turbopath.AnchoredUnixPath]string `json:"expandedInputs"`
+	ExpandedOutputs        []turbopath.AnchoredUnixPath          `json:"expandedOutputs,omitempty"`
 	Framework              string                                `json:"framework"`
+	DependencyHashes       map[string]string                     `json:"dependencyHashes"`
 	EnvVars                TaskEnvVarSummary                     `json:"environmentVariables"`
 	Execution              *TaskExecutionSummary                 `json:"execution,omitempty"`
+	Timings                *TaskTimings                          `json:"timings,omitempty"`
+	Resources              *TaskResources                        `json:"resources,omitempty"`
 }
@@ -0,0 +1,90 @@
+package runsummary
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// The events written with EmitTaskEvents
+const (
+	taskEventStart    = "start"
+	taskEventCacheHit = "cache-hit"
+	taskEventComplete = "complete"
+	taskEventFail     = "fail"
+	taskEventCanceled = "canceled"
+	// taskEventNoCommand ends a task whose package has no script for it
+	taskEventNoCommand = "no-command"
+)
+
+// taskEventWriter writes a line of JSON for each time a task starts or finishes
+type taskEventWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// taskLifecycleEvent is a single line written with EmitTaskEvents
+type taskLifecycleEvent struct {
+	Event  string    `json:"event"`
+	TaskID string    `json:"taskId"`
+	Hash   string    `json:"hash,omitempty"`
+	Time   time.Time `json:"time"`
+	// Duration is how long the task took, only populated for the events that finish it
+	Duration time.Duration `json:"duration,omitempty"`
+	// Error, only populated for failed tasks
+	Error string `json:"error,omitempty"`
+	// Why the run was canceled, only populated for canceled tasks
+	CancellationReason string `json:"cancellationReason,omitempty"`
+}
+
+// EmitTaskEvents makes the run summary write a JSON object to w, one per line, when each
+// task starts, and when it finishes. It must be called before any task runs. Errors writing
+// to w are left to w to handle, they don't stop the run.
+func (summary *RunSummary) EmitTaskEvents(w io.Writer) {
+	summary.ExecutionSummary.events = &taskEventWriter{w: w}
+}
+
+// write writes an event for the given execution event, if events are emitted
+func (tw *taskEventWriter) write(event *executionEvent, hash string) {
+	if tw == nil {
+		return
+	}
+	lifecycleEvent := &taskLifecycleEvent{
+		Event:              taskEventName(event.Status),
+		TaskID:             event.Label,
+		Hash:               hash,
+		Time:               event.Time,
+		Duration:           event.Duration,
+		CancellationReason: event.CancellationReason,
+	}
+	if event.Err != nil {
+		lifecycleEvent.Error = event.Err.Error()
+	}
+	line, err := json.Marshal(lifecycleEvent)
+	if err != nil {
+		return
+	}
+	line = append(line, '\n')
+
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	_, _ = tw.w.Write(line)
+}
+
+func taskEventName(status executionEventName) string {
+	switch status {
+	case targetBuilding:
+		return taskEventStart
+	case TargetCached:
+		return taskEventCacheHit
+	case TargetBuildFailed, TargetBuildTimeout:
+		return taskEventFail
+	case TargetCanceled:
+		return taskEventCanceled
+	case TargetNoop:
+		return taskEventNoCommand
+	default:
+		return taskEventComplete
+	}
+}
@@ -0,0 +1,78 @@
+package runsummary
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestRunSummary_EmitTaskEvents(t *testing.T) {
+	summary := NewRunSummary(time.Now(), "", "", nil, &GlobalHashSummary{})
+	events := &bytes.Buffer{}
+	summary.EmitTaskEvents(events)
+
+	outcomes := []struct {
+		taskID  string
+		outcome executionEventName
+		err     error
+	}{
+		{"web#build", TargetBuilt, nil},
+		{"docs#build", TargetCached, nil},
+		{"api#build", TargetBuildFailed, errors.New("exit status 1")},
+		{"api#test", TargetBuildTimeout, errors.New("timed out")},
+		{"ui#build", TargetCanceled, errors.New("interrupted")},
+		{"lint#build", TargetNoop, nil},
+	}
+	for _, o := range outcomes {
+		tracer, _ := summary.TrackTask(o.taskID, "hash-of-"+o.taskID)
+		tracer(o.outcome, o.err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(events.String(), "\n"), "\n")
+	// Each task starts, then finishes
+	assert.Equal(t, len(lines), 2*len(outcomes))
+	got := []string{}
+	for i, line := range lines {
+		event := taskLifecycleEvent{}
+		assert.NilError(t, json.Unmarshal([]byte(line), &event), "line %q", line)
+		got = append(got, event.Event+" "+event.TaskID)
+		assert.Equal(t, event.Hash, "hash-of-"+event.TaskID)
+
+		outcome := outcomes[i/2]
+		if i%2 == 0 {
+			assert.Equal(t, event.Duration, time.Duration(0), "line %q", line)
+			assert.Equal(t, event.Error, "")
+			continue
+		}
+		switch outcome.outcome {
+		case TargetBuildFailed, TargetBuildTimeout:
+			assert.Equal(t, event.Error, "running "+outcome.taskID+" failed: "+outcome.err.Error())
+		case TargetCanceled:
+			assert.Equal(t, event.Error, "")
+			assert.Equal(t, event.CancellationReason, outcome.err.Error())
+		default:
+			assert.Equal(t, event.Error, "")
+		}
+	}
+	assert.DeepEqual(t, got, []string{
+		"start web#build", "complete web#build",
+		"start docs#build", "cache-hit docs#build",
+		"start api#build", "fail api#build",
+		"start api#test", "fail api#test",
+		"start ui#build", "canceled ui#build",
+		"start lint#build", "no-command lint#build",
+	})
+}
+
+func TestRunSummary_withoutTaskEvents(t *testing.T) {
+	summary := NewRunSummary(time.Now(), "", "", nil, &GlobalHashSummary{})
+	// Without EmitTaskEvents, tracking a task writes nothing
+	tracer, execution := summary.TrackTask("web#build", "hash")
+	tracer(TargetBuilt, nil)
+	assert.Equal(t, execution.Status, "built")
+}
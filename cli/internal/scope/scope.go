@@ -223,6 +223,12 @@ func (o *Opts) getPackageChangeFunc(scm scm.SCM, cwd turbopath.AbsoluteSystemPat
 		if err != nil {
 			return nil, err
 		}
+		// --since runs everything when a file outside of every workspace changed, since it
+		// could be a config file that any of them depend on. --filter=[ref] only selects the
+		// workspace root for it.
+		if o.LegacyFilter.Since != "" && fromRef == o.LegacyFilter.Since && hasChangeOutsideWorkspaces(filteredChangedFiles, ctx.PackageManager.Lockfile, ctx.WorkspaceInfos) {
+			return makeAllPkgs(), nil
+		}
 		changedPkgs := getChangedPackages(filteredChangedFiles, ctx.WorkspaceInfos)
 
 		if lockfileChanges, fullChanges := getChangesFromLockfile(scm, ctx, changedFiles, fromRef); !fullChanges {
@@ -346,6 +352,18 @@ func fileInPackage(changedFile string, packagePath string) bool {
 	return false
 }
 
+// hasChangeOutsideWorkspaces returns whether any of the changed files is outside of every
+// workspace. The lockfile isn't counted, since the workspaces whose dependencies changed in
+// it are selected by getChangesFromLockfile.
+func hasChangeOutsideWorkspaces(changedFiles []string, lockfilePath string, packageInfos workspace.Catalog) bool {
+	for _, changedFile := range changedFiles {
+		if filepath.ToSlash(changedFile) != lockfilePath && getChangedPackages([]string{changedFile}, packageInfos).Includes(util.RootPkgName) {
+			return true
+		}
+	}
+	return false
+}
+
 func getChangedPackages(changedFiles []string, packageInfos workspace.Catalog) util.Set {
 	changedPackages := make(util.Set)
 	for _, changedFile := range changedFiles {
@@ -348,6 +348,19 @@ func TestResolvePackages(t *testing.T) {
 			ignore:     "libs/libB/**/*.ts",
 			globalDeps: []string{"libs/**/*.ts"},
 		},
+		{
+			name:     "a file outside of every workspace changed, forcing a build of everything",
+			changed:  []string{".eslintrc.js", "libs/libB/src/index.ts"},
+			expected: []string{"//", "app0", "app1", "app2", "app2-a", "libA", "libB", "libC", "libD"},
+			since:    "dummy",
+		},
+		{
+			name:     "an ignored file outside of every workspace changed",
+			changed:  []string{".eslintrc.js", "libs/libB/src/index.ts"},
+			expected: []string{"libB"},
+			since:    "dummy",
+			ignore:   ".eslintrc.js",
+		},
 		{
 			name:                "an app changed, user asked for dependencies to build",
 			changed:             []string{"app/app2/src/index.ts"},
@@ -0,0 +1,96 @@
+// Package statscmd implements the `turbo stats` command
+package statscmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"github.com/vercel/turbo/cli/internal/cmdutil"
+	"github.com/vercel/turbo/cli/internal/runstats"
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"github.com/vercel/turbo/cli/internal/turbostate"
+	"github.com/vercel/turbo/cli/internal/ui"
+	"github.com/vercel/turbo/cli/internal/util"
+)
+
+// ExecuteStats executes the `stats` command.
+func ExecuteStats(helper *cmdutil.Helper, args *turbostate.ParsedArgsFromRust) error {
+	base, err := helper.GetCmdBase(args)
+	if err != nil {
+		return err
+	}
+	if args.TestRun {
+		base.UI.Info("Stats test run successful")
+		return nil
+	}
+	return runStats(base.UI, runstats.Path(base.RepoRoot), args.Command.Stats)
+}
+
+func runStats(terminal cli.Ui, path turbopath.AbsoluteSystemPath, opts *turbostate.StatsPayload) error {
+	history, err := runstats.Load(path)
+	if err != nil {
+		return err
+	}
+
+	if opts.Reset {
+		if err := path.Remove(); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to reset stats: %w", err)
+		}
+		terminal.Output(fmt.Sprintf("Removed the stats of %v run(s)", len(history.Runs)))
+		return nil
+	}
+
+	if opts.MaxRuns != nil {
+		if *opts.MaxRuns < 1 {
+			return fmt.Errorf("--max-runs must be at least 1")
+		}
+		history.MaxRuns = *opts.MaxRuns
+		history.Trim()
+		if err := history.Save(path); err != nil {
+			return fmt.Errorf("failed to save stats: %w", err)
+		}
+		terminal.Output(ui.Dim(fmt.Sprintf("Keeping the stats of the last %v run(s)", history.MaxRuns)))
+	}
+
+	if len(history.Runs) == 0 {
+		terminal.Output("No runs have been recorded yet")
+		return nil
+	}
+	terminal.Output(util.Sprintf("${BOLD}Tasks of the last %v run(s)${RESET}, slowest first", len(history.Runs)))
+	terminal.Output("")
+	return printReport(os.Stdout, history.Report(), opts.Limit)
+}
+
+// printReport writes a table of the given number of slowest tasks. A limit of zero prints
+// every task.
+func printReport(w io.Writer, reports []*runstats.TaskReport, limit int) error {
+	if limit > 0 && len(reports) > limit {
+		reports = reports[:limit]
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "  Task\tp50\tp95\tExecuted\tCached\tHit rate")
+	for _, report := range reports {
+		fmt.Fprintf(tw, "  %v\t%v\t%v\t%v\t%v\t%.0f%%\n",
+			report.TaskID,
+			formatDuration(report.Executed, report.P50),
+			formatDuration(report.Executed, report.P95),
+			report.Executed,
+			report.Cached,
+			100*report.HitRate(),
+		)
+	}
+	return tw.Flush()
+}
+
+// formatDuration shows a percentile of a task's durations, or a dash if the task was
+// never executed
+func formatDuration(executed int, duration time.Duration) string {
+	if executed == 0 {
+		return "-"
+	}
+	return duration.Truncate(time.Millisecond).String()
+}
@@ -0,0 +1,56 @@
+package statscmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/vercel/turbo/cli/internal/runstats"
+	"github.com/vercel/turbo/cli/internal/turbopath"
+	"github.com/vercel/turbo/cli/internal/turbostate"
+	"gotest.tools/v3/assert"
+)
+
+func Test_printReport(t *testing.T) {
+	history := &runstats.History{MaxRuns: runstats.DefaultMaxRuns}
+	history.Add(&runstats.Run{Tasks: []runstats.Task{
+		{TaskID: "web#build", Outcome: runstats.OutcomeExecuted, DurationMs: 61500},
+		{TaskID: "ui#lint", Outcome: runstats.OutcomeCached, DurationMs: 20},
+		{TaskID: "docs#build", Outcome: runstats.OutcomeExecuted, DurationMs: 800},
+	}})
+	history.Add(&runstats.Run{Tasks: []runstats.Task{
+		{TaskID: "web#build", Outcome: runstats.OutcomeCached, DurationMs: 120},
+	}})
+
+	output := &bytes.Buffer{}
+	assert.NilError(t, printReport(output, history.Report(), 2))
+	lines := strings.Split(strings.TrimRight(output.String(), "\n"), "\n")
+	assert.DeepEqual(t, lines, []string{
+		"  Task        p50     p95     Executed  Cached  Hit rate",
+		"  web#build   1m1.5s  1m1.5s  1         1       50%",
+		"  docs#build  800ms   800ms   1         0       0%",
+	})
+}
+
+func Test_runStats_maxRunsAndReset(t *testing.T) {
+	path := turbopath.AbsoluteSystemPathFromUpstream(t.TempDir()).UntypedJoin("stats.json")
+	for i := 0; i < 3; i++ {
+		assert.NilError(t, runstats.Record(path, &runstats.Run{}))
+	}
+
+	maxRuns := 1
+	assert.NilError(t, runStats(cli.NewMockUi(), path, &turbostate.StatsPayload{MaxRuns: &maxRuns}))
+	history, err := runstats.Load(path)
+	assert.NilError(t, err)
+	assert.Equal(t, history.MaxRuns, 1)
+	assert.Equal(t, len(history.Runs), 1)
+
+	zero := 0
+	assert.ErrorContains(t, runStats(cli.NewMockUi(), path, &turbostate.StatsPayload{MaxRuns: &zero}), "--max-runs must be at least 1")
+
+	terminal := cli.NewMockUi()
+	assert.NilError(t, runStats(terminal, path, &turbostate.StatsPayload{Reset: true}))
+	assert.Equal(t, terminal.OutputWriter.String(), "Removed the stats of 1 run(s)\n")
+	assert.Assert(t, !path.FileExists())
+}
@@ -4,7 +4,10 @@
 package taskhash
 
 import (
+	"bytes"
 	"fmt"
+	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"sync"
@@ -15,9 +18,11 @@ import (
 	"github.com/vercel/turbo/cli/internal/doublestar"
 	"github.com/vercel/turbo/cli/internal/env"
 	"github.com/vercel/turbo/cli/internal/fs"
+	"github.com/vercel/turbo/cli/internal/globby"
 	"github.com/vercel/turbo/cli/internal/hashing"
 	"github.com/vercel/turbo/cli/internal/inference"
 	"github.com/vercel/turbo/cli/internal/nodes"
+	"github.com/vercel/turbo/cli/internal/process"
 	"github.com/vercel/turbo/cli/internal/turbopath"
 	"github.com/vercel/turbo/cli/internal/util"
 	"github.com/vercel/turbo/cli/internal/workspace"
@@ -33,6 +38,8 @@ type Tracker struct {
 	rootNode   string
 	globalHash string
 	pipeline   fs.Pipeline
+	// globalPassThroughEnv are the env vars that are left out of the hash of every task
+	globalPassThroughEnv []string
 
 	packageInputsHashes packageFileHashes
 
@@ -41,23 +48,42 @@ type Tracker struct {
 	// before walking the task graph, it does not need to be protected by a mutex.
 	packageInputsExpandedHashes map[packageFileHashKey]map[turbopath.AnchoredUnixPath]string
 
+	// rootInputsHashes is a map of a set of repo-root-anchored input globs to the hash of the
+	// files they match. Like packageInputsHashes, it is written before walking the task graph.
+	rootInputsHashes map[rootInputsHashKey]string
+
+	// hashInputsFromHashes is a map of a package directory and hashInputsFrom command to the
+	// hash of the command's output. Each command is run once, before walking the task graph.
+	hashInputsFromHashes map[hashInputsFromKey]string
+
+	// dotEnvHashes is a map of a package directory and list of .env files to the hash of
+	// the variables they set. Like rootInputsHashes, it is written before walking the task graph.
+	dotEnvHashes map[dotEnvKey]string
+
 	// mu is a mutex that we can lock/unlock to read/write from maps
 	// the fields below should be protected by the mutex.
 	mu                   sync.RWMutex
 	packageTaskEnvVars   map[string]env.DetailedMap // taskId -> envvar pairs that affect the hash.
 	packageTaskHashes    map[string]string          // taskID -> hash
 	packageTaskFramework map[string]string          // taskID -> inferred framework for package
+	// packageTaskDependencyHashes is a map of a taskID to the hashes of the tasks it depends on
+	// that were folded into its hash, keyed by the taskID of the dependency
+	packageTaskDependencyHashes map[string]map[string]string
 }
 
 // NewTracker creates a tracker for package-inputs combinations and package-task combinations.
-func NewTracker(rootNode string, globalHash string, pipeline fs.Pipeline) *Tracker {
+// The env vars of globalPassThroughEnv are left out of the hash of every task.
+func NewTracker(rootNode string, globalHash string, pipeline fs.Pipeline, globalPassThroughEnv []string) *Tracker {
 	return &Tracker{
 		rootNode:             rootNode,
 		globalHash:           globalHash,
 		pipeline:             pipeline,
+		globalPassThroughEnv: globalPassThroughEnv,
 		packageTaskHashes:    make(map[string]string),
 		packageTaskFramework: make(map[string]string),
 		packageTaskEnvVars:   make(map[string]env.DetailedMap),
+
+		packageTaskDependencyHashes: make(map[string]map[string]string),
 	}
 }
 
@@ -83,6 +109,66 @@ func (pfs packageFileSpec) ToKey() packageFileHashKey {
 	return packageFileHashKey(fmt.Sprintf("%v#%v", pfs.pkg, strings.Join(pfs.inputs, "!")))
 }
 
+// rootInputsHashKey is a hashable representation of a set of repo-root-anchored input globs
+type rootInputsHashKey string
+
+func rootInputsKey(rootInputs []string) rootInputsHashKey {
+	sorted := make([]string, len(rootInputs))
+	copy(sorted, rootInputs)
+	sort.Strings(sorted)
+	return rootInputsHashKey(strings.Join(sorted, "!"))
+}
+
+// hashRootInputs hashes the files matched by a set of globs anchored at the repository root
+func hashRootInputs(rootInputs []string, repoRoot turbopath.AbsoluteSystemPath) (string, error) {
+	var includePatterns []string
+	var excludePatterns []string
+	for _, pattern := range rootInputs {
+		if strings.HasPrefix(pattern, "!") {
+			excludePatterns = append(excludePatterns, pattern[1:])
+		} else {
+			includePatterns = append(includePatterns, pattern)
+		}
+	}
+
+	matches, err := globby.GlobFiles(repoRoot.ToStringDuringMigration(), includePatterns, excludePatterns)
+	if err != nil {
+		return "", err
+	}
+	files := make([]turbopath.AbsoluteSystemPath, len(matches))
+	for i, match := range matches {
+		files[i] = turbopath.AbsoluteSystemPathFromUpstream(match)
+	}
+
+	hashObject, err := hashing.GetHashableDeps(repoRoot, files)
+	if err != nil {
+		return "", fmt.Errorf("error hashing files: %w", err)
+	}
+	return fs.HashObject(hashObject)
+}
+
+// hashInputsFromKey is a hashable representation of a hashInputsFrom command and the
+// directory it runs in
+type hashInputsFromKey string
+
+func hashInputsFromKeyFor(packageDir turbopath.AnchoredSystemPath, command string) hashInputsFromKey {
+	return hashInputsFromKey(fmt.Sprintf("%v#%v", packageDir.ToUnixPath(), command))
+}
+
+// hashCommandOutput runs a hashInputsFrom command via the system shell in the given
+// directory, and hashes its stdout
+func hashCommandOutput(command string, dir turbopath.AbsoluteSystemPath) (string, error) {
+	cmd := process.ShellCommand(command)
+	cmd.Dir = dir.ToString()
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("hashInputsFrom command \"%v\" failed in %v: %w: %v", command, dir, err, strings.TrimSpace(stderr.String()))
+	}
+	return fs.HashObject(string(output))
+}
+
 func safeCompileIgnoreFile(filepath string) (*gitignore.GitIgnore, error) {
 	if fs.FileExists(filepath) {
 		return gitignore.CompileIgnoreFile(filepath)
@@ -107,6 +193,14 @@ func (pfs *packageFileSpec) getHashObject(pkg *fs.PackageJSON, repoRoot turbopat
 	return hashObject
 }
 
+// GetPackageInputs returns the hashes of the files that are hashed into a task of the package
+// with the given input patterns, keyed by their path from the package, the same way the
+// task's hash is calculated
+func GetPackageInputs(pkg *fs.PackageJSON, inputs []string, repoRoot turbopath.AbsoluteSystemPath) map[turbopath.AnchoredUnixPath]string {
+	pfs := &packageFileSpec{pkg: pkg.Name, inputs: inputs}
+	return pfs.getHashObject(pkg, repoRoot)
+}
+
 func (pfs *packageFileSpec) hash(hashObject map[turbopath.AnchoredUnixPath]string) (string, error) {
 	hashOfFiles, otherErr := fs.HashObject(hashObject)
 	if otherErr != nil {
@@ -132,14 +226,34 @@ func manuallyHashPackage(pkg *fs.PackageJSON, inputs []string, rootPath turbopat
 	pathPrefix := rootPath.UntypedJoin(pkg.Dir.ToStringDuringMigration())
 	includePattern := ""
 	excludePattern := ""
+	includeDefault := false
+	// Like the git path, inputs can reach outside of the package, e.g. "../shared/**", so the
+	// directories they reach into are walked along with the package
+	var outsideDirs []turbopath.AbsoluteSystemPath
 	if len(inputs) > 0 {
 		var includePatterns []string
 		var excludePatterns []string
 		for _, pattern := range inputs {
+			if pattern == fs.TurboDefaultInput {
+				includeDefault = true
+				continue
+			}
+			if err := hashing.CheckInputPattern(rootPath, pathPrefix, pattern); err != nil {
+				return nil, err
+			}
 			if len(pattern) > 0 && pattern[0] == '!' {
 				excludePatterns = append(excludePatterns, pathPrefix.UntypedJoin(pattern[1:]).ToString())
-			} else {
-				includePatterns = append(includePatterns, pathPrefix.UntypedJoin(pattern).ToString())
+				continue
+			}
+			absolutePattern := pathPrefix.UntypedJoin(pattern)
+			includePatterns = append(includePatterns, absolutePattern.ToString())
+			dir := globBase(absolutePattern.Dir())
+			inPackage, err := pathPrefix.ContainsPath(dir)
+			if err != nil {
+				return nil, err
+			}
+			if !inPackage {
+				outsideDirs = append(outsideDirs, dir)
 			}
 		}
 		if len(includePatterns) > 0 {
@@ -150,54 +264,111 @@ func manuallyHashPackage(pkg *fs.PackageJSON, inputs []string, rootPath turbopat
 		}
 	}
 
-	err = fs.Walk(pathPrefix.ToStringDuringMigration(), func(name string, isDir bool) error {
-		convertedName := turbopath.AbsoluteSystemPathFromUpstream(name)
-		rootMatch := ignore.MatchesPath(convertedName.ToString())
-		otherMatch := ignorePkg.MatchesPath(convertedName.ToString())
-		if !rootMatch && !otherMatch {
-			if !isDir {
-				if includePattern != "" {
-					val, err := doublestar.PathMatch(includePattern, convertedName.ToString())
-					if err != nil {
-						return err
+	// With $TURBO_DEFAULT every file of the package is included, but the files outside of
+	// it still have to match an input
+	walk := func(dir turbopath.AbsoluteSystemPath, includePattern string) error {
+		return fs.Walk(dir.ToStringDuringMigration(), func(name string, isDir bool) error {
+			convertedName := turbopath.AbsoluteSystemPathFromUpstream(name)
+			rootMatch := ignore.MatchesPath(convertedName.ToString())
+			otherMatch := ignorePkg.MatchesPath(convertedName.ToString())
+			if !rootMatch && !otherMatch {
+				if !isDir {
+					if includePattern != "" {
+						val, err := doublestar.PathMatch(includePattern, convertedName.ToString())
+						if err != nil {
+							return err
+						}
+						if !val {
+							return nil
+						}
 					}
-					if !val {
-						return nil
+					if excludePattern != "" {
+						val, err := doublestar.PathMatch(excludePattern, convertedName.ToString())
+						if err != nil {
+							return err
+						}
+						if val {
+							return nil
+						}
 					}
-				}
-				if excludePattern != "" {
-					val, err := doublestar.PathMatch(excludePattern, convertedName.ToString())
+					hash, err := fs.GitLikeHashFile(convertedName.ToString())
 					if err != nil {
-						return err
+						return fmt.Errorf("could not hash file %v. \n%w", convertedName.ToString(), err)
 					}
-					if val {
-						return nil
-					}
-				}
-				hash, err := fs.GitLikeHashFile(convertedName.ToString())
-				if err != nil {
-					return fmt.Errorf("could not hash file %v. \n%w", convertedName.ToString(), err)
-				}
 
-				relativePath, err := convertedName.RelativeTo(pathPrefix)
-				if err != nil {
-					return fmt.Errorf("File path cannot be made relative: %w", err)
+					// Files outside of the package start with "../", as they do with git
+					relativePath, err := convertedName.RelativeTo(pathPrefix)
+					if err != nil {
+						return fmt.Errorf("File path cannot be made relative: %w", err)
+					}
+					hashObject[relativePath.ToUnixPath()] = hash
 				}
-				hashObject[relativePath.ToUnixPath()] = hash
 			}
-		}
-		return nil
-	})
-	if err != nil {
+			return nil
+		})
+	}
+
+	packageIncludePattern := includePattern
+	if includeDefault {
+		packageIncludePattern = ""
+	}
+	if err := walk(pathPrefix, packageIncludePattern); err != nil {
 		return nil, err
 	}
+	for _, dir := range outsideDirs {
+		if !dir.DirExists() {
+			continue
+		}
+		if err := walk(dir, includePattern); err != nil {
+			return nil, err
+		}
+	}
 	return hashObject, nil
 }
 
+// globBase returns the longest leading part of a glob pattern without any glob syntax, which
+// is the directory that holds every file the pattern can match
+func globBase(pattern turbopath.AbsoluteSystemPath) turbopath.AbsoluteSystemPath {
+	base := pattern
+	for strings.ContainsAny(base.ToString(), "*?[{") {
+		base = base.Dir()
+	}
+	return base
+}
+
 // packageFileHashes is a map from a package and optional input globs to the hash of
 // the matched files in the package.
 type packageFileHashes map[packageFileHashKey]string
 
+// dotEnvKey is a hashable representation of a list of .env files and the package
+// directory they are relative to
+type dotEnvKey string
+
+func dotEnvKeyFor(packageDir turbopath.AnchoredSystemPath, files []string) dotEnvKey {
+	return dotEnvKey(fmt.Sprintf("%v#%v", packageDir.ToUnixPath(), strings.Join(files, "!")))
+}
+
+// hashDotEnv hashes the variables set by the given .env files, relative to dir. Files
+// that don't exist are skipped, and later files override the variables of earlier ones.
+func hashDotEnv(files []string, dir turbopath.AbsoluteSystemPath) (string, error) {
+	vars := env.EnvironmentVariableMap{}
+	for _, file := range files {
+		path := dir.UntypedJoin(filepath.FromSlash(file))
+		contents, err := path.ReadFile()
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		fileVars, err := env.ParseDotEnv(contents)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse dotEnv file %v: %w", path, err)
+		}
+		vars.Merge(fileVars)
+	}
+	return fs.HashObject(vars.ToHashable())
+}
+
 // CalculateFileHashes hashes each unique package-inputs combination that is present
 // in the task graph. Must be called before calculating task hashes.
 func (th *Tracker) CalculateFileHashes(
@@ -208,6 +379,9 @@ func (th *Tracker) CalculateFileHashes(
 	repoRoot turbopath.AbsoluteSystemPath,
 ) error {
 	hashTasks := make(util.Set)
+	rootInputs := make(map[rootInputsHashKey][]string)
+	hashInputsFromHashes := make(map[hashInputsFromKey]string)
+	dotEnvHashes := make(map[dotEnvKey]string)
 
 	for _, v := range allTasks {
 		taskID, ok := v.(string)
@@ -227,13 +401,72 @@ func (th *Tracker) CalculateFileHashes(
 			return fmt.Errorf("missing pipeline entry %v", taskID)
 		}
 
+		if len(taskDefinition.Inputs) > 0 {
+			pkg, ok := workspaceInfos.PackageJSONs[pkgName]
+			if !ok {
+				return fmt.Errorf("cannot find package %v", pkgName)
+			}
+			for _, input := range taskDefinition.Inputs {
+				if err := hashing.CheckInputPattern(repoRoot, pkg.Dir.RestoreAnchor(repoRoot), input); err != nil {
+					return fmt.Errorf("invalid inputs of %v: %w", taskID, err)
+				}
+			}
+		}
+
 		pfs := &packageFileSpec{
 			pkg:    pkgName,
 			inputs: taskDefinition.Inputs,
 		}
 
 		hashTasks.Add(pfs)
+
+		if len(taskDefinition.RootInputs) > 0 {
+			rootInputs[rootInputsKey(taskDefinition.RootInputs)] = taskDefinition.RootInputs
+		}
+
+		if taskDefinition.HashInputsFrom != "" {
+			pkg, ok := workspaceInfos.PackageJSONs[pkgName]
+			if !ok {
+				return fmt.Errorf("cannot find package %v", pkgName)
+			}
+			// Tasks in the same package that share a command also share its output
+			key := hashInputsFromKeyFor(pkg.Dir, taskDefinition.HashInputsFrom)
+			if _, ok := hashInputsFromHashes[key]; !ok {
+				hash, err := hashCommandOutput(taskDefinition.HashInputsFrom, pkg.Dir.RestoreAnchor(repoRoot))
+				if err != nil {
+					return err
+				}
+				hashInputsFromHashes[key] = hash
+			}
+		}
+
+		if len(taskDefinition.DotEnv) > 0 {
+			pkg, ok := workspaceInfos.PackageJSONs[pkgName]
+			if !ok {
+				return fmt.Errorf("cannot find package %v", pkgName)
+			}
+			key := dotEnvKeyFor(pkg.Dir, taskDefinition.DotEnv)
+			if _, ok := dotEnvHashes[key]; !ok {
+				hash, err := hashDotEnv(taskDefinition.DotEnv, pkg.Dir.RestoreAnchor(repoRoot))
+				if err != nil {
+					return err
+				}
+				dotEnvHashes[key] = hash
+			}
+		}
 	}
+	th.hashInputsFromHashes = hashInputsFromHashes
+	th.dotEnvHashes = dotEnvHashes
+
+	rootInputsHashes := make(map[rootInputsHashKey]string, len(rootInputs))
+	for key, globs := range rootInputs {
+		hash, err := hashRootInputs(globs, repoRoot)
+		if err != nil {
+			return err
+		}
+		rootInputsHashes[key] = hash
+	}
+	th.rootInputsHashes = rootInputsHashes
 
 	hashes := make(map[packageFileHashKey]string, len(hashTasks))
 	hashObjects := make(map[packageFileHashKey]map[turbopath.AnchoredUnixPath]string, len(hashTasks))
@@ -286,8 +519,11 @@ type taskHashInputs struct {
 	taskDependencyHashes []string
 }
 
-func (th *Tracker) calculateDependencyHashes(dependencySet dag.Set) ([]string, error) {
+// calculateDependencyHashes returns the sorted, deduplicated hashes of the task's dependencies,
+// along with the hash of each dependency keyed by its taskID
+func (th *Tracker) calculateDependencyHashes(dependencySet dag.Set) ([]string, map[string]string, error) {
 	dependencyHashSet := make(util.Set)
+	dependencyHashes := make(map[string]string)
 
 	rootPrefix := th.rootNode + util.TaskDelimiter
 	th.mu.RLock()
@@ -298,20 +534,21 @@ func (th *Tracker) calculateDependencyHashes(dependencySet dag.Set) ([]string, e
 		}
 		dependencyTask, ok := dependency.(string)
 		if !ok {
-			return nil, fmt.Errorf("unknown task: %v", dependency)
+			return nil, nil, fmt.Errorf("unknown task: %v", dependency)
 		}
 		if strings.HasPrefix(dependencyTask, rootPrefix) {
 			continue
 		}
 		dependencyHash, ok := th.packageTaskHashes[dependencyTask]
 		if !ok {
-			return nil, fmt.Errorf("missing hash for dependent task: %v", dependencyTask)
+			return nil, nil, fmt.Errorf("missing hash for dependent task: %v", dependencyTask)
 		}
 		dependencyHashSet.Add(dependencyHash)
+		dependencyHashes[dependencyTask] = dependencyHash
 	}
 	dependenciesHashList := dependencyHashSet.UnsafeListOfStrings()
 	sort.Strings(dependenciesHashList)
-	return dependenciesHashList, nil
+	return dependenciesHashList, dependencyHashes, nil
 }
 
 // CalculateTaskHash calculates the hash for package-task combination. It is threadsafe, provided
@@ -326,8 +563,47 @@ func (th *Tracker) CalculateTaskHash(packageTask *nodes.PackageTask, dependencyS
 		return "", fmt.Errorf("cannot find package-file hash for %v", pkgFileHashKey)
 	}
 
+	// Root inputs, hashInputsFrom output and dotEnv variables are folded into the hash of the
+	// package files, rather than added as separate fields, so that the hash of tasks using
+	// none of them is unchanged.
+	additionalInputHashes := []string{}
+	if len(packageTask.TaskDefinition.RootInputs) > 0 {
+		rootKey := rootInputsKey(packageTask.TaskDefinition.RootInputs)
+		hashOfRootInputs, ok := th.rootInputsHashes[rootKey]
+		if !ok {
+			return "", fmt.Errorf("cannot find root inputs hash for %v", rootKey)
+		}
+		additionalInputHashes = append(additionalInputHashes, hashOfRootInputs)
+	}
+	if packageTask.TaskDefinition.HashInputsFrom != "" {
+		commandKey := hashInputsFromKeyFor(packageTask.Pkg.Dir, packageTask.TaskDefinition.HashInputsFrom)
+		hashOfCommandOutput, ok := th.hashInputsFromHashes[commandKey]
+		if !ok {
+			return "", fmt.Errorf("cannot find hashInputsFrom hash for %v", commandKey)
+		}
+		additionalInputHashes = append(additionalInputHashes, hashOfCommandOutput)
+	}
+	if len(packageTask.TaskDefinition.DotEnv) > 0 {
+		dotEnvKey := dotEnvKeyFor(packageTask.Pkg.Dir, packageTask.TaskDefinition.DotEnv)
+		hashOfDotEnv, ok := th.dotEnvHashes[dotEnvKey]
+		if !ok {
+			return "", fmt.Errorf("cannot find dotEnv hash for %v", dotEnvKey)
+		}
+		additionalInputHashes = append(additionalInputHashes, hashOfDotEnv)
+	}
+	if len(additionalInputHashes) > 0 {
+		combinedHash, err := fs.HashObject(append([]string{hashOfFiles}, additionalInputHashes...))
+		if err != nil {
+			return "", err
+		}
+		hashOfFiles = combinedHash
+	}
+
 	var keyMatchers []string
-	framework := inference.InferFramework(packageTask.Pkg)
+	framework, err := frameworkForTask(packageTask)
+	if err != nil {
+		return "", err
+	}
 	if framework != nil && framework.EnvMatcher != "" {
 		// log auto detected framework and env prefix
 		logger.Debug(fmt.Sprintf("auto detected framework for %s", packageTask.PackageName), "framework", framework.Slug, "env_prefix", framework.EnvMatcher)
@@ -342,9 +618,15 @@ func (th *Tracker) CalculateTaskHash(packageTask *nodes.PackageTask, dependencyS
 	if err != nil {
 		return "", err
 	}
+	if len(packageTask.TaskDefinition.PassThroughEnv) > 0 {
+		envVars = envVars.Without(packageTask.TaskDefinition.PassThroughEnv)
+	}
+	if len(th.globalPassThroughEnv) > 0 {
+		envVars = envVars.Without(packageTask.TaskDefinition.UnhashedGlobalPassThroughEnv(th.globalPassThroughEnv))
+	}
 	hashableEnvPairs := envVars.All.ToHashable()
 	outputs := packageTask.HashableOutputs()
-	taskDependencyHashes, err := th.calculateDependencyHashes(dependencySet)
+	taskDependencyHashes, dependencyHashesByTask, err := th.calculateDependencyHashes(dependencySet)
 	if err != nil {
 		return "", err
 	}
@@ -368,6 +650,7 @@ func (th *Tracker) CalculateTaskHash(packageTask *nodes.PackageTask, dependencyS
 	th.mu.Lock()
 	th.packageTaskEnvVars[packageTask.TaskID] = envVars
 	th.packageTaskHashes[packageTask.TaskID] = hash
+	th.packageTaskDependencyHashes[packageTask.TaskID] = dependencyHashesByTask
 	if framework != nil {
 		th.packageTaskFramework[packageTask.TaskID] = framework.Slug
 	}
@@ -375,6 +658,23 @@ func (th *Tracker) CalculateTaskHash(packageTask *nodes.PackageTask, dependencyS
 	return hash, nil
 }
 
+// frameworkForTask returns the framework whose environment variables are automatically
+// included in the hash of the given task, respecting the task's "framework" override
+func frameworkForTask(packageTask *nodes.PackageTask) (*inference.Framework, error) {
+	switch override := packageTask.TaskDefinition.Framework; override {
+	case "":
+		return inference.InferFramework(packageTask.Pkg), nil
+	case inference.NoFramework:
+		return nil, nil
+	default:
+		framework := inference.FrameworkBySlug(override)
+		if framework == nil {
+			return nil, fmt.Errorf("unknown framework \"%v\" configured for %v", override, packageTask.TaskID)
+		}
+		return framework, nil
+	}
+}
+
 // GetExpandedInputs gets the expanded set of inputs for a given PackageTask
 func (th *Tracker) GetExpandedInputs(packageTask *nodes.PackageTask) map[turbopath.AnchoredUnixPath]string {
 	pfs := specFromPackageTask(packageTask)
@@ -401,3 +701,16 @@ func (th *Tracker) GetFramework(taskID string) string {
 	defer th.mu.RUnlock()
 	return th.packageTaskFramework[taskID]
 }
+
+// GetDependencyHashes returns the hash of each dependency that contributed to the hash of
+// the given taskID, keyed by the taskID of the dependency
+func (th *Tracker) GetDependencyHashes(taskID string) map[string]string {
+	th.mu.RLock()
+	defer th.mu.RUnlock()
+	dependencyHashes := th.packageTaskDependencyHashes[taskID]
+	hashesCopy := make(map[string]string, len(dependencyHashes))
+	for dependency, hash := range dependencyHashes {
+		hashesCopy[dependency] = hash
+	}
+	return hashesCopy
+}
@@ -2,9 +2,12 @@ package taskhash
 
 import (
 	"path/filepath"
+	"reflect"
+	"runtime"
 	"strings"
 	"testing"
 
+	"github.com/pyr-sh/dag"
 	"github.com/vercel/turbo/cli/internal/fs"
 	"github.com/vercel/turbo/cli/internal/turbopath"
 )
@@ -135,4 +138,167 @@ func Test_manuallyHashPackage(t *testing.T) {
 	if count != len(justFileHashes) {
 		t.Errorf("found extra hashes in %v", hashes)
 	}
+	// $TURBO_DEFAULT includes every file of the package, before exclusions
+	defaultHashes, err := manuallyHashPackage(pkg, []string{"$TURBO_DEFAULT", filepath.FromSlash("some-dir/other-file"), "!" + filepath.FromSlash("some-dir/excluded-file")}, repoRoot)
+	if err != nil {
+		t.Fatalf("failed to calculate manual hashes: %v", err)
+	}
+	delete(hashes, "some-dir/excluded-file")
+	if !reflect.DeepEqual(defaultHashes, hashes) {
+		t.Errorf("hashes with $TURBO_DEFAULT, got %v want %v", defaultHashes, hashes)
+	}
+}
+
+func Test_manuallyHashPackage_outsideInputs(t *testing.T) {
+	repoRoot := turbopath.AbsoluteSystemPathFromUpstream(t.TempDir())
+	for _, file := range []string{"packages/web/index.js", "packages/shared/util.js", "packages/shared/nested/types.js", "packages/other/other.js"} {
+		path := repoRoot.UntypedJoin(filepath.FromSlash(file))
+		if err := path.EnsureDir(); err != nil {
+			t.Fatalf("failed to ensure directories for %v: %v", path, err)
+		}
+		if err := path.WriteFile([]byte("some-file-contents"), 0644); err != nil {
+			t.Fatalf("failed to write %v: %v", path, err)
+		}
+	}
+	pkg := &fs.PackageJSON{Dir: turbopath.AnchoredUnixPath("packages/web").ToSystemPath()}
+
+	hashes, err := manuallyHashPackage(pkg, []string{"$TURBO_DEFAULT", filepath.FromSlash("../shared/**"), "!" + filepath.FromSlash("../shared/nested/**")}, repoRoot)
+	if err != nil {
+		t.Fatalf("failed to calculate manual hashes: %v", err)
+	}
+	expected := map[turbopath.AnchoredUnixPath]string{
+		"index.js":          "7e59c6a6ea9098c6d3beb00e753e2c54ea502311",
+		"../shared/util.js": "7e59c6a6ea9098c6d3beb00e753e2c54ea502311",
+	}
+	if !reflect.DeepEqual(hashes, expected) {
+		t.Errorf("hashes with inputs outside of the package, got %v want %v", hashes, expected)
+	}
+
+	_, err = manuallyHashPackage(pkg, []string{filepath.FromSlash("../../../outside/**")}, repoRoot)
+	if err == nil {
+		t.Error("expected an error for an input outside of the repository")
+	}
+}
+
+func Test_hashCommandOutput(t *testing.T) {
+	dir := turbopath.AbsoluteSystemPath(t.TempDir())
+	if err := dir.UntypedJoin("probe").WriteFile([]byte("first"), 0644); err != nil {
+		t.Fatalf("failed to write probe file: %v", err)
+	}
+
+	command := "cat probe"
+	if runtime.GOOS == "windows" {
+		command = "type probe"
+	}
+
+	first, err := hashCommandOutput(command, dir)
+	if err != nil {
+		t.Fatalf("failed to hash command output: %v", err)
+	}
+	again, err := hashCommandOutput(command, dir)
+	if err != nil {
+		t.Fatalf("failed to hash command output: %v", err)
+	}
+	if first != again {
+		t.Errorf("hash of unchanged output, got %v want %v", again, first)
+	}
+
+	if err := dir.UntypedJoin("probe").WriteFile([]byte("second"), 0644); err != nil {
+		t.Fatalf("failed to write probe file: %v", err)
+	}
+	second, err := hashCommandOutput(command, dir)
+	if err != nil {
+		t.Fatalf("failed to hash command output: %v", err)
+	}
+	if first == second {
+		t.Errorf("expected hash to change when the command output changed, got %v both times", first)
+	}
+
+	if _, err := hashCommandOutput(command+"-does-not-exist", dir); err == nil {
+		t.Error("expected an error for a failing command")
+	}
+}
+
+func Test_hashDotEnv(t *testing.T) {
+	dir := turbopath.AbsoluteSystemPath(t.TempDir())
+	writeDotEnv := func(name string, contents string) {
+		t.Helper()
+		if err := dir.UntypedJoin(name).WriteFile([]byte(contents), 0644); err != nil {
+			t.Fatalf("failed to write %v: %v", name, err)
+		}
+	}
+	hash := func(files ...string) string {
+		t.Helper()
+		hash, err := hashDotEnv(files, dir)
+		if err != nil {
+			t.Fatalf("failed to hash dotEnv files: %v", err)
+		}
+		return hash
+	}
+
+	writeDotEnv(".env", "API_URL=https://example.com\nREGION=us-east-1\n")
+	first := hash(".env", ".env.local")
+	if again := hash(".env", ".env.local"); first != again {
+		t.Errorf("hash of unchanged files, got %v want %v", again, first)
+	}
+
+	// Only the variables matter, not their order or the comments around them
+	writeDotEnv(".env", "# Deployment\nREGION=us-east-1\n\nAPI_URL=https://example.com\n")
+	if reordered := hash(".env", ".env.local"); reordered != first {
+		t.Errorf("expected reordering variables to keep the hash, got %v want %v", reordered, first)
+	}
+
+	// .env.local was missing, and now overrides a variable
+	writeDotEnv(".env.local", "REGION=eu-west-1\n")
+	overridden := hash(".env", ".env.local")
+	if overridden == first {
+		t.Errorf("expected hash to change when a variable was overridden, got %v both times", first)
+	}
+	// Later files take precedence
+	writeDotEnv(".env", "API_URL=https://example.com\nREGION=eu-west-1\n")
+	if err := dir.UntypedJoin(".env.local").Remove(); err != nil {
+		t.Fatalf("failed to remove .env.local: %v", err)
+	}
+	if same := hash(".env", ".env.local"); same != overridden {
+		t.Errorf("expected the same variables to hash the same, got %v want %v", same, overridden)
+	}
+
+	writeDotEnv(".env", "NOT AN ASSIGNMENT\n")
+	if _, err := hashDotEnv([]string{".env"}, dir); err == nil {
+		t.Error("expected an error for a malformed .env file")
+	}
+}
+
+func Test_calculateDependencyHashes(t *testing.T) {
+	th := NewTracker("___ROOT___", "global-hash", fs.Pipeline{}, nil)
+	th.packageTaskHashes["a#build"] = "hash-of-a"
+	th.packageTaskHashes["b#build"] = "hash-of-b"
+	th.packageTaskHashes["c#build"] = "hash-of-a"
+
+	dependencySet := make(dag.Set)
+	for _, dependency := range []string{"___ROOT___", "___ROOT___#lint", "b#build", "a#build", "c#build"} {
+		dependencySet.Add(dependency)
+	}
+
+	hashes, byTask, err := th.calculateDependencyHashes(dependencySet)
+	if err != nil {
+		t.Fatalf("failed to calculate dependency hashes: %v", err)
+	}
+	// Hashes are deduplicated when they're folded into the task hash, but every dependency is reported
+	if want := []string{"hash-of-a", "hash-of-b"}; !reflect.DeepEqual(hashes, want) {
+		t.Errorf("dependency hashes, got %v want %v", hashes, want)
+	}
+	wantByTask := map[string]string{
+		"a#build": "hash-of-a",
+		"b#build": "hash-of-b",
+		"c#build": "hash-of-a",
+	}
+	if !reflect.DeepEqual(byTask, wantByTask) {
+		t.Errorf("dependency hashes by task, got %v want %v", byTask, wantByTask)
+	}
+
+	dependencySet.Add("d#build")
+	if _, _, err := th.calculateDependencyHashes(dependencySet); err == nil {
+		t.Error("expected an error for a dependency without a hash")
+	}
 }
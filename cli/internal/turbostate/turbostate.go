@@ -29,16 +29,55 @@ type PrunePayload struct {
 	OutputDir string   `json:"output_dir"`
 }
 
+// CachePayload is the subcommand and flags passed for the `cache` subcommand
+type CachePayload struct {
+	Command  string `json:"command"`
+	Fix      bool   `json:"fix"`
+	Prune    bool   `json:"prune"`
+	CacheDir string `json:"cache_dir"`
+}
+
+// DiffPayload is the arguments passed for the `diff` subcommand
+type DiffPayload struct {
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// StatsPayload is the extra flags passed for the `stats` subcommand
+type StatsPayload struct {
+	Reset bool `json:"reset"`
+	// MaxRuns is nil when the history size isn't being changed
+	MaxRuns *int `json:"max_runs"`
+	Limit   int  `json:"limit"`
+}
+
 // RunPayload is the extra flags passed for the `run` subcommand
 type RunPayload struct {
-	CacheDir          string   `json:"cache_dir"`
-	CacheWorkers      int      `json:"cache_workers"`
-	Concurrency       string   `json:"concurrency"`
-	ContinueExecution bool     `json:"continue_execution"`
-	DryRun            string   `json:"dry_run"`
-	Filter            []string `json:"filter"`
-	Force             bool     `json:"force"`
-	GlobalDeps        []string `json:"global_deps"`
+	AffectedOutput         string   `json:"affected_output"`
+	AuditIO                bool     `json:"audit_io"`
+	CacheBackend           string   `json:"cache_backend"`
+	CacheSigning           string   `json:"cache_signing"`
+	CacheDir               string   `json:"cache_dir"`
+	CacheKeySalt           string   `json:"cache_key_salt"`
+	CacheWorkers           int      `json:"cache_workers"`
+	CacheUploadConcurrency int      `json:"cache_upload_concurrency"`
+	CheckReproducible      bool     `json:"check_reproducible"`
+	CompletionWebhook      string   `json:"completion_webhook"`
+	CompressLogs           bool     `json:"compress_logs"`
+	Concurrency            string   `json:"concurrency"`
+	ConcurrencyGroup       []string `json:"concurrency_group"`
+	ContinueExecution      bool     `json:"continue_execution"`
+	DeferCacheWrites       bool     `json:"defer_cache_writes"`
+	DryRun                 string   `json:"dry_run"`
+	DumpInputs             string   `json:"dump_inputs"`
+	EnvMode                string   `json:"env_mode"`
+	ErrorOnEmpty           bool     `json:"error_on_empty"`
+	Errors                 string   `json:"errors"`
+	EventsSocket           string   `json:"events_socket"`
+	Filter                 []string `json:"filter"`
+	Force                  bool     `json:"force"`
+	GithubAnnotations      *bool    `json:"github_annotations"`
+	GlobalDeps             []string `json:"global_deps"`
 	// NOTE: Graph has three effective states that is modeled using a *string:
 	//   nil -> no flag passed
 	//   ""  -> flag passed but no file name attached: print to stdout
@@ -48,29 +87,63 @@ type RunPayload struct {
 	Graph               *string  `json:"graph"`
 	Ignore              []string `json:"ignore"`
 	IncludeDependencies bool     `json:"include_dependencies"`
+	IsolateOutputs      bool     `json:"isolate_outputs"`
+	KillTimeout         string   `json:"kill_timeout"`
+	LogFileName         string   `json:"log_file_name"`
+	MaxCacheSize        string   `json:"max_cache_size"`
+	MaxLogBytesPerTask  string   `json:"max_log_bytes_per_task"`
 	NoCache             bool     `json:"no_cache"`
 	NoDaemon            bool     `json:"no_daemon"`
 	NoDeps              bool     `json:"no_deps"`
-	Only                bool     `json:"only"`
-	OutputLogs          string   `json:"output_logs"`
-	PassThroughArgs     []string `json:"pass_through_args"`
-	Parallel            bool     `json:"parallel"`
-	Profile             string   `json:"profile"`
-	RemoteOnly          bool     `json:"remote_only"`
-	Scope               []string `json:"scope"`
-	Since               string   `json:"since"`
-	SinglePackage       bool     `json:"single_package"`
-	Tasks               []string `json:"tasks"`
-	PkgInferenceRoot    string   `json:"pkg_inference_root"`
-	LogPrefix           string   `json:"log_prefix"`
+	NoLockfileCache     bool     `json:"no_lockfile_cache"`
+	// NoOp is nil when the flag isn't passed, "" when it is passed without a
+	// duration, and the duration to simulate each task for otherwise.
+	NoOp                   *string  `json:"no_op"`
+	NoPreamble             bool     `json:"no_preamble"`
+	NoWorkspaceCache       bool     `json:"no_workspace_cache"`
+	Offline                bool     `json:"offline"`
+	Only                   bool     `json:"only"`
+	Output                 string   `json:"output"`
+	OutputLogs             string   `json:"output_logs"`
+	PassThroughArgs        []string `json:"pass_through_args"`
+	Parallel               bool     `json:"parallel"`
+	Prefetch               bool     `json:"prefetch"`
+	Profile                string   `json:"profile"`
+	RemoteCacheReadOnly    bool     `json:"remote_cache_read_only"`
+	RemoteOnly             bool     `json:"remote_only"`
+	RerunDependentsOf      []string `json:"rerun_dependents_of"`
+	RequireRemoteCache     bool     `json:"require_remote_cache"`
+	RunTimeout             string   `json:"run_timeout"`
+	Scope                  []string `json:"scope"`
+	SequentialPrefixColors bool     `json:"sequential_prefix_colors"`
+	SerialWithinPackage    bool     `json:"serial_within_package"`
+	Since                  string   `json:"since"`
+	SinglePackage          bool     `json:"single_package"`
+	StreamLogsTo           string   `json:"stream_logs_to"`
+	Strict                 bool     `json:"strict"`
+	StrictEnv              bool     `json:"strict_env"`
+	SummarizeGlobalHash    bool     `json:"summarize_global_hash"`
+	SummaryPath            string   `json:"summary_path"`
+	TaskOrderFile          string   `json:"task_order_file"`
+	Tasks                  []string `json:"tasks"`
+	ValidateConfig         bool     `json:"validate_config"`
+	VerifyCacheOutputs     bool     `json:"verify_cache_outputs"`
+	PkgInferenceRoot       string   `json:"pkg_inference_root"`
+	LogPrefix              string   `json:"log_prefix"`
+	LogPrefixTemplate      string   `json:"log_prefix_template"`
+	LogOrder               string   `json:"log_order"`
 }
 
 // Command consists of the data necessary to run a command.
 // Only one of these fields should be initialized at a time.
 type Command struct {
+	Cache  *CachePayload  `json:"cache"`
 	Daemon *DaemonPayload `json:"daemon"`
+	Diff   *DiffPayload   `json:"diff"`
 	Prune  *PrunePayload  `json:"prune"`
 	Run    *RunPayload    `json:"run"`
+	Stats  *StatsPayload  `json:"stats"`
+	Watch  *RunPayload    `json:"watch"`
 }
 
 // ParsedArgsFromRust are the parsed command line arguments passed
@@ -89,6 +89,27 @@ func (into *stripAnsiWriter) Write(p []byte) (int, error) {
 	return len(p), nil
 }
 
+// OutputToStderr returns a copy of a UI built by BuildColoredUi that writes all of its
+// output to stderr, so that stdout can carry machine-readable output. Other UIs are
+// returned unchanged.
+func OutputToStderr(terminal cli.Ui) cli.Ui {
+	colored, ok := terminal.(*cli.ColoredUi)
+	if !ok {
+		return terminal
+	}
+	basic, ok := colored.Ui.(*cli.BasicUi)
+	if !ok {
+		return terminal
+	}
+	redirected := *colored
+	redirected.Ui = &cli.BasicUi{
+		Reader:      basic.Reader,
+		Writer:      basic.ErrorWriter,
+		ErrorWriter: basic.ErrorWriter,
+	}
+	return &redirected
+}
+
 // Default returns the default colored ui
 func Default() *cli.ColoredUi {
 	return BuildColoredUi(ColorModeUndefined)
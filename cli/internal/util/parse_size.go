@@ -0,0 +1,46 @@
+package util
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// _sizeUnits are the multipliers of the units accepted by ParseSize. KB, MB, GB and TB
+// are decimal, their KiB, MiB, GiB and TiB counterparts are binary.
+var _sizeUnits = map[string]float64{
+	"":    1,
+	"b":   1,
+	"kb":  1e3,
+	"mb":  1e6,
+	"gb":  1e9,
+	"tb":  1e12,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+}
+
+// ParseSize parses a size in bytes, which can be a number of bytes (e.g. 1048576) or a
+// number with a unit (e.g. 500MB, 2GB, 1.5GiB). Units are case-insensitive.
+func ParseSize(sizeRaw string) (int64, error) {
+	trimmed := strings.TrimSpace(sizeRaw)
+	unitStart := strings.LastIndexFunc(trimmed, func(r rune) bool {
+		return (r >= '0' && r <= '9') || r == '.'
+	}) + 1
+	number, unit := trimmed[:unitStart], strings.ToLower(strings.TrimSpace(trimmed[unitStart:]))
+	multiplier, ok := _sizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("invalid size %q: unknown unit %q, use B, KB, MB, GB or TB", sizeRaw, trimmed[unitStart:])
+	}
+	value, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q: this should be a number of bytes, or a number with a unit like 500MB or 2GB", sizeRaw)
+	}
+	bytes := value * multiplier
+	if bytes < 1 || bytes > math.MaxInt64 {
+		return 0, fmt.Errorf("invalid size %q: this should be at least 1 byte", sizeRaw)
+	}
+	return int64(bytes), nil
+}
@@ -0,0 +1,37 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSize(t *testing.T) {
+	cases := []struct {
+		Input    string
+		Expected int64
+	}{
+		{"1048576", 1048576},
+		{"500MB", 500_000_000},
+		{"2GB", 2_000_000_000},
+		{"2gb", 2_000_000_000},
+		{"1.5 GB", 1_500_000_000},
+		{"10KiB", 10240},
+		{"1GiB", 1 << 30},
+		{"64B", 64},
+	}
+	for _, tc := range cases {
+		t.Run(tc.Input, func(t *testing.T) {
+			result, err := ParseSize(tc.Input)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+
+	for _, input := range []string{"", "GB", "5 parsecs", "0", "-5MB", "1.2.3GB"} {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseSize(input)
+			assert.Error(t, err)
+		})
+	}
+}
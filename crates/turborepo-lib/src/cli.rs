@@ -52,6 +52,45 @@ pub enum DryRunMode {
     Json,
 }
 
+// NOTE: This *must* be kept in sync with the `_outputNDJSONValue` constant in
+// run.go.
+#[derive(Copy, Clone, Debug, PartialEq, Serialize, ValueEnum)]
+pub enum OutputFormat {
+    Ndjson,
+}
+
+// NOTE: This *must* be kept in sync with the `_logOrderGroupedValue` constant
+// in run.go.
+#[derive(Copy, Clone, Debug, PartialEq, Serialize, ValueEnum)]
+pub enum LogOrder {
+    Stream,
+    Grouped,
+}
+
+// NOTE: This *must* be kept in sync with the `_errorFormatJSONValue` constant
+// in run.go.
+#[derive(Copy, Clone, Debug, PartialEq, Serialize, ValueEnum)]
+pub enum ErrorFormat {
+    Text,
+    Json,
+}
+
+// NOTE: This *must* be kept in sync with the `_cacheSigningRequiredValue`
+// constant in run.go.
+#[derive(Copy, Clone, Debug, PartialEq, Serialize, ValueEnum)]
+pub enum CacheSigning {
+    Optional,
+    Required,
+}
+
+// NOTE: These *must* be kept in sync with the `_envModeStrictValue` constant in
+// run.go.
+#[derive(Copy, Clone, Debug, PartialEq, Serialize, ValueEnum)]
+pub enum EnvMode {
+    Loose,
+    Strict,
+}
+
 #[derive(Parser, Clone, Default, Debug, PartialEq, Serialize)]
 #[clap(author, about = "The build system that makes ship happen", long_about = None)]
 #[clap(disable_help_subcommand = true)]
@@ -164,6 +203,31 @@ pub enum DaemonCommand {
     Stop,
 }
 
+#[derive(Subcommand, Clone, Debug, Serialize, PartialEq)]
+#[serde(tag = "command")]
+pub enum CacheCommand {
+    /// Checks the local cache for orphaned and corrupt entries, and whether
+    /// the remote cache can be reached
+    Doctor {
+        /// Remove broken entries from the local cache
+        #[clap(long)]
+        fix: bool,
+        /// Override the filesystem cache directory.
+        #[clap(long)]
+        cache_dir: Option<String>,
+    },
+    /// Verifies every entry of the local cache against its manifest, and
+    /// exits with an error if any is corrupt
+    Verify {
+        /// Remove corrupt entries from the local cache
+        #[clap(long)]
+        prune: bool,
+        /// Override the filesystem cache directory.
+        #[clap(long)]
+        cache_dir: Option<String>,
+    },
+}
+
 impl Args {
     pub fn new() -> Result<Self> {
         let mut clap_args = match Args::try_parse() {
@@ -219,6 +283,12 @@ pub enum Command {
     // them as `{ "Bin": {} }` instead of as `"Bin"`.
     /// Get the path to the Turbo binary
     Bin {},
+    /// Inspect and repair the local and remote caches
+    Cache {
+        #[clap(subcommand)]
+        #[serde(flatten)]
+        command: CacheCommand,
+    },
     /// Generate the autocompletion script for the specified shell
     #[serde(skip)]
     Completion { shell: Shell },
@@ -231,6 +301,15 @@ pub enum Command {
         #[serde(flatten)]
         command: Option<DaemonCommand>,
     },
+    /// Compare two run summaries, reporting the tasks whose hashes or cache
+    /// states changed and why
+    Diff {
+        /// The summary of the earlier run, written by --dry=json or
+        /// --summary-path
+        before: String,
+        /// The summary of the later run
+        after: String,
+    },
     /// Link your local directory to a Vercel organization and enable remote
     /// caching.
     Link {
@@ -265,9 +344,28 @@ pub enum Command {
     ///
     /// Arguments passed after '--' will be passed through to the named tasks.
     Run(Box<RunArgs>),
+    /// Report the slowest tasks and their cache hit rates across recent runs
+    Stats {
+        /// Remove the recorded history of runs
+        #[clap(long)]
+        reset: bool,
+        /// Keep the given number of most recent runs in the history, dropping
+        /// older runs (default 100)
+        #[clap(long, value_name = "RUNS")]
+        max_runs: Option<u32>,
+        /// Report the given number of slowest tasks. Use 0 to report every
+        /// task
+        #[clap(long, default_value_t = 10)]
+        limit: u32,
+    },
     /// Unlink the current directory from your Vercel organization and disable
     /// Remote Caching
     Unlink {},
+    /// Run tasks, then re-run the tasks whose inputs change as files are
+    /// edited
+    ///
+    /// Takes the same arguments as `turbo run`.
+    Watch(Box<RunArgs>),
 }
 
 #[derive(Parser, Clone, Debug, Default, Serialize, PartialEq)]
@@ -275,19 +373,92 @@ pub struct RunArgs {
     /// Override the filesystem cache directory.
     #[clap(long)]
     pub cache_dir: Option<String>,
+    /// Fold a salt into the global hash, so that a new salt invalidates every
+    /// cache. Can also be set with TURBO_CACHE_KEY_SALT
+    #[clap(long, value_name = "SALT")]
+    pub cache_key_salt: Option<String>,
     /// Set the number of concurrent cache operations (default 10)
     #[clap(long, default_value_t = 10)]
     pub cache_workers: u32,
+    /// Limit the number of artifacts uploaded to the remote cache at once.
+    /// Uploads proceed in the background once outputs are cached locally
+    #[clap(long, value_name = "COUNT")]
+    pub cache_upload_concurrency: Option<u32>,
+    /// Use the cache backend at the given URL in place of the Vercel Remote
+    /// Cache. The URL scheme selects the backend
+    #[clap(long, value_name = "URL")]
+    pub cache_backend: Option<String>,
+    /// Set whether artifacts must be signed with TURBO_CACHE_SIGNING_KEY to be
+    /// restored. Use "optional" to restore unsigned artifacts, and "required"
+    /// to treat them as cache misses. (default optional)
+    #[clap(long, value_enum)]
+    pub cache_signing: Option<CacheSigning>,
+    /// Keep the local cache under a size such as 2GB, evicting the least
+    /// recently used artifacts
+    #[clap(long, value_name = "SIZE")]
+    pub max_cache_size: Option<String>,
+    /// Run each task twice and compare the files produced by both executions
+    /// to detect tasks whose outputs are not reproducible
+    #[clap(long)]
+    pub check_reproducible: bool,
+    /// Hash the tasks without running them, and write the packages that have
+    /// at least one task that would not be restored from the cache to the
+    /// given file, one per line
+    #[clap(long, value_name = "FILE")]
+    pub affected_output: Option<String>,
+    /// Run each task under a syscall tracer (strace on Linux, dtrace on
+    /// macOS) and report files it reads or writes that are not declared as
+    /// inputs or outputs. Tasks run significantly slower
+    #[clap(long)]
+    pub audit_io: bool,
+    /// POST the JSON summary of the run to the given URL when the run ends.
+    /// The body is signed with TURBO_WEBHOOK_SECRET, if it is set
+    #[clap(long, value_name = "URL")]
+    pub completion_webhook: Option<String>,
+    /// Store task logs gzipped, as .log.gz files. Logs are decompressed when
+    /// they are replayed
+    #[clap(long)]
+    pub compress_logs: bool,
     /// Limit the concurrency of task execution. Use 1 for serial (i.e.
     /// one-at-a-time) execution.
     #[clap(long)]
     pub concurrency: Option<String>,
+    /// Limit how many tasks of a "concurrencyGroup" run at once, as
+    /// <group>=<limit>. Can be passed multiple times
+    #[clap(long, action = ArgAction::Append, value_name = "GROUP=LIMIT")]
+    pub concurrency_group: Vec<String>,
     /// Continue execution even if a task exits with an error or non-zero
     /// exit code. The default behavior is to bail
     #[clap(long = "continue")]
     pub continue_execution: bool,
+    /// Only write task outputs to the cache once every task in the run has
+    /// succeeded. If any task fails, nothing is written to the cache
+    #[clap(long)]
+    pub defer_cache_writes: bool,
     #[clap(alias = "dry", long = "dry-run", num_args = 0..=1, default_missing_value = "text")]
     pub dry_run: Option<DryRunMode>,
+    /// Write the files that went into each task's hash, along with their
+    /// hashes, into <DIR>/<package>/<task>.json
+    #[clap(long, value_name = "DIR")]
+    pub dump_inputs: Option<String>,
+    /// Use "strict" to run tasks with only the environment variables that are
+    /// part of their hash or their passThroughEnv, along with PATH. Use
+    /// "loose" to pass every environment variable. (default loose)
+    #[clap(long, value_enum)]
+    pub env_mode: Option<EnvMode>,
+    /// Exit with an error when no tasks are in scope after filtering,
+    /// instead of successfully running nothing
+    #[clap(long)]
+    pub error_on_empty: bool,
+    /// Set the format of the errors printed at the end of the run. Use
+    /// "json" to print each error as a JSON object with its task, package,
+    /// exit code, log file and category. (default text)
+    #[clap(long, value_enum)]
+    pub errors: Option<ErrorFormat>,
+    /// Write a line of JSON to the Unix socket at this path when each task
+    /// starts, hits the cache, completes or fails
+    #[clap(long, value_name = "PATH")]
+    pub events_socket: Option<String>,
     /// Run turbo in single-package mode
     #[clap(long, global = true)]
     pub single_package: bool,
@@ -300,6 +471,17 @@ pub struct RunArgs {
     /// Ignore the existing cache (to force execution)
     #[clap(long)]
     pub force: bool,
+    /// Group the output of each task and annotate failed tasks using GitHub
+    /// Actions workflow commands. Enabled when GITHUB_ACTIONS is true, unless
+    /// --log-order is given. Use --github-annotations=false to disable
+    #[clap(
+        long,
+        num_args = 0..=1,
+        default_missing_value = "true",
+        require_equals = true,
+        value_name = "BOOL"
+    )]
+    pub github_annotations: Option<bool>,
     /// Specify glob of global filesystem dependencies to be hashed. Useful
     /// for .env and files
     #[clap(long = "global-deps", action = ArgAction::Append)]
@@ -316,6 +498,23 @@ pub struct RunArgs {
     /// Include the dependencies of tasks in execution.
     #[clap(long)]
     pub include_dependencies: bool,
+    /// Capture task logs and stage cache artifacts in a workspace unique to
+    /// this run, so that concurrent runs in the same repository don't
+    /// interfere with each other
+    #[clap(long)]
+    pub isolate_outputs: bool,
+    /// How long tasks are given to exit after being sent SIGTERM when the run
+    /// is stopped, before they are killed (default 10s)
+    #[clap(long, value_name = "DURATION")]
+    pub kill_timeout: Option<String>,
+    /// The name of the log files written into --stream-logs-to. Supports
+    /// {package}, {task} and {hash} (default "{package}/{task}.log")
+    #[clap(long, value_name = "TEMPLATE")]
+    pub log_file_name: Option<String>,
+    /// Truncate the output of each task after a size such as 10MB, leaving
+    /// the rest of its output out of the terminal and its log file
+    #[clap(long, value_name = "SIZE")]
+    pub max_log_bytes_per_task: Option<String>,
     /// Avoid saving task results to the cache. Useful for development/watch
     /// tasks.
     #[clap(long)]
@@ -326,6 +525,26 @@ pub struct RunArgs {
     /// Exclude dependent task consumers from execution.
     #[clap(long)]
     pub no_deps: bool,
+    /// Parse the lockfile instead of reusing the result of parsing it on a
+    /// previous run
+    #[clap(long)]
+    pub no_lockfile_cache: bool,
+    /// Walk the task graph without running any commands, simulating each
+    /// task for the given duration (e.g. 500ms) when one is provided
+    #[clap(long, num_args = 0..=1, default_missing_value = "", value_name = "DURATION")]
+    pub no_op: Option<String>,
+    /// Don't print the packages in scope, the tasks being run and whether
+    /// remote caching is enabled before running tasks
+    #[clap(long)]
+    pub no_preamble: bool,
+    /// Discover workspaces instead of reusing the workspaces found on a
+    /// previous run
+    #[clap(long)]
+    pub no_workspace_cache: bool,
+    /// Disable remote caching and fail rather than make network requests,
+    /// for builds without network access
+    #[clap(long)]
+    pub offline: bool,
     /// Set type of process output logging. Use "full" to show
     /// all output. Use "hash-only" to show only turbo-computed
     /// task hashes. Use "new-only" to show only new output with
@@ -333,6 +552,11 @@ pub struct RunArgs {
     /// output. (default full)
     #[clap(long, value_enum)]
     pub output_logs: Option<OutputLogsMode>,
+    /// Stream machine-readable output to stdout while the run progresses.
+    /// Use "ndjson" to write a JSON object for each task, one per line, as soon
+    /// as it finishes
+    #[clap(long, value_enum)]
+    pub output: Option<OutputFormat>,
     #[clap(long, hide = true)]
     pub only: bool,
     /// Execute all tasks in parallel.
@@ -340,29 +564,102 @@ pub struct RunArgs {
     pub parallel: bool,
     #[clap(long, hide = true, default_missing_value = "")]
     pub pkg_inference_root: Option<String>,
+    /// Calculate the hash of every task before running any, and download
+    /// their remote cache artifacts in the background while tasks run
+    #[clap(long)]
+    pub prefetch: bool,
     /// File to write turbo's performance profile output into.
     /// You can load the file up in chrome://tracing to see
     /// which parts of your build were slow.
     #[clap(long)]
     pub profile: Option<String>,
+    /// Restore artifacts from the remote cache without uploading the
+    /// outputs of executed tasks to it. The local cache is still written
+    #[clap(long)]
+    pub remote_cache_read_only: bool,
     /// Ignore the local filesystem cache for all tasks. Only
     /// allow reading and caching artifacts using the remote cache.
     #[clap(long)]
     pub remote_only: bool,
+    /// Execute every task that depends on the given task, even if it is
+    /// cached. Use a task name to match it in every package, or
+    /// <package>#<task>
+    #[clap(long, action = ArgAction::Append, value_name = "TASK")]
+    pub rerun_dependents_of: Vec<String>,
+    /// Fail before running any task if the remote cache is disabled,
+    /// read-only or doesn't accept artifacts
+    #[clap(long)]
+    pub require_remote_cache: bool,
+    /// Stop the whole run once it has run for a duration such as 30m,
+    /// stopping the tasks that are still running as if it was interrupted
+    #[clap(long, value_name = "DURATION")]
+    pub run_timeout: Option<String>,
     /// Specify package(s) to act as entry points for task execution.
     /// Supports globs.
     #[clap(long)]
     pub scope: Vec<String>,
+    /// Color the prefixes of task logs in the order packages first log,
+    /// rather than from a hash of the package name that is stable across runs
+    #[clap(long)]
+    pub sequential_prefix_colors: bool,
+    /// Run at most one task of each package at a time, for packages whose
+    /// tasks contend for the same resources when they run concurrently
+    #[clap(long)]
+    pub serial_within_package: bool,
     /// Limit/Set scope to changed packages since a mergebase.
     /// This uses the git diff ${target_branch}... mechanism
     /// to identify which packages have changed.
     #[clap(long)]
     pub since: Option<String>,
+    /// Write the output of each task into <DIR>/<package>/<task>.log as the
+    /// task runs, in addition to the cached log file
+    #[clap(long, alias = "log-dir", value_name = "DIR")]
+    pub stream_logs_to: Option<String>,
+    /// Fail the run, instead of warning, when the configuration can lead to
+    /// inconsistent caching, such as outputs that are tracked by git
+    #[clap(long)]
+    pub strict: bool,
+    /// Fail the run, instead of warning, when the script of a task uses
+    /// environment variables that the task doesn't declare
+    #[clap(long)]
+    pub strict_env: bool,
+    /// Print the inputs of the global hash as JSON, sorted so that the
+    /// output of two runs can be diffed, instead of running tasks
+    #[clap(long)]
+    pub summarize_global_hash: bool,
+    /// Write the JSON summary of the run, the same as --dry=json reports
+    /// plus the outcome of each task, to the given file when the run ends
+    #[clap(long, value_name = "PATH")]
+    pub summary_path: Option<String>,
+    /// Start the tasks listed in the given file, one task ID per line, as
+    /// early as their dependencies allow, ahead of other tasks ready to run
+    #[clap(long, value_name = "FILE")]
+    pub task_order_file: Option<String>,
+    /// Check the turbo.json of the root and of every workspace for unknown
+    /// keys, values of the wrong type and invalid globs, then exit without
+    /// running tasks
+    #[clap(long)]
+    pub validate_config: bool,
+    /// Check the outputs restored from the local cache against the hashes
+    /// recorded in the cache manifest, and execute the task on a mismatch
+    #[clap(long)]
+    pub verify_cache_outputs: bool,
     /// Use "none" to remove prefixes from task logs. Note that tasks running
     /// in parallel interleave their logs and prefix is the only way
     /// to identify which task produced a log.
     #[clap(long, value_enum)]
     pub log_prefix: Option<LogPrefix>,
+    /// Set the prefix of task logs. {package}, {task}, {taskId} and {hash}
+    /// are replaced with the task's values, and {hash:8} with the first 8
+    /// characters of its hash. (default "{package}:{task}")
+    #[clap(long, value_name = "TEMPLATE")]
+    pub log_prefix_template: Option<String>,
+    /// Set the order of task logs. Use "stream" to show output as tasks
+    /// print it, interleaving the logs of concurrent tasks. Use "grouped" to
+    /// show the output of each task at once when it finishes. (default
+    /// stream)
+    #[clap(long, value_enum)]
+    pub log_order: Option<LogOrder>,
     // NOTE: The following two are hidden because clap displays them in the help text incorrectly:
     // > Usage: turbo [OPTIONS] [TASKS]... [-- <FORWARDED_ARGS>...] [COMMAND]
     #[clap(hide = true)]
@@ -409,7 +706,7 @@ pub async fn run(repo_state: Option<RepoState>) -> Result<Payload> {
     // If this is a run command, and we know the actual invocation path, set the
     // inference root, as long as the user hasn't overridden the cwd
     if clap_args.cwd.is_none() {
-        if let Some(Command::Run(run_args)) = &mut clap_args.command {
+        if let Some(Command::Run(run_args) | Command::Watch(run_args)) = &mut clap_args.command {
             if let Ok(invocation_dir) = env::var(INVOCATION_DIR_ENV_VAR) {
                 let invocation_path = Path::new(&invocation_dir);
 
@@ -434,7 +731,7 @@ pub async fn run(repo_state: Option<RepoState>) -> Result<Payload> {
 
     // Do this after the above, since we're now always setting cwd.
     if let Some(repo_state) = repo_state {
-        if let Some(Command::Run(run_args)) = &mut clap_args.command {
+        if let Some(Command::Run(run_args) | Command::Watch(run_args)) = &mut clap_args.command {
             run_args.single_package = matches!(repo_state.mode, RepoMode::SinglePackage);
         }
         clap_args.cwd = Some(repo_state.root);
@@ -506,9 +803,13 @@ pub async fn run(repo_state: Option<RepoState>) -> Result<Payload> {
 
             Ok(Payload::Rust(Ok(0)))
         }
-        Command::Daemon { .. } | Command::Prune { .. } | Command::Run(_) => {
-            Ok(Payload::Go(Box::new(clap_args)))
-        }
+        Command::Cache { .. }
+        | Command::Daemon { .. }
+        | Command::Diff { .. }
+        | Command::Prune { .. }
+        | Command::Run(_)
+        | Command::Stats { .. }
+        | Command::Watch(_) => Ok(Payload::Go(Box::new(clap_args))),
         Command::Completion { shell } => {
             generate(*shell, &mut Args::command(), "turbo", &mut io::stdout());
 
@@ -586,7 +887,10 @@ mod test {
 
     use anyhow::Result;
 
-    use crate::cli::{Args, Command, DryRunMode, OutputLogsMode, RunArgs, Verbosity};
+    use crate::cli::{
+        Args, CacheCommand, CacheSigning, Command, DryRunMode, EnvMode, ErrorFormat, LogOrder,
+        OutputFormat, OutputLogsMode, RunArgs, Verbosity,
+    };
 
     #[test]
     fn test_parse_run() -> Result<()> {
@@ -613,11 +917,11 @@ mod test {
         );
 
         assert_eq!(
-            Args::try_parse_from(["turbo", "run", "build", "--cache-dir", "foobar"]).unwrap(),
+            Args::try_parse_from(["turbo", "run", "build", "--max-cache-size", "2GB"]).unwrap(),
             Args {
                 command: Some(Command::Run(Box::new(RunArgs {
                     tasks: vec!["build".to_string()],
-                    cache_dir: Some("foobar".to_string()),
+                    max_cache_size: Some("2GB".to_string()),
                     ..get_default_run_args()
                 }))),
                 ..Args::default()
@@ -625,11 +929,18 @@ mod test {
         );
 
         assert_eq!(
-            Args::try_parse_from(["turbo", "run", "build", "--cache-workers", "100"]).unwrap(),
+            Args::try_parse_from([
+                "turbo",
+                "run",
+                "build",
+                "--cache-backend",
+                "file:///tmp/cache"
+            ])
+            .unwrap(),
             Args {
                 command: Some(Command::Run(Box::new(RunArgs {
                     tasks: vec!["build".to_string()],
-                    cache_workers: 100,
+                    cache_backend: Some("file:///tmp/cache".to_string()),
                     ..get_default_run_args()
                 }))),
                 ..Args::default()
@@ -637,11 +948,11 @@ mod test {
         );
 
         assert_eq!(
-            Args::try_parse_from(["turbo", "run", "build", "--concurrency", "20"]).unwrap(),
+            Args::try_parse_from(["turbo", "run", "build", "--cache-key-salt", "node-20"]).unwrap(),
             Args {
                 command: Some(Command::Run(Box::new(RunArgs {
                     tasks: vec!["build".to_string()],
-                    concurrency: Some("20".to_string()),
+                    cache_key_salt: Some("node-20".to_string()),
                     ..get_default_run_args()
                 }))),
                 ..Args::default()
@@ -649,11 +960,11 @@ mod test {
         );
 
         assert_eq!(
-            Args::try_parse_from(["turbo", "run", "build", "--continue"]).unwrap(),
+            Args::try_parse_from(["turbo", "run", "build", "--cache-dir", "foobar"]).unwrap(),
             Args {
                 command: Some(Command::Run(Box::new(RunArgs {
                     tasks: vec!["build".to_string()],
-                    continue_execution: true,
+                    cache_dir: Some("foobar".to_string()),
                     ..get_default_run_args()
                 }))),
                 ..Args::default()
@@ -661,11 +972,11 @@ mod test {
         );
 
         assert_eq!(
-            Args::try_parse_from(["turbo", "run", "build", "--dry-run"]).unwrap(),
+            Args::try_parse_from(["turbo", "run", "build", "--cache-signing", "required"]).unwrap(),
             Args {
                 command: Some(Command::Run(Box::new(RunArgs {
                     tasks: vec!["build".to_string()],
-                    dry_run: Some(DryRunMode::Text),
+                    cache_signing: Some(CacheSigning::Required),
                     ..get_default_run_args()
                 }))),
                 ..Args::default()
@@ -673,11 +984,24 @@ mod test {
         );
 
         assert_eq!(
-            Args::try_parse_from(["turbo", "run", "build", "--dry-run", "json"]).unwrap(),
+            Args::try_parse_from(["turbo", "run", "build", "--cache-upload-concurrency", "4"])
+                .unwrap(),
             Args {
                 command: Some(Command::Run(Box::new(RunArgs {
                     tasks: vec!["build".to_string()],
-                    dry_run: Some(DryRunMode::Json),
+                    cache_upload_concurrency: Some(4),
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--cache-workers", "100"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    cache_workers: 100,
                     ..get_default_run_args()
                 }))),
                 ..Args::default()
@@ -686,19 +1010,17 @@ mod test {
 
         assert_eq!(
             Args::try_parse_from([
-                "turbo", "run", "build", "--filter", "water", "--filter", "earth", "--filter",
-                "fire", "--filter", "air"
+                "turbo",
+                "run",
+                "build",
+                "--completion-webhook",
+                "https://example.com/hook"
             ])
             .unwrap(),
             Args {
                 command: Some(Command::Run(Box::new(RunArgs {
                     tasks: vec!["build".to_string()],
-                    filter: vec![
-                        "water".to_string(),
-                        "earth".to_string(),
-                        "fire".to_string(),
-                        "air".to_string()
-                    ],
+                    completion_webhook: Some("https://example.com/hook".to_string()),
                     ..get_default_run_args()
                 }))),
                 ..Args::default()
@@ -706,11 +1028,11 @@ mod test {
         );
 
         assert_eq!(
-            Args::try_parse_from(["turbo", "run", "build", "--force"]).unwrap(),
+            Args::try_parse_from(["turbo", "run", "build", "--compress-logs"]).unwrap(),
             Args {
                 command: Some(Command::Run(Box::new(RunArgs {
                     tasks: vec!["build".to_string()],
-                    force: true,
+                    compress_logs: true,
                     ..get_default_run_args()
                 }))),
                 ..Args::default()
@@ -718,11 +1040,11 @@ mod test {
         );
 
         assert_eq!(
-            Args::try_parse_from(["turbo", "run", "build", "--global-deps", ".env"]).unwrap(),
+            Args::try_parse_from(["turbo", "run", "build", "--concurrency", "20"]).unwrap(),
             Args {
                 command: Some(Command::Run(Box::new(RunArgs {
                     tasks: vec!["build".to_string()],
-                    global_deps: vec![".env".to_string()],
+                    concurrency: Some("20".to_string()),
                     ..get_default_run_args()
                 }))),
                 ..Args::default()
@@ -730,20 +1052,12 @@ mod test {
         );
 
         assert_eq!(
-            Args::try_parse_from([
-                "turbo",
-                "run",
-                "build",
-                "--global-deps",
-                ".env",
-                "--global-deps",
-                ".env.development"
-            ])
-            .unwrap(),
+            Args::try_parse_from(["turbo", "run", "build", "--affected-output", "packages.txt"])
+                .unwrap(),
             Args {
                 command: Some(Command::Run(Box::new(RunArgs {
                     tasks: vec!["build".to_string()],
-                    global_deps: vec![".env".to_string(), ".env.development".to_string()],
+                    affected_output: Some("packages.txt".to_string()),
                     ..get_default_run_args()
                 }))),
                 ..Args::default()
@@ -751,11 +1065,11 @@ mod test {
         );
 
         assert_eq!(
-            Args::try_parse_from(["turbo", "run", "build", "--graph"]).unwrap(),
+            Args::try_parse_from(["turbo", "run", "build", "--audit-io"]).unwrap(),
             Args {
                 command: Some(Command::Run(Box::new(RunArgs {
                     tasks: vec!["build".to_string()],
-                    graph: Some("".to_string()),
+                    audit_io: true,
                     ..get_default_run_args()
                 }))),
                 ..Args::default()
@@ -763,11 +1077,11 @@ mod test {
         );
 
         assert_eq!(
-            Args::try_parse_from(["turbo", "run", "build", "--graph", "out.html"]).unwrap(),
+            Args::try_parse_from(["turbo", "run", "build", "--check-reproducible"]).unwrap(),
             Args {
                 command: Some(Command::Run(Box::new(RunArgs {
                     tasks: vec!["build".to_string()],
-                    graph: Some("out.html".to_string()),
+                    check_reproducible: true,
                     ..get_default_run_args()
                 }))),
                 ..Args::default()
@@ -775,11 +1089,11 @@ mod test {
         );
 
         assert_eq!(
-            Args::try_parse_from(["turbo", "run", "build", "--ignore", "foo.js"]).unwrap(),
+            Args::try_parse_from(["turbo", "run", "build", "--stream-logs-to", "logs"]).unwrap(),
             Args {
                 command: Some(Command::Run(Box::new(RunArgs {
                     tasks: vec!["build".to_string()],
-                    ignore: vec!["foo.js".to_string()],
+                    stream_logs_to: Some("logs".to_string()),
                     ..get_default_run_args()
                 }))),
                 ..Args::default()
@@ -788,13 +1102,20 @@ mod test {
 
         assert_eq!(
             Args::try_parse_from([
-                "turbo", "run", "build", "--ignore", "foo.js", "--ignore", "bar.js"
+                "turbo",
+                "run",
+                "build",
+                "--log-dir",
+                "logs",
+                "--log-file-name",
+                "{package}-{task}.log"
             ])
             .unwrap(),
             Args {
                 command: Some(Command::Run(Box::new(RunArgs {
                     tasks: vec!["build".to_string()],
-                    ignore: vec!["foo.js".to_string(), "bar.js".to_string()],
+                    log_file_name: Some("{package}-{task}.log".to_string()),
+                    stream_logs_to: Some("logs".to_string()),
                     ..get_default_run_args()
                 }))),
                 ..Args::default()
@@ -802,11 +1123,11 @@ mod test {
         );
 
         assert_eq!(
-            Args::try_parse_from(["turbo", "run", "build", "--include-dependencies"]).unwrap(),
+            Args::try_parse_from(["turbo", "run", "build", "--summarize-global-hash"]).unwrap(),
             Args {
                 command: Some(Command::Run(Box::new(RunArgs {
                     tasks: vec!["build".to_string()],
-                    include_dependencies: true,
+                    summarize_global_hash: true,
                     ..get_default_run_args()
                 }))),
                 ..Args::default()
@@ -814,11 +1135,18 @@ mod test {
         );
 
         assert_eq!(
-            Args::try_parse_from(["turbo", "run", "build", "--no-cache"]).unwrap(),
+            Args::try_parse_from([
+                "turbo",
+                "run",
+                "build",
+                "--summary-path",
+                ".turbo/summary.json"
+            ])
+            .unwrap(),
             Args {
                 command: Some(Command::Run(Box::new(RunArgs {
                     tasks: vec!["build".to_string()],
-                    no_cache: true,
+                    summary_path: Some(".turbo/summary.json".to_string()),
                     ..get_default_run_args()
                 }))),
                 ..Args::default()
@@ -826,11 +1154,18 @@ mod test {
         );
 
         assert_eq!(
-            Args::try_parse_from(["turbo", "run", "build", "--no-daemon"]).unwrap(),
+            Args::try_parse_from([
+                "turbo",
+                "run",
+                "build",
+                "--task-order-file",
+                "priorities.txt"
+            ])
+            .unwrap(),
             Args {
                 command: Some(Command::Run(Box::new(RunArgs {
                     tasks: vec!["build".to_string()],
-                    no_daemon: true,
+                    task_order_file: Some("priorities.txt".to_string()),
                     ..get_default_run_args()
                 }))),
                 ..Args::default()
@@ -838,30 +1173,23 @@ mod test {
         );
 
         assert_eq!(
-            Args::try_parse_from(["turbo", "run", "build", "--no-deps"]).unwrap(),
+            Args::try_parse_from(["turbo", "run", "build", "--validate-config"]).unwrap(),
             Args {
                 command: Some(Command::Run(Box::new(RunArgs {
                     tasks: vec!["build".to_string()],
-                    no_deps: true,
+                    validate_config: true,
                     ..get_default_run_args()
                 }))),
                 ..Args::default()
             }
         );
 
-        // Test that ouput-logs is not serialized by default
-        assert_eq!(
-            serde_json::to_string(&Args::try_parse_from(["turbo", "run", "build"]).unwrap())?
-                .contains("\"output_logs\":null"),
-            true
-        );
-
         assert_eq!(
-            Args::try_parse_from(["turbo", "run", "build", "--output-logs", "full"]).unwrap(),
+            Args::try_parse_from(["turbo", "run", "build", "--verify-cache-outputs"]).unwrap(),
             Args {
                 command: Some(Command::Run(Box::new(RunArgs {
                     tasks: vec!["build".to_string()],
-                    output_logs: Some(OutputLogsMode::Full),
+                    verify_cache_outputs: true,
                     ..get_default_run_args()
                 }))),
                 ..Args::default()
@@ -869,11 +1197,11 @@ mod test {
         );
 
         assert_eq!(
-            Args::try_parse_from(["turbo", "run", "build", "--output-logs", "none"]).unwrap(),
+            Args::try_parse_from(["turbo", "run", "build", "--strict-env"]).unwrap(),
             Args {
                 command: Some(Command::Run(Box::new(RunArgs {
                     tasks: vec!["build".to_string()],
-                    output_logs: Some(OutputLogsMode::None),
+                    strict_env: true,
                     ..get_default_run_args()
                 }))),
                 ..Args::default()
@@ -881,11 +1209,11 @@ mod test {
         );
 
         assert_eq!(
-            Args::try_parse_from(["turbo", "run", "build", "--output-logs", "hash-only"]).unwrap(),
+            Args::try_parse_from(["turbo", "run", "build", "--strict"]).unwrap(),
             Args {
                 command: Some(Command::Run(Box::new(RunArgs {
                     tasks: vec!["build".to_string()],
-                    output_logs: Some(OutputLogsMode::HashOnly),
+                    strict: true,
                     ..get_default_run_args()
                 }))),
                 ..Args::default()
@@ -893,11 +1221,20 @@ mod test {
         );
 
         assert_eq!(
-            Args::try_parse_from(["turbo", "run", "build", "--parallel"]).unwrap(),
+            Args::try_parse_from([
+                "turbo",
+                "run",
+                "build",
+                "--concurrency-group",
+                "heavy=2",
+                "--concurrency-group",
+                "e2e=1"
+            ])
+            .unwrap(),
             Args {
                 command: Some(Command::Run(Box::new(RunArgs {
                     tasks: vec!["build".to_string()],
-                    parallel: true,
+                    concurrency_group: vec!["heavy=2".to_string(), "e2e=1".to_string()],
                     ..get_default_run_args()
                 }))),
                 ..Args::default()
@@ -905,11 +1242,11 @@ mod test {
         );
 
         assert_eq!(
-            Args::try_parse_from(["turbo", "run", "build", "--profile", "profile_out"]).unwrap(),
+            Args::try_parse_from(["turbo", "run", "build", "--continue"]).unwrap(),
             Args {
                 command: Some(Command::Run(Box::new(RunArgs {
                     tasks: vec!["build".to_string()],
-                    profile: Some("profile_out".to_string()),
+                    continue_execution: true,
                     ..get_default_run_args()
                 }))),
                 ..Args::default()
@@ -917,11 +1254,11 @@ mod test {
         );
 
         assert_eq!(
-            Args::try_parse_from(["turbo", "run", "build", "--remote-only"]).unwrap(),
+            Args::try_parse_from(["turbo", "run", "build", "--defer-cache-writes"]).unwrap(),
             Args {
                 command: Some(Command::Run(Box::new(RunArgs {
                     tasks: vec!["build".to_string()],
-                    remote_only: true,
+                    defer_cache_writes: true,
                     ..get_default_run_args()
                 }))),
                 ..Args::default()
@@ -929,12 +1266,11 @@ mod test {
         );
 
         assert_eq!(
-            Args::try_parse_from(["turbo", "run", "build", "--scope", "foo", "--scope", "bar"])
-                .unwrap(),
+            Args::try_parse_from(["turbo", "run", "build", "--dump-inputs", "ci-inputs"]).unwrap(),
             Args {
                 command: Some(Command::Run(Box::new(RunArgs {
                     tasks: vec!["build".to_string()],
-                    scope: vec!["foo".to_string(), "bar".to_string()],
+                    dump_inputs: Some("ci-inputs".to_string()),
                     ..get_default_run_args()
                 }))),
                 ..Args::default()
@@ -942,11 +1278,11 @@ mod test {
         );
 
         assert_eq!(
-            Args::try_parse_from(["turbo", "run", "build", "--since", "foo"]).unwrap(),
+            Args::try_parse_from(["turbo", "run", "build", "--error-on-empty"]).unwrap(),
             Args {
                 command: Some(Command::Run(Box::new(RunArgs {
                     tasks: vec!["build".to_string()],
-                    since: Some("foo".to_string()),
+                    error_on_empty: true,
                     ..get_default_run_args()
                 }))),
                 ..Args::default()
@@ -954,77 +1290,686 @@ mod test {
         );
 
         assert_eq!(
-            Args::try_parse_from(["turbo", "build"]).unwrap(),
+            Args::try_parse_from([
+                "turbo",
+                "run",
+                "build",
+                "--events-socket",
+                "/tmp/turbo.sock"
+            ])
+            .unwrap(),
             Args {
-                run_args: Some(RunArgs {
+                command: Some(Command::Run(Box::new(RunArgs {
                     tasks: vec!["build".to_string()],
+                    events_socket: Some("/tmp/turbo.sock".to_string()),
                     ..get_default_run_args()
-                }),
+                }))),
                 ..Args::default()
             }
         );
 
         assert_eq!(
-            Args::try_parse_from(["turbo", "build", "lint", "test"]).unwrap(),
+            Args::try_parse_from(["turbo", "run", "build", "--errors", "json"]).unwrap(),
             Args {
-                run_args: Some(RunArgs {
-                    tasks: vec!["build".to_string(), "lint".to_string(), "test".to_string()],
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    errors: Some(ErrorFormat::Json),
                     ..get_default_run_args()
-                }),
+                }))),
                 ..Args::default()
             }
         );
 
-        Ok(())
-    }
-
-    #[test]
-    fn test_parse_bin() {
         assert_eq!(
-            Args::try_parse_from(["turbo", "bin"]).unwrap(),
+            Args::try_parse_from(["turbo", "run", "build", "--dry-run"]).unwrap(),
             Args {
-                command: Some(Command::Bin {}),
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    dry_run: Some(DryRunMode::Text),
+                    ..get_default_run_args()
+                }))),
                 ..Args::default()
             }
         );
 
-        CommandTestCase {
-            command: "bin",
-            command_args: vec![],
-            global_args: vec![vec!["--cwd", "../examples/with-yarn"]],
-            expected_output: Args {
-                command: Some(Command::Bin {}),
-                cwd: Some(PathBuf::from("../examples/with-yarn")),
-                ..Args::default()
-            },
-        }
-        .test();
-    }
-
-    #[test]
-    fn test_parse_login() {
         assert_eq!(
-            Args::try_parse_from(["turbo", "login"]).unwrap(),
+            Args::try_parse_from(["turbo", "run", "build", "--dry-run", "json"]).unwrap(),
             Args {
-                command: Some(Command::Login { sso_team: None }),
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    dry_run: Some(DryRunMode::Json),
+                    ..get_default_run_args()
+                }))),
                 ..Args::default()
             }
         );
 
-        CommandTestCase {
-            command: "login",
-            command_args: vec![],
-            global_args: vec![vec!["--cwd", "../examples/with-yarn"]],
-            expected_output: Args {
-                command: Some(Command::Login { sso_team: None }),
-                cwd: Some(PathBuf::from("../examples/with-yarn")),
-                ..Args::default()
-            },
-        }
-        .test();
-
-        CommandTestCase {
-            command: "login",
+        assert_eq!(
+            Args::try_parse_from([
+                "turbo", "run", "build", "--filter", "water", "--filter", "earth", "--filter",
+                "fire", "--filter", "air"
+            ])
+            .unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    filter: vec![
+                        "water".to_string(),
+                        "earth".to_string(),
+                        "fire".to_string(),
+                        "air".to_string()
+                    ],
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--force"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    force: true,
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--github-annotations"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    github_annotations: Some(true),
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "--github-annotations", "build"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    github_annotations: Some(true),
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--github-annotations=false"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    github_annotations: Some(false),
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--global-deps", ".env"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    global_deps: vec![".env".to_string()],
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from([
+                "turbo",
+                "run",
+                "build",
+                "--global-deps",
+                ".env",
+                "--global-deps",
+                ".env.development"
+            ])
+            .unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    global_deps: vec![".env".to_string(), ".env.development".to_string()],
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--graph"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    graph: Some("".to_string()),
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--graph", "out.html"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    graph: Some("out.html".to_string()),
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--ignore", "foo.js"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    ignore: vec!["foo.js".to_string()],
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from([
+                "turbo", "run", "build", "--ignore", "foo.js", "--ignore", "bar.js"
+            ])
+            .unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    ignore: vec!["foo.js".to_string(), "bar.js".to_string()],
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--include-dependencies"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    include_dependencies: true,
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--isolate-outputs"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    isolate_outputs: true,
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--kill-timeout", "30s"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    kill_timeout: Some("30s".to_string()),
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--max-log-bytes-per-task", "10MB"])
+                .unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    max_log_bytes_per_task: Some("10MB".to_string()),
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--no-cache"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    no_cache: true,
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--no-daemon"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    no_daemon: true,
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--no-deps"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    no_deps: true,
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--no-lockfile-cache"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    no_lockfile_cache: true,
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--no-op"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    no_op: Some("".to_string()),
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--no-op=500ms"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    no_op: Some("500ms".to_string()),
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--no-preamble"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    no_preamble: true,
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--no-workspace-cache"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    no_workspace_cache: true,
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        // Test that ouput-logs is not serialized by default
+        assert_eq!(
+            serde_json::to_string(&Args::try_parse_from(["turbo", "run", "build"]).unwrap())?
+                .contains("\"output_logs\":null"),
+            true
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--env-mode", "strict"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    env_mode: Some(EnvMode::Strict),
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from([
+                "turbo",
+                "run",
+                "build",
+                "--log-prefix-template",
+                "{package} › {task}"
+            ])
+            .unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    log_prefix_template: Some("{package} › {task}".to_string()),
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--log-order", "grouped"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    log_order: Some(LogOrder::Grouped),
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--output", "ndjson"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    output: Some(OutputFormat::Ndjson),
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--output-logs", "full"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    output_logs: Some(OutputLogsMode::Full),
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--output-logs", "none"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    output_logs: Some(OutputLogsMode::None),
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--output-logs", "hash-only"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    output_logs: Some(OutputLogsMode::HashOnly),
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--offline"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    offline: true,
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--parallel"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    parallel: true,
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--prefetch"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    prefetch: true,
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--profile", "profile_out"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    profile: Some("profile_out".to_string()),
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--remote-cache-read-only"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    remote_cache_read_only: true,
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--run-timeout", "30m"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    run_timeout: Some("30m".to_string()),
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--remote-only"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    remote_only: true,
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--rerun-dependents-of", "ui#build"])
+                .unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    rerun_dependents_of: vec!["ui#build".to_string()],
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--sequential-prefix-colors"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    sequential_prefix_colors: true,
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--serial-within-package"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    serial_within_package: true,
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--require-remote-cache"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    require_remote_cache: true,
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--scope", "foo", "--scope", "bar"])
+                .unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    scope: vec!["foo".to_string(), "bar".to_string()],
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "run", "build", "--since", "foo"]).unwrap(),
+            Args {
+                command: Some(Command::Run(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    since: Some("foo".to_string()),
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "build"]).unwrap(),
+            Args {
+                run_args: Some(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    ..get_default_run_args()
+                }),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "build", "lint", "test"]).unwrap(),
+            Args {
+                run_args: Some(RunArgs {
+                    tasks: vec!["build".to_string(), "lint".to_string(), "test".to_string()],
+                    ..get_default_run_args()
+                }),
+                ..Args::default()
+            }
+        );
+
+        Ok(())
+    }
+
+    #[test]
+    fn test_parse_bin() {
+        assert_eq!(
+            Args::try_parse_from(["turbo", "bin"]).unwrap(),
+            Args {
+                command: Some(Command::Bin {}),
+                ..Args::default()
+            }
+        );
+
+        CommandTestCase {
+            command: "bin",
+            command_args: vec![],
+            global_args: vec![vec!["--cwd", "../examples/with-yarn"]],
+            expected_output: Args {
+                command: Some(Command::Bin {}),
+                cwd: Some(PathBuf::from("../examples/with-yarn")),
+                ..Args::default()
+            },
+        }
+        .test();
+    }
+
+    #[test]
+    fn test_parse_login() {
+        assert_eq!(
+            Args::try_parse_from(["turbo", "login"]).unwrap(),
+            Args {
+                command: Some(Command::Login { sso_team: None }),
+                ..Args::default()
+            }
+        );
+
+        CommandTestCase {
+            command: "login",
+            command_args: vec![],
+            global_args: vec![vec!["--cwd", "../examples/with-yarn"]],
+            expected_output: Args {
+                command: Some(Command::Login { sso_team: None }),
+                cwd: Some(PathBuf::from("../examples/with-yarn")),
+                ..Args::default()
+            },
+        }
+        .test();
+
+        CommandTestCase {
+            command: "login",
             command_args: vec![vec!["--sso-team", "my-team"]],
             global_args: vec![vec!["--cwd", "../examples/with-yarn"]],
             expected_output: Args {
@@ -1084,6 +2029,132 @@ mod test {
         .test();
     }
 
+    #[test]
+    fn test_parse_cache() {
+        assert_eq!(
+            Args::try_parse_from(["turbo", "cache", "doctor"]).unwrap(),
+            Args {
+                command: Some(Command::Cache {
+                    command: CacheCommand::Doctor {
+                        fix: false,
+                        cache_dir: None,
+                    }
+                }),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "cache", "doctor", "--fix", "--cache-dir", "cache"])
+                .unwrap(),
+            Args {
+                command: Some(Command::Cache {
+                    command: CacheCommand::Doctor {
+                        fix: true,
+                        cache_dir: Some("cache".to_string()),
+                    }
+                }),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "cache", "verify"]).unwrap(),
+            Args {
+                command: Some(Command::Cache {
+                    command: CacheCommand::Verify {
+                        prune: false,
+                        cache_dir: None,
+                    }
+                }),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "cache", "verify", "--prune"]).unwrap(),
+            Args {
+                command: Some(Command::Cache {
+                    command: CacheCommand::Verify {
+                        prune: true,
+                        cache_dir: None,
+                    }
+                }),
+                ..Args::default()
+            }
+        );
+    }
+
+    #[test]
+    fn test_parse_diff() {
+        assert_eq!(
+            Args::try_parse_from(["turbo", "diff", "before.json", "after.json"]).unwrap(),
+            Args {
+                command: Some(Command::Diff {
+                    before: "before.json".to_string(),
+                    after: "after.json".to_string(),
+                }),
+                ..Args::default()
+            }
+        );
+
+        assert!(Args::try_parse_from(["turbo", "diff", "before.json"]).is_err());
+    }
+
+    #[test]
+    fn test_parse_stats() {
+        assert_eq!(
+            Args::try_parse_from(["turbo", "stats"]).unwrap(),
+            Args {
+                command: Some(Command::Stats {
+                    reset: false,
+                    max_runs: None,
+                    limit: 10,
+                }),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "stats", "--max-runs", "20", "--limit", "0"]).unwrap(),
+            Args {
+                command: Some(Command::Stats {
+                    reset: false,
+                    max_runs: Some(20),
+                    limit: 0,
+                }),
+                ..Args::default()
+            }
+        );
+
+        assert_eq!(
+            Args::try_parse_from(["turbo", "stats", "--reset"]).unwrap(),
+            Args {
+                command: Some(Command::Stats {
+                    reset: true,
+                    max_runs: None,
+                    limit: 10,
+                }),
+                ..Args::default()
+            }
+        );
+    }
+
+    #[test]
+    fn test_parse_watch() {
+        assert_eq!(
+            Args::try_parse_from(["turbo", "watch", "build", "--filter", "web"]).unwrap(),
+            Args {
+                command: Some(Command::Watch(Box::new(RunArgs {
+                    tasks: vec!["build".to_string()],
+                    filter: vec!["web".to_string()],
+                    ..get_default_run_args()
+                }))),
+                ..Args::default()
+            }
+        );
+    }
+
     #[test]
     fn test_parse_prune() {
         let default_prune = Command::Prune {
@@ -33,8 +33,14 @@ static TURBO_PURE_OUTPUT_ARGS: [&str; 6] = [
     "--dry-run=json",
 ];
 
-static TURBO_SKIP_NOTIFIER_ARGS: [&str; 5] =
-    ["--help", "--h", "--version", "--v", "--no-update-notifier"];
+static TURBO_SKIP_NOTIFIER_ARGS: [&str; 6] = [
+    "--help",
+    "--h",
+    "--version",
+    "--v",
+    "--no-update-notifier",
+    "--offline",
+];
 
 fn turbo_version_has_shim(version: &str) -> bool {
     let version = Version::parse(version).unwrap();
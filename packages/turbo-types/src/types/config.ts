@@ -64,6 +64,8 @@ export interface RootSchema extends BaseSchema {
    * A list of environment variables for implicit global hash dependencies.
    *
    * The variables included in this list will affect all task hashes.
+   * Use a wildcard to include every matching variable (e.g. MYAPP_*), and
+   * prefix a name with ! to leave it out (e.g. !MYAPP_SECRET).
    *
    * Documentation: https://turbo.build/repo/docs/reference/configuration#globalenv
    *
@@ -71,6 +73,32 @@ export interface RootSchema extends BaseSchema {
    */
   globalEnv?: string[];
 
+  /**
+   * A list of environment variables that every task is given, but that are
+   * never part of the global hash or of any task hash.
+   *
+   * A task's passThroughEnv adds to this list. A variable can't be in both
+   * globalEnv and globalPassThroughEnv.
+   *
+   * Documentation: https://turbo.build/repo/docs/reference/configuration#globalpassthroughenv
+   *
+   * @default []
+   */
+  globalPassThroughEnv?: string[];
+
+  /**
+   * A list of commands, such as `node --version`, whose output will affect
+   * all task hashes.
+   *
+   * Each command is run from the root of the repository without a shell.
+   * A command that fails or times out is an error.
+   *
+   * Documentation: https://turbo.build/repo/docs/reference/configuration#globaltoolversions
+   *
+   * @default []
+   */
+  globalToolVersions?: string[];
+
   /**
    * Configuration options that control how turbo interfaces with the remote cache.
    *
@@ -79,6 +107,64 @@ export interface RootSchema extends BaseSchema {
    * @default {}
    */
   remoteCache?: RemoteCache;
+
+  /**
+   * A semver range of turbo versions that can be used with this repository.
+   *
+   * If the running version of turbo does not satisfy this range, turbo
+   * will exit with an error asking you to upgrade.
+   *
+   * Documentation: https://turbo.build/repo/docs/reference/configuration#turboversion
+   */
+  turboVersion?: string;
+
+  /**
+   * A map of task exit codes to a label describing the kind of failure they represent
+   * (e.g. { "2": "test failure", "127": "missing binary" }).
+   *
+   * The label is included in the error turbo reports for a failed task, and in the run summary.
+   *
+   * Documentation: https://turbo.build/repo/docs/reference/configuration#exitcodecategories
+   *
+   * @default {}
+   */
+  exitCodeCategories?: Record<string, string>;
+
+  /**
+   * Commands to run in the package directory of every task that turbo executes,
+   * before and after the task's own command.
+   *
+   * Documentation: https://turbo.build/repo/docs/reference/configuration#taskhooks
+   *
+   * @default {}
+   */
+  taskHooks?: TaskHooks;
+
+  /**
+   * Named lists of environment variables that tasks can include in their
+   * hashes with "envGroups", instead of repeating them in each task's "env".
+   *
+   * Env groups can only be defined in the root turbo.json.
+   *
+   * Documentation: https://turbo.build/repo/docs/reference/configuration#envgroups
+   *
+   * @default {}
+   */
+  envGroups?: Record<string, string[]>;
+
+  /**
+   * Shared configs to build on, merged in order before this one. Entries
+   * starting with "." are paths to a config file, or a directory containing
+   * turbo.json, relative to this file. Other entries are the names of
+   * installed packages, whose turbo.json is used.
+   *
+   * Later configs take precedence, and this config takes precedence over all of them.
+   *
+   * Documentation: https://turbo.build/repo/docs/reference/configuration#extends
+   *
+   * @default []
+   */
+  extends?: string[];
 }
 
 export interface Pipeline {
@@ -108,12 +194,39 @@ export interface Pipeline {
    * You no longer need to use the $ prefix.
    * (e.g. $GITHUB_TOKEN -> GITHUB_TOKEN)
    *
+   * Use a wildcard to include every matching variable (e.g. MYAPP_*), and
+   * prefix a name with ! to leave it out (e.g. !MYAPP_SECRET).
+   *
    * Documentation: https://turbo.build/repo/docs/reference/configuration#env
    *
    * @default []
    */
   env?: string[];
 
+  /**
+   * The names of env groups, defined in the root turbo.json's "envGroups",
+   * whose environment variables this task depends on.
+   *
+   * They are added to the task's env, exactly as if they had been listed there.
+   *
+   * Documentation: https://turbo.build/repo/docs/reference/configuration#envgroups-1
+   *
+   * @default []
+   */
+  envGroups?: string[];
+
+  /**
+   * Environment variables the task uses that don't affect its outputs (e.g.
+   * logging or telemetry settings). They are never included in the task's hash,
+   * even if they are inferred from the workspace's framework, so changing them
+   * doesn't cause a cache miss.
+   *
+   * Documentation: https://turbo.build/repo/docs/reference/configuration#passthroughenv
+   *
+   * @default []
+   */
+  passThroughEnv?: string[];
+
   /**
    * The set of glob patterns indicating a task's cacheable filesystem outputs.
    *
@@ -127,6 +240,20 @@ export interface Pipeline {
    */
   outputs?: string[];
 
+  /**
+   * The set of glob patterns of the task's outputs that are restored from the
+   * cache when present, but never saved to it.
+   *
+   * This is useful for files produced by generators that aren't deterministic,
+   * which would otherwise cause a new artifact to be uploaded every time they
+   * are regenerated.
+   *
+   * Documentation: https://turbo.build/repo/docs/reference/configuration#restoreonlyoutputs
+   *
+   * @default []
+   */
+  restoreOnlyOutputs?: string[];
+
   /**
    * Whether or not to cache the outputs of the task.
    *
@@ -148,6 +275,7 @@ export interface Pipeline {
    * it will not cause a cache miss.
    *
    * If omitted or empty, all files in the package are considered as inputs.
+   * Include "$TURBO_DEFAULT" to add globs to the default inputs.
    *
    * Documentation: https://turbo.build/repo/docs/reference/configuration#inputs
    *
@@ -155,6 +283,19 @@ export interface Pipeline {
    */
   inputs?: string[];
 
+  /**
+   * The set of glob patterns, relative to the repository root, of files
+   * outside of the workspace to consider as additional inputs to this task.
+   *
+   * Changes to files covered by these globs will cause a cache miss and
+   * the task will be rerun.
+   *
+   * Documentation: https://turbo.build/repo/docs/reference/configuration#rootinputs
+   *
+   * @default []
+   */
+  rootInputs?: string[];
+
   /**
    * Output mode for the task.
    *
@@ -184,6 +325,153 @@ export interface Pipeline {
    * @default false
    */
   persistent?: boolean;
+
+  /**
+   * How long the task's command may run, as a duration like "120s" or "5m".
+   * A task that runs longer is stopped and fails, like a task that exits
+   * with a non-zero code.
+   *
+   * Documentation: https://turbo.build/repo/docs/reference/configuration#timeout
+   */
+  timeout?: string;
+
+  /**
+   * How many times a failing task is retried before it fails the run. Tasks
+   * are retried when their command exits with a non-zero code or times out.
+   *
+   * Documentation: https://turbo.build/repo/docs/reference/configuration#retries
+   *
+   * @default 0
+   */
+  retries?: number;
+
+  /**
+   * How long to wait before each retry, as a duration like "5s".
+   *
+   * Documentation: https://turbo.build/repo/docs/reference/configuration#retrybackoff
+   */
+  retryBackoff?: string;
+
+  /**
+   * Overrides the framework inferred for the workspace, which determines the
+   * environment variables that are automatically included in the task's hash.
+   *
+   * Use "none" to disable framework inference for this task.
+   *
+   * Documentation: https://turbo.build/repo/docs/reference/configuration#framework
+   */
+  framework?: string;
+
+  /**
+   * A command whose output is included in the task's hash, for state that isn't
+   * captured by a file or an environment variable (e.g. the commit of a git submodule).
+   *
+   * The command is run once per workspace, in the workspace's directory, before
+   * any tasks are hashed.
+   *
+   * Documentation: https://turbo.build/repo/docs/reference/configuration#hashinputsfrom
+   */
+  hashInputsFrom?: string;
+
+  /**
+   * .env files, relative to the workspace, whose variables are included in the
+   * task's hash. Later files take precedence, and missing files are skipped.
+   * The variables are not loaded into the task's environment.
+   *
+   * Documentation: https://turbo.build/repo/docs/reference/configuration#dotenv
+   */
+  dotEnv?: string[];
+
+  /**
+   * Normalizes the task's outputs on disk before they are cached, so that
+   * machine-specific noise (e.g. absolute paths) doesn't make otherwise equal
+   * outputs differ.
+   *
+   * Either a built-in normalizer ("builtin:repo-root"), or a command that is
+   * run in the workspace's directory with the paths of the output files on
+   * its stdin.
+   *
+   * Documentation: https://turbo.build/repo/docs/reference/configuration#outputtransform
+   */
+  outputTransform?: string;
+
+  /**
+   * A command run in the workspace's directory after the task's outputs are
+   * restored from the cache, for side effects that the outputs alone don't
+   * reproduce (e.g. re-linking binaries).
+   *
+   * Documentation: https://turbo.build/repo/docs/reference/configuration#postcacherestore
+   */
+  postCacheRestore?: PostCacheRestoreHook;
+
+  /**
+   * A regular expression matched against each line the task prints to stdout.
+   * The first capture group of each match is the path, relative to the
+   * workspace's directory, of a file that is cached along with `outputs`.
+   *
+   * Documentation: https://turbo.build/repo/docs/reference/configuration#outputsfromlog
+   */
+  outputsFromLog?: string;
+
+  /**
+   * Connects the task's command to the terminal, so that it can read from stdin
+   * (e.g. a dev server that accepts keypresses). Its output is shown without
+   * prefixes and isn't written to the task's log file.
+   *
+   * Documentation: https://turbo.build/repo/docs/reference/configuration#interactive
+   */
+  interactive?: boolean;
+
+  /**
+   * The name of a group of tasks that `--concurrency-group <group>=<limit>`
+   * limits to a number running at once (e.g. tasks that use a lot of memory).
+   *
+   * Documentation: https://turbo.build/repo/docs/reference/configuration#concurrencygroup
+   */
+  concurrencyGroup?: string;
+
+  /**
+   * Whether the task can run at the same time as other tasks that aren't
+   * parallel safe. Tasks with `false` run one at a time, even with
+   * `--parallel` (e.g. tasks that listen on the same port). Persistent
+   * tasks can't set it to `false`.
+   *
+   * Documentation: https://turbo.build/repo/docs/reference/configuration#parallelsafe
+   *
+   * @default true
+   */
+  parallelSafe?: boolean;
+}
+
+export interface PostCacheRestoreHook {
+  /**
+   * The command to run. `TURBO_HASH` and `TURBO_TASK` are set to the hash and the
+   * ID (e.g. "web#build") of the restored task.
+   */
+  command: string;
+
+  /**
+   * Fail the task when the command fails. Otherwise, the failure is reported as a
+   * warning and the cache hit is kept.
+   *
+   * @default false
+   */
+  fatal?: boolean;
+}
+
+export interface TaskHooks {
+  /**
+   * Runs before the task's command. `TURBO_TASK_ID` and `TURBO_HASH` are set to
+   * the ID (e.g. "web#build") and the hash of the task. If it fails, the task
+   * fails without running its command.
+   */
+  before?: string;
+
+  /**
+   * Runs once the task's command finished, whether or not it succeeded, with the
+   * same environment variables as `before`. If it fails, a warning is printed.
+   */
+  after?: string;
 }
 
 export interface RemoteCache {